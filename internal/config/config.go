@@ -0,0 +1,420 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"order_manager/internal/log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const envPrefix = "ORDER_MANAGER_"
+
+const (
+	StorageSQLite = "sqlite"
+	StorageInMem  = "inmem"
+)
+
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+)
+
+const (
+	TaxModeInclusive = "inclusive"
+	TaxModeExclusive = "exclusive"
+)
+
+const (
+	DeductOnOrder       = "order"
+	DeductOnPreparation = "preparation"
+)
+
+const (
+	RestockNever     = "never"
+	RestockUnstarted = "unstarted"
+	RestockAlways    = "always"
+)
+
+type HTTP struct {
+	Addr            string        `yaml:"addr"`
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+type Storage struct {
+	Backend string `yaml:"backend"`
+	DSN     string `yaml:"dsn"`
+}
+
+type Log struct {
+	Level  string `yaml:"level"`
+	Output string `yaml:"output"`
+}
+
+// Retry controls the retries of the commands failing on concurrent modifications.
+type Retry struct {
+	Attempts int           `yaml:"attempts"`
+	Backoff  time.Duration `yaml:"backoff"`
+}
+
+// ServiceCharge controls the service charge added to the bills of large parties.
+type ServiceCharge struct {
+	// Percent of the bill charged, 0 disables the service charge.
+	Percent   float64 `yaml:"percent"`
+	MinGuests int     `yaml:"min_guests"`
+}
+
+// Tax controls the taxes charged on the bills.
+type Tax struct {
+	// Mode is inclusive when the menu prices include the taxes, exclusive when they are added on top.
+	Mode string `yaml:"mode"`
+	// Categories maps the tax categories of the menu items to their rate in percent.
+	Categories map[string]float64 `yaml:"categories"`
+}
+
+// Inventory controls how the preparations move the stock of the ingredients.
+type Inventory struct {
+	// DeductOn is order when the stock is taken as the order is taken, preparation when the preparation starts.
+	DeductOn string `yaml:"deduct_on"`
+	// Restock tells which aborted preparations give their stock back: never, the unstarted ones or always.
+	Restock string `yaml:"restock"`
+}
+
+// Waitlist controls the turn times from which the waits quoted to the walk-in parties are estimated.
+type Waitlist struct {
+	// TurnTimeLookback is how far back the closed tables are timed.
+	TurnTimeLookback time.Duration `yaml:"turn_time_lookback"`
+	// DefaultTurnTime is the turn time of the dining tables on which no table was closed within the lookback.
+	DefaultTurnTime time.Duration `yaml:"default_turn_time"`
+}
+
+type Config struct {
+	HTTP          HTTP          `yaml:"http"`
+	Storage       Storage       `yaml:"storage"`
+	Log           Log           `yaml:"log"`
+	Retry         Retry         `yaml:"retry"`
+	ServiceCharge ServiceCharge `yaml:"service_charge"`
+	Tax           Tax           `yaml:"tax"`
+	Inventory     Inventory     `yaml:"inventory"`
+	Waitlist      Waitlist      `yaml:"waitlist"`
+}
+
+// Default returns the configuration used when nothing else is provided.
+func Default() Config {
+	return Config{
+		HTTP: HTTP{
+			Addr:            ":8080",
+			ReadTimeout:     5 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 10 * time.Second,
+		},
+		Storage: Storage{
+			Backend: StorageSQLite,
+			DSN:     "./db",
+		},
+		Log: Log{
+			Level:  "info",
+			Output: OutputStdout,
+		},
+		Retry: Retry{
+			Attempts: 3,
+			Backoff:  10 * time.Millisecond,
+		},
+		Tax: Tax{
+			Mode: TaxModeInclusive,
+		},
+		Inventory: Inventory{
+			DeductOn: DeductOnOrder,
+			Restock:  RestockUnstarted,
+		},
+		Waitlist: Waitlist{
+			TurnTimeLookback: 28 * 24 * time.Hour,
+			DefaultTurnTime:  time.Hour,
+		},
+	}
+}
+
+// Load builds the configuration from, in increasing order of precedence:
+// the defaults, the YAML file given by -config or ORDER_MANAGER_CONFIG,
+// the ORDER_MANAGER_* environment variables and the command-line flags.
+// The resulting configuration is validated before being returned.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("order_manager", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	configPath := fs.String("config", getenv(envPrefix+"CONFIG"), "path to a YAML configuration file")
+	flags := Config{}
+	fs.StringVar(&flags.HTTP.Addr, "addr", "", "HTTP listen address")
+	fs.DurationVar(&flags.HTTP.ReadTimeout, "read-timeout", 0, "HTTP read timeout")
+	fs.DurationVar(&flags.HTTP.WriteTimeout, "write-timeout", 0, "HTTP write timeout")
+	fs.DurationVar(&flags.HTTP.IdleTimeout, "idle-timeout", 0, "HTTP idle timeout")
+	fs.DurationVar(&flags.HTTP.ShutdownTimeout, "shutdown-timeout", 0, "graceful shutdown timeout")
+	fs.StringVar(&flags.Storage.Backend, "storage", "", "storage backend (sqlite or inmem)")
+	fs.StringVar(&flags.Storage.DSN, "dsn", "", "sqlite data source name")
+	fs.StringVar(&flags.Log.Level, "log-level", "", "log level (debug, info, warning or error)")
+	fs.StringVar(&flags.Log.Output, "log-output", "", "log output (stdout, stderr or a file path)")
+	fs.IntVar(&flags.Retry.Attempts, "retry-attempts", 0, "attempts of the commands failing on concurrent modifications")
+	fs.DurationVar(&flags.Retry.Backoff, "retry-backoff", 0, "wait before retrying a command, growing with each attempt")
+	fs.Float64Var(&flags.ServiceCharge.Percent, "service-charge-percent", 0, "percent of the bill charged as service, 0 disables it")
+	fs.IntVar(&flags.ServiceCharge.MinGuests, "service-charge-min-guests", 0, "guests from which the service charge applies")
+	fs.StringVar(&flags.Tax.Mode, "tax-mode", "", "tax mode (inclusive or exclusive)")
+	fs.StringVar(&flags.Inventory.DeductOn, "inventory-deduct-on", "", "moment the stock is taken (order or preparation)")
+	fs.StringVar(&flags.Inventory.Restock, "inventory-restock", "", "aborted preparations giving their stock back (never, unstarted or always)")
+	fs.DurationVar(&flags.Waitlist.TurnTimeLookback, "waitlist-turn-time-lookback", 0, "how far back the closed tables are timed")
+	fs.DurationVar(&flags.Waitlist.DefaultTurnTime, "waitlist-default-turn-time", 0, "turn time of the dining tables without closed tables to time")
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, fmt.Errorf("invalid flags: %w", err)
+	}
+
+	if *configPath != "" {
+		if err := cfg.loadFile(*configPath); err != nil {
+			return Config{}, err
+		}
+	}
+
+	if err := cfg.loadEnv(getenv); err != nil {
+		return Config{}, err
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "addr":
+			cfg.HTTP.Addr = flags.HTTP.Addr
+		case "read-timeout":
+			cfg.HTTP.ReadTimeout = flags.HTTP.ReadTimeout
+		case "write-timeout":
+			cfg.HTTP.WriteTimeout = flags.HTTP.WriteTimeout
+		case "idle-timeout":
+			cfg.HTTP.IdleTimeout = flags.HTTP.IdleTimeout
+		case "shutdown-timeout":
+			cfg.HTTP.ShutdownTimeout = flags.HTTP.ShutdownTimeout
+		case "storage":
+			cfg.Storage.Backend = flags.Storage.Backend
+		case "dsn":
+			cfg.Storage.DSN = flags.Storage.DSN
+		case "log-level":
+			cfg.Log.Level = flags.Log.Level
+		case "log-output":
+			cfg.Log.Output = flags.Log.Output
+		case "retry-attempts":
+			cfg.Retry.Attempts = flags.Retry.Attempts
+		case "retry-backoff":
+			cfg.Retry.Backoff = flags.Retry.Backoff
+		case "service-charge-percent":
+			cfg.ServiceCharge.Percent = flags.ServiceCharge.Percent
+		case "service-charge-min-guests":
+			cfg.ServiceCharge.MinGuests = flags.ServiceCharge.MinGuests
+		case "tax-mode":
+			cfg.Tax.Mode = flags.Tax.Mode
+		case "inventory-deduct-on":
+			cfg.Inventory.DeductOn = flags.Inventory.DeductOn
+		case "inventory-restock":
+			cfg.Inventory.Restock = flags.Inventory.Restock
+		case "waitlist-turn-time-lookback":
+			cfg.Waitlist.TurnTimeLookback = flags.Waitlist.TurnTimeLookback
+		case "waitlist-default-turn-time":
+			cfg.Waitlist.DefaultTurnTime = flags.Waitlist.DefaultTurnTime
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("cannot open config file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && err != io.EOF {
+		return fmt.Errorf("cannot parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (c *Config) loadEnv(getenv func(string) string) error {
+	stringFields := map[string]*string{
+		"ADDR":                &c.HTTP.Addr,
+		"STORAGE":             &c.Storage.Backend,
+		"DSN":                 &c.Storage.DSN,
+		"LOG_LEVEL":           &c.Log.Level,
+		"LOG_OUTPUT":          &c.Log.Output,
+		"TAX_MODE":            &c.Tax.Mode,
+		"INVENTORY_DEDUCT_ON": &c.Inventory.DeductOn,
+		"INVENTORY_RESTOCK":   &c.Inventory.Restock,
+	}
+	for name, field := range stringFields {
+		if v := getenv(envPrefix + name); v != "" {
+			*field = v
+		}
+	}
+
+	durationFields := map[string]*time.Duration{
+		"READ_TIMEOUT":                &c.HTTP.ReadTimeout,
+		"WRITE_TIMEOUT":               &c.HTTP.WriteTimeout,
+		"IDLE_TIMEOUT":                &c.HTTP.IdleTimeout,
+		"SHUTDOWN_TIMEOUT":            &c.HTTP.ShutdownTimeout,
+		"RETRY_BACKOFF":               &c.Retry.Backoff,
+		"WAITLIST_TURN_TIME_LOOKBACK": &c.Waitlist.TurnTimeLookback,
+		"WAITLIST_DEFAULT_TURN_TIME":  &c.Waitlist.DefaultTurnTime,
+	}
+	for name, field := range durationFields {
+		v := getenv(envPrefix + name)
+		if v == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s%s: %w", envPrefix, name, err)
+		}
+		*field = d
+	}
+
+	intFields := map[string]*int{
+		"RETRY_ATTEMPTS":            &c.Retry.Attempts,
+		"SERVICE_CHARGE_MIN_GUESTS": &c.ServiceCharge.MinGuests,
+	}
+	for name, field := range intFields {
+		v := getenv(envPrefix + name)
+		if v == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s%s: %w", envPrefix, name, err)
+		}
+		*field = n
+	}
+
+	floatFields := map[string]*float64{
+		"SERVICE_CHARGE_PERCENT": &c.ServiceCharge.Percent,
+	}
+	for name, field := range floatFields {
+		v := getenv(envPrefix + name)
+		if v == "" {
+			continue
+		}
+
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s%s: %w", envPrefix, name, err)
+		}
+		*field = f
+	}
+
+	return nil
+}
+
+// Validate reports every invalid setting of the configuration at once.
+func (c Config) Validate() error {
+	var errs []error
+
+	if c.HTTP.Addr == "" {
+		errs = append(errs, errors.New("http.addr must not be empty"))
+	}
+
+	timeouts := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"http.read_timeout", c.HTTP.ReadTimeout},
+		{"http.write_timeout", c.HTTP.WriteTimeout},
+		{"http.idle_timeout", c.HTTP.IdleTimeout},
+		{"http.shutdown_timeout", c.HTTP.ShutdownTimeout},
+		{"retry.backoff", c.Retry.Backoff},
+	}
+	for _, timeout := range timeouts {
+		if timeout.value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %s", timeout.name, timeout.value))
+		}
+	}
+
+	switch c.Storage.Backend {
+	case StorageSQLite:
+		if c.Storage.DSN == "" {
+			errs = append(errs, errors.New("storage.dsn must not be empty with the sqlite backend"))
+		}
+	case StorageInMem:
+	default:
+		errs = append(errs, fmt.Errorf("storage.backend must be %q or %q, got %q", StorageSQLite, StorageInMem, c.Storage.Backend))
+	}
+
+	if _, err := log.ParseLevel(c.Log.Level); err != nil {
+		errs = append(errs, fmt.Errorf("log.level: %w", err))
+	}
+
+	if strings.TrimSpace(c.Log.Output) == "" {
+		errs = append(errs, errors.New("log.output must not be empty"))
+	}
+
+	if c.Retry.Attempts < 1 {
+		errs = append(errs, fmt.Errorf("retry.attempts must be at least 1, got %d", c.Retry.Attempts))
+	}
+
+	if c.ServiceCharge.Percent < 0 || c.ServiceCharge.Percent > 100 {
+		errs = append(errs, fmt.Errorf("service_charge.percent must be between 0 and 100, got %v", c.ServiceCharge.Percent))
+	}
+
+	if c.ServiceCharge.MinGuests < 0 {
+		errs = append(errs, fmt.Errorf("service_charge.min_guests must not be negative, got %d", c.ServiceCharge.MinGuests))
+	}
+
+	if c.Tax.Mode != TaxModeInclusive && c.Tax.Mode != TaxModeExclusive {
+		errs = append(errs, fmt.Errorf("tax.mode must be %q or %q, got %q", TaxModeInclusive, TaxModeExclusive, c.Tax.Mode))
+	}
+
+	for category, rate := range c.Tax.Categories {
+		if category == "" {
+			errs = append(errs, errors.New("tax.categories must not have an empty name"))
+		}
+
+		if rate < 0 || rate > 100 {
+			errs = append(errs, fmt.Errorf("tax.categories.%s must be between 0 and 100, got %v", category, rate))
+		}
+	}
+
+	if c.Inventory.DeductOn != DeductOnOrder && c.Inventory.DeductOn != DeductOnPreparation {
+		errs = append(errs, fmt.Errorf("inventory.deduct_on must be %q or %q, got %q", DeductOnOrder, DeductOnPreparation, c.Inventory.DeductOn))
+	}
+
+	if c.Inventory.Restock != RestockNever && c.Inventory.Restock != RestockUnstarted && c.Inventory.Restock != RestockAlways {
+		errs = append(errs, fmt.Errorf("inventory.restock must be %q, %q or %q, got %q", RestockNever, RestockUnstarted, RestockAlways, c.Inventory.Restock))
+	}
+
+	if c.Waitlist.TurnTimeLookback <= 0 {
+		errs = append(errs, fmt.Errorf("waitlist.turn_time_lookback must be positive, got %s", c.Waitlist.TurnTimeLookback))
+	}
+
+	if c.Waitlist.DefaultTurnTime <= 0 {
+		errs = append(errs, fmt.Errorf("waitlist.default_turn_time must be positive, got %s", c.Waitlist.DefaultTurnTime))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package config_test
+
+import (
+	"order_manager/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func env(vars map[string]string) func(string) string {
+	return func(key string) string {
+		return vars[key]
+	}
+}
+
+func MustWriteConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(path, []byte(content), 0600)
+	require.NoError(t, err)
+
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := config.Load(nil, env(nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, config.Default(), cfg)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := MustWriteConfigFile(t, `
+http:
+  addr: ":9000"
+  read_timeout: 1s
+storage:
+  backend: sqlite
+  dsn: ./file.db
+log:
+  level: debug
+`)
+
+	t.Run("file", func(t *testing.T) {
+		cfg, err := config.Load([]string{"-config", path}, env(nil))
+
+		require.NoError(t, err)
+		assert.Equal(t, ":9000", cfg.HTTP.Addr)
+		assert.Equal(t, time.Second, cfg.HTTP.ReadTimeout)
+		assert.Equal(t, config.Default().HTTP.WriteTimeout, cfg.HTTP.WriteTimeout)
+		assert.Equal(t, "./file.db", cfg.Storage.DSN)
+		assert.Equal(t, "debug", cfg.Log.Level)
+	})
+
+	t.Run("environment over file", func(t *testing.T) {
+		cfg, err := config.Load(nil, env(map[string]string{
+			"ORDER_MANAGER_CONFIG":                     path,
+			"ORDER_MANAGER_ADDR":                       ":9001",
+			"ORDER_MANAGER_READ_TIMEOUT":               "2s",
+			"ORDER_MANAGER_RETRY_ATTEMPTS":             "5",
+			"ORDER_MANAGER_SERVICE_CHARGE_PERCENT":     "12.5",
+			"ORDER_MANAGER_WAITLIST_DEFAULT_TURN_TIME": "75m",
+		}))
+
+		require.NoError(t, err)
+		assert.Equal(t, ":9001", cfg.HTTP.Addr)
+		assert.Equal(t, 2*time.Second, cfg.HTTP.ReadTimeout)
+		assert.Equal(t, 5, cfg.Retry.Attempts)
+		assert.Equal(t, 12.5, cfg.ServiceCharge.Percent)
+		assert.Equal(t, 75*time.Minute, cfg.Waitlist.DefaultTurnTime)
+		assert.Equal(t, "./file.db", cfg.Storage.DSN)
+	})
+
+	t.Run("flags over environment", func(t *testing.T) {
+		cfg, err := config.Load(
+			[]string{"-config", path, "-addr", ":9002", "-storage", "inmem", "-log-level", "error", "-inventory-deduct-on", "preparation"},
+			env(map[string]string{"ORDER_MANAGER_ADDR": ":9001", "ORDER_MANAGER_LOG_LEVEL": "warning", "ORDER_MANAGER_INVENTORY_DEDUCT_ON": "order"}),
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, config.DeductOnPreparation, cfg.Inventory.DeductOn)
+		assert.Equal(t, ":9002", cfg.HTTP.Addr)
+		assert.Equal(t, config.StorageInMem, cfg.Storage.Backend)
+		assert.Equal(t, "error", cfg.Log.Level)
+		assert.Equal(t, time.Second, cfg.HTTP.ReadTimeout)
+	})
+}
+
+func TestLoadFailures(t *testing.T) {
+	tt := []struct {
+		testName string
+		args     []string
+		env      map[string]string
+		file     string
+	}{
+		{testName: "unknown flag", args: []string{"-unknown"}},
+		{testName: "missing config file", args: []string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}},
+		{testName: "unknown file key", file: "http:\n  port: 8080\n"},
+		{testName: "malformed file", file: "http: [\n"},
+		{testName: "invalid environment duration", env: map[string]string{"ORDER_MANAGER_IDLE_TIMEOUT": "soon"}},
+		{testName: "empty address", args: []string{"-addr", ""}},
+		{testName: "unknown backend", args: []string{"-storage", "postgres"}},
+		{testName: "empty sqlite dsn", args: []string{"-dsn", ""}},
+		{testName: "unknown log level", args: []string{"-log-level", "verbose"}},
+		{testName: "negative timeout", args: []string{"-write-timeout", "-1s"}},
+		{testName: "invalid environment retry attempts", env: map[string]string{"ORDER_MANAGER_RETRY_ATTEMPTS": "many"}},
+		{testName: "no retry attempt", args: []string{"-retry-attempts", "0"}},
+		{testName: "invalid environment service charge", env: map[string]string{"ORDER_MANAGER_SERVICE_CHARGE_PERCENT": "twelve"}},
+		{testName: "service charge above 100 percent", args: []string{"-service-charge-percent", "120"}},
+		{testName: "negative service charge guests", args: []string{"-service-charge-min-guests", "-1"}},
+		{testName: "unknown tax mode", args: []string{"-tax-mode", "vat"}},
+		{testName: "tax rate above 100 percent", file: "tax:\n  categories:\n    food: 120\n"},
+		{testName: "unknown stock deduction", args: []string{"-inventory-deduct-on", "service"}},
+		{testName: "unknown restock policy", env: map[string]string{"ORDER_MANAGER_INVENTORY_RESTOCK": "sometimes"}},
+		{testName: "no turn time lookback", args: []string{"-waitlist-turn-time-lookback", "0s"}},
+		{testName: "negative default turn time", file: "waitlist:\n  default_turn_time: -1h\n"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			args := tc.args
+			if tc.file != "" {
+				args = append(args, "-config", MustWriteConfigFile(t, tc.file))
+			}
+
+			_, err := config.Load(args, env(tc.env))
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestValidateReportsAllErrors(t *testing.T) {
+	cfg := config.Default()
+	cfg.HTTP.Addr = ""
+	cfg.Storage.Backend = "postgres"
+
+	err := cfg.Validate()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "http.addr")
+	assert.Contains(t, err.Error(), "storage.backend")
+}
@@ -2,7 +2,9 @@ package domain
 
 import (
 	"context"
+	"math"
 	"order_manager/internal/id"
+	"slices"
 )
 
 type BillStatus string
@@ -11,23 +13,77 @@ const (
 	BillStatusPending BillStatus = "pending"
 	BillPartiallyPaid BillStatus = "partially_paid"
 	BillStatusPaid    BillStatus = "paid"
+	// BillStatusSplit is the status of a bill replaced by the sub-bills it was split into.
+	BillStatusSplit BillStatus = "split"
+	// BillStatusPartiallyRefunded is the status of a paid bill of which part was refunded.
+	BillStatusPartiallyRefunded BillStatus = "partially_refunded"
+	// BillStatusRefunded is the status of a paid bill that was refunded in full.
+	BillStatusRefunded BillStatus = "refunded"
 )
 
 func (s BillStatus) IsValid() bool {
-	return s == BillStatusPending || s == BillPartiallyPaid || s == BillStatusPaid
+	return s == BillStatusPending || s == BillPartiallyPaid || s == BillStatusPaid || s == BillStatusSplit || s == BillStatusPartiallyRefunded || s == BillStatusRefunded
 }
 
 type Bill struct {
-	ID          id.ID
-	TableID     id.ID
-	Items       []MenuItem
-	Status      BillStatus
-	TotalAmount int
-	Paid        int
+	ID      id.ID
+	TableID id.ID
+	// ParentID is the bill this bill was split from, nil for the bill of a whole table.
+	ParentID id.ID
+	Items    []BillItem
+	Status   BillStatus
+	// TotalAmount is the amount due, the discounted items, the taxes excluded from their price and the service charge.
+	TotalAmount   int
+	ServiceCharge int
+	// ServiceChargeRate is the percent of the subtotal charged as service charge.
+	ServiceChargeRate float64
+	// TaxExclusive is set when the taxes are charged on top of the item amounts.
+	TaxExclusive bool
+	// Taxes break the discounted item amounts down per tax rate.
+	Taxes []TaxLine
+	// Discounts are the lines of the promotions and manual discounts taken off the items.
+	Discounts []Discount
+	// Payments is the ledger of the bill, the amount paid, the tips and the status of the bill derive from it.
+	Payments []Payment
+	// Version is incremented on every save, a save based on an outdated version fails with ESTALE.
+	Version int
+}
+
+// BillItem is a preparation charged on a bill.
+type BillItem struct {
+	PreparationID id.ID
+	MenuItem      MenuItem
+	Seat          int
+	// Amount is the price of the preparation, modifiers included.
+	Amount int
+	// TaxRate is the rate in percent of the tax category of the item when the bill was generated.
+	TaxRate float64
+	// Discount is the part of the amount taken off by the discounts of the bill.
+	Discount int
+}
+
+func (i BillItem) IsValid() bool {
+	return i.PreparationID != id.NilID() && i.MenuItem.IsValid() && i.Seat >= 0 && i.Amount >= 0 && i.TaxRate >= 0 && i.Discount >= 0 && i.Discount <= i.Amount
+}
+
+// DiscountedAmount returns the amount charged for the item.
+func (i BillItem) DiscountedAmount() int {
+	return i.Amount - i.Discount
+}
+
+// BillLine groups the items of a bill that are the same menu item charged the same amount.
+type BillLine struct {
+	MenuItem MenuItem
+	Quantity int
+	// UnitAmount is the amount of each item of the line, modifiers included.
+	UnitAmount int
+	// Amount is the line total before Discount is taken off.
+	Amount   int
+	Discount int
 }
 
 func (b Bill) IsValid() bool {
-	isValid := b.ID != id.NilID() && b.TableID != id.NilID() && b.Items != nil && b.Status.IsValid() && b.TotalAmount >= 0 && b.Paid >= 0
+	isValid := b.ID != id.NilID() && b.TableID != id.NilID() && b.ParentID != b.ID && b.Items != nil && b.Status.IsValid() && b.TotalAmount >= 0 && b.ServiceCharge >= 0 && b.ServiceCharge <= b.TotalAmount && b.ServiceChargeRate >= 0 && b.Paid()+b.Refunded() <= b.TotalAmount && b.Version >= 0
 
 	for _, item := range b.Items {
 		if !item.IsValid() {
@@ -35,64 +91,948 @@ func (b Bill) IsValid() bool {
 		}
 	}
 
+	for _, line := range b.Taxes {
+		if !line.IsValid() {
+			return false
+		}
+	}
+
+	for _, discount := range b.Discounts {
+		if !discount.IsValid() {
+			return false
+		}
+	}
+
+	for _, payment := range b.Payments {
+		if !payment.IsValid() {
+			return false
+		}
+	}
+
 	return isValid
 }
 
+// RemainingAmount returns the amount still due on the bill, the amounts refunded are no longer due.
+func (b Bill) RemainingAmount() int {
+	return b.TotalAmount - b.Paid() - b.Refunded()
+}
+
+// Paid returns the amount settled by the payments of the bill net of the refunds, tips excluded.
+func (b Bill) Paid() int {
+	paid := 0
+	for _, payment := range b.Payments {
+		paid += payment.Amount
+	}
+
+	return paid
+}
+
+// Tips returns the tips paid on top of the amount due net of the refunds, which are not part of the sales.
+func (b Bill) Tips() int {
+	tips := 0
+	for _, payment := range b.Payments {
+		tips += payment.Tip
+	}
+
+	return tips
+}
+
+// Refunded returns the amount given back by the refunds of the bill, tips excluded.
+func (b Bill) Refunded() int {
+	refunded := 0
+	for _, payment := range b.Payments {
+		if payment.IsRefund() {
+			refunded -= payment.Amount
+		}
+	}
+
+	return refunded
+}
+
+// Cash returns the cash the payments and the refunds of the bill leave in the drawer, tips included.
+func (b Bill) Cash() int {
+	cash := 0
+	for _, payment := range b.Payments {
+		cash += payment.cash()
+	}
+
+	return cash
+}
+
+// settle derives the status of a bill that was not split from its ledger.
+func (b *Bill) settle() {
+	paid := b.Paid()
+	switch refunded := b.Refunded(); {
+	case refunded > 0 && paid == 0:
+		b.Status = BillStatusRefunded
+	case refunded > 0:
+		b.Status = BillStatusPartiallyRefunded
+	case paid == 0:
+		b.Status = BillStatusPending
+	case paid < b.TotalAmount:
+		b.Status = BillPartiallyPaid
+	default:
+		b.Status = BillStatusPaid
+	}
+}
+
+// Lines returns the items of the bill grouped by menu item and amount, in the order they were first charged.
+func (b Bill) Lines() []BillLine {
+	lines := make([]BillLine, 0, len(b.Items))
+	for _, item := range b.Items {
+		idx := slices.IndexFunc(lines, func(line BillLine) bool {
+			return line.MenuItem.ID == item.MenuItem.ID && line.MenuItem.Name == item.MenuItem.Name && line.UnitAmount == item.Amount
+		})
+		if idx == -1 {
+			lines = append(lines, BillLine{MenuItem: item.MenuItem, UnitAmount: item.Amount})
+			idx = len(lines) - 1
+		}
+
+		lines[idx].Quantity++
+		lines[idx].Amount += item.Amount
+		lines[idx].Discount += item.Discount
+	}
+
+	return lines
+}
+
+// Subtotal returns the amount of the items of the bill as priced on the menu less their discounts,
+// before the service charge and the taxes excluded from the prices.
+func (b Bill) Subtotal() int {
+	if b.TaxExclusive {
+		return b.TotalAmount - b.ServiceCharge - b.Tax()
+	}
+
+	return b.TotalAmount - b.ServiceCharge
+}
+
+// Tax returns the taxes charged on the items of the bill, whether included in their price or not.
+func (b Bill) Tax() int {
+	tax := 0
+	for _, line := range b.Taxes {
+		tax += line.Tax
+	}
+
+	return tax
+}
+
+// Discount returns the amount of the discounts of the bill.
+func (b Bill) Discount() int {
+	discount := 0
+	for _, line := range b.Discounts {
+		discount += line.Amount
+	}
+
+	return discount
+}
+
+// price computes the taxes, the service charge and the total amount of the bill from its discounted items.
+// The service charge is computed on the items and is not taxed.
+func (b *Bill) price() {
+	subtotal := 0
+	for _, item := range b.Items {
+		subtotal += item.DiscountedAmount()
+	}
+
+	b.ServiceCharge = int(math.Round(float64(subtotal) * b.ServiceChargeRate / 100))
+	b.Taxes = computeTaxes(b.TaxExclusive, b.Items)
+	b.TotalAmount = subtotal + b.ServiceCharge
+	if b.TaxExclusive {
+		b.TotalAmount += b.Tax()
+	}
+}
+
+// ServiceChargePolicy controls the service charge added to the bills of large parties.
+type ServiceChargePolicy struct {
+	// Percent of the subtotal charged, 0 disables the service charge.
+	Percent float64
+	// MinGuests is the number of guests from which the service charge applies.
+	MinGuests int
+}
+
+// rate returns the percent of the subtotal charged to a party of guests.
+func (p ServiceChargePolicy) rate(guests int) float64 {
+	if p.Percent <= 0 || guests < p.MinGuests {
+		return 0
+	}
+
+	return p.Percent
+}
+
+// SalesReport separates the sales of bills from their taxes and from the gratuities paid on top of them.
+type SalesReport struct {
+	// Sales are the item amounts net of discounts and taxes, before the refunds.
+	Sales int
+	// Discounts were taken off the item amounts before the sales.
+	Discounts int
+	// Taxes are the taxes charged on the bills, before the refunds.
+	Taxes int
+	// ServiceCharges are the service charges of the bills, before the refunds.
+	ServiceCharges int
+	// Tips are net of the tips refunded.
+	Tips int
+	// Refunds are the amounts given back on the bills, tips excluded.
+	Refunds int
+	// Cash is the cash expected in the drawer for the bills, the cash payments and their tips less the cash refunded.
+	Cash int
+}
+
+// Gratuities returns the service charges and the tips of the report.
+func (r SalesReport) Gratuities() int {
+	return r.ServiceCharges + r.Tips
+}
+
 type BillRepository interface {
+	// Save persists the bill if the stored version is the one preceding bill.Version,
+	// otherwise it fails with ESTALE. A bill that was never saved is stored as is,
+	// unless it is not a sub-bill and the table already has such a bill, then it fails with ECONFLICT.
 	Save(ctx context.Context, bill Bill) error
+	// SaveAll persists the bills atomically, each one following the version rule of Save.
+	SaveAll(ctx context.Context, bills []Bill) error
 	FindByID(ctx context.Context, id id.ID) (Bill, error)
+	FindByTableID(ctx context.Context, tableID id.ID) ([]Bill, error)
 }
 
 type BillService struct {
-	repo BillRepository
+	repo          BillRepository
+	events        EventPublisher
+	serviceCharge ServiceChargePolicy
+	tax           TaxPolicy
+	promotions    PromotionRepository
+	clock         Clock
 }
 
-func NewBillService(repo BillRepository) *BillService {
-	return &BillService{repo: repo}
+func NewBillService(repo BillRepository, events EventPublisher) *BillService {
+	return &BillService{repo: repo, events: events, clock: SystemClock{}}
 }
 
+// WithPromotions makes the service apply the active promotions of the repository to the bills it generates.
+func (s *BillService) WithPromotions(promotions PromotionRepository) *BillService {
+	s.promotions = promotions
+	return s
+}
+
+// WithClock replaces the clock telling the service which promotions are in their time window.
+func (s *BillService) WithClock(clock Clock) *BillService {
+	s.clock = clock
+	return s
+}
+
+// WithTaxPolicy makes the service charge the taxes of the policy on the bills it generates.
+func (s *BillService) WithTaxPolicy(policy TaxPolicy) *BillService {
+	s.tax = policy
+	return s
+}
+
+// WithServiceCharge makes the service add a service charge to the bills it generates
+// for the tables seating enough guests.
+func (s *BillService) WithServiceCharge(policy ServiceChargePolicy) *BillService {
+	s.serviceCharge = policy
+	return s
+}
+
+// save bumps the bill version and saves it.
+// Possible errors:
+// - ESTALE if the bill was modified since it was read.
+func (s *BillService) save(ctx context.Context, bill *Bill) error {
+	bill.Version++
+	return s.repo.Save(ctx, *bill)
+}
+
+// GenerateBill charges the preparations of a closed table that were not aborted.
+// The active promotions are applied to the items, each discount recorded as a line of the bill,
+// before the taxes and the service charge are computed on the discounted amounts.
+// Possible errors:
+// - EINVALID if the table is not closed.
+// - ECONFLICT if a bill was already generated for the table.
+// - Any error returned by the repositories when fetching the promotions or saving the bill.
 func (s *BillService) GenerateBill(ctx context.Context, table Table) (Bill, error) {
 	if table.Status != TableStatusClosed {
 		return Bill{}, Errorf(EINVALID, "table with id %s is not closed", table.ID)
 	}
 
+	bills, err := s.repo.FindByTableID(ctx, table.ID)
+	if err != nil {
+		return Bill{}, err
+	}
+	for _, bill := range bills {
+		if bill.ParentID == id.NilID() {
+			return Bill{}, Errorf(ECONFLICT, "a bill was already generated for table %s", table.ID)
+		}
+	}
+
+	promotions := make([]Promotion, 0)
+	if s.promotions != nil {
+		var err error
+		if promotions, err = s.promotions.FindAll(ctx); err != nil {
+			return Bill{}, err
+		}
+	}
+
 	bill := Bill{
-		ID:      id.New(),
-		TableID: table.ID,
-		Status:  BillStatusPending,
-		Items:   make([]MenuItem, 0),
+		ID:                id.New(),
+		TableID:           table.ID,
+		Status:            BillStatusPending,
+		Items:             make([]BillItem, 0),
+		Payments:          make([]Payment, 0),
+		TaxExclusive:      s.tax.Exclusive,
+		ServiceChargeRate: s.serviceCharge.rate(table.GuestCount),
 	}
 
 	for _, order := range table.Orders {
 		for _, preparation := range order.Preparations {
-			bill.Items = append(bill.Items, preparation.MenuItem)
-			bill.TotalAmount += preparation.MenuItem.Price
+			if preparation.Status == PreparationStatusAborted {
+				continue
+			}
+
+			bill.Items = append(bill.Items, BillItem{
+				PreparationID: preparation.ID,
+				MenuItem:      preparation.MenuItem,
+				Seat:          preparation.Seat,
+				Amount:        preparation.Price(),
+				TaxRate:       s.tax.Rates[preparation.MenuItem.TaxCategory],
+			})
+		}
+	}
+
+	bill.Discounts = applyPromotions(bill.Items, promotions, s.clock.Now())
+	bill.price()
+
+	if err := s.save(ctx, &bill); err != nil {
+		return bill, err
+	}
+
+	publish(ctx, s.events, BillGenerated{Bill: bill})
+
+	return bill, nil
+}
+
+// FindBill returns a bill by its ID.
+// Possible errors:
+// - ENOTFOUND if the bill could not be found.
+func (s *BillService) FindBill(ctx context.Context, billID id.ID) (Bill, error) {
+	return s.repo.FindByID(ctx, billID)
+}
+
+// FindTableBills returns all bills generated for a table.
+// Possible errors:
+// - Any error returned by the repository when fetching the bills.
+func (s *BillService) FindTableBills(ctx context.Context, tableID id.ID) ([]Bill, error) {
+	return s.repo.FindByTableID(ctx, tableID)
+}
+
+// IsTableSettled reports whether all the charges of a table are paid,
+// that is the table has bills and every one of them that was not split is paid.
+// Possible errors:
+// - Any error returned by the repository when fetching the bills.
+func (s *BillService) IsTableSettled(ctx context.Context, tableID id.ID) (bool, error) {
+	bills, err := s.repo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return false, err
+	}
+
+	return isSettled(bills), nil
+}
+
+// TableSalesReport sums the sales, discounts, taxes, service charges, tips, refunds and cash of the bills of a table.
+// Split bills are left out, their amounts are reported by their sub-bills.
+// The sales, taxes and service charges are gross of the refunds, which are reported apart
+// since a refund of a whole payment cannot be broken down per tax rate.
+// Possible errors:
+// - Any error returned by the repository when fetching the bills.
+func (s *BillService) TableSalesReport(ctx context.Context, tableID id.ID) (SalesReport, error) {
+	bills, err := s.repo.FindByTableID(ctx, tableID)
+	if err != nil {
+		return SalesReport{}, err
+	}
+
+	var report SalesReport
+	for _, bill := range bills {
+		if bill.Status == BillStatusSplit {
+			continue
+		}
+
+		report.Sales += bill.TotalAmount - bill.ServiceCharge - bill.Tax()
+		report.Discounts += bill.Discount()
+		report.Taxes += bill.Tax()
+		report.ServiceCharges += bill.ServiceCharge
+		report.Tips += bill.Tips()
+		report.Refunds += bill.Refunded()
+		report.Cash += bill.Cash()
+	}
+
+	return report, nil
+}
+
+func isSettled(bills []Bill) bool {
+	if len(bills) == 0 {
+		return false
+	}
+
+	for _, bill := range bills {
+		if bill.Status == BillStatusPending || bill.Status == BillPartiallyPaid {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SplitBillByItems splits a bill into one sub-bill per group of preparation IDs.
+// Every item of the bill must be assigned to exactly one group.
+// Possible errors:
+// - ENOTFOUND if the bill could not be found.
+// - ECONFLICT if the bill is already split or has payments.
+// - EINVALID if there are less than two groups or a group is empty.
+// - EINVALID if a preparation is not on the bill, assigned twice or not assigned.
+// - ESTALE if the bill was modified concurrently.
+// - Any error returned by the repository when saving the bills.
+func (s *BillService) SplitBillByItems(ctx context.Context, billID id.ID, groups [][]id.ID) ([]Bill, error) {
+	bill, err := s.findPendingBill(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(groups) < 2 {
+		return nil, Errorf(EINVALID, "a bill must be split in at least two groups")
+	}
+
+	assigned := make(map[id.ID]int)
+	for i, group := range groups {
+		if len(group) == 0 {
+			return nil, Errorf(EINVALID, "group %d has no item", i+1)
+		}
+
+		for _, preparationID := range group {
+			if !slices.ContainsFunc(bill.Items, func(item BillItem) bool { return item.PreparationID == preparationID }) {
+				return nil, Errorf(EINVALID, "preparation %s is not on bill %s", preparationID, billID)
+			}
+
+			if _, ok := assigned[preparationID]; ok {
+				return nil, Errorf(EINVALID, "preparation %s is assigned twice", preparationID)
+			}
+			assigned[preparationID] = i
+		}
+	}
+
+	parts := make([]Bill, len(groups))
+	for i := range parts {
+		parts[i] = newSubBill(bill)
+	}
+
+	for _, item := range bill.Items {
+		i, ok := assigned[item.PreparationID]
+		if !ok {
+			return nil, Errorf(EINVALID, "preparation %s is not assigned", item.PreparationID)
+		}
+
+		parts[i].addItem(item)
+	}
+	shareServiceCharge(bill, parts)
+	shareTaxes(bill, parts, itemsAtRate)
+	shareDiscounts(bill, parts)
+
+	if err := s.split(ctx, bill, parts); err != nil {
+		return nil, err
+	}
+
+	return parts, nil
+}
+
+// SplitBillBySeat splits a bill into one sub-bill per seat, in ascending seat order.
+// Items shared by the table are charged on a last sub-bill of their own.
+// Possible errors:
+// - ENOTFOUND if the bill could not be found.
+// - ECONFLICT if the bill is already split or has payments.
+// - EINVALID if all the items of the bill are for the same seat.
+// - ESTALE if the bill was modified concurrently.
+// - Any error returned by the repository when saving the bills.
+func (s *BillService) SplitBillBySeat(ctx context.Context, billID id.ID) ([]Bill, error) {
+	bill, err := s.findPendingBill(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+
+	seats := make([]int, 0)
+	for _, item := range bill.Items {
+		if !slices.Contains(seats, item.Seat) {
+			seats = append(seats, item.Seat)
+		}
+	}
+
+	if len(seats) < 2 {
+		return nil, Errorf(EINVALID, "all the items of bill %s are for the same seat", billID)
+	}
+
+	// The shared items, on seat 0, are sorted last.
+	slices.SortFunc(seats, func(a, b int) int {
+		switch {
+		case a == b:
+			return 0
+		case a == 0:
+			return 1
+		case b == 0:
+			return -1
+		default:
+			return a - b
+		}
+	})
+
+	parts := make([]Bill, len(seats))
+	for i, seat := range seats {
+		parts[i] = newSubBill(bill)
+		for _, item := range bill.Items {
+			if item.Seat == seat {
+				parts[i].addItem(item)
+			}
 		}
 	}
+	shareServiceCharge(bill, parts)
+	shareTaxes(bill, parts, itemsAtRate)
+	shareDiscounts(bill, parts)
+
+	if err := s.split(ctx, bill, parts); err != nil {
+		return nil, err
+	}
 
-	return bill, s.repo.Save(ctx, bill)
+	return parts, nil
 }
 
-func (s *BillService) PayBill(ctx context.Context, billID id.ID, amount int) error {
+// SplitBillEqually splits the amount of a bill into equal shares.
+// When the amount cannot be divided evenly, the first shares are charged one more unit each
+// so that the shares sum to the amount of the bill. The shares carry no item,
+// the service charge, the taxes and the discounts are shared in proportion to the amount of every share.
+// Possible errors:
+// - ENOTFOUND if the bill could not be found.
+// - ECONFLICT if the bill is already split or has payments.
+// - EINVALID if there are less than two shares or more shares than units in the amount.
+// - ESTALE if the bill was modified concurrently.
+// - Any error returned by the repository when saving the bills.
+func (s *BillService) SplitBillEqually(ctx context.Context, billID id.ID, shares int) ([]Bill, error) {
+	bill, err := s.findPendingBill(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+
+	if shares < 2 {
+		return nil, Errorf(EINVALID, "a bill must be split in at least two shares")
+	}
+
+	if shares > bill.TotalAmount {
+		return nil, Errorf(EINVALID, "an amount of %d cannot be split in %d shares", bill.TotalAmount, shares)
+	}
+
+	weights := make([]int, shares)
+	for i := range weights {
+		weights[i] = 1
+	}
+	totals := distribute(bill.TotalAmount, weights)
+	serviceCharges := distribute(bill.ServiceCharge, totals)
+
+	parts := make([]Bill, shares)
+	amounts := make(map[id.ID]int, shares)
+	for i := range parts {
+		parts[i] = newSubBill(bill)
+		parts[i].ServiceCharge = serviceCharges[i]
+		amounts[parts[i].ID] = totals[i]
+	}
+	shareTaxes(bill, parts, func(part Bill, _ float64) int { return amounts[part.ID] })
+	// The taxes excluded from the prices are already part of the shared amount.
+	for i := range parts {
+		parts[i].TotalAmount = totals[i]
+	}
+	// The discount of every share is in proportion to its amount, the lines of the bill filling it in turn
+	// so that the lines of a share sum to its discount.
+	discounts := distribute(bill.Discount(), totals)
+	for _, line := range bill.Discounts {
+		for i, amount := range distribute(line.Amount, discounts) {
+			if amount > 0 {
+				parts[i].Discounts = append(parts[i].Discounts, Discount{PromotionID: line.PromotionID, Name: line.Name, Amount: amount, Reason: line.Reason})
+				discounts[i] -= amount
+			}
+		}
+	}
+
+	if err := s.split(ctx, bill, parts); err != nil {
+		return nil, err
+	}
+
+	return parts, nil
+}
+
+// findPendingBill returns a bill that has neither been split nor paid yet.
+func (s *BillService) findPendingBill(ctx context.Context, billID id.ID) (Bill, error) {
 	bill, err := s.repo.FindByID(ctx, billID)
 	if err != nil {
+		return Bill{}, err
+	}
+
+	if bill.Status == BillStatusSplit {
+		return Bill{}, Errorf(ECONFLICT, "bill with id %s is already split", billID)
+	}
+
+	if len(bill.Payments) > 0 || bill.Status != BillStatusPending {
+		return Bill{}, Errorf(ECONFLICT, "bill with id %s has payments", billID)
+	}
+
+	return bill, nil
+}
+
+// split replaces the bill with its parts.
+func (s *BillService) split(ctx context.Context, bill Bill, parts []Bill) error {
+	bill.Status = BillStatusSplit
+	bill.Version++
+	for i := range parts {
+		parts[i].Version++
+	}
+
+	if err := s.repo.SaveAll(ctx, append([]Bill{bill}, parts...)); err != nil {
 		return err
 	}
 
-	if bill.Status == BillStatusPaid {
-		return Errorf(EINVALID, "bill with id %s is already paid", billID)
+	publish(ctx, s.events, BillSplit{Bill: bill, Parts: parts})
+
+	return nil
+}
+
+func newSubBill(parent Bill) Bill {
+	return Bill{
+		ID:                id.New(),
+		TableID:           parent.TableID,
+		ParentID:          parent.ID,
+		Status:            BillStatusPending,
+		Items:             make([]BillItem, 0),
+		TaxExclusive:      parent.TaxExclusive,
+		Taxes:             make([]TaxLine, 0),
+		Discounts:         make([]Discount, 0),
+		Payments:          make([]Payment, 0),
+		ServiceChargeRate: parent.ServiceChargeRate,
+	}
+}
+
+func (b *Bill) addItem(item BillItem) {
+	b.Items = append(b.Items, item)
+	b.TotalAmount += item.DiscountedAmount()
+}
+
+// itemsAtRate returns the amount of the items of a part taxed at a rate.
+func itemsAtRate(part Bill, rate float64) int {
+	amount := 0
+	for _, item := range part.Items {
+		if item.TaxRate == rate {
+			amount += item.DiscountedAmount()
+		}
+	}
+
+	return amount
+}
+
+// shareServiceCharge charges the service charge of a bill on its parts, in proportion to their subtotal.
+func shareServiceCharge(bill Bill, parts []Bill) {
+	weights := make([]int, len(parts))
+	for i, part := range parts {
+		weights[i] = part.Subtotal()
+	}
+
+	for i, charge := range distribute(bill.ServiceCharge, weights) {
+		parts[i].ServiceCharge = charge
+		parts[i].TotalAmount += charge
+	}
+}
+
+// shareDiscounts records the discounts of a bill on its parts. The discounts of an item go with the item,
+// the discounts of the whole bill are shared in proportion to the part of them spread on the items of every part.
+func shareDiscounts(bill Bill, parts []Bill) {
+	weights := make([]int, len(parts))
+	for i, part := range parts {
+		for _, item := range part.Items {
+			weights[i] += item.Discount
+		}
+
+		for _, line := range bill.Discounts {
+			if line.PreparationID == id.NilID() {
+				continue
+			}
+
+			if slices.ContainsFunc(part.Items, func(item BillItem) bool { return item.PreparationID == line.PreparationID }) {
+				parts[i].Discounts = append(parts[i].Discounts, line)
+				weights[i] -= line.Amount
+			}
+		}
+	}
+
+	for _, line := range bill.Discounts {
+		if line.PreparationID != id.NilID() {
+			continue
+		}
+
+		for i, amount := range distribute(line.Amount, weights) {
+			if amount > 0 {
+				parts[i].Discounts = append(parts[i].Discounts, Discount{PromotionID: line.PromotionID, Name: line.Name, Amount: amount, Reason: line.Reason})
+			}
+		}
+	}
+}
+
+// distribute divides an amount in proportion to the weights, rounding down.
+// The units left by the rounding go one by one to the first positive weights,
+// so that the shares always sum to the amount.
+func distribute(amount int, weights []int) []int {
+	shares := make([]int, len(weights))
+
+	total := 0
+	for _, weight := range weights {
+		total += weight
+	}
+	if total == 0 {
+		return shares
+	}
+
+	left := amount
+	for i, weight := range weights {
+		shares[i] = amount * weight / total
+		left -= shares[i]
+	}
+
+	for i := 0; left > 0; i++ {
+		if weights[i] > 0 {
+			shares[i]++
+			left--
+		}
+	}
+
+	return shares
+}
+
+// DiscountBill grants a manual discount on a bill, either a percent or a fixed amount of its subtotal,
+// and prices the bill again. The discount is spread on the items in proportion to their discounted amount
+// and recorded as a line of the bill along with the reason it was granted.
+// Possible errors:
+// - EINVALID if the reason is empty.
+// - EINVALID if not exactly one of the percent, up to 100, and the amount is positive.
+// - ENOTFOUND if the bill could not be found.
+// - ECONFLICT if the bill is split or has payments.
+// - EINVALID if nothing is left to discount on the items of the bill.
+// - ESTALE if the bill was modified concurrently.
+// - Any error returned by the repository when saving the bill.
+func (s *BillService) DiscountBill(ctx context.Context, billID id.ID, percent float64, amount int, reason string) (Bill, error) {
+	if reason == "" {
+		return Bill{}, Errorf(EINVALID, "a manual discount requires a reason")
+	}
+
+	if (percent > 0) == (amount > 0) || percent < 0 || percent > 100 || amount < 0 {
+		return Bill{}, Errorf(EINVALID, "a manual discount is either a percent up to 100 or an amount")
+	}
+
+	bill, err := s.findPendingBill(ctx, billID)
+	if err != nil {
+		return Bill{}, err
+	}
+
+	weights := make([]int, len(bill.Items))
+	subtotal := 0
+	for i, item := range bill.Items {
+		weights[i] = item.DiscountedAmount()
+		subtotal += weights[i]
+	}
+
+	discount := Discount{Name: "Manual discount", Amount: min(amount, subtotal), Reason: reason}
+	if percent > 0 {
+		discount.Amount = int(math.Round(float64(subtotal) * percent / 100))
+	}
+
+	if discount.Amount == 0 {
+		return Bill{}, Errorf(EINVALID, "bill with id %s has nothing left to discount", billID)
+	}
+
+	for i, share := range distribute(discount.Amount, weights) {
+		bill.Items[i].Discount += share
+	}
+	bill.Discounts = append(bill.Discounts, discount)
+	bill.price()
+
+	if err := s.save(ctx, &bill); err != nil {
+		return Bill{}, err
+	}
+
+	publish(ctx, s.events, BillDiscounted{Bill: bill, Discount: discount})
+
+	return bill, nil
+}
+
+// PayBill records a payment on the ledger of a bill and updates its status.
+// The tip is paid on top of the amount and does not count towards the amount due.
+// For a cash payment, tendered is the cash handed over, 0 when it is exactly the amount and the tip,
+// and the rest is given back as change.
+// Possible errors:
+// - EINVALID if the tender type is unknown.
+// - EINVALID if the amount is not positive.
+// - EINVALID if the tip is negative.
+// - EINVALID if the cash tendered does not cover the amount and the tip.
+// - EINVALID if cash is tendered for a card or voucher payment.
+// - ENOTFOUND if the bill could not be found.
+// - ECONFLICT if the bill was split, its sub-bills are paid instead.
+// - ECONFLICT if the bill is already paid.
+// - ECONFLICT if the amount is more than the remaining amount.
+// - ESTALE if the bill was modified concurrently, payments are never retried
+// so that the payer can check the remaining amount again.
+// - Any error returned by the repository when saving the bill.
+func (s *BillService) PayBill(ctx context.Context, billID id.ID, tender TenderType, amount int, tip int, tendered int) (Payment, error) {
+	if !tender.IsValid() {
+		return Payment{}, Errorf(EINVALID, "unknown tender type %q", tender)
+	}
+
+	if amount <= 0 {
+		return Payment{}, Errorf(EINVALID, "amount must be positive")
+	}
+
+	if tip < 0 {
+		return Payment{}, Errorf(EINVALID, "tip must not be negative")
+	}
+
+	payment := Payment{ID: id.New(), Tender: tender, Amount: amount, Tip: tip, PaidAt: s.clock.Now()}
+	switch {
+	case tender != TenderCash && tendered != 0:
+		return Payment{}, Errorf(EINVALID, "only cash can be tendered")
+	case tender == TenderCash && tendered == 0:
+		payment.Tendered = amount + tip
+	case tender == TenderCash && tendered < amount+tip:
+		return Payment{}, Errorf(EINVALID, "cash tendered does not cover the amount and the tip")
+	case tender == TenderCash:
+		payment.Tendered = tendered
+		payment.Change = tendered - amount - tip
+	}
+
+	bill, err := s.repo.FindByID(ctx, billID)
+	if err != nil {
+		return Payment{}, err
+	}
+
+	if bill.Status == BillStatusSplit {
+		return Payment{}, Errorf(ECONFLICT, "bill with id %s is split, its sub-bills must be paid instead", billID)
+	}
+
+	if bill.Status == BillStatusPaid || bill.Status == BillStatusPartiallyRefunded || bill.Status == BillStatusRefunded {
+		return Payment{}, Errorf(ECONFLICT, "bill with id %s is already paid", billID)
+	}
+
+	if amount > bill.RemainingAmount() {
+		return Payment{}, Errorf(ECONFLICT, "amount paid is more than total amount")
+	}
+
+	bill.Payments = append(bill.Payments, payment)
+	bill.settle()
+
+	if err := s.save(ctx, &bill); err != nil {
+		return Payment{}, err
+	}
+
+	publish(ctx, s.events, PaymentReceived{Bill: bill, Payment: payment})
+	if bill.Status != BillStatusPaid {
+		return payment, nil
+	}
+
+	publish(ctx, s.events, BillPaid{Bill: bill})
+
+	// The payment went through, failing to check the other bills of the table only skips the event.
+	bills, err := s.repo.FindByTableID(ctx, bill.TableID)
+	if err == nil && isSettled(bills) {
+		publish(ctx, s.events, TableSettled{TableID: bill.TableID})
+	}
+
+	return payment, nil
+}
+
+// RefundPayment gives back a payment of a paid bill, in full or for one of its items, on the tender it was paid with.
+// The refund is recorded on the ledger of the bill as a negative entry referencing the payment.
+// A full refund gives back what is left of the payment and of its tip. An item refund gives back the part of
+// the total amount charged for the item, in proportion to its discounted amount, and no tip.
+// Every item is refunded at most once.
+// Possible errors:
+// - EINVALID if the reason is empty.
+// - ENOTFOUND if the bill could not be found, or the payment or the item is not on the bill.
+// - ECONFLICT if the bill is not paid.
+// - ECONFLICT if the payment or the item is already refunded.
+// - ECONFLICT if the item was charged more than what is left of the payment.
+// - ESTALE if the bill was modified concurrently.
+// - Any error returned by the repository when saving the bill.
+func (s *BillService) RefundPayment(ctx context.Context, billID id.ID, paymentID id.ID, preparationID id.ID, reason string) (Payment, error) {
+	if reason == "" {
+		return Payment{}, Errorf(EINVALID, "a refund requires a reason")
+	}
+
+	bill, err := s.repo.FindByID(ctx, billID)
+	if err != nil {
+		return Payment{}, err
+	}
+
+	if bill.Status != BillStatusPaid && bill.Status != BillStatusPartiallyRefunded {
+		return Payment{}, Errorf(ECONFLICT, "bill with id %s is not paid", billID)
+	}
+
+	i := slices.IndexFunc(bill.Payments, func(payment Payment) bool { return payment.ID == paymentID && !payment.IsRefund() })
+	if i < 0 {
+		return Payment{}, Errorf(ENOTFOUND, "payment with id %s not found on bill %s", paymentID, billID)
+	}
+
+	payment := bill.Payments[i]
+	amount, tip := payment.Amount, payment.Tip
+	for _, entry := range bill.Payments {
+		if entry.RefundOf == paymentID {
+			amount += entry.Amount
+			tip += entry.Tip
+		}
+	}
+
+	if amount+tip == 0 {
+		return Payment{}, Errorf(ECONFLICT, "payment with id %s is already refunded", paymentID)
+	}
+
+	refund := Payment{ID: id.New(), Tender: payment.Tender, Amount: -amount, Tip: -tip, RefundOf: paymentID, Reason: reason, PaidAt: s.clock.Now()}
+	if preparationID != id.NilID() {
+		charged, err := bill.charged(preparationID)
+		if err != nil {
+			return Payment{}, err
+		}
+
+		if charged > amount {
+			return Payment{}, Errorf(ECONFLICT, "item %s was charged more than what is left of payment %s", preparationID, paymentID)
+		}
+
+		refund.Amount, refund.Tip, refund.PreparationID = -charged, 0, preparationID
+	}
+
+	bill.Payments = append(bill.Payments, refund)
+	bill.settle()
+
+	if err := s.save(ctx, &bill); err != nil {
+		return Payment{}, err
+	}
+
+	publish(ctx, s.events, PaymentRefunded{Bill: bill, Refund: refund})
+
+	return refund, nil
+}
+
+// charged returns the part of the total amount of the bill charged for an item that was not refunded yet,
+// in proportion to the discounted amounts of the items.
+// Possible errors:
+// - ENOTFOUND if the item is not on the bill.
+// - ECONFLICT if the item is already refunded.
+func (b Bill) charged(preparationID id.ID) (int, error) {
+	i := slices.IndexFunc(b.Items, func(item BillItem) bool { return item.PreparationID == preparationID })
+	if i < 0 {
+		return 0, Errorf(ENOTFOUND, "preparation %s is not on bill %s", preparationID, b.ID)
 	}
 
-	if bill.Paid+amount > bill.TotalAmount {
-		return Errorf(EINVALID, "amount paid is more than total amount")
+	if slices.ContainsFunc(b.Payments, func(payment Payment) bool { return payment.PreparationID == preparationID }) {
+		return 0, Errorf(ECONFLICT, "preparation %s is already refunded", preparationID)
 	}
 
-	bill.Paid += amount
-	if bill.Paid == bill.TotalAmount {
-		bill.Status = BillStatusPaid
-	} else {
-		bill.Status = BillPartiallyPaid
+	weights := make([]int, len(b.Items))
+	for k, item := range b.Items {
+		weights[k] = item.DiscountedAmount()
 	}
 
-	return s.repo.Save(ctx, bill)
+	return distribute(b.TotalAmount, weights)[i], nil
 }
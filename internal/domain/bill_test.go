@@ -6,6 +6,7 @@ import (
 	"order_manager/internal/id"
 	"order_manager/internal/inmem"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,10 +25,9 @@ func TestBillIsValid(t *testing.T) {
 				bill: domain.Bill{
 					ID:          id.New(),
 					TableID:     id.New(),
-					Items:       []domain.MenuItem{{ID: id.New(), Name: "test", Price: 100}},
+					Items:       []domain.BillItem{{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}, Amount: 100}},
 					Status:      domain.BillStatusPending,
 					TotalAmount: 100,
-					Paid:        0,
 				},
 			},
 			{
@@ -35,10 +35,10 @@ func TestBillIsValid(t *testing.T) {
 				bill: domain.Bill{
 					ID:          id.New(),
 					TableID:     id.New(),
-					Items:       []domain.MenuItem{{ID: id.New(), Name: "test", Price: 100}},
+					Items:       []domain.BillItem{{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}, Amount: 100}},
 					Status:      domain.BillPartiallyPaid,
 					TotalAmount: 100,
-					Paid:        50,
+					Payments:    []domain.Payment{cardPayment(50)},
 				},
 			},
 			{
@@ -46,10 +46,10 @@ func TestBillIsValid(t *testing.T) {
 				bill: domain.Bill{
 					ID:          id.New(),
 					TableID:     id.New(),
-					Items:       []domain.MenuItem{{ID: id.New(), Name: "test", Price: 100}},
+					Items:       []domain.BillItem{{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}, Amount: 100}},
 					Status:      domain.BillStatusPaid,
 					TotalAmount: 100,
-					Paid:        100,
+					Payments:    []domain.Payment{cardPayment(100)},
 				},
 			},
 		}
@@ -74,10 +74,9 @@ func TestBillIsValid(t *testing.T) {
 				bill: domain.Bill{
 					ID:          id.NilID(),
 					TableID:     id.New(),
-					Items:       []domain.MenuItem{{ID: id.New(), Name: "test", Price: 100}},
+					Items:       []domain.BillItem{{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}, Amount: 100}},
 					Status:      domain.BillStatusPending,
 					TotalAmount: 100,
-					Paid:        0,
 				},
 			},
 			{
@@ -85,10 +84,9 @@ func TestBillIsValid(t *testing.T) {
 				bill: domain.Bill{
 					ID:          id.New(),
 					TableID:     id.NilID(),
-					Items:       []domain.MenuItem{{ID: id.New(), Name: "test", Price: 100}},
+					Items:       []domain.BillItem{{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}, Amount: 100}},
 					Status:      domain.BillStatusPending,
 					TotalAmount: 100,
-					Paid:        0,
 				},
 			},
 			{
@@ -96,10 +94,9 @@ func TestBillIsValid(t *testing.T) {
 				bill: domain.Bill{
 					ID:          id.New(),
 					TableID:     id.New(),
-					Items:       []domain.MenuItem{{ID: id.NilID(), Name: "test", Price: 100}},
+					Items:       []domain.BillItem{{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.NilID(), Name: "test", Price: 100}, Amount: 100}},
 					Status:      domain.BillStatusPending,
 					TotalAmount: 100,
-					Paid:        0,
 				},
 			},
 			{
@@ -107,10 +104,9 @@ func TestBillIsValid(t *testing.T) {
 				bill: domain.Bill{
 					ID:          id.New(),
 					TableID:     id.New(),
-					Items:       []domain.MenuItem{{ID: id.New(), Name: "test", Price: 100}},
+					Items:       []domain.BillItem{{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}, Amount: 100}},
 					Status:      "invalid",
 					TotalAmount: 100,
-					Paid:        0,
 				},
 			},
 			{
@@ -118,10 +114,9 @@ func TestBillIsValid(t *testing.T) {
 				bill: domain.Bill{
 					ID:          id.New(),
 					TableID:     id.New(),
-					Items:       []domain.MenuItem{{ID: id.New(), Name: "test", Price: 100}},
+					Items:       []domain.BillItem{{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}, Amount: 100}},
 					Status:      domain.BillStatusPending,
 					TotalAmount: -100,
-					Paid:        0,
 				},
 			},
 			{
@@ -129,10 +124,32 @@ func TestBillIsValid(t *testing.T) {
 				bill: domain.Bill{
 					ID:          id.New(),
 					TableID:     id.New(),
-					Items:       []domain.MenuItem{{ID: id.New(), Name: "test", Price: 100}},
+					Items:       []domain.BillItem{{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}, Amount: 100}},
 					Status:      domain.BillStatusPending,
 					TotalAmount: 100,
-					Paid:        -100,
+					Payments:    []domain.Payment{cardPayment(-100)},
+				},
+			},
+			{
+				testName: "service charge above total amount",
+				bill: domain.Bill{
+					ID:            id.New(),
+					TableID:       id.New(),
+					Items:         []domain.BillItem{{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}, Amount: 100}},
+					Status:        domain.BillStatusPending,
+					TotalAmount:   100,
+					ServiceCharge: 120,
+				},
+			},
+			{
+				testName: "negative tips",
+				bill: domain.Bill{
+					ID:          id.New(),
+					TableID:     id.New(),
+					Items:       []domain.BillItem{{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}, Amount: 100}},
+					Status:      domain.BillStatusPending,
+					TotalAmount: 100,
+					Payments:    []domain.Payment{{ID: id.New(), Tender: domain.TenderCard, Amount: 50, Tip: -10, PaidAt: time.Now()}},
 				},
 			},
 		}
@@ -146,9 +163,29 @@ func TestBillIsValid(t *testing.T) {
 	})
 }
 
+func TestBillLines(t *testing.T) {
+	beer := domain.MenuItem{ID: id.New(), Name: "beer", Price: 500}
+	burger := domain.MenuItem{ID: id.New(), Name: "burger", Price: 1200}
+	bill := domain.Bill{
+		Items: []domain.BillItem{
+			{PreparationID: id.New(), MenuItem: beer, Amount: 500},
+			{PreparationID: id.New(), MenuItem: burger, Amount: 1200},
+			{PreparationID: id.New(), MenuItem: beer, Seat: 2, Amount: 500, Discount: 500},
+			{PreparationID: id.New(), MenuItem: burger, Amount: 1350},
+			{PreparationID: id.New(), MenuItem: beer, Amount: 500},
+		},
+	}
+
+	assert.Equal(t, []domain.BillLine{
+		{MenuItem: beer, Quantity: 3, UnitAmount: 500, Amount: 1500, Discount: 500},
+		{MenuItem: burger, Quantity: 1, UnitAmount: 1200, Amount: 1200},
+		{MenuItem: burger, Quantity: 1, UnitAmount: 1350, Amount: 1350},
+	}, bill.Lines())
+}
+
 func TestCreateBill(t *testing.T) {
 	billRepo := inmem.NewBill()
-	billService := domain.NewBillService(billRepo)
+	billService := domain.NewBillService(billRepo, nil)
 
 	t.Run("Success", func(t *testing.T) {
 		t.Parallel()
@@ -165,13 +202,48 @@ func TestCreateBill(t *testing.T) {
 					Status: domain.TableStatusClosed,
 					Orders: []domain.Order{
 						{ID: id.New(), Preparations: []domain.Preparation{
-							{MenuItem: domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 100}},
-							{MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 150}},
+							{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 100}},
+							{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 150}},
 						}},
 					},
 				},
 				expectedAmount: 250,
 			},
+			{
+				testName: "aborted preparations are not charged",
+				table: domain.Table{
+					ID:     id.New(),
+					Status: domain.TableStatusClosed,
+					Orders: []domain.Order{
+						{ID: id.New(), Preparations: []domain.Preparation{
+							{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 100}, Status: domain.PreparationStatusServed},
+							{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 150}, Status: domain.PreparationStatusAborted},
+						}},
+					},
+				},
+				expectedAmount: 100,
+			},
+			{
+				testName: "modifiers are charged",
+				table: domain.Table{
+					ID:     id.New(),
+					Status: domain.TableStatusClosed,
+					Orders: []domain.Order{
+						{ID: id.New(), Preparations: []domain.Preparation{
+							{
+								ID:       id.New(),
+								MenuItem: domain.MenuItem{ID: id.New(), Name: "Burger", Price: 1000},
+								Modifiers: []domain.Modifier{
+									{GroupID: id.New(), GroupName: "Extras", OptionID: id.New(), OptionName: "Bacon", PriceDelta: 200},
+									{GroupID: id.New(), GroupName: "Extras", OptionID: id.New(), OptionName: "No onions", PriceDelta: 0},
+								},
+								Status: domain.PreparationStatusServed,
+							},
+						}},
+					},
+				},
+				expectedAmount: 1200,
+			},
 			{
 				testName: "valid table with no orders",
 				table: domain.Table{
@@ -210,8 +282,8 @@ func TestCreateBill(t *testing.T) {
 					Status: domain.TableStatusOpened,
 					Orders: []domain.Order{
 						{ID: id.New(), Preparations: []domain.Preparation{
-							{MenuItem: domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 100}},
-							{MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 150}},
+							{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 100}},
+							{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 150}},
 						}},
 					},
 				},
@@ -248,7 +320,7 @@ func TestCreateBill(t *testing.T) {
 
 func TestPayBill(t *testing.T) {
 	billRepo := inmem.NewBill()
-	billService := domain.NewBillService(billRepo)
+	billService := domain.NewBillService(billRepo, nil)
 
 	t.Run("Success", func(t *testing.T) {
 		t.Parallel()
@@ -264,9 +336,9 @@ func TestPayBill(t *testing.T) {
 					ID:      id.New(),
 					TableID: id.New(),
 					Status:  domain.BillStatusPending,
-					Items: []domain.MenuItem{
-						{ID: id.New(), Name: "Spaghetti", Price: 100},
-						{ID: id.New(), Name: "Pizza", Price: 150},
+					Items: []domain.BillItem{
+						{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 100}, Amount: 100},
+						{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 150}, Amount: 150},
 					},
 					TotalAmount: 250,
 				},
@@ -278,9 +350,9 @@ func TestPayBill(t *testing.T) {
 					ID:      id.New(),
 					TableID: id.New(),
 					Status:  domain.BillStatusPending,
-					Items: []domain.MenuItem{
-						{ID: id.New(), Name: "Spaghetti", Price: 100},
-						{ID: id.New(), Name: "Pizza", Price: 150},
+					Items: []domain.BillItem{
+						{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 100}, Amount: 100},
+						{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 150}, Amount: 150},
 					},
 					TotalAmount: 250,
 				},
@@ -295,12 +367,12 @@ func TestPayBill(t *testing.T) {
 				err := billRepo.Save(context.Background(), tc.bill)
 				require.NoError(t, err, "failed to save bill")
 
-				err = billService.PayBill(context.Background(), tc.bill.ID, tc.amount)
+				_, err = billService.PayBill(context.Background(), tc.bill.ID, domain.TenderCard, tc.amount, 0, 0)
 
 				require.NoError(t, err, "failed to pay bill")
 				bill, err := billRepo.FindByID(context.Background(), tc.bill.ID)
 				require.NoError(t, err, "failed to find bill")
-				assert.Equal(t, tc.amount, bill.Paid, "bill paid amount not correct")
+				assert.Equal(t, tc.amount, bill.Paid(), "bill paid amount not correct")
 			})
 		}
 	})
@@ -312,6 +384,7 @@ func TestPayBill(t *testing.T) {
 			testName string
 			bill     domain.Bill
 			amount   int
+			errCode  string
 		}{
 			{
 				testName: "pay already paid bill",
@@ -319,14 +392,15 @@ func TestPayBill(t *testing.T) {
 					ID:      id.New(),
 					TableID: id.New(),
 					Status:  domain.BillStatusPaid,
-					Items: []domain.MenuItem{
-						{ID: id.New(), Name: "Spaghetti", Price: 100},
-						{ID: id.New(), Name: "Pizza", Price: 150},
+					Items: []domain.BillItem{
+						{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 100}, Amount: 100},
+						{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 150}, Amount: 150},
 					},
 					TotalAmount: 250,
-					Paid:        250,
+					Payments:    []domain.Payment{cardPayment(250)},
 				},
-				amount: 100,
+				amount:  100,
+				errCode: domain.ECONFLICT,
 			},
 			{
 				testName: "pay more than total amount",
@@ -334,13 +408,14 @@ func TestPayBill(t *testing.T) {
 					ID:      id.New(),
 					TableID: id.New(),
 					Status:  domain.BillStatusPending,
-					Items: []domain.MenuItem{
-						{ID: id.New(), Name: "Spaghetti", Price: 100},
-						{ID: id.New(), Name: "Pizza", Price: 150},
+					Items: []domain.BillItem{
+						{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 100}, Amount: 100},
+						{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 150}, Amount: 150},
 					},
 					TotalAmount: 250,
 				},
-				amount: 300,
+				amount:  300,
+				errCode: domain.ECONFLICT,
 			},
 			{
 				testName: "pay partially more than total amount",
@@ -348,11 +423,24 @@ func TestPayBill(t *testing.T) {
 					ID:          id.New(),
 					TableID:     id.New(),
 					Status:      domain.BillStatusPending,
-					Items:       []domain.MenuItem{{ID: id.New(), Name: "test", Price: 200}},
+					Items:       []domain.BillItem{{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 200}, Amount: 200}},
+					TotalAmount: 200,
+					Payments:    []domain.Payment{cardPayment(100)},
+				},
+				amount:  101,
+				errCode: domain.ECONFLICT,
+			},
+			{
+				testName: "pay non positive amount",
+				bill: domain.Bill{
+					ID:          id.New(),
+					TableID:     id.New(),
+					Status:      domain.BillStatusPending,
+					Items:       []domain.BillItem{{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 200}, Amount: 200}},
 					TotalAmount: 200,
-					Paid:        100,
 				},
-				amount: 101,
+				amount:  0,
+				errCode: domain.EINVALID,
 			},
 		}
 
@@ -363,16 +451,17 @@ func TestPayBill(t *testing.T) {
 				err := billRepo.Save(context.Background(), tc.bill)
 				require.NoError(t, err, "failed to save bill")
 
-				err = billService.PayBill(context.Background(), tc.bill.ID, tc.amount)
+				_, err = billService.PayBill(context.Background(), tc.bill.ID, domain.TenderCard, tc.amount, 0, 0)
 
 				require.Error(t, err, "paying bill should fail")
+				assert.Equal(t, tc.errCode, domain.ErrorCode(err), "invalid error code")
 			})
 		}
 
 		t.Run("bill not found", func(t *testing.T) {
 			t.Parallel()
 
-			err := billService.PayBill(context.Background(), id.New(), 100)
+			_, err := billService.PayBill(context.Background(), id.New(), domain.TenderCard, 100, 0, 0)
 
 			require.Error(t, err, "paying not found bill should fail")
 		})
@@ -380,57 +469,139 @@ func TestPayBill(t *testing.T) {
 		t.Run("context error", func(t *testing.T) {
 			t.Parallel()
 
-			err := billService.PayBill(context.Background(), id.New(), 100)
+			_, err := billService.PayBill(context.Background(), id.New(), domain.TenderCard, 100, 0, 0)
 
 			require.Error(t, err, "paying bill should fail")
 		})
 	})
 
 }
+func TestPayBillTenders(t *testing.T) {
+	billRepo := inmem.NewBill()
+	now := time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)
+	billService := domain.NewBillService(billRepo, nil).WithClock(fixedClock(now))
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		tt := []struct {
+			testName string
+			tender   domain.TenderType
+			amount   int
+			tip      int
+			tendered int
+			change   int
+		}{
+			{testName: "card", tender: domain.TenderCard, amount: 100, tip: 10},
+			{testName: "voucher", tender: domain.TenderVoucher, amount: 100},
+			{testName: "exact cash", tender: domain.TenderCash, amount: 100, tendered: 0},
+			{testName: "cash with change", tender: domain.TenderCash, amount: 100, tip: 5, tendered: 200, change: 95},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				bill := generateSplittableBill(t, billRepo, 0)
+
+				payment, err := billService.PayBill(ctx, bill.ID, tc.tender, tc.amount, tc.tip, tc.tendered)
+
+				require.NoError(t, err, "failed to pay bill")
+				assert.Equal(t, tc.tender, payment.Tender, "invalid tender")
+				assert.Equal(t, tc.change, payment.Change, "invalid change")
+				assert.Equal(t, now, payment.PaidAt, "invalid payment time")
+				bill, err = billRepo.FindByID(ctx, bill.ID)
+				require.NoError(t, err, "failed to find bill")
+				assert.Equal(t, []domain.Payment{payment}, bill.Payments, "payment not recorded in the ledger")
+			})
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		tt := []struct {
+			testName string
+			tender   domain.TenderType
+			tendered int
+		}{
+			{testName: "unknown tender", tender: "cheque"},
+			{testName: "cash tendered below amount", tender: domain.TenderCash, tendered: 90},
+			{testName: "card with tendered cash", tender: domain.TenderCard, tendered: 200},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				bill := generateSplittableBill(t, billRepo, 0)
+
+				_, err := billService.PayBill(ctx, bill.ID, tc.tender, 100, 0, tc.tendered)
+
+				assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+	})
+}
+
+func TestBillStatusFollowsLedger(t *testing.T) {
+	billRepo := inmem.NewBill()
+	billService := domain.NewBillService(billRepo, nil)
+	ctx := context.Background()
+	bill := generateSplittableBill(t, billRepo, 0, 0)
+
+	_, err := billService.PayBill(ctx, bill.ID, domain.TenderCash, 120, 0, 150)
+	require.NoError(t, err, "failed to pay bill")
+	bill, err = billRepo.FindByID(ctx, bill.ID)
+	require.NoError(t, err, "failed to find bill")
+	assert.Equal(t, domain.BillPartiallyPaid, bill.Status, "invalid status after a partial payment")
+	assert.Equal(t, 180, bill.RemainingAmount(), "change should not count towards the amount paid")
+
+	_, err = billService.PayBill(ctx, bill.ID, domain.TenderVoucher, 180, 0, 0)
+	require.NoError(t, err, "failed to pay bill")
+	bill, err = billRepo.FindByID(ctx, bill.ID)
+	require.NoError(t, err, "failed to find bill")
+	assert.Equal(t, domain.BillStatusPaid, bill.Status, "invalid status after the last payment")
+	assert.Len(t, bill.Payments, 2, "invalid ledger")
+}
+
 func TestPayBillPartiallySuccess(t *testing.T) {
 	billRepo := inmem.NewBill()
-	billService := domain.NewBillService(billRepo)
+	billService := domain.NewBillService(billRepo, nil)
 	bill := domain.Bill{
 		ID:      id.New(),
 		TableID: id.New(),
 		Status:  domain.BillStatusPending,
-		Items: []domain.MenuItem{
-			{ID: id.New(), Name: "Spaghetti", Price: 100},
-			{ID: id.New(), Name: "Pizza", Price: 150},
+		Items: []domain.BillItem{
+			{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 100}, Amount: 100},
+			{PreparationID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 150}, Amount: 150},
 		},
 		TotalAmount: 250,
 	}
 	billRepo.Save(context.Background(), bill)
 
-	err := billService.PayBill(context.Background(), bill.ID, 100)
+	_, err := billService.PayBill(context.Background(), bill.ID, domain.TenderCard, 100, 0, 0)
 
 	require.NoError(t, err, "failed to pay bill")
 	bill, err = billRepo.FindByID(context.Background(), bill.ID)
 	require.NoError(t, err, "failed to find bill")
 	assert.Equal(t, domain.BillPartiallyPaid, bill.Status, "bill status not partially paid")
-	assert.Equal(t, 100, bill.Paid, "bill already paid not correct")
+	assert.Equal(t, 100, bill.Paid(), "bill already paid not correct")
 }
 
 func TestPayBillAlreadyPaid(t *testing.T) {
 	billRepo := inmem.NewBill()
-	billService := domain.NewBillService(billRepo)
+	billService := domain.NewBillService(billRepo, nil)
 	bill := domain.Bill{
 		ID:          id.New(),
 		TableID:     id.New(),
 		Status:      domain.BillStatusPaid,
 		TotalAmount: 250,
-		Paid:        250,
+		Payments:    []domain.Payment{cardPayment(250)},
 	}
 	billRepo.Save(context.Background(), bill)
 
-	err := billService.PayBill(context.Background(), bill.ID, 100)
+	_, err := billService.PayBill(context.Background(), bill.ID, domain.TenderCard, 100, 0, 0)
 
 	require.Error(t, err, "paying already paid bill should fail")
 }
 
 func TestPayBillMoreThanTotalAmount(t *testing.T) {
 	billRepo := inmem.NewBill()
-	billService := domain.NewBillService(billRepo)
+	billService := domain.NewBillService(billRepo, nil)
 	bill := domain.Bill{
 		ID:          id.New(),
 		TableID:     id.New(),
@@ -439,33 +610,865 @@ func TestPayBillMoreThanTotalAmount(t *testing.T) {
 	}
 	billRepo.Save(context.Background(), bill)
 
-	err := billService.PayBill(context.Background(), bill.ID, 300)
+	_, err := billService.PayBill(context.Background(), bill.ID, domain.TenderCard, 300, 0, 0)
 
 	require.Error(t, err, "paying more than total amount should fail")
 }
 
 func TestPayBillPartiallyMoreThanTotalAmount(t *testing.T) {
 	billRepo := inmem.NewBill()
-	billService := domain.NewBillService(billRepo)
+	billService := domain.NewBillService(billRepo, nil)
 	bill := domain.Bill{
 		ID:          id.New(),
 		TableID:     id.New(),
 		Status:      domain.BillStatusPending,
 		TotalAmount: 200,
-		Paid:        100,
+		Payments:    []domain.Payment{cardPayment(100)},
 	}
 	billRepo.Save(context.Background(), bill)
 
-	err := billService.PayBill(context.Background(), bill.ID, 101)
+	_, err := billService.PayBill(context.Background(), bill.ID, domain.TenderCard, 101, 0, 0)
 
 	require.Error(t, err, "paying more than total amount should fail")
 }
 
 func TestPayBillNotFound(t *testing.T) {
 	billRepo := inmem.NewBill()
-	billService := domain.NewBillService(billRepo)
+	billService := domain.NewBillService(billRepo, nil)
 
-	err := billService.PayBill(context.Background(), id.New(), 100)
+	_, err := billService.PayBill(context.Background(), id.New(), domain.TenderCard, 100, 0, 0)
 
 	require.Error(t, err, "paying not found bill should fail")
 }
+
+func TestBillPublishesEvents(t *testing.T) {
+	billRepo := inmem.NewBill()
+	publisher := &recordingPublisher{}
+	billService := domain.NewBillService(billRepo, publisher)
+	table := domain.Table{
+		ID:     id.New(),
+		Status: domain.TableStatusClosed,
+		Orders: []domain.Order{{
+			ID:           id.New(),
+			Status:       domain.OrderStatusDone,
+			Preparations: []domain.Preparation{{ID: id.New(), Status: domain.PreparationStatusServed, MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 150}}},
+		}},
+	}
+
+	bill, err := billService.GenerateBill(context.Background(), table)
+	require.NoError(t, err, "failed to generate bill")
+	mustPayBill(t, billService, bill.ID, 100, 0)
+	mustPayBill(t, billService, bill.ID, 50, 0)
+
+	assert.Equal(t, []string{"bill.generated", "bill.payment_received", "bill.payment_received", "bill.paid", "table.settled"}, publisher.names())
+	payment := publisher.events[2].(domain.PaymentReceived)
+	assert.Equal(t, 50, payment.Payment.Amount, "invalid payment amount")
+	paid := publisher.events[3].(domain.BillPaid)
+	assert.Equal(t, domain.BillStatusPaid, paid.Bill.Status, "invalid bill status")
+}
+
+func generateSplittableBill(t *testing.T, billRepo domain.BillRepository, seats ...int) domain.Bill {
+	t.Helper()
+
+	bill := domain.Bill{
+		ID:      id.New(),
+		TableID: id.New(),
+		Status:  domain.BillStatusPending,
+		Items:   make([]domain.BillItem, 0, len(seats)),
+	}
+	for i, seat := range seats {
+		amount := 100 * (i + 1)
+		bill.Items = append(bill.Items, domain.BillItem{
+			PreparationID: id.New(),
+			MenuItem:      domain.MenuItem{ID: id.New(), Name: "item", Price: amount},
+			Seat:          seat,
+			Amount:        amount,
+		})
+		bill.TotalAmount += amount
+	}
+
+	require.NoError(t, billRepo.Save(context.Background(), bill), "initial setup failed")
+
+	return bill
+}
+
+func TestSplitBillByItems(t *testing.T) {
+	billRepo := inmem.NewBill()
+	billService := domain.NewBillService(billRepo, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		bill := generateSplittableBill(t, billRepo, 0, 0, 0)
+		items := bill.Items
+
+		parts, err := billService.SplitBillByItems(context.Background(), bill.ID, [][]id.ID{
+			{items[1].PreparationID},
+			{items[2].PreparationID, items[0].PreparationID},
+		})
+
+		require.NoError(t, err, "split bill failed")
+		require.Len(t, parts, 2, "invalid number of sub-bills")
+		assert.Equal(t, []domain.BillItem{items[1]}, parts[0].Items, "invalid items of the first sub-bill")
+		assert.Equal(t, 200, parts[0].TotalAmount, "invalid amount of the first sub-bill")
+		assert.Equal(t, []domain.BillItem{items[0], items[2]}, parts[1].Items, "invalid items of the second sub-bill")
+		assert.Equal(t, 400, parts[1].TotalAmount, "invalid amount of the second sub-bill")
+		for _, part := range parts {
+			assert.Equal(t, bill.ID, part.ParentID, "invalid parent bill")
+			assert.Equal(t, bill.TableID, part.TableID, "invalid table")
+			assert.Equal(t, domain.BillStatusPending, part.Status, "invalid sub-bill status")
+		}
+
+		bill, err = billRepo.FindByID(context.Background(), bill.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.BillStatusSplit, bill.Status, "bill not marked as split")
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+
+		bill := generateSplittableBill(t, billRepo, 0, 0, 0)
+		first, second, third := bill.Items[0].PreparationID, bill.Items[1].PreparationID, bill.Items[2].PreparationID
+
+		tt := []struct {
+			testName string
+			groups   [][]id.ID
+		}{
+			{testName: "single group", groups: [][]id.ID{{first, second, third}}},
+			{testName: "empty group", groups: [][]id.ID{{first, second, third}, {}}},
+			{testName: "preparation not on bill", groups: [][]id.ID{{first, second}, {third, id.New()}}},
+			{testName: "preparation assigned twice", groups: [][]id.ID{{first, second}, {third, first}}},
+			{testName: "preparation not assigned", groups: [][]id.ID{{first}, {third}}},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := billService.SplitBillByItems(context.Background(), bill.ID, tc.groups)
+
+				assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+	})
+}
+
+func TestSplitBillBySeat(t *testing.T) {
+	billRepo := inmem.NewBill()
+	billService := domain.NewBillService(billRepo, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		bill := generateSplittableBill(t, billRepo, 2, 0, 1, 2)
+
+		parts, err := billService.SplitBillBySeat(context.Background(), bill.ID)
+
+		require.NoError(t, err, "split bill failed")
+		require.Len(t, parts, 3, "invalid number of sub-bills")
+		assert.Equal(t, []domain.BillItem{bill.Items[2]}, parts[0].Items, "invalid items of seat 1")
+		assert.Equal(t, []domain.BillItem{bill.Items[0], bill.Items[3]}, parts[1].Items, "invalid items of seat 2")
+		assert.Equal(t, 500, parts[1].TotalAmount, "invalid amount of seat 2")
+		assert.Equal(t, []domain.BillItem{bill.Items[1]}, parts[2].Items, "invalid shared items")
+	})
+
+	t.Run("Single seat", func(t *testing.T) {
+		t.Parallel()
+
+		bill := generateSplittableBill(t, billRepo, 1, 1)
+
+		_, err := billService.SplitBillBySeat(context.Background(), bill.ID)
+
+		assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+	})
+}
+
+func TestSplitBillEqually(t *testing.T) {
+	billRepo := inmem.NewBill()
+	billService := domain.NewBillService(billRepo, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		tt := []struct {
+			testName string
+			seats    []int
+			shares   int
+			amounts  []int
+		}{
+			{testName: "even split", seats: []int{0, 0, 0}, shares: 3, amounts: []int{200, 200, 200}},
+			{testName: "remainder on the first shares", seats: []int{0, 0}, shares: 7, amounts: []int{43, 43, 43, 43, 43, 43, 42}},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				t.Parallel()
+
+				bill := generateSplittableBill(t, billRepo, tc.seats...)
+
+				parts, err := billService.SplitBillEqually(context.Background(), bill.ID, tc.shares)
+
+				require.NoError(t, err, "split bill failed")
+				amounts := make([]int, 0, len(parts))
+				for _, part := range parts {
+					amounts = append(amounts, part.TotalAmount)
+					assert.Empty(t, part.Items, "shares should carry no item")
+				}
+				assert.Equal(t, tc.amounts, amounts, "invalid shares")
+			})
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+
+		tt := []struct {
+			testName string
+			setup    func(t *testing.T) domain.Bill
+			shares   int
+			errCode  string
+		}{
+			{
+				testName: "single share",
+				setup:    func(t *testing.T) domain.Bill { return generateSplittableBill(t, billRepo, 0) },
+				shares:   1,
+				errCode:  domain.EINVALID,
+			},
+			{
+				testName: "more shares than units",
+				setup:    func(t *testing.T) domain.Bill { return generateSplittableBill(t, billRepo, 0) },
+				shares:   101,
+				errCode:  domain.EINVALID,
+			},
+			{
+				testName: "bill not found",
+				setup:    func(t *testing.T) domain.Bill { return domain.Bill{ID: id.New()} },
+				shares:   2,
+				errCode:  domain.ENOTFOUND,
+			},
+			{
+				testName: "bill with payments",
+				setup: func(t *testing.T) domain.Bill {
+					bill := generateSplittableBill(t, billRepo, 0)
+					mustPayBill(t, billService, bill.ID, 10, 0)
+					return bill
+				},
+				shares:  2,
+				errCode: domain.ECONFLICT,
+			},
+			{
+				testName: "bill already split",
+				setup: func(t *testing.T) domain.Bill {
+					bill := generateSplittableBill(t, billRepo, 0)
+					_, err := billService.SplitBillEqually(context.Background(), bill.ID, 2)
+					require.NoError(t, err)
+					return bill
+				},
+				shares:  2,
+				errCode: domain.ECONFLICT,
+			},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				t.Parallel()
+
+				bill := tc.setup(t)
+
+				_, err := billService.SplitBillEqually(context.Background(), bill.ID, tc.shares)
+
+				assert.Equal(t, tc.errCode, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+	})
+}
+
+func TestSplitBillEquallySharesDiscounts(t *testing.T) {
+	billRepo := inmem.NewBill()
+	billService := domain.NewBillService(billRepo, nil)
+
+	bill := generateSplittableBill(t, billRepo, 0, 0)
+	bill.Discounts = []domain.Discount{
+		{PromotionID: id.New(), Name: "happy hour", Amount: 5},
+		{Name: "manager", Amount: 5, Reason: "late order"},
+	}
+	bill.TotalAmount -= bill.Discount()
+	bill.Version++
+	require.NoError(t, billRepo.Save(context.Background(), bill), "initial setup failed")
+
+	parts, err := billService.SplitBillEqually(context.Background(), bill.ID, 3)
+
+	require.NoError(t, err, "split bill failed")
+	amounts := make([]int, 0, len(parts))
+	discounts := make([]int, 0, len(parts))
+	lines := make(map[string]int)
+	for _, part := range parts {
+		amounts = append(amounts, part.TotalAmount)
+		discounts = append(discounts, part.Discount())
+		for _, line := range part.Discounts {
+			lines[line.Name] += line.Amount
+		}
+	}
+	assert.Equal(t, []int{97, 97, 96}, amounts, "invalid shares")
+	assert.Equal(t, []int{4, 3, 3}, discounts, "the discount of every share should follow its amount")
+	assert.Equal(t, map[string]int{"happy hour": 5, "manager": 5}, lines, "the shares should sum to every discount line")
+}
+
+func TestTableSettledOnceEverySubBillIsPaid(t *testing.T) {
+	billRepo := inmem.NewBill()
+	publisher := &recordingPublisher{}
+	billService := domain.NewBillService(billRepo, publisher)
+	ctx := context.Background()
+	bill := generateSplittableBill(t, billRepo, 1, 2)
+
+	settled, err := billService.IsTableSettled(ctx, bill.TableID)
+	require.NoError(t, err)
+	assert.False(t, settled, "table with a pending bill should not be settled")
+
+	parts, err := billService.SplitBillBySeat(ctx, bill.ID)
+	require.NoError(t, err)
+
+	_, err = billService.PayBill(ctx, bill.ID, domain.TenderCard, bill.TotalAmount, 0, 0)
+	assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "split bill should not be payable")
+
+	mustPayBill(t, billService, parts[0].ID, parts[0].TotalAmount, 0)
+	settled, err = billService.IsTableSettled(ctx, bill.TableID)
+	require.NoError(t, err)
+	assert.False(t, settled, "table with a pending sub-bill should not be settled")
+
+	mustPayBill(t, billService, parts[1].ID, parts[1].TotalAmount, 0)
+	settled, err = billService.IsTableSettled(ctx, bill.TableID)
+	require.NoError(t, err)
+	assert.True(t, settled, "table with every sub-bill paid should be settled")
+
+	assert.Equal(t, []string{
+		"bill.split",
+		"bill.payment_received",
+		"bill.paid",
+		"bill.payment_received",
+		"bill.paid",
+		"table.settled",
+	}, publisher.names())
+}
+
+func TestGenerateBillWithServiceCharge(t *testing.T) {
+	billRepo := inmem.NewBill()
+	policy := domain.ServiceChargePolicy{Percent: 12.5, MinGuests: 6}
+	billService := domain.NewBillService(billRepo, nil).WithServiceCharge(policy)
+
+	tt := []struct {
+		testName      string
+		guests        int
+		serviceCharge int
+	}{
+		{testName: "party below the threshold", guests: 5, serviceCharge: 0},
+		{testName: "party at the threshold", guests: 6, serviceCharge: 31},
+		{testName: "party above the threshold", guests: 8, serviceCharge: 31},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+
+			table := domain.Table{
+				ID:         id.New(),
+				Status:     domain.TableStatusClosed,
+				GuestCount: tc.guests,
+				Orders: []domain.Order{
+					{ID: id.New(), Preparations: []domain.Preparation{
+						{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 100}},
+						{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 150}},
+					}},
+				},
+			}
+
+			bill, err := billService.GenerateBill(context.Background(), table)
+
+			require.NoError(t, err, "failed to generate bill")
+			assert.Equal(t, 250, bill.Subtotal(), "invalid subtotal")
+			assert.Equal(t, tc.serviceCharge, bill.ServiceCharge, "invalid service charge")
+			assert.Equal(t, 250+tc.serviceCharge, bill.TotalAmount, "invalid total amount")
+		})
+	}
+}
+
+func TestGenerateBillTwice(t *testing.T) {
+	billRepo := inmem.NewBill()
+	publisher := &recordingPublisher{}
+	billService := domain.NewBillService(billRepo, publisher)
+	ctx := context.Background()
+	table := domain.Table{
+		ID:     id.New(),
+		Status: domain.TableStatusClosed,
+		Orders: []domain.Order{
+			{ID: id.New(), Preparations: []domain.Preparation{
+				{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 100}},
+			}},
+		},
+	}
+
+	bill, err := billService.GenerateBill(ctx, table)
+	require.NoError(t, err, "failed to generate bill")
+
+	_, err = billService.GenerateBill(ctx, table)
+	assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "a second bill was generated for the table")
+
+	bills, err := billService.FindTableBills(ctx, table.ID)
+	require.NoError(t, err, "failed to find bills")
+	assert.Equal(t, []domain.Bill{bill}, bills)
+	assert.Equal(t, []string{"bill.generated"}, publisher.names())
+}
+
+func TestPayBillWithTip(t *testing.T) {
+	billRepo := inmem.NewBill()
+	publisher := &recordingPublisher{}
+	billService := domain.NewBillService(billRepo, publisher)
+	ctx := context.Background()
+	bill := generateSplittableBill(t, billRepo, 0, 0)
+
+	mustPayBill(t, billService, bill.ID, 100, 15)
+	mustPayBill(t, billService, bill.ID, 200, 25)
+
+	bill, err := billRepo.FindByID(ctx, bill.ID)
+	require.NoError(t, err, "failed to find bill")
+	assert.Equal(t, domain.BillStatusPaid, bill.Status, "tips should not count towards the amount due")
+	assert.Equal(t, 300, bill.Paid(), "invalid paid amount")
+	assert.Equal(t, 40, bill.Tips(), "invalid tips")
+	payment := publisher.events[1].(domain.PaymentReceived)
+	assert.Equal(t, 25, payment.Payment.Tip, "invalid payment tip")
+
+	t.Run("negative tip", func(t *testing.T) {
+		bill := generateSplittableBill(t, billRepo, 0)
+
+		_, err := billService.PayBill(ctx, bill.ID, domain.TenderCard, 100, -1, 0)
+
+		assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+	})
+}
+
+func TestSplitBillSharesServiceCharge(t *testing.T) {
+	billRepo := inmem.NewBill()
+	billService := domain.NewBillService(billRepo, nil)
+
+	generateBill := func(t *testing.T) domain.Bill {
+		bill := generateSplittableBill(t, billRepo, 1, 2)
+		bill.ServiceCharge = 31
+		bill.TotalAmount += bill.ServiceCharge
+		bill.Version++
+		require.NoError(t, billRepo.Save(context.Background(), bill), "initial setup failed")
+		return bill
+	}
+
+	t.Run("by seat", func(t *testing.T) {
+		t.Parallel()
+
+		bill := generateBill(t)
+
+		parts, err := billService.SplitBillBySeat(context.Background(), bill.ID)
+
+		require.NoError(t, err, "split bill failed")
+		assert.Equal(t, 11, parts[0].ServiceCharge, "invalid service charge of seat 1")
+		assert.Equal(t, 111, parts[0].TotalAmount, "invalid amount of seat 1")
+		assert.Equal(t, 20, parts[1].ServiceCharge, "invalid service charge of seat 2")
+		assert.Equal(t, 220, parts[1].TotalAmount, "invalid amount of seat 2")
+	})
+
+	t.Run("equally", func(t *testing.T) {
+		t.Parallel()
+
+		bill := generateBill(t)
+
+		parts, err := billService.SplitBillEqually(context.Background(), bill.ID, 2)
+
+		require.NoError(t, err, "split bill failed")
+		assert.Equal(t, 16, parts[0].ServiceCharge, "invalid service charge of the first share")
+		assert.Equal(t, 166, parts[0].TotalAmount, "invalid amount of the first share")
+		assert.Equal(t, 15, parts[1].ServiceCharge, "invalid service charge of the second share")
+		assert.Equal(t, 165, parts[1].TotalAmount, "invalid amount of the second share")
+	})
+
+	t.Run("equally in uneven shares", func(t *testing.T) {
+		t.Parallel()
+
+		bill := generateSplittableBill(t, billRepo, 0)
+		bill.ServiceCharge = 10
+		bill.TotalAmount += bill.ServiceCharge
+		bill.Version++
+		require.NoError(t, billRepo.Save(context.Background(), bill), "initial setup failed")
+
+		parts, err := billService.SplitBillEqually(context.Background(), bill.ID, 3)
+
+		require.NoError(t, err, "split bill failed")
+		amounts := make([]int, 0, len(parts))
+		serviceCharge := 0
+		for _, part := range parts {
+			amounts = append(amounts, part.TotalAmount)
+			serviceCharge += part.ServiceCharge
+		}
+		assert.Equal(t, []int{37, 37, 36}, amounts, "the leftover units of the service charge should not pile up on the first shares")
+		assert.Equal(t, bill.ServiceCharge, serviceCharge, "shares should sum to the service charge")
+	})
+}
+
+func TestTableSalesReport(t *testing.T) {
+	billRepo := inmem.NewBill()
+	billService := domain.NewBillService(billRepo, nil)
+	ctx := context.Background()
+	bill := generateSplittableBill(t, billRepo, 1, 2)
+	bill.ServiceCharge = 30
+	bill.TotalAmount += bill.ServiceCharge
+	bill.Version++
+	require.NoError(t, billRepo.Save(ctx, bill), "initial setup failed")
+
+	parts, err := billService.SplitBillBySeat(ctx, bill.ID)
+	require.NoError(t, err, "split bill failed")
+	mustPayBill(t, billService, parts[0].ID, parts[0].TotalAmount, 20)
+	mustPayBill(t, billService, parts[1].ID, parts[1].TotalAmount, 5)
+
+	report, err := billService.TableSalesReport(ctx, bill.TableID)
+
+	require.NoError(t, err, "failed to report sales")
+	assert.Equal(t, domain.SalesReport{Sales: 300, ServiceCharges: 30, Tips: 25}, report)
+	assert.Equal(t, 55, report.Gratuities(), "invalid gratuities")
+}
+
+func TestTableSalesReportIsNetOfTaxes(t *testing.T) {
+	billRepo := inmem.NewBill()
+	billService := domain.NewBillService(billRepo, nil).WithTaxPolicy(domain.TaxPolicy{Rates: map[string]float64{"food": 10}})
+	table := domain.Table{
+		ID:     id.New(),
+		Status: domain.TableStatusClosed,
+		Orders: []domain.Order{
+			{ID: id.New(), Preparations: []domain.Preparation{
+				{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 1100, TaxCategory: "food"}},
+			}},
+		},
+	}
+	_, err := billService.GenerateBill(context.Background(), table)
+	require.NoError(t, err, "failed to generate bill")
+
+	report, err := billService.TableSalesReport(context.Background(), table.ID)
+
+	require.NoError(t, err, "failed to report sales")
+	assert.Equal(t, domain.SalesReport{Sales: 1000, Taxes: 100}, report)
+}
+
+func TestGenerateBillWithTaxes(t *testing.T) {
+	billRepo := inmem.NewBill()
+	rates := map[string]float64{"food": 10, "alcohol": 20}
+
+	table := domain.Table{
+		ID:     id.New(),
+		Status: domain.TableStatusClosed,
+		Orders: []domain.Order{
+			{ID: id.New(), Preparations: []domain.Preparation{
+				{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Wine", Price: 505, TaxCategory: "alcohol"}},
+				{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 1005, TaxCategory: "food"}},
+				{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 1500, TaxCategory: "food"}},
+				{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Water", Price: 100}},
+			}},
+		},
+	}
+
+	tt := []struct {
+		testName    string
+		exclusive   bool
+		taxes       []domain.TaxLine
+		totalAmount int
+	}{
+		{
+			testName: "taxes included in the prices",
+			taxes: []domain.TaxLine{
+				{Rate: 0, Net: 100, Tax: 0, Gross: 100},
+				{Rate: 10, Net: 2277, Tax: 228, Gross: 2505},
+				{Rate: 20, Net: 421, Tax: 84, Gross: 505},
+			},
+			totalAmount: 3110,
+		},
+		{
+			testName:  "taxes excluded from the prices",
+			exclusive: true,
+			taxes: []domain.TaxLine{
+				{Rate: 0, Net: 100, Tax: 0, Gross: 100},
+				{Rate: 10, Net: 2505, Tax: 251, Gross: 2756},
+				{Rate: 20, Net: 505, Tax: 101, Gross: 606},
+			},
+			totalAmount: 3462,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+
+			billService := domain.NewBillService(billRepo, nil).WithTaxPolicy(domain.TaxPolicy{Exclusive: tc.exclusive, Rates: rates})
+
+			table := table
+			table.ID = id.New()
+
+			bill, err := billService.GenerateBill(context.Background(), table)
+
+			require.NoError(t, err, "failed to generate bill")
+			assert.Equal(t, tc.taxes, bill.Taxes, "invalid tax breakdown")
+			assert.Equal(t, tc.totalAmount, bill.TotalAmount, "invalid total amount")
+			assert.Equal(t, 3110, bill.Subtotal(), "invalid subtotal")
+		})
+	}
+}
+
+func TestSplitBillSharesTaxes(t *testing.T) {
+	billRepo := inmem.NewBill()
+	billService := domain.NewBillService(billRepo, nil).WithTaxPolicy(domain.TaxPolicy{
+		Exclusive: true,
+		Rates:     map[string]float64{"food": 10, "alcohol": 20},
+	})
+	table := domain.Table{
+		ID:     id.New(),
+		Status: domain.TableStatusClosed,
+		Orders: []domain.Order{
+			{ID: id.New(), Preparations: []domain.Preparation{
+				{ID: id.New(), Seat: 1, MenuItem: domain.MenuItem{ID: id.New(), Name: "Wine", Price: 505, TaxCategory: "alcohol"}},
+				{ID: id.New(), Seat: 1, MenuItem: domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 1005, TaxCategory: "food"}},
+				{ID: id.New(), Seat: 2, MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 1500, TaxCategory: "food"}},
+			}},
+		},
+	}
+
+	t.Run("by seat", func(t *testing.T) {
+		t.Parallel()
+
+		table := table
+		table.ID = id.New()
+
+		bill, err := billService.GenerateBill(context.Background(), table)
+		require.NoError(t, err, "failed to generate bill")
+
+		parts, err := billService.SplitBillBySeat(context.Background(), bill.ID)
+
+		require.NoError(t, err, "split bill failed")
+		assert.Equal(t, []domain.TaxLine{
+			{Rate: 10, Net: 1005, Tax: 101, Gross: 1106},
+			{Rate: 20, Net: 505, Tax: 101, Gross: 606},
+		}, parts[0].Taxes, "invalid taxes of seat 1")
+		assert.Equal(t, []domain.TaxLine{{Rate: 10, Net: 1500, Tax: 150, Gross: 1650}}, parts[1].Taxes, "invalid taxes of seat 2")
+		assert.Equal(t, bill.TotalAmount, parts[0].TotalAmount+parts[1].TotalAmount, "sub-bills should sum to the bill")
+	})
+
+	t.Run("equally", func(t *testing.T) {
+		t.Parallel()
+
+		table := table
+		table.ID = id.New()
+
+		bill, err := billService.GenerateBill(context.Background(), table)
+		require.NoError(t, err, "failed to generate bill")
+
+		parts, err := billService.SplitBillEqually(context.Background(), bill.ID, 2)
+
+		require.NoError(t, err, "split bill failed")
+		assert.Equal(t, []domain.TaxLine{
+			{Rate: 10, Net: 1253, Tax: 126, Gross: 1379},
+			{Rate: 20, Net: 253, Tax: 51, Gross: 304},
+		}, parts[0].Taxes, "invalid taxes of the first share")
+		assert.Equal(t, 1681, parts[0].TotalAmount, "invalid amount of the first share")
+		assert.Equal(t, bill.TotalAmount, parts[0].TotalAmount+parts[1].TotalAmount, "shares should sum to the bill")
+	})
+
+	t.Run("equally in uneven shares", func(t *testing.T) {
+		t.Parallel()
+
+		table := table
+		table.ID = id.New()
+
+		bill, err := billService.GenerateBill(context.Background(), table)
+		require.NoError(t, err, "failed to generate bill")
+
+		parts, err := billService.SplitBillEqually(context.Background(), bill.ID, 3)
+
+		require.NoError(t, err, "split bill failed")
+		amounts := make([]int, 0, len(parts))
+		tax := 0
+		for _, part := range parts {
+			amounts = append(amounts, part.TotalAmount)
+			tax += part.Tax()
+		}
+		assert.Equal(t, []int{1121, 1121, 1120}, amounts, "the excluded taxes should not drift the shares apart")
+		assert.Equal(t, bill.Tax(), tax, "shares should sum to the taxes of the bill")
+	})
+}
+
+func cardPayment(amount int) domain.Payment {
+	return domain.Payment{ID: id.New(), Tender: domain.TenderCard, Amount: amount, PaidAt: time.Now()}
+}
+
+func mustPayBill(t *testing.T, billService *domain.BillService, billID id.ID, amount int, tip int) {
+	t.Helper()
+
+	_, err := billService.PayBill(context.Background(), billID, domain.TenderCard, amount, tip, 0)
+	require.NoError(t, err, "failed to pay bill")
+}
+
+func TestRefundPayment(t *testing.T) {
+	billRepo := inmem.NewBill()
+	publisher := &recordingPublisher{}
+	billService := domain.NewBillService(billRepo, publisher)
+	ctx := context.Background()
+
+	payBill := func(t *testing.T, tender domain.TenderType, tip int) (domain.Bill, domain.Payment) {
+		t.Helper()
+
+		bill := generateSplittableBill(t, billRepo, 1, 2)
+		payment, err := billService.PayBill(ctx, bill.ID, tender, bill.TotalAmount, tip, 0)
+		require.NoError(t, err, "initial setup failed")
+
+		return bill, payment
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Run("full refund", func(t *testing.T) {
+			bill, payment := payBill(t, domain.TenderCard, 30)
+
+			refund, err := billService.RefundPayment(ctx, bill.ID, payment.ID, id.NilID(), "wrong table")
+
+			require.NoError(t, err, "refund failed")
+			assert.Equal(t, domain.TenderCard, refund.Tender, "refund should use the tender of the payment")
+			assert.Equal(t, payment.ID, refund.RefundOf, "refund should reference the payment")
+			assert.Equal(t, -300, refund.Amount, "invalid refund amount")
+			assert.Equal(t, -30, refund.Tip, "invalid refund tip")
+			bill, err = billRepo.FindByID(ctx, bill.ID)
+			require.NoError(t, err, "failed to find bill")
+			assert.Equal(t, domain.BillStatusRefunded, bill.Status, "invalid bill status")
+			assert.Equal(t, 0, bill.Paid(), "invalid paid amount")
+			assert.Equal(t, 300, bill.Refunded(), "invalid refunded amount")
+			assert.Equal(t, 0, bill.RemainingAmount(), "a refunded amount should not be due")
+			assert.Equal(t, "bill.payment_refunded", publisher.names()[len(publisher.names())-1])
+		})
+
+		t.Run("refund per item", func(t *testing.T) {
+			bill, payment := payBill(t, domain.TenderCard, 30)
+
+			refund, err := billService.RefundPayment(ctx, bill.ID, payment.ID, bill.Items[0].PreparationID, "cold dish")
+
+			require.NoError(t, err, "refund failed")
+			assert.Equal(t, -100, refund.Amount, "invalid refund amount")
+			assert.Equal(t, 0, refund.Tip, "an item refund should not give back the tip")
+			assert.Equal(t, bill.Items[0].PreparationID, refund.PreparationID, "refund should reference the item")
+			bill, err = billRepo.FindByID(ctx, bill.ID)
+			require.NoError(t, err, "failed to find bill")
+			assert.Equal(t, domain.BillStatusPartiallyRefunded, bill.Status, "invalid bill status")
+			assert.Equal(t, 200, bill.Paid(), "invalid paid amount")
+
+			_, err = billService.RefundPayment(ctx, bill.ID, payment.ID, id.NilID(), "closing")
+			require.NoError(t, err, "failed to refund the rest of the payment")
+			bill, err = billRepo.FindByID(ctx, bill.ID)
+			require.NoError(t, err, "failed to find bill")
+			assert.Equal(t, domain.BillStatusRefunded, bill.Status, "invalid bill status")
+			assert.Equal(t, 0, bill.Tips(), "invalid tips")
+		})
+
+		t.Run("item refund includes service charge", func(t *testing.T) {
+			bill := generateSplittableBill(t, billRepo, 1, 2)
+			bill.ServiceCharge = 31
+			bill.TotalAmount += bill.ServiceCharge
+			bill.Version++
+			require.NoError(t, billRepo.Save(ctx, bill), "initial setup failed")
+			payment, err := billService.PayBill(ctx, bill.ID, domain.TenderCard, bill.TotalAmount, 0, 0)
+			require.NoError(t, err, "initial setup failed")
+
+			refund, err := billService.RefundPayment(ctx, bill.ID, payment.ID, bill.Items[1].PreparationID, "wrong wine")
+
+			require.NoError(t, err, "refund failed")
+			assert.Equal(t, -220, refund.Amount, "invalid refund amount")
+		})
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		bill, payment := payBill(t, domain.TenderCard, 0)
+		_, err := billService.RefundPayment(ctx, bill.ID, payment.ID, bill.Items[0].PreparationID, "cold dish")
+		require.NoError(t, err, "initial setup failed")
+		refunded, refundedPayment := payBill(t, domain.TenderCard, 0)
+		_, err = billService.RefundPayment(ctx, refunded.ID, refundedPayment.ID, id.NilID(), "wrong table")
+		require.NoError(t, err, "initial setup failed")
+		unpaid := generateSplittableBill(t, billRepo, 1)
+
+		tt := []struct {
+			testName      string
+			billID        id.ID
+			paymentID     id.ID
+			preparationID id.ID
+			reason        string
+			errCode       string
+		}{
+			{testName: "no reason", billID: bill.ID, paymentID: payment.ID, errCode: domain.EINVALID},
+			{testName: "unknown bill", billID: id.New(), paymentID: payment.ID, reason: "r", errCode: domain.ENOTFOUND},
+			{testName: "unpaid bill", billID: unpaid.ID, paymentID: payment.ID, reason: "r", errCode: domain.ECONFLICT},
+			{testName: "unknown payment", billID: bill.ID, paymentID: id.New(), reason: "r", errCode: domain.ENOTFOUND},
+			{testName: "unknown item", billID: bill.ID, paymentID: payment.ID, preparationID: id.New(), reason: "r", errCode: domain.ENOTFOUND},
+			{testName: "item already refunded", billID: bill.ID, paymentID: payment.ID, preparationID: bill.Items[0].PreparationID, reason: "r", errCode: domain.ECONFLICT},
+			{testName: "payment already refunded", billID: refunded.ID, paymentID: refundedPayment.ID, reason: "r", errCode: domain.ECONFLICT},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				_, err := billService.RefundPayment(ctx, tc.billID, tc.paymentID, tc.preparationID, tc.reason)
+				assert.Equal(t, tc.errCode, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+	})
+
+	t.Run("refunded bill cannot be paid", func(t *testing.T) {
+		bill, payment := payBill(t, domain.TenderCard, 0)
+		_, err := billService.RefundPayment(ctx, bill.ID, payment.ID, bill.Items[0].PreparationID, "cold dish")
+		require.NoError(t, err, "initial setup failed")
+
+		_, err = billService.PayBill(ctx, bill.ID, domain.TenderCard, 100, 0, 0)
+
+		assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "invalid error code")
+	})
+}
+
+func TestTableSalesReportWithRefunds(t *testing.T) {
+	billRepo := inmem.NewBill()
+	billService := domain.NewBillService(billRepo, nil)
+	ctx := context.Background()
+	bill := generateSplittableBill(t, billRepo, 1, 2)
+	cash, err := billService.PayBill(ctx, bill.ID, domain.TenderCash, 100, 10, 200)
+	require.NoError(t, err, "initial setup failed")
+	_, err = billService.PayBill(ctx, bill.ID, domain.TenderCard, 200, 20, 0)
+	require.NoError(t, err, "initial setup failed")
+	_, err = billService.RefundPayment(ctx, bill.ID, cash.ID, bill.Items[0].PreparationID, "cold dish")
+	require.NoError(t, err, "initial setup failed")
+
+	report, err := billService.TableSalesReport(ctx, bill.TableID)
+
+	require.NoError(t, err, "failed to report sales")
+	assert.Equal(t, domain.SalesReport{Sales: 300, Tips: 30, Refunds: 100, Cash: 10}, report)
+}
+
+func TestTableSalesReportIsGrossOfRefunds(t *testing.T) {
+	billRepo := inmem.NewBill()
+	billService := domain.NewBillService(billRepo, nil).WithTaxPolicy(domain.TaxPolicy{Rates: map[string]float64{"food": 10}})
+	ctx := context.Background()
+	table := domain.Table{
+		ID:     id.New(),
+		Status: domain.TableStatusClosed,
+		Orders: []domain.Order{
+			{ID: id.New(), Preparations: []domain.Preparation{
+				{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 1100, TaxCategory: "food"}},
+				{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pasta", Price: 550, TaxCategory: "food"}},
+			}},
+		},
+	}
+	bill, err := billService.GenerateBill(ctx, table)
+	require.NoError(t, err, "initial setup failed")
+	payment, err := billService.PayBill(ctx, bill.ID, domain.TenderCard, bill.TotalAmount, 0, 0)
+	require.NoError(t, err, "initial setup failed")
+	_, err = billService.RefundPayment(ctx, bill.ID, payment.ID, bill.Items[1].PreparationID, "cold dish")
+	require.NoError(t, err, "initial setup failed")
+
+	report, err := billService.TableSalesReport(ctx, table.ID)
+
+	// The refunded pasta still counts in the sales and the taxes, its 550 are reported apart.
+	require.NoError(t, err, "failed to report sales")
+	assert.Equal(t, domain.SalesReport{Sales: 1500, Taxes: 150, Refunds: 550}, report)
+}
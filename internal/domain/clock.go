@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// Clock tells the time to the services, it is injected so that the rules depending on the time can be tested.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the clock of the system, the one used by the services unless told otherwise.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
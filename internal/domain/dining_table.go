@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"context"
+	"order_manager/internal/id"
+	"slices"
+)
+
+// DiningTable is a physical table of the restaurant that guests can be seated at.
+// A Table is a seating session opened on a DiningTable.
+type DiningTable struct {
+	ID       id.ID
+	Name     string
+	Capacity int
+	Zone     string
+}
+
+func (t DiningTable) IsValid() bool {
+	return t.ID != id.NilID() && t.Name != "" && t.Capacity > 0
+}
+
+type DiningTableRepository interface {
+	Save(ctx context.Context, table DiningTable) error
+	FindByID(ctx context.Context, id id.ID) (DiningTable, error)
+	FindAll(ctx context.Context) ([]DiningTable, error)
+}
+
+type DiningTableService struct {
+	repo   DiningTableRepository
+	events EventPublisher
+}
+
+// NewDiningTableService creates a new dining table service.
+// The service is responsible for the registry of physical tables.
+func NewDiningTableService(repo DiningTableRepository, events EventPublisher) *DiningTableService {
+	return &DiningTableService{repo: repo, events: events}
+}
+
+// CreateDiningTable registers a new physical table.
+// Possible errors:
+// - EINVALID if the name is empty or the capacity is not positive.
+// - ECONFLICT if a dining table with the same name already exists.
+// - Any error returned by the repository when saving the dining table.
+func (s *DiningTableService) CreateDiningTable(ctx context.Context, name string, capacity int, zone string) (DiningTable, error) {
+	table := DiningTable{
+		ID:       id.New(),
+		Name:     name,
+		Capacity: capacity,
+		Zone:     zone,
+	}
+
+	if !table.IsValid() {
+		return DiningTable{}, Errorf(EINVALID, "invalid dining table")
+	}
+
+	tables, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return DiningTable{}, err
+	}
+
+	if slices.ContainsFunc(tables, func(t DiningTable) bool { return t.Name == name }) {
+		return DiningTable{}, Errorf(ECONFLICT, "dining table %s already exists", name)
+	}
+
+	if err := s.repo.Save(ctx, table); err != nil {
+		return DiningTable{}, err
+	}
+
+	publish(ctx, s.events, DiningTableCreated{DiningTable: table})
+
+	return table, nil
+}
+
+// FindDiningTable returns a dining table by its ID.
+// Possible errors:
+// - ENOTFOUND if the dining table could not be found.
+func (s *DiningTableService) FindDiningTable(ctx context.Context, tableID id.ID) (DiningTable, error) {
+	return s.repo.FindByID(ctx, tableID)
+}
+
+// FindAllDiningTables returns all registered dining tables.
+// Possible errors:
+// - Any error returned by the repository when fetching the dining tables.
+func (s *DiningTableService) FindAllDiningTables(ctx context.Context) ([]DiningTable, error) {
+	return s.repo.FindAll(ctx)
+}
@@ -0,0 +1,68 @@
+package domain_test
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"order_manager/internal/inmem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsDiningTableValid(t *testing.T) {
+	tt := []struct {
+		testName string
+		table    domain.DiningTable
+		valid    bool
+	}{
+		{testName: "valid table", table: domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 4, Zone: "terrace"}, valid: true},
+		{testName: "valid table without zone", table: domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 4}, valid: true},
+		{testName: "nil ID", table: domain.DiningTable{ID: id.NilID(), Name: "T1", Capacity: 4}, valid: false},
+		{testName: "empty name", table: domain.DiningTable{ID: id.New(), Name: "", Capacity: 4}, valid: false},
+		{testName: "zero capacity", table: domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 0}, valid: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.valid, tc.table.IsValid())
+		})
+	}
+}
+
+func TestCreateDiningTable(t *testing.T) {
+	diningTableRepo := inmem.NewDiningTable()
+	diningTableService := domain.NewDiningTableService(diningTableRepo, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		table, err := diningTableService.CreateDiningTable(context.Background(), "T1", 4, "terrace")
+		require.NoError(t, err, "dining table creation failed")
+
+		assert.NotEqual(t, id.NilID(), table.ID, "generated ID is nil")
+		savedTable, err := diningTableRepo.FindByID(context.Background(), table.ID)
+		require.NoError(t, err, "dining table not saved")
+		assert.Equal(t, table, savedTable)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		tt := []struct {
+			testName string
+			name     string
+			capacity int
+			errCode  string
+		}{
+			{testName: "empty name", name: "", capacity: 4, errCode: domain.EINVALID},
+			{testName: "zero capacity", name: "T2", capacity: 0, errCode: domain.EINVALID},
+			{testName: "duplicated name", name: "T1", capacity: 2, errCode: domain.ECONFLICT},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				_, err := diningTableService.CreateDiningTable(context.Background(), tc.name, tc.capacity, "")
+				assert.Equal(t, tc.errCode, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+	})
+}
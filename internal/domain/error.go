@@ -10,6 +10,8 @@ var (
 	EUNKNOWN  = "EUNKNOWN"
 	ENOTFOUND = "ENOTFOUND"
 	EINVALID  = "EINVALID"
+	ECONFLICT = "ECONFLICT"
+	ESTALE    = "ESTALE"
 	ECANCELED = "ECANCELED"
 )
 
@@ -0,0 +1,251 @@
+package domain
+
+import (
+	"context"
+	"order_manager/internal/id"
+)
+
+// Event is something that happened to an aggregate, published once the change is saved.
+type Event interface {
+	EventName() string
+}
+
+// EventPublisher dispatches the events published by the services to their subscribers.
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...Event)
+}
+
+// publish is a no-op when the service has no publisher.
+func publish(ctx context.Context, publisher EventPublisher, events ...Event) {
+	if publisher == nil || len(events) == 0 {
+		return
+	}
+
+	publisher.Publish(ctx, events...)
+}
+
+type TableOpened struct {
+	Table Table
+}
+
+type TableClosed struct {
+	TableID id.ID
+}
+
+type OrderTaken struct {
+	TableID id.ID
+	Order   Order
+}
+
+type OrderAborted struct {
+	TableID id.ID
+	Order   Order
+}
+
+// OrderReady is published when every station is done with an order, its last preparation being finished or aborted.
+type OrderReady struct {
+	TableID id.ID
+	Order   Order
+}
+
+type PreparationStarted struct {
+	TableID     id.ID
+	OrderID     id.ID
+	Preparation Preparation
+}
+
+type PreparationFinished struct {
+	TableID     id.ID
+	OrderID     id.ID
+	Preparation Preparation
+}
+
+type PreparationServed struct {
+	TableID     id.ID
+	OrderID     id.ID
+	Preparation Preparation
+}
+
+type PreparationAborted struct {
+	TableID     id.ID
+	OrderID     id.ID
+	Preparation Preparation
+}
+
+// CourseFired is published when the held preparations of a course are released to the kitchen.
+type CourseFired struct {
+	TableID      id.ID
+	Course       int
+	Preparations []Preparation
+}
+
+type BillGenerated struct {
+	Bill Bill
+}
+
+// PaymentReceived is published for every payment made on a bill.
+type PaymentReceived struct {
+	Bill    Bill
+	Payment Payment
+}
+
+// PaymentRefunded is published for every refund of a payment of a bill.
+type PaymentRefunded struct {
+	Bill   Bill
+	Refund Payment
+}
+
+// BillPaid is published once a bill is fully paid.
+type BillPaid struct {
+	Bill Bill
+}
+
+// BillSplit is published when a bill is replaced by its sub-bills.
+type BillSplit struct {
+	Bill  Bill
+	Parts []Bill
+}
+
+// TableSettled is published once every bill of a table is paid.
+type TableSettled struct {
+	TableID id.ID
+}
+
+type MenuItemCreated struct {
+	Item MenuItem
+}
+
+type MenuItemUpdated struct {
+	Item MenuItem
+}
+
+type MenuCategoryCreated struct {
+	Category MenuCategory
+}
+
+type MenuItemAddedToCategory struct {
+	CategoryID id.ID
+	Item       MenuItem
+}
+
+type MenuItemRemovedFromCategory struct {
+	CategoryID id.ID
+	ItemID     id.ID
+}
+
+type DiningTableCreated struct {
+	DiningTable DiningTable
+}
+
+type PromotionCreated struct {
+	Promotion Promotion
+}
+
+// PromotionUpdated is published when a promotion is enabled or disabled.
+type PromotionUpdated struct {
+	Promotion Promotion
+}
+
+// BillDiscounted is published when a manual discount is granted on a bill.
+type BillDiscounted struct {
+	Bill     Bill
+	Discount Discount
+}
+
+type IngredientCreated struct {
+	Ingredient Ingredient
+}
+
+// StockCounted is published when the stock counted of an ingredient replaces the one recorded.
+type StockCounted struct {
+	Ingredient Ingredient
+}
+
+type StockDelivered struct {
+	Ingredient Ingredient
+	Quantity   int
+}
+
+type StockAdjusted struct {
+	Ingredient Ingredient
+	Quantity   int
+	Reason     string
+}
+
+type RecipeSet struct {
+	Recipe Recipe
+}
+
+type StationCreated struct {
+	Station Station
+}
+
+// StationRouted is published when the menu items and categories routed to a station are replaced.
+type StationRouted struct {
+	Station Station
+}
+
+// ReservationBooked is published when a party is booked.
+type ReservationBooked struct {
+	Reservation Reservation
+}
+
+// ReservationUpdated is published when a reservation is assigned a dining table or changes status.
+type ReservationUpdated struct {
+	Reservation Reservation
+}
+
+// WaitlistJoined is published when a walk-in party joins the waitlist.
+type WaitlistJoined struct {
+	Entry WaitlistEntry
+}
+
+// WaitlistUpdated is published when a party of the waitlist is seated or leaves.
+type WaitlistUpdated struct {
+	Entry WaitlistEntry
+}
+
+// PartySuggested is published when a closed table frees a dining table that a waiting party fits.
+type PartySuggested struct {
+	TableID       id.ID
+	DiningTableID id.ID
+	Entry         WaitlistEntry
+}
+
+func (TableOpened) EventName() string                 { return "table.opened" }
+func (TableClosed) EventName() string                 { return "table.closed" }
+func (OrderTaken) EventName() string                  { return "order.taken" }
+func (OrderAborted) EventName() string                { return "order.aborted" }
+func (OrderReady) EventName() string                  { return "order.ready" }
+func (PreparationStarted) EventName() string          { return "preparation.started" }
+func (PreparationFinished) EventName() string         { return "preparation.finished" }
+func (PreparationServed) EventName() string           { return "preparation.served" }
+func (PreparationAborted) EventName() string          { return "preparation.aborted" }
+func (CourseFired) EventName() string                 { return "course.fired" }
+func (BillGenerated) EventName() string               { return "bill.generated" }
+func (PaymentReceived) EventName() string             { return "bill.payment_received" }
+func (PaymentRefunded) EventName() string             { return "bill.payment_refunded" }
+func (BillPaid) EventName() string                    { return "bill.paid" }
+func (BillSplit) EventName() string                   { return "bill.split" }
+func (TableSettled) EventName() string                { return "table.settled" }
+func (MenuItemCreated) EventName() string             { return "menu.item_created" }
+func (MenuItemUpdated) EventName() string             { return "menu.item_updated" }
+func (MenuCategoryCreated) EventName() string         { return "menu.category_created" }
+func (MenuItemAddedToCategory) EventName() string     { return "menu.item_added_to_category" }
+func (MenuItemRemovedFromCategory) EventName() string { return "menu.item_removed_from_category" }
+func (DiningTableCreated) EventName() string          { return "dining_table.created" }
+func (PromotionCreated) EventName() string            { return "promotion.created" }
+func (PromotionUpdated) EventName() string            { return "promotion.updated" }
+func (BillDiscounted) EventName() string              { return "bill.discounted" }
+func (IngredientCreated) EventName() string           { return "inventory.ingredient_created" }
+func (StockCounted) EventName() string                { return "inventory.stock_counted" }
+func (StockDelivered) EventName() string              { return "inventory.stock_delivered" }
+func (StockAdjusted) EventName() string               { return "inventory.stock_adjusted" }
+func (RecipeSet) EventName() string                   { return "inventory.recipe_set" }
+func (StationCreated) EventName() string              { return "station.created" }
+func (StationRouted) EventName() string               { return "station.routed" }
+func (ReservationBooked) EventName() string           { return "reservation.booked" }
+func (ReservationUpdated) EventName() string          { return "reservation.updated" }
+func (WaitlistJoined) EventName() string              { return "waitlist.joined" }
+func (WaitlistUpdated) EventName() string             { return "waitlist.updated" }
+func (PartySuggested) EventName() string              { return "waitlist.party_suggested" }
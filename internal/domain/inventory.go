@@ -0,0 +1,534 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"order_manager/internal/id"
+	"slices"
+	"time"
+)
+
+type Unit string
+
+const (
+	UnitGram       Unit = "g"
+	UnitMillilitre Unit = "ml"
+	UnitPiece      Unit = "piece"
+)
+
+func (u Unit) IsValid() bool {
+	return u == UnitGram || u == UnitMillilitre || u == UnitPiece
+}
+
+// Ingredient is a stock of the kitchen, counted in its unit.
+type Ingredient struct {
+	ID   id.ID
+	Name string
+	Unit Unit
+	// Stock is the quantity left. It goes negative when the kitchen used more than recorded, until the next count.
+	Stock int
+	// LowStockThreshold is the stock at or below which the menu items using the ingredient are made unavailable,
+	// nil when they are never made unavailable automatically.
+	LowStockThreshold *int
+}
+
+func (i Ingredient) IsValid() bool {
+	return i.ID != id.NilID() && i.Name != "" && i.Unit.IsValid() && (i.LowStockThreshold == nil || *i.LowStockThreshold >= 0)
+}
+
+// IsLow reports whether the stock is at or below the low-stock threshold.
+func (i Ingredient) IsLow() bool {
+	return i.LowStockThreshold != nil && i.Stock <= *i.LowStockThreshold
+}
+
+// RecipeLine is the quantity of an ingredient, in its unit, used for a portion of a menu item.
+type RecipeLine struct {
+	IngredientID id.ID
+	Quantity     int
+}
+
+// Recipe lists the ingredients a menu item consumes, a line per ingredient.
+type Recipe struct {
+	MenuItemID id.ID
+	Lines      []RecipeLine
+}
+
+func (r Recipe) IsValid() bool {
+	if r.MenuItemID == id.NilID() {
+		return false
+	}
+
+	for i, line := range r.Lines {
+		if line.IngredientID == id.NilID() || line.Quantity <= 0 {
+			return false
+		}
+
+		if slices.ContainsFunc(r.Lines[:i], func(l RecipeLine) bool { return l.IngredientID == line.IngredientID }) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// StockMovementKind tells what moved the stock of an ingredient.
+type StockMovementKind string
+
+const (
+	StockMovementDelivery StockMovementKind = "delivery"
+	// StockMovementCount is the difference between a stock counted and the one recorded before.
+	StockMovementCount      StockMovementKind = "count"
+	StockMovementAdjustment StockMovementKind = "adjustment"
+	// StockMovementConsumption is the stock taken by a preparation.
+	StockMovementConsumption StockMovementKind = "consumption"
+	// StockMovementRestock is the stock given back by an aborted preparation.
+	StockMovementRestock StockMovementKind = "restock"
+)
+
+func (k StockMovementKind) IsValid() bool {
+	return k == StockMovementDelivery ||
+		k == StockMovementCount ||
+		k == StockMovementAdjustment ||
+		k == StockMovementConsumption ||
+		k == StockMovementRestock
+}
+
+// StockMovement is a change of the stock of an ingredient, recorded along with the stock it changes.
+type StockMovement struct {
+	IngredientID id.ID
+	Kind         StockMovementKind
+	// Delta is the quantity added to the stock, negative when taken from it.
+	Delta int
+	// Reason is why the stock was adjusted. It is also given to the movements reverting those of a change that
+	// failed to be saved, which keep the kind of the movement they revert.
+	Reason string
+	// PreparationID is the preparation consuming or giving back the stock, nil for the other kinds.
+	PreparationID id.ID
+	At            time.Time
+}
+
+func (m StockMovement) IsValid() bool {
+	return m.IngredientID != id.NilID() && m.Kind.IsValid() && (m.Kind != StockMovementAdjustment || m.Reason != "") && !m.At.IsZero()
+}
+
+// StockDeduction is the moment the stock used by a preparation is taken.
+type StockDeduction string
+
+const (
+	// DeductOnOrder takes the stock when the order is taken.
+	DeductOnOrder StockDeduction = "order"
+	// DeductOnPreparation takes the stock when the preparation starts.
+	DeductOnPreparation StockDeduction = "preparation"
+)
+
+// RestockPolicy tells which aborted preparations give their stock back.
+type RestockPolicy string
+
+const (
+	RestockNever RestockPolicy = "never"
+	// RestockUnstarted gives back the stock of the preparations aborted before they started,
+	// the ingredients of the others being considered used.
+	RestockUnstarted RestockPolicy = "unstarted"
+	RestockAlways    RestockPolicy = "always"
+)
+
+// InventoryPolicy controls how the preparations move the stock.
+type InventoryPolicy struct {
+	DeductOn StockDeduction
+	Restock  RestockPolicy
+}
+
+type InventoryRepository interface {
+	SaveIngredient(ctx context.Context, ingredient Ingredient) error
+	FindIngredient(ctx context.Context, id id.ID) (Ingredient, error)
+	FindIngredients(ctx context.Context, ids []id.ID) ([]Ingredient, error)
+	FindAllIngredients(ctx context.Context) ([]Ingredient, error)
+	// AdjustStock atomically adds the deltas of the movements to the stock of their ingredients and records them,
+	// then returns the adjusted ingredients, each once in the order of its first movement.
+	// Possible errors:
+	// - EINVALID if a movement is invalid, nothing being adjusted.
+	// - ENOTFOUND if an ingredient could not be found, nothing being adjusted.
+	AdjustStock(ctx context.Context, movements []StockMovement) ([]Ingredient, error)
+	// SetStock sets the stock of an ingredient along with the count movement of its difference with the stock it replaces,
+	// and returns the ingredient.
+	// Possible errors:
+	// - ENOTFOUND if the ingredient could not be found.
+	SetStock(ctx context.Context, ingredientID id.ID, stock int, at time.Time) (Ingredient, error)
+	// FindStockMovements returns the movements of the stock of an ingredient, oldest first.
+	FindStockMovements(ctx context.Context, ingredientID id.ID) ([]StockMovement, error)
+	// SaveRecipe replaces the recipe of a menu item, a recipe without lines removing it.
+	SaveRecipe(ctx context.Context, recipe Recipe) error
+	// FindRecipes returns the recipes of the menu items that have one.
+	FindRecipes(ctx context.Context, menuItemIDs []id.ID) ([]Recipe, error)
+	// FindRecipesByIngredient returns the recipes using an ingredient.
+	FindRecipesByIngredient(ctx context.Context, ingredientID id.ID) ([]Recipe, error)
+}
+
+type InventoryService struct {
+	repo   InventoryRepository
+	menu   MenuRepository
+	events EventPublisher
+	policy InventoryPolicy
+	clock  Clock
+}
+
+// NewInventoryService creates a new inventory service.
+// The service is responsible for the stock of the ingredients and the recipes of the menu items,
+// it takes the stock on orders and gives back that of the unstarted aborted preparations unless told otherwise.
+func NewInventoryService(repo InventoryRepository, menu MenuRepository, events EventPublisher) *InventoryService {
+	return &InventoryService{
+		repo:   repo,
+		menu:   menu,
+		events: events,
+		policy: InventoryPolicy{DeductOn: DeductOnOrder, Restock: RestockUnstarted},
+		clock:  SystemClock{},
+	}
+}
+
+// WithPolicy sets when the preparations take the stock and which aborted ones give it back.
+func (s *InventoryService) WithPolicy(policy InventoryPolicy) *InventoryService {
+	s.policy = policy
+	return s
+}
+
+// WithClock replaces the clock timing the stock movements.
+func (s *InventoryService) WithClock(clock Clock) *InventoryService {
+	s.clock = clock
+	return s
+}
+
+// CreateIngredient registers an ingredient with its initial stock, its ID is assigned by the service.
+// Possible errors:
+// - EINVALID if the ingredient is invalid or the stock is negative.
+// - Any error returned by the repository when saving the ingredient.
+func (s *InventoryService) CreateIngredient(ctx context.Context, name string, unit Unit, stock int, lowStockThreshold *int) (Ingredient, error) {
+	ingredient := Ingredient{ID: id.New(), Name: name, Unit: unit, Stock: stock, LowStockThreshold: lowStockThreshold}
+	if !ingredient.IsValid() || stock < 0 {
+		return Ingredient{}, Errorf(EINVALID, "invalid ingredient")
+	}
+
+	if err := s.repo.SaveIngredient(ctx, ingredient); err != nil {
+		return Ingredient{}, err
+	}
+
+	publish(ctx, s.events, IngredientCreated{Ingredient: ingredient})
+
+	return ingredient, nil
+}
+
+// FindIngredient returns an ingredient with its stock.
+// Possible errors:
+// - ENOTFOUND if the ingredient could not be found.
+func (s *InventoryService) FindIngredient(ctx context.Context, ingredientID id.ID) (Ingredient, error) {
+	return s.repo.FindIngredient(ctx, ingredientID)
+}
+
+// FindAllIngredients returns all the ingredients with their stock.
+// Possible errors:
+// - Any error returned by the repository when fetching the ingredients.
+func (s *InventoryService) FindAllIngredients(ctx context.Context) ([]Ingredient, error) {
+	return s.repo.FindAllIngredients(ctx)
+}
+
+// CountStock records the stock counted of an ingredient, replacing the one recorded.
+// Possible errors:
+// - EINVALID if the stock is negative.
+// - ENOTFOUND if the ingredient could not be found.
+// - Any error returned by the repositories when saving the stock or the menu items it makes unavailable.
+func (s *InventoryService) CountStock(ctx context.Context, ingredientID id.ID, stock int) (Ingredient, error) {
+	if stock < 0 {
+		return Ingredient{}, Errorf(EINVALID, "stock must not be negative")
+	}
+
+	ingredient, err := s.repo.SetStock(ctx, ingredientID, stock, s.clock.Now())
+	if err != nil {
+		return Ingredient{}, err
+	}
+
+	publish(ctx, s.events, StockCounted{Ingredient: ingredient})
+
+	if err := s.markLowStock(ctx, []Ingredient{ingredient}); err != nil {
+		return Ingredient{}, err
+	}
+
+	return ingredient, nil
+}
+
+// ReceiveDelivery adds a quantity delivered to the stock of an ingredient.
+// The menu items made unavailable on low stock are not made available again, see MenuService.MarkMenuItemAvailable.
+// Possible errors:
+// - EINVALID if the quantity is not positive.
+// - ENOTFOUND if the ingredient could not be found.
+// - Any error returned by the repository when saving the stock.
+func (s *InventoryService) ReceiveDelivery(ctx context.Context, ingredientID id.ID, quantity int) (Ingredient, error) {
+	if quantity <= 0 {
+		return Ingredient{}, Errorf(EINVALID, "delivered quantity must be positive")
+	}
+
+	movement := StockMovement{IngredientID: ingredientID, Kind: StockMovementDelivery, Delta: quantity, At: s.clock.Now()}
+	ingredients, err := s.repo.AdjustStock(ctx, []StockMovement{movement})
+	if err != nil {
+		return Ingredient{}, err
+	}
+
+	publish(ctx, s.events, StockDelivered{Ingredient: ingredients[0], Quantity: quantity})
+
+	return ingredients[0], nil
+}
+
+// AdjustStock corrects the stock of an ingredient by a quantity, negative for waste or losses, along with a reason.
+// Possible errors:
+// - EINVALID if the quantity is 0 or the reason is empty.
+// - ENOTFOUND if the ingredient could not be found.
+// - Any error returned by the repositories when saving the stock or the menu items it makes unavailable.
+func (s *InventoryService) AdjustStock(ctx context.Context, ingredientID id.ID, quantity int, reason string) (Ingredient, error) {
+	if quantity == 0 {
+		return Ingredient{}, Errorf(EINVALID, "adjusted quantity must not be 0")
+	}
+
+	if reason == "" {
+		return Ingredient{}, Errorf(EINVALID, "a stock adjustment needs a reason")
+	}
+
+	movement := StockMovement{IngredientID: ingredientID, Kind: StockMovementAdjustment, Delta: quantity, Reason: reason, At: s.clock.Now()}
+	ingredients, err := s.repo.AdjustStock(ctx, []StockMovement{movement})
+	if err != nil {
+		return Ingredient{}, err
+	}
+
+	publish(ctx, s.events, StockAdjusted{Ingredient: ingredients[0], Quantity: quantity, Reason: reason})
+
+	if err := s.markLowStock(ctx, ingredients); err != nil {
+		return Ingredient{}, err
+	}
+
+	return ingredients[0], nil
+}
+
+// FindStockMovements returns the movements of the stock of an ingredient, oldest first.
+// Possible errors:
+// - ENOTFOUND if the ingredient could not be found.
+// - Any error returned by the repository when fetching the movements.
+func (s *InventoryService) FindStockMovements(ctx context.Context, ingredientID id.ID) ([]StockMovement, error) {
+	if _, err := s.repo.FindIngredient(ctx, ingredientID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindStockMovements(ctx, ingredientID)
+}
+
+// SetRecipe replaces the ingredients a menu item consumes, no lines removing its recipe.
+// Possible errors:
+// - EINVALID if a line has no ingredient or a quantity that is not positive, or if an ingredient is repeated.
+// - ENOTFOUND if the menu item or an ingredient could not be found.
+// - Any error returned by the repository when saving the recipe.
+func (s *InventoryService) SetRecipe(ctx context.Context, menuItemID id.ID, lines []RecipeLine) (Recipe, error) {
+	if lines == nil {
+		lines = make([]RecipeLine, 0)
+	}
+
+	recipe := Recipe{MenuItemID: menuItemID, Lines: lines}
+	if !recipe.IsValid() {
+		return Recipe{}, Errorf(EINVALID, "invalid recipe")
+	}
+
+	if _, err := s.menu.FindItem(ctx, menuItemID); err != nil {
+		return Recipe{}, err
+	}
+
+	ingredientIDs := make([]id.ID, 0, len(lines))
+	for _, line := range lines {
+		ingredientIDs = append(ingredientIDs, line.IngredientID)
+	}
+
+	if _, err := s.repo.FindIngredients(ctx, ingredientIDs); err != nil {
+		return Recipe{}, err
+	}
+
+	if err := s.repo.SaveRecipe(ctx, recipe); err != nil {
+		return Recipe{}, err
+	}
+
+	publish(ctx, s.events, RecipeSet{Recipe: recipe})
+
+	return recipe, nil
+}
+
+// FindRecipe returns the recipe of a menu item, without lines when it has none.
+// Possible errors:
+// - ENOTFOUND if the menu item could not be found.
+func (s *InventoryService) FindRecipe(ctx context.Context, menuItemID id.ID) (Recipe, error) {
+	if _, err := s.menu.FindItem(ctx, menuItemID); err != nil {
+		return Recipe{}, err
+	}
+
+	recipes, err := s.repo.FindRecipes(ctx, []id.ID{menuItemID})
+	if err != nil {
+		return Recipe{}, err
+	}
+
+	if len(recipes) == 0 {
+		return Recipe{MenuItemID: menuItemID, Lines: make([]RecipeLine, 0)}, nil
+	}
+
+	return recipes[0], nil
+}
+
+// consume takes the stock used by the preparations and makes unavailable the menu items of the ingredients left low.
+// It returns a function giving the stock back, the menu items staying unavailable.
+func (s *InventoryService) consume(ctx context.Context, preparations []Preparation) (func(context.Context) error, error) {
+	movements, err := s.movements(ctx, preparations, StockMovementConsumption, -1)
+	if err != nil || len(movements) == 0 {
+		return noUndo, err
+	}
+
+	ingredients, err := s.repo.AdjustStock(ctx, movements)
+	if err != nil {
+		return noUndo, err
+	}
+
+	undo := s.revert(movements)
+	if err := s.markLowStock(ctx, ingredients); err != nil {
+		return noUndo, errors.Join(err, undo(ctx))
+	}
+
+	return undo, nil
+}
+
+// restock gives back the stock used by the preparations, following the restock policy.
+// The preparations are given with the status they had before they were aborted.
+// It returns a function taking the stock again.
+func (s *InventoryService) restock(ctx context.Context, preparations []Preparation) (func(context.Context) error, error) {
+	restocked := make([]Preparation, 0, len(preparations))
+	for _, prep := range preparations {
+		if s.restocks(prep) {
+			restocked = append(restocked, prep)
+		}
+	}
+
+	movements, err := s.movements(ctx, restocked, StockMovementRestock, 1)
+	if err != nil || len(movements) == 0 {
+		return noUndo, err
+	}
+
+	if _, err := s.repo.AdjustStock(ctx, movements); err != nil {
+		return noUndo, err
+	}
+
+	return s.revert(movements), nil
+}
+
+// restocks reports whether aborting a preparation in its current status gives its stock back.
+func (s *InventoryService) restocks(prep Preparation) bool {
+	if !prep.isAbortable() {
+		return false
+	}
+
+	unstarted := prep.Status == PreparationStatusHeld || prep.Status == PreparationStatusPending
+	deducted := s.policy.DeductOn == DeductOnOrder || !unstarted
+	switch s.policy.Restock {
+	case RestockAlways:
+		return deducted
+	case RestockUnstarted:
+		return deducted && unstarted
+	default:
+		return false
+	}
+}
+
+// movements returns the movements of the ingredients used by the preparations, one per preparation and recipe line,
+// of the given kind and with the recipe quantities multiplied by sign.
+func (s *InventoryService) movements(ctx context.Context, preparations []Preparation, kind StockMovementKind, sign int) ([]StockMovement, error) {
+	movements := make([]StockMovement, 0)
+	if len(preparations) == 0 {
+		return movements, nil
+	}
+
+	menuItemIDs := make([]id.ID, 0, len(preparations))
+	for _, prep := range preparations {
+		if !slices.Contains(menuItemIDs, prep.MenuItem.ID) {
+			menuItemIDs = append(menuItemIDs, prep.MenuItem.ID)
+		}
+	}
+
+	recipes, err := s.repo.FindRecipes(ctx, menuItemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	for _, prep := range preparations {
+		for _, recipe := range recipes {
+			if recipe.MenuItemID != prep.MenuItem.ID {
+				continue
+			}
+
+			for _, line := range recipe.Lines {
+				movements = append(movements, StockMovement{
+					IngredientID:  line.IngredientID,
+					Kind:          kind,
+					Delta:         sign * line.Quantity,
+					PreparationID: prep.ID,
+					At:            now,
+				})
+			}
+		}
+	}
+
+	return movements, nil
+}
+
+// revert returns a function recording the movements giving back the deltas of the movements.
+func (s *InventoryService) revert(movements []StockMovement) func(context.Context) error {
+	return func(ctx context.Context) error {
+		now := s.clock.Now()
+		reverse := make([]StockMovement, 0, len(movements))
+		for _, movement := range movements {
+			movement.Delta = -movement.Delta
+			movement.Reason = "reverted"
+			movement.At = now
+			reverse = append(reverse, movement)
+		}
+
+		_, err := s.repo.AdjustStock(ctx, reverse)
+		return err
+	}
+}
+
+// markLowStock makes unavailable the menu items using the ingredients whose stock is low.
+func (s *InventoryService) markLowStock(ctx context.Context, ingredients []Ingredient) error {
+	for _, ingredient := range ingredients {
+		if !ingredient.IsLow() {
+			continue
+		}
+
+		recipes, err := s.repo.FindRecipesByIngredient(ctx, ingredient.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, recipe := range recipes {
+			item, err := s.menu.FindItem(ctx, recipe.MenuItemID)
+			if err != nil {
+				return err
+			}
+
+			if item.Unavailable {
+				continue
+			}
+
+			item, err = s.menu.SetAvailability(ctx, item.ID, true, nil)
+			if err != nil {
+				return err
+			}
+
+			publish(ctx, s.events, MenuItemUpdated{Item: item})
+		}
+	}
+
+	return nil
+}
+
+func noUndo(context.Context) error {
+	return nil
+}
@@ -0,0 +1,356 @@
+package domain_test
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"order_manager/internal/inmem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func threshold(n int) *int {
+	return &n
+}
+
+func TestCreateIngredient(t *testing.T) {
+	inventoryService := domain.NewInventoryService(inmem.NewInventory(), inmem.NewMenu(), nil)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		ingredient, err := inventoryService.CreateIngredient(context.Background(), "flour", domain.UnitGram, 5000, threshold(500))
+
+		require.NoError(t, err, "create ingredient failed")
+		assert.NotEqual(t, id.NilID(), ingredient.ID, "generated ingredient ID is nil")
+		assert.False(t, ingredient.IsLow(), "ingredient is low")
+
+		saved, err := inventoryService.FindIngredient(context.Background(), ingredient.ID)
+		require.NoError(t, err)
+		assert.Equal(t, ingredient, saved, "ingredient not correctly saved")
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+
+		tt := []struct {
+			testName  string
+			name      string
+			unit      domain.Unit
+			stock     int
+			threshold *int
+		}{
+			{testName: "empty name", name: "", unit: domain.UnitGram},
+			{testName: "unknown unit", name: "flour", unit: "kg"},
+			{testName: "negative stock", name: "flour", unit: domain.UnitGram, stock: -1},
+			{testName: "negative threshold", name: "flour", unit: domain.UnitGram, threshold: threshold(-1)},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				_, err := inventoryService.CreateIngredient(context.Background(), tc.name, tc.unit, tc.stock, tc.threshold)
+
+				assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+	})
+}
+
+func TestStockMovements(t *testing.T) {
+	publisher := &recordingPublisher{}
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	inventoryService := domain.NewInventoryService(inmem.NewInventory(), inmem.NewMenu(), publisher).WithClock(fixedClock(now))
+	ctx := context.Background()
+
+	ingredient, err := inventoryService.CreateIngredient(ctx, "milk", domain.UnitMillilitre, 1000, nil)
+	require.NoError(t, err, "initial setup failed")
+
+	ingredient, err = inventoryService.ReceiveDelivery(ctx, ingredient.ID, 2000)
+	require.NoError(t, err, "receive delivery failed")
+	assert.Equal(t, 3000, ingredient.Stock, "delivery not added")
+
+	ingredient, err = inventoryService.AdjustStock(ctx, ingredient.ID, -250, "spilled")
+	require.NoError(t, err, "adjust stock failed")
+	assert.Equal(t, 2750, ingredient.Stock, "adjustment not added")
+
+	ingredient, err = inventoryService.CountStock(ctx, ingredient.ID, 2600)
+	require.NoError(t, err, "count stock failed")
+	assert.Equal(t, 2600, ingredient.Stock, "count not recorded")
+
+	tt := []struct {
+		testName string
+		move     func() error
+		errCode  string
+	}{
+		{
+			testName: "delivery of nothing",
+			move: func() error {
+				_, err := inventoryService.ReceiveDelivery(ctx, ingredient.ID, 0)
+				return err
+			},
+			errCode: domain.EINVALID,
+		},
+		{
+			testName: "adjustment without reason",
+			move: func() error {
+				_, err := inventoryService.AdjustStock(ctx, ingredient.ID, -1, "")
+				return err
+			},
+			errCode: domain.EINVALID,
+		},
+		{
+			testName: "adjustment of nothing",
+			move: func() error {
+				_, err := inventoryService.AdjustStock(ctx, ingredient.ID, 0, "nothing")
+				return err
+			},
+			errCode: domain.EINVALID,
+		},
+		{
+			testName: "negative count",
+			move: func() error {
+				_, err := inventoryService.CountStock(ctx, ingredient.ID, -1)
+				return err
+			},
+			errCode: domain.EINVALID,
+		},
+		{
+			testName: "unknown ingredient",
+			move: func() error {
+				_, err := inventoryService.ReceiveDelivery(ctx, id.New(), 10)
+				return err
+			},
+			errCode: domain.ENOTFOUND,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			assert.Equal(t, tc.errCode, domain.ErrorCode(tc.move()), "invalid error code")
+		})
+	}
+
+	assert.Equal(t, []string{
+		"inventory.ingredient_created",
+		"inventory.stock_delivered",
+		"inventory.stock_adjusted",
+		"inventory.stock_counted",
+	}, publisher.names())
+
+	movements, err := inventoryService.FindStockMovements(ctx, ingredient.ID)
+	require.NoError(t, err, "find stock movements failed")
+	assert.Equal(t, []domain.StockMovement{
+		{IngredientID: ingredient.ID, Kind: domain.StockMovementDelivery, Delta: 2000, At: now},
+		{IngredientID: ingredient.ID, Kind: domain.StockMovementAdjustment, Delta: -250, Reason: "spilled", At: now},
+		{IngredientID: ingredient.ID, Kind: domain.StockMovementCount, Delta: -150, At: now},
+	}, movements, "movements not recorded with the stock")
+
+	_, err = inventoryService.FindStockMovements(ctx, id.New())
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
+}
+
+func TestSetRecipe(t *testing.T) {
+	menuRepo := inmem.NewMenu()
+	inventoryService := domain.NewInventoryService(inmem.NewInventory(), menuRepo, nil)
+	ctx := context.Background()
+
+	item := domain.MenuItem{ID: id.New(), Name: "pancakes", Price: 800}
+	require.NoError(t, menuRepo.SaveItem(ctx, item), "initial setup failed")
+	flour, err := inventoryService.CreateIngredient(ctx, "flour", domain.UnitGram, 5000, nil)
+	require.NoError(t, err, "initial setup failed")
+	eggs, err := inventoryService.CreateIngredient(ctx, "eggs", domain.UnitPiece, 60, nil)
+	require.NoError(t, err, "initial setup failed")
+
+	t.Run("Success", func(t *testing.T) {
+		lines := []domain.RecipeLine{{IngredientID: flour.ID, Quantity: 120}, {IngredientID: eggs.ID, Quantity: 2}}
+		recipe, err := inventoryService.SetRecipe(ctx, item.ID, lines)
+		require.NoError(t, err, "set recipe failed")
+
+		saved, err := inventoryService.FindRecipe(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, recipe, saved, "recipe not correctly saved")
+
+		_, err = inventoryService.SetRecipe(ctx, item.ID, nil)
+		require.NoError(t, err, "remove recipe failed")
+
+		saved, err = inventoryService.FindRecipe(ctx, item.ID)
+		require.NoError(t, err)
+		assert.Empty(t, saved.Lines, "recipe not removed")
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		tt := []struct {
+			testName   string
+			menuItemID id.ID
+			lines      []domain.RecipeLine
+			errCode    string
+		}{
+			{testName: "unknown menu item", menuItemID: id.New(), lines: []domain.RecipeLine{{IngredientID: flour.ID, Quantity: 120}}, errCode: domain.ENOTFOUND},
+			{testName: "unknown ingredient", menuItemID: item.ID, lines: []domain.RecipeLine{{IngredientID: id.New(), Quantity: 120}}, errCode: domain.ENOTFOUND},
+			{testName: "no quantity", menuItemID: item.ID, lines: []domain.RecipeLine{{IngredientID: flour.ID, Quantity: 0}}, errCode: domain.EINVALID},
+			{
+				testName:   "repeated ingredient",
+				menuItemID: item.ID,
+				lines:      []domain.RecipeLine{{IngredientID: flour.ID, Quantity: 120}, {IngredientID: flour.ID, Quantity: 10}},
+				errCode:    domain.EINVALID,
+			},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				_, err := inventoryService.SetRecipe(ctx, tc.menuItemID, tc.lines)
+
+				assert.Equal(t, tc.errCode, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+	})
+}
+
+func TestPreparationsMoveStock(t *testing.T) {
+	tt := []struct {
+		testName string
+		policy   domain.InventoryPolicy
+		// The stock of the soup, of 100 g, after two were ordered, one started and then both aborted.
+		afterOrder int
+		afterStart int
+		afterAbort int
+	}{
+		{
+			testName:   "deducted on order, unstarted restocked",
+			policy:     domain.InventoryPolicy{DeductOn: domain.DeductOnOrder, Restock: domain.RestockUnstarted},
+			afterOrder: 800, afterStart: 800, afterAbort: 900,
+		},
+		{
+			testName:   "deducted on order, always restocked",
+			policy:     domain.InventoryPolicy{DeductOn: domain.DeductOnOrder, Restock: domain.RestockAlways},
+			afterOrder: 800, afterStart: 800, afterAbort: 1000,
+		},
+		{
+			testName:   "deducted on order, never restocked",
+			policy:     domain.InventoryPolicy{DeductOn: domain.DeductOnOrder, Restock: domain.RestockNever},
+			afterOrder: 800, afterStart: 800, afterAbort: 800,
+		},
+		{
+			testName:   "deducted on preparation, always restocked",
+			policy:     domain.InventoryPolicy{DeductOn: domain.DeductOnPreparation, Restock: domain.RestockAlways},
+			afterOrder: 1000, afterStart: 900, afterAbort: 1000,
+		},
+		{
+			testName:   "deducted on preparation, unstarted restocked",
+			policy:     domain.InventoryPolicy{DeductOn: domain.DeductOnPreparation, Restock: domain.RestockUnstarted},
+			afterOrder: 1000, afterStart: 900, afterAbort: 900,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			ctx := context.Background()
+			menuRepo := inmem.NewMenu()
+			tableRepo := inmem.NewTable()
+			inventoryService := domain.NewInventoryService(inmem.NewInventory(), menuRepo, nil).WithPolicy(tc.policy)
+			tableService := domain.NewTableService(tableRepo, inmem.NewDiningTable(), nil).WithMenu(menuRepo).WithInventory(inventoryService)
+
+			soup := domain.MenuItem{ID: id.New(), Name: "soup", Price: 500}
+			require.NoError(t, menuRepo.SaveItem(ctx, soup), "initial setup failed")
+			stock, err := inventoryService.CreateIngredient(ctx, "stock", domain.UnitMillilitre, 1000, nil)
+			require.NoError(t, err, "initial setup failed")
+			_, err = inventoryService.SetRecipe(ctx, soup.ID, []domain.RecipeLine{{IngredientID: stock.ID, Quantity: 100}})
+			require.NoError(t, err, "initial setup failed")
+			table := domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened}
+			require.NoError(t, tableRepo.Save(ctx, table), "initial setup failed")
+
+			stockLeft := func() int {
+				ingredient, err := inventoryService.FindIngredient(ctx, stock.ID)
+				require.NoError(t, err)
+				return ingredient.Stock
+			}
+
+			order, err := tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: soup}, {MenuItem: soup}})
+			require.NoError(t, err, "take order failed")
+			assert.Equal(t, tc.afterOrder, stockLeft(), "invalid stock after the order")
+
+			require.NoError(t, tableService.StartPreparation(ctx, order.Preparations[0].ID), "start preparation failed")
+			assert.Equal(t, tc.afterStart, stockLeft(), "invalid stock after the start")
+
+			require.NoError(t, tableService.AbortOrder(ctx, order.ID), "abort order failed")
+			assert.Equal(t, tc.afterAbort, stockLeft(), "invalid stock after the abort")
+
+			movements, err := inventoryService.FindStockMovements(ctx, stock.ID)
+			require.NoError(t, err)
+			moved := 0
+			for _, movement := range movements {
+				assert.Contains(t, []id.ID{order.Preparations[0].ID, order.Preparations[1].ID}, movement.PreparationID, "movement not linked to its preparation")
+				moved += movement.Delta
+			}
+			assert.Equal(t, tc.afterAbort-1000, moved, "movements do not add up to the stock moved")
+		})
+	}
+}
+
+func TestLowStockMakesItemsUnavailable(t *testing.T) {
+	ctx := context.Background()
+	menuRepo := inmem.NewMenu()
+	tableRepo := inmem.NewTable()
+	publisher := &recordingPublisher{}
+	inventoryService := domain.NewInventoryService(inmem.NewInventory(), menuRepo, publisher)
+	tableService := domain.NewTableService(tableRepo, inmem.NewDiningTable(), nil).WithMenu(menuRepo).WithInventory(inventoryService)
+
+	steaks := 5
+	burger := domain.MenuItem{ID: id.New(), Name: "burger", Price: 1200}
+	steak := domain.MenuItem{ID: id.New(), Name: "steak", Price: 2400, Portions: &steaks}
+	salad := domain.MenuItem{ID: id.New(), Name: "salad", Price: 900}
+	for _, item := range []domain.MenuItem{burger, steak, salad} {
+		require.NoError(t, menuRepo.SaveItem(ctx, item), "initial setup failed")
+	}
+
+	beef, err := inventoryService.CreateIngredient(ctx, "beef", domain.UnitGram, 1000, threshold(400))
+	require.NoError(t, err, "initial setup failed")
+	lettuce, err := inventoryService.CreateIngredient(ctx, "lettuce", domain.UnitGram, 1000, nil)
+	require.NoError(t, err, "initial setup failed")
+	_, err = inventoryService.SetRecipe(ctx, burger.ID, []domain.RecipeLine{{IngredientID: beef.ID, Quantity: 200}, {IngredientID: lettuce.ID, Quantity: 20}})
+	require.NoError(t, err, "initial setup failed")
+	_, err = inventoryService.SetRecipe(ctx, steak.ID, []domain.RecipeLine{{IngredientID: beef.ID, Quantity: 300}})
+	require.NoError(t, err, "initial setup failed")
+	_, err = inventoryService.SetRecipe(ctx, salad.ID, []domain.RecipeLine{{IngredientID: lettuce.ID, Quantity: 150}})
+	require.NoError(t, err, "initial setup failed")
+
+	table := domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened}
+	require.NoError(t, tableRepo.Save(ctx, table), "initial setup failed")
+
+	available := func(itemID id.ID) bool {
+		item, err := menuRepo.FindItem(ctx, itemID)
+		require.NoError(t, err)
+		return item.IsAvailable()
+	}
+
+	_, err = tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: burger}, {MenuItem: steak}})
+	require.NoError(t, err, "take order failed")
+	assert.True(t, available(burger.ID), "beef is not low yet")
+
+	_, err = tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: burger}})
+	require.NoError(t, err, "take order failed")
+	assert.False(t, available(burger.ID), "burger still available on low beef")
+	assert.False(t, available(steak.ID), "steak still available on low beef")
+	assert.True(t, available(salad.ID), "salad made unavailable without beef")
+
+	steak, err = menuRepo.FindItem(ctx, steak.ID)
+	require.NoError(t, err)
+	require.NotNil(t, steak.Portions)
+	assert.Equal(t, 4, *steak.Portions, "portions left changed by the low stock")
+
+	// The unavailable items read from the menu can no longer be ordered.
+	burger, err = menuRepo.FindItem(ctx, burger.ID)
+	require.NoError(t, err)
+	_, err = tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: burger}})
+	assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "invalid error code")
+
+	// A delivery does not make them available again.
+	_, err = inventoryService.ReceiveDelivery(ctx, beef.ID, 5000)
+	require.NoError(t, err, "receive delivery failed")
+	assert.False(t, available(burger.ID), "burger made available by the delivery")
+
+	assert.Contains(t, publisher.names(), "menu.item_updated")
+}
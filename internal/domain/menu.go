@@ -16,10 +16,113 @@ type MenuItem struct {
 	ID    id.ID
 	Name  string
 	Price int
+	// TaxCategory selects the tax rate of the item, the items of no category are not taxed.
+	TaxCategory    string
+	ModifierGroups []ModifierGroup
+	// Unavailable is set when the kitchen ran out of the item, it is "86'd" and cannot be ordered.
+	Unavailable bool
+	// Portions is the number of portions left, taken by every order of the item, nil when they are not counted.
+	Portions *int
 }
 
 func (i MenuItem) IsValid() bool {
-	return i.ID != id.NilID() && i.Name != "" && i.Price >= 0
+	isValid := i.ID != id.NilID() && i.Name != "" && i.Price >= 0 && (i.Portions == nil || *i.Portions >= 0)
+
+	for _, group := range i.ModifierGroups {
+		if !group.IsValid() {
+			return false
+		}
+	}
+
+	return isValid
+}
+
+// IsAvailable reports whether the item can be ordered, that is it is not marked unavailable and has portions left.
+func (i MenuItem) IsAvailable() bool {
+	return !i.Unavailable && (i.Portions == nil || *i.Portions > 0)
+}
+
+// ModifierGroup is a set of options offered with a menu item, such as the cooking of a burger.
+// Between MinSelections and MaxSelections options must be chosen when ordering the item.
+type ModifierGroup struct {
+	ID            id.ID
+	Name          string
+	MinSelections int
+	MaxSelections int
+	Options       []ModifierOption
+}
+
+// ModifierOption is a choice of a modifier group, changing the item price by PriceDelta.
+type ModifierOption struct {
+	ID         id.ID
+	Name       string
+	PriceDelta int
+}
+
+func (g ModifierGroup) IsValid() bool {
+	isValid := g.ID != id.NilID() &&
+		g.Name != "" &&
+		g.MinSelections >= 0 &&
+		g.MaxSelections >= 1 &&
+		g.MinSelections <= g.MaxSelections &&
+		g.MaxSelections <= len(g.Options)
+
+	for i, option := range g.Options {
+		if option.ID == id.NilID() || option.Name == "" {
+			return false
+		}
+
+		if slices.ContainsFunc(g.Options[:i], func(o ModifierOption) bool { return o.ID == option.ID }) {
+			return false
+		}
+	}
+
+	return isValid
+}
+
+// SelectModifiers returns the modifiers matching the chosen option IDs,
+// in the order the groups and options are offered with the item.
+// Possible errors:
+// - EINVALID if an option is not offered with the item or chosen twice.
+// - EINVALID if the number of options chosen in a group is out of its bounds.
+func (i MenuItem) SelectModifiers(optionIDs []id.ID) ([]Modifier, error) {
+	for idx, optionID := range optionIDs {
+		if slices.Contains(optionIDs[:idx], optionID) {
+			return nil, Errorf(EINVALID, "option %s is chosen twice for %s", optionID, i.Name)
+		}
+
+		offered := slices.ContainsFunc(i.ModifierGroups, func(g ModifierGroup) bool {
+			return slices.ContainsFunc(g.Options, func(o ModifierOption) bool { return o.ID == optionID })
+		})
+		if !offered {
+			return nil, Errorf(EINVALID, "option %s is not offered with %s", optionID, i.Name)
+		}
+	}
+
+	var modifiers []Modifier
+	for _, group := range i.ModifierGroups {
+		selected := 0
+		for _, option := range group.Options {
+			if !slices.Contains(optionIDs, option.ID) {
+				continue
+			}
+
+			selected++
+			modifiers = append(modifiers, Modifier{
+				GroupID:    group.ID,
+				GroupName:  group.Name,
+				OptionID:   option.ID,
+				OptionName: option.Name,
+				PriceDelta: option.PriceDelta,
+			})
+		}
+
+		if selected < group.MinSelections || selected > group.MaxSelections {
+			return nil, Errorf(EINVALID, "%s of %s requires between %d and %d options, got %d", group.Name, i.Name, group.MinSelections, group.MaxSelections, selected)
+		}
+	}
+
+	return modifiers, nil
 }
 
 func (c MenuCategory) IsValid() bool {
@@ -37,19 +140,46 @@ func (c MenuCategory) IsValid() bool {
 type MenuRepository interface {
 	SaveItem(ctx context.Context, item MenuItem) error
 	FindItem(ctx context.Context, id id.ID) (MenuItem, error)
+	// FindItems finds the items with the given IDs, once each even when an ID is repeated.
 	FindItems(ctx context.Context, ids []id.ID) ([]MenuItem, error)
 	FindAllItems(ctx context.Context) ([]MenuItem, error)
+	// TakePortions atomically takes portions of menu items, a number per item ID.
+	// The items must be available and have enough portions left when they are counted, otherwise nothing is taken.
+	// Possible errors:
+	// - ENOTFOUND if an item could not be found.
+	// - ECONFLICT if an item is unavailable or has fewer portions left than taken.
+	TakePortions(ctx context.Context, portions map[id.ID]int) error
+	// ReturnPortions gives back portions taken of menu items, a number per item ID.
+	// Possible errors:
+	// - ENOTFOUND if an item could not be found.
+	ReturnPortions(ctx context.Context, portions map[id.ID]int) error
+	// SetAvailability atomically marks a menu item available or not and returns it, nothing else of the item is written.
+	// Making the item available replaces its portions left by portions, nil not counting them.
+	// Making it unavailable leaves them as they are, so that the portions taken meanwhile are not given back.
+	// Possible errors:
+	// - ENOTFOUND if the item could not be found.
+	SetAvailability(ctx context.Context, itemID id.ID, unavailable bool, portions *int) (MenuItem, error)
 
 	SaveCategory(ctx context.Context, category MenuCategory) error
 	FindCategory(ctx context.Context, id id.ID) (MenuCategory, error)
+	// FindAllCategories returns the categories ordered by name.
+	FindAllCategories(ctx context.Context) ([]MenuCategory, error)
 }
 
 type MenuService struct {
-	repo MenuRepository
+	repo   MenuRepository
+	events EventPublisher
+	tax    *TaxPolicy
+}
+
+func NewMenuService(repo MenuRepository, events EventPublisher) *MenuService {
+	return &MenuService{repo: repo, events: events}
 }
 
-func NewMenuService(repo MenuRepository) *MenuService {
-	return &MenuService{repo: repo}
+// WithTaxPolicy makes the service only accept the tax categories of the policy on the menu items.
+func (s *MenuService) WithTaxPolicy(policy TaxPolicy) *MenuService {
+	s.tax = &policy
+	return s
 }
 
 func (s *MenuService) FindMenuItems(ctx context.Context, itemIDs []id.ID) ([]MenuItem, error) {
@@ -60,6 +190,14 @@ func (s *MenuService) FindAllMenuItems(ctx context.Context) ([]MenuItem, error)
 	return s.repo.FindAllItems(ctx)
 }
 
+func (s *MenuService) FindCategory(ctx context.Context, categoryID id.ID) (MenuCategory, error) {
+	return s.repo.FindCategory(ctx, categoryID)
+}
+
+func (s *MenuService) FindAllCategories(ctx context.Context) ([]MenuCategory, error) {
+	return s.repo.FindAllCategories(ctx)
+}
+
 func (s *MenuService) CreateCategory(ctx context.Context, name string) (MenuCategory, error) {
 	category := MenuCategory{
 		ID:        id.New(),
@@ -72,28 +210,146 @@ func (s *MenuService) CreateCategory(ctx context.Context, name string) (MenuCate
 		return MenuCategory{}, err
 	}
 
+	publish(ctx, s.events, MenuCategoryCreated{Category: category})
+
 	return category, nil
 }
 
-func (s *MenuService) CreateMenuItem(ctx context.Context, name string, price int) (MenuItem, error) {
+// CreateMenuItem adds an item to the menu, taxed according to its tax category.
+// Possible errors:
+// - EINVALID if the item has no name or a negative price.
+// - EINVALID if the tax category is not one of the tax policy of the service.
+// - Any error returned by the repository when saving the menu item.
+func (s *MenuService) CreateMenuItem(ctx context.Context, name string, price int, taxCategory string) (MenuItem, error) {
 	item := MenuItem{
-		ID:    id.New(),
-		Name:  name,
-		Price: price,
+		ID:          id.New(),
+		Name:        name,
+		Price:       price,
+		TaxCategory: taxCategory,
 	}
 
 	if !item.IsValid() {
 		return MenuItem{}, Errorf(EINVALID, "invalid item")
 	}
 
+	if s.tax != nil && !s.tax.HasCategory(taxCategory) {
+		return MenuItem{}, Errorf(EINVALID, "unknown tax category %q", taxCategory)
+	}
+
 	err := s.repo.SaveItem(ctx, item)
 	if err != nil {
 		return MenuItem{}, err
 	}
 
+	publish(ctx, s.events, MenuItemCreated{Item: item})
+
+	return item, nil
+}
+
+// MarkMenuItemAvailable makes an item orderable again. The portions left are counted from portions,
+// they are not counted when it is nil.
+// Possible errors:
+// - EINVALID if the portions are negative.
+// - ENOTFOUND if the menu item could not be found.
+// - Any error returned by the repository when saving the menu item.
+func (s *MenuService) MarkMenuItemAvailable(ctx context.Context, itemID id.ID, portions *int) (MenuItem, error) {
+	if portions != nil && *portions < 0 {
+		return MenuItem{}, Errorf(EINVALID, "portions must not be negative")
+	}
+
+	item, err := s.repo.SetAvailability(ctx, itemID, false, portions)
+	if err != nil {
+		return MenuItem{}, err
+	}
+
+	publish(ctx, s.events, MenuItemUpdated{Item: item})
+
+	return item, nil
+}
+
+// MarkMenuItemUnavailable stops the orders of an item, when the kitchen ran out of it.
+// Possible errors:
+// - ENOTFOUND if the menu item could not be found.
+// - Any error returned by the repository when saving the menu item.
+func (s *MenuService) MarkMenuItemUnavailable(ctx context.Context, itemID id.ID) (MenuItem, error) {
+	item, err := s.repo.SetAvailability(ctx, itemID, true, nil)
+	if err != nil {
+		return MenuItem{}, err
+	}
+
+	publish(ctx, s.events, MenuItemUpdated{Item: item})
+
 	return item, nil
 }
 
+// AddModifierGroup offers a new group of options with a menu item.
+// The IDs of the group and of its options are generated.
+// Possible errors:
+// - ENOTFOUND if the menu item could not be found.
+// - EINVALID if the group has no name, no option or invalid selection bounds.
+// - Any error returned by the repository when saving the menu item.
+func (s *MenuService) AddModifierGroup(ctx context.Context, itemID id.ID, name string, minSelections int, maxSelections int, options []ModifierOption) (ModifierGroup, error) {
+	item, err := s.repo.FindItem(ctx, itemID)
+	if err != nil {
+		return ModifierGroup{}, err
+	}
+
+	group := ModifierGroup{
+		ID:            id.New(),
+		Name:          name,
+		MinSelections: minSelections,
+		MaxSelections: maxSelections,
+		Options:       make([]ModifierOption, 0, len(options)),
+	}
+	for _, option := range options {
+		option.ID = id.New()
+		group.Options = append(group.Options, option)
+	}
+
+	if !group.IsValid() {
+		return ModifierGroup{}, Errorf(EINVALID, "invalid modifier group")
+	}
+
+	item.ModifierGroups = append(slices.Clone(item.ModifierGroups), group)
+	if err := s.repo.SaveItem(ctx, item); err != nil {
+		return ModifierGroup{}, err
+	}
+
+	publish(ctx, s.events, MenuItemUpdated{Item: item})
+
+	return group, nil
+}
+
+// RemoveModifierGroup stops offering a group of options with a menu item.
+// Possible errors:
+// - ENOTFOUND if the menu item could not be found.
+// - ENOTFOUND if the group is not offered with the menu item.
+// - Any error returned by the repository when saving the menu item.
+func (s *MenuService) RemoveModifierGroup(ctx context.Context, itemID id.ID, groupID id.ID) error {
+	item, err := s.repo.FindItem(ctx, itemID)
+	if err != nil {
+		return err
+	}
+
+	idx := slices.IndexFunc(item.ModifierGroups, func(group ModifierGroup) bool { return group.ID == groupID })
+	if idx == -1 {
+		return Errorf(ENOTFOUND, "modifier group %s not found on item %s", groupID, itemID)
+	}
+
+	item.ModifierGroups = slices.Delete(slices.Clone(item.ModifierGroups), idx, idx+1)
+	if len(item.ModifierGroups) == 0 {
+		item.ModifierGroups = nil
+	}
+
+	if err := s.repo.SaveItem(ctx, item); err != nil {
+		return err
+	}
+
+	publish(ctx, s.events, MenuItemUpdated{Item: item})
+
+	return nil
+}
+
 func (s *MenuService) AddItemToCategory(ctx context.Context, categoryID id.ID, itemID id.ID) error {
 	category, err := s.repo.FindCategory(ctx, categoryID)
 	if err != nil {
@@ -101,7 +357,7 @@ func (s *MenuService) AddItemToCategory(ctx context.Context, categoryID id.ID, i
 	}
 
 	if slices.ContainsFunc(category.MenuItems, func(item MenuItem) bool { return item.ID == itemID }) {
-		return Errorf(EINVALID, "item already exists in category")
+		return Errorf(ECONFLICT, "item %s is already in category %s", itemID, categoryID)
 	}
 
 	item, err := s.repo.FindItem(ctx, itemID)
@@ -110,5 +366,32 @@ func (s *MenuService) AddItemToCategory(ctx context.Context, categoryID id.ID, i
 	}
 
 	category.MenuItems = append(category.MenuItems, item)
-	return s.repo.SaveCategory(ctx, category)
+	if err := s.repo.SaveCategory(ctx, category); err != nil {
+		return err
+	}
+
+	publish(ctx, s.events, MenuItemAddedToCategory{CategoryID: categoryID, Item: item})
+
+	return nil
+}
+
+func (s *MenuService) RemoveItemFromCategory(ctx context.Context, categoryID id.ID, itemID id.ID) error {
+	category, err := s.repo.FindCategory(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	idx := slices.IndexFunc(category.MenuItems, func(item MenuItem) bool { return item.ID == itemID })
+	if idx == -1 {
+		return Errorf(ENOTFOUND, "item %s not found in category %s", itemID, categoryID)
+	}
+
+	category.MenuItems = slices.Delete(slices.Clone(category.MenuItems), idx, idx+1)
+	if err := s.repo.SaveCategory(ctx, category); err != nil {
+		return err
+	}
+
+	publish(ctx, s.events, MenuItemRemovedFromCategory{CategoryID: categoryID, ItemID: itemID})
+
+	return nil
 }
@@ -140,28 +140,31 @@ func TestIsMenuCategoryValid(t *testing.T) {
 
 func TestCreateMenuItem(t *testing.T) {
 	menuRepo := inmem.NewMenu()
-	menuService := domain.NewMenuService(menuRepo)
+	menuService := domain.NewMenuService(menuRepo, nil).WithTaxPolicy(domain.TaxPolicy{Rates: map[string]float64{"food": 5.5}})
 
 	t.Run("Success", func(t *testing.T) {
 		t.Parallel()
 
 		tt := []struct {
-			testName  string
-			itemName  string
-			itemPrice int
+			testName    string
+			itemName    string
+			itemPrice   int
+			taxCategory string
 		}{
 			{testName: "valid item", itemName: "Spaghetti", itemPrice: 100},
 			{testName: "item with price 0", itemName: "Spaghetti", itemPrice: 0},
+			{testName: "item with tax category", itemName: "Spaghetti", itemPrice: 100, taxCategory: "food"},
 		}
 
 		for _, tc := range tt {
 			t.Run(tc.testName, func(t *testing.T) {
-				item, err := menuService.CreateMenuItem(context.Background(), tc.itemName, tc.itemPrice)
+				item, err := menuService.CreateMenuItem(context.Background(), tc.itemName, tc.itemPrice, tc.taxCategory)
 				require.Nil(t, err, "item creation failed")
 				item, err = menuRepo.FindItem(context.Background(), item.ID)
 				require.Nil(t, err, "item not saved")
 				assert.Equal(t, tc.itemName, item.Name, "item name not correctly saved")
 				assert.Equal(t, tc.itemPrice, item.Price, "item price not correctly saved")
+				assert.Equal(t, tc.taxCategory, item.TaxCategory, "item tax category not correctly saved")
 			})
 		}
 	})
@@ -170,17 +173,19 @@ func TestCreateMenuItem(t *testing.T) {
 		t.Parallel()
 
 		tt := []struct {
-			testName  string
-			itemName  string
-			itemPrice int
+			testName    string
+			itemName    string
+			itemPrice   int
+			taxCategory string
 		}{
 			{testName: "empty item name", itemName: "", itemPrice: 100},
 			{testName: "negative item price", itemName: "Spaghetti", itemPrice: -100},
+			{testName: "unknown tax category", itemName: "Spaghetti", itemPrice: 100, taxCategory: "alcohol"},
 		}
 
 		for _, tc := range tt {
 			t.Run(tc.testName, func(t *testing.T) {
-				_, err := menuService.CreateMenuItem(context.Background(), tc.itemName, tc.itemPrice)
+				_, err := menuService.CreateMenuItem(context.Background(), tc.itemName, tc.itemPrice, tc.taxCategory)
 				require.NotNil(t, err, "item creation should fail")
 			})
 		}
@@ -190,7 +195,7 @@ func TestCreateMenuItem(t *testing.T) {
 
 func TestCreateMenuCategory(t *testing.T) {
 	menuRepo := inmem.NewMenu()
-	menuService := domain.NewMenuService(menuRepo)
+	menuService := domain.NewMenuService(menuRepo, nil)
 
 	t.Run("Success", func(t *testing.T) {
 		t.Parallel()
@@ -232,9 +237,27 @@ func TestCreateMenuCategory(t *testing.T) {
 	})
 }
 
+func TestFindAllCategories(t *testing.T) {
+	menuService := domain.NewMenuService(inmem.NewMenu(), nil)
+
+	for _, name := range []string{"Pasta", "Desserts", "Starters"} {
+		_, err := menuService.CreateCategory(context.Background(), name)
+		require.NoError(t, err, "Initial setup failed")
+	}
+
+	categories, err := menuService.FindAllCategories(context.Background())
+
+	require.NoError(t, err)
+	names := make([]string, 0, len(categories))
+	for _, category := range categories {
+		names = append(names, category.Name)
+	}
+	assert.Equal(t, []string{"Desserts", "Pasta", "Starters"}, names, "categories not ordered by name")
+}
+
 func TestAddMenuItemToCategory(t *testing.T) {
 	menuRepo := inmem.NewMenu()
-	menuService := domain.NewMenuService(menuRepo)
+	menuService := domain.NewMenuService(menuRepo, nil)
 
 	t.Run("Success", func(t *testing.T) {
 		t.Parallel()
@@ -301,8 +324,317 @@ func TestAddMenuItemToCategory(t *testing.T) {
 
 			err = menuService.AddItemToCategory(context.Background(), category.ID, item.ID)
 
-			require.Error(t, err, "adding menu item to category should fail")
+			assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "invalid error code")
+		})
+	})
+
+}
+
+func TestRemoveMenuItemFromCategory(t *testing.T) {
+	menuRepo := inmem.NewMenu()
+	menuService := domain.NewMenuService(menuRepo, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		item := domain.MenuItem{ID: id.New(), Name: "Spaghetti", Price: 100}
+		other := domain.MenuItem{ID: id.New(), Name: "Penne", Price: 100}
+		category := domain.MenuCategory{ID: id.New(),
+			Name:      "Pasta",
+			MenuItems: []domain.MenuItem{item, other},
+		}
+		err := menuRepo.SaveCategory(context.Background(), category)
+		require.Nil(t, err, "error creating category")
+
+		err = menuService.RemoveItemFromCategory(context.Background(), category.ID, item.ID)
+
+		require.NoError(t, err, "removing menu item from category failed")
+		updatedCategory, err := menuRepo.FindCategory(context.Background(), category.ID)
+		require.NoError(t, err, "category not found")
+		assert.Equal(t, []domain.MenuItem{other}, updatedCategory.MenuItems, "menu item not removed from category")
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("category not found", func(t *testing.T) {
+			t.Parallel()
+
+			err := menuService.RemoveItemFromCategory(context.Background(), id.New(), id.New())
+			assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
+		})
+
+		t.Run("item not in category", func(t *testing.T) {
+			t.Parallel()
+
+			category := domain.MenuCategory{ID: id.New(),
+				Name:      "Pasta",
+				MenuItems: make([]domain.MenuItem, 0),
+			}
+			err := menuRepo.SaveCategory(context.Background(), category)
+			require.Nil(t, err, "error creating category")
+
+			err = menuService.RemoveItemFromCategory(context.Background(), category.ID, id.New())
+			assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
 		})
 	})
+}
+
+func TestSelectModifiers(t *testing.T) {
+	rare := domain.ModifierOption{ID: id.New(), Name: "Rare"}
+	medium := domain.ModifierOption{ID: id.New(), Name: "Medium"}
+	bacon := domain.ModifierOption{ID: id.New(), Name: "Bacon", PriceDelta: 200}
+	cheese := domain.ModifierOption{ID: id.New(), Name: "Cheese", PriceDelta: 100}
+	cooking := domain.ModifierGroup{ID: id.New(), Name: "Cooking", MinSelections: 1, MaxSelections: 1, Options: []domain.ModifierOption{rare, medium}}
+	extras := domain.ModifierGroup{ID: id.New(), Name: "Extras", MinSelections: 0, MaxSelections: 1, Options: []domain.ModifierOption{bacon, cheese}}
+	item := domain.MenuItem{ID: id.New(), Name: "Burger", Price: 1000, ModifierGroups: []domain.ModifierGroup{cooking, extras}}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		tt := []struct {
+			testName  string
+			optionIDs []id.ID
+			expected  []domain.Modifier
+		}{
+			{
+				testName:  "required option only",
+				optionIDs: []id.ID{medium.ID},
+				expected: []domain.Modifier{
+					{GroupID: cooking.ID, GroupName: "Cooking", OptionID: medium.ID, OptionName: "Medium"},
+				},
+			},
+			{
+				testName:  "options in menu order",
+				optionIDs: []id.ID{bacon.ID, rare.ID},
+				expected: []domain.Modifier{
+					{GroupID: cooking.ID, GroupName: "Cooking", OptionID: rare.ID, OptionName: "Rare"},
+					{GroupID: extras.ID, GroupName: "Extras", OptionID: bacon.ID, OptionName: "Bacon", PriceDelta: 200},
+				},
+			},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				t.Parallel()
+
+				modifiers, err := item.SelectModifiers(tc.optionIDs)
+
+				require.NoError(t, err, "select modifiers failed")
+				assert.Equal(t, tc.expected, modifiers, "invalid modifiers")
+			})
+		}
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+
+		tt := []struct {
+			testName  string
+			optionIDs []id.ID
+		}{
+			{testName: "missing required option", optionIDs: nil},
+			{testName: "too many options", optionIDs: []id.ID{rare.ID, medium.ID}},
+			{testName: "option chosen twice", optionIDs: []id.ID{rare.ID, rare.ID}},
+			{testName: "option not offered", optionIDs: []id.ID{rare.ID, id.New()}},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := item.SelectModifiers(tc.optionIDs)
+
+				assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+	})
+}
+
+func TestAddModifierGroup(t *testing.T) {
+	menuRepo := inmem.NewMenu()
+	menuService := domain.NewMenuService(menuRepo, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		item, err := menuService.CreateMenuItem(context.Background(), "Burger", 1000, "")
+		require.NoError(t, err, "initial setup failed")
+
+		options := []domain.ModifierOption{{Name: "Bacon", PriceDelta: 200}, {Name: "Cheese", PriceDelta: 100}}
+		group, err := menuService.AddModifierGroup(context.Background(), item.ID, "Extras", 0, 2, options)
+
+		require.NoError(t, err, "add modifier group failed")
+		assert.NotEqual(t, id.NilID(), group.ID, "generated group ID is nil")
+		require.Len(t, group.Options, 2, "invalid number of options")
+		for _, option := range group.Options {
+			assert.NotEqual(t, id.NilID(), option.ID, "generated option ID is nil")
+		}
+
+		item, err = menuRepo.FindItem(context.Background(), item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.ModifierGroup{group}, item.ModifierGroups, "group not correctly saved")
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+
+		item, err := menuService.CreateMenuItem(context.Background(), "Burger", 1000, "")
+		require.NoError(t, err, "initial setup failed")
+
+		options := []domain.ModifierOption{{Name: "Rare"}, {Name: "Medium"}}
+
+		tt := []struct {
+			testName      string
+			itemID        id.ID
+			name          string
+			minSelections int
+			maxSelections int
+			options       []domain.ModifierOption
+			errCode       string
+		}{
+			{testName: "item not found", itemID: id.New(), name: "Cooking", minSelections: 1, maxSelections: 1, options: options, errCode: domain.ENOTFOUND},
+			{testName: "empty name", itemID: item.ID, name: "", minSelections: 1, maxSelections: 1, options: options, errCode: domain.EINVALID},
+			{testName: "no options", itemID: item.ID, name: "Cooking", minSelections: 0, maxSelections: 1, options: nil, errCode: domain.EINVALID},
+			{testName: "min above max", itemID: item.ID, name: "Cooking", minSelections: 2, maxSelections: 1, options: options, errCode: domain.EINVALID},
+			{testName: "max above options", itemID: item.ID, name: "Cooking", minSelections: 0, maxSelections: 3, options: options, errCode: domain.EINVALID},
+			{testName: "unnamed option", itemID: item.ID, name: "Cooking", minSelections: 0, maxSelections: 1, options: []domain.ModifierOption{{Name: ""}}, errCode: domain.EINVALID},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				t.Parallel()
+
+				_, err := menuService.AddModifierGroup(context.Background(), tc.itemID, tc.name, tc.minSelections, tc.maxSelections, tc.options)
+
+				assert.Equal(t, tc.errCode, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+	})
+}
+
+func TestRemoveModifierGroup(t *testing.T) {
+	menuRepo := inmem.NewMenu()
+	menuService := domain.NewMenuService(menuRepo, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		item, err := menuService.CreateMenuItem(context.Background(), "Burger", 1000, "")
+		require.NoError(t, err, "initial setup failed")
+		group, err := menuService.AddModifierGroup(context.Background(), item.ID, "Extras", 0, 1, []domain.ModifierOption{{Name: "Bacon", PriceDelta: 200}})
+		require.NoError(t, err, "initial setup failed")
+
+		err = menuService.RemoveModifierGroup(context.Background(), item.ID, group.ID)
+
+		require.NoError(t, err, "remove modifier group failed")
+		item, err = menuRepo.FindItem(context.Background(), item.ID)
+		require.NoError(t, err)
+		assert.Empty(t, item.ModifierGroups, "group not removed")
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+
+		item, err := menuService.CreateMenuItem(context.Background(), "Burger", 1000, "")
+		require.NoError(t, err, "initial setup failed")
+
+		tt := []struct {
+			testName string
+			itemID   id.ID
+			groupID  id.ID
+		}{
+			{testName: "item not found", itemID: id.New(), groupID: id.New()},
+			{testName: "group not found", itemID: item.ID, groupID: id.New()},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				t.Parallel()
+
+				err := menuService.RemoveModifierGroup(context.Background(), tc.itemID, tc.groupID)
+
+				assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+	})
+}
+
+func TestMarkMenuItemAvailability(t *testing.T) {
+	menuRepo := inmem.NewMenu()
+	menuService := domain.NewMenuService(menuRepo, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		item, err := menuService.CreateMenuItem(ctx, "Burger", 1000, "")
+		require.NoError(t, err, "initial setup failed")
+		assert.True(t, item.IsAvailable(), "a new item is available")
+
+		item, err = menuService.MarkMenuItemUnavailable(ctx, item.ID)
+		require.NoError(t, err, "mark unavailable failed")
+		assert.False(t, item.IsAvailable(), "item is still available")
+
+		portions := 3
+		item, err = menuService.MarkMenuItemAvailable(ctx, item.ID, &portions)
+		require.NoError(t, err, "mark available failed")
+		assert.True(t, item.IsAvailable(), "item is still unavailable")
+
+		saved, err := menuRepo.FindItem(ctx, item.ID)
+		require.NoError(t, err)
+		require.NotNil(t, saved.Portions, "portions not correctly saved")
+		assert.Equal(t, 3, *saved.Portions, "portions not correctly saved")
+
+		portions = 0
+		item, err = menuService.MarkMenuItemAvailable(ctx, item.ID, &portions)
+		require.NoError(t, err, "mark available failed")
+		assert.False(t, item.IsAvailable(), "an item without portions left is unavailable")
+
+		item, err = menuService.MarkMenuItemAvailable(ctx, item.ID, nil)
+		require.NoError(t, err, "mark available failed")
+		assert.True(t, item.IsAvailable(), "item is still unavailable")
+		assert.Nil(t, item.Portions, "portions are still counted")
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+
+		item, err := menuService.CreateMenuItem(context.Background(), "Burger", 1000, "")
+		require.NoError(t, err, "initial setup failed")
+
+		negative := -1
+		_, err = menuService.MarkMenuItemAvailable(context.Background(), item.ID, &negative)
+		assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+
+		_, err = menuService.MarkMenuItemAvailable(context.Background(), id.New(), nil)
+		assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
+
+		_, err = menuService.MarkMenuItemUnavailable(context.Background(), id.New())
+		assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
+	})
+}
 
+func TestMenuPublishesEvents(t *testing.T) {
+	publisher := &recordingPublisher{}
+	menuService := domain.NewMenuService(inmem.NewMenu(), publisher)
+	ctx := context.Background()
+
+	item, err := menuService.CreateMenuItem(ctx, "item", 100, "")
+	require.NoError(t, err)
+	category, err := menuService.CreateCategory(ctx, "starters")
+	require.NoError(t, err)
+	require.NoError(t, menuService.AddItemToCategory(ctx, category.ID, item.ID))
+	require.NoError(t, menuService.RemoveItemFromCategory(ctx, category.ID, item.ID))
+
+	// A failed operation publishes nothing.
+	require.Error(t, menuService.RemoveItemFromCategory(ctx, category.ID, item.ID))
+
+	assert.Equal(t, []string{
+		"menu.item_created",
+		"menu.category_created",
+		"menu.item_added_to_category",
+		"menu.item_removed_from_category",
+	}, publisher.names())
+	assert.Equal(t, domain.MenuItemCreated{Item: item}, publisher.events[0])
 }
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"order_manager/internal/id"
+	"time"
+)
+
+type TenderType string
+
+const (
+	TenderCash    TenderType = "cash"
+	TenderCard    TenderType = "card"
+	TenderVoucher TenderType = "voucher"
+)
+
+func (t TenderType) IsValid() bool {
+	return t == TenderCash || t == TenderCard || t == TenderVoucher
+}
+
+// Payment is an entry of the ledger of a bill, either a payment or a refund giving back part of a payment.
+type Payment struct {
+	ID     id.ID
+	Tender TenderType
+	// Amount is the part of the amount due that the payment settles, negative for a refund.
+	Amount int
+	// Tip is paid on top of the amount, negative for a refund.
+	Tip int
+	// Tendered is the cash handed over for a cash payment, Change is the cash given back.
+	// Both are 0 for the other tenders and for the refunds.
+	Tendered int
+	Change   int
+	// RefundOf is the payment a refund gives back, nil for a payment.
+	RefundOf id.ID
+	// PreparationID is the item a refund gives back, nil for a payment or the refund of a whole payment.
+	PreparationID id.ID
+	// Reason is why a refund was granted.
+	Reason string
+	PaidAt time.Time
+}
+
+// IsRefund reports whether the entry gives back part of a payment.
+func (p Payment) IsRefund() bool {
+	return p.RefundOf != id.NilID()
+}
+
+func (p Payment) IsValid() bool {
+	if p.ID == id.NilID() || !p.Tender.IsValid() || p.Change < 0 || p.PaidAt.IsZero() {
+		return false
+	}
+
+	if p.IsRefund() {
+		return p.Amount <= 0 && p.Tip <= 0 && p.Amount+p.Tip < 0 && p.Tendered == 0 && p.Change == 0 && p.Reason != ""
+	}
+
+	isValid := p.Amount > 0 && p.Tip >= 0 && p.PreparationID == id.NilID() && p.Reason == ""
+	if p.Tender != TenderCash {
+		return isValid && p.Tendered == 0 && p.Change == 0
+	}
+
+	return isValid && p.Tendered == p.Amount+p.Tip+p.Change
+}
+
+// cash returns the cash the entry leaves in the drawer, the change being given back from it.
+func (p Payment) cash() int {
+	if p.Tender != TenderCash {
+		return 0
+	}
+
+	return p.Amount + p.Tip
+}
@@ -0,0 +1,357 @@
+package domain
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"order_manager/internal/id"
+	"slices"
+	"time"
+)
+
+type PromotionKind string
+
+const (
+	// PromotionPercentage takes Percent off every targeted item, or off the bill.
+	PromotionPercentage PromotionKind = "percentage"
+	// PromotionFixedAmount takes Amount off every targeted item, or once off the bill.
+	PromotionFixedAmount PromotionKind = "fixed_amount"
+	// PromotionFixedPrice sells the targeted items at Amount instead of their menu price, modifiers still charged.
+	// Combined with a Window it gives happy-hour prices.
+	PromotionFixedPrice PromotionKind = "fixed_price"
+	// PromotionBuyXGetY offers FreeQuantity targeted items for every BuyQuantity targeted items bought,
+	// the cheapest items being the free ones.
+	PromotionBuyXGetY PromotionKind = "buy_x_get_y"
+)
+
+type PromotionScope string
+
+const (
+	// PromotionScopeLine promotions discount the items they target.
+	PromotionScopeLine PromotionScope = "line"
+	// PromotionScopeBill promotions discount the bill as a whole.
+	PromotionScopeBill PromotionScope = "bill"
+)
+
+// TimeWindow is a period of the day, from Start to End after midnight.
+// A window ending before it starts spans midnight.
+type TimeWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+func (w TimeWindow) IsValid() bool {
+	day := 24 * time.Hour
+	return w.Start >= 0 && w.Start < day && w.End >= 0 && w.End <= day && w.Start != w.End
+}
+
+// Contains reports whether the time of the day of t, in its location, is within the window.
+func (w TimeWindow) Contains(t time.Time) bool {
+	year, month, day := t.Date()
+	elapsed := t.Sub(time.Date(year, month, day, 0, 0, 0, 0, t.Location()))
+
+	if w.Start < w.End {
+		return elapsed >= w.Start && elapsed < w.End
+	}
+
+	return elapsed >= w.Start || elapsed < w.End
+}
+
+// Promotion is a discount granted automatically on the bills generated while it is active.
+//
+// The promotions are applied in a deterministic order: the line promotions before the bill promotions,
+// then by descending priority, then by creation. A promotion that is not stackable only discounts the items
+// no promotion discounted before it and keeps the following promotions off them.
+// A stackable promotion discounts the amount left by the previous ones on every item not kept off.
+type Promotion struct {
+	ID    id.ID
+	Name  string
+	Kind  PromotionKind
+	Scope PromotionScope
+	// Percent is the discount of the percentage promotions.
+	Percent float64
+	// Amount is the discount of the fixed amount promotions or the price of the fixed price promotions.
+	Amount       int
+	BuyQuantity  int
+	FreeQuantity int
+	// MenuItemIDs are the menu items a line promotion targets, every item when empty.
+	MenuItemIDs []id.ID
+	// Window limits the promotion to a period of the day, nil when it applies all day.
+	Window    *TimeWindow
+	Priority  int
+	Stackable bool
+	Active    bool
+}
+
+func (p Promotion) IsValid() bool {
+	if p.ID == id.NilID() || p.Name == "" || (p.Window != nil && !p.Window.IsValid()) {
+		return false
+	}
+
+	for i, itemID := range p.MenuItemIDs {
+		if slices.Contains(p.MenuItemIDs[:i], itemID) {
+			return false
+		}
+	}
+
+	switch p.Scope {
+	case PromotionScopeLine:
+	case PromotionScopeBill:
+		if len(p.MenuItemIDs) > 0 {
+			return false
+		}
+	default:
+		return false
+	}
+
+	switch p.Kind {
+	case PromotionPercentage:
+		return p.Percent > 0 && p.Percent <= 100
+	case PromotionFixedAmount:
+		return p.Amount > 0
+	case PromotionFixedPrice:
+		return p.Scope == PromotionScopeLine && p.Amount >= 0
+	case PromotionBuyXGetY:
+		return p.Scope == PromotionScopeLine && p.BuyQuantity > 0 && p.FreeQuantity > 0
+	default:
+		return false
+	}
+}
+
+// appliesAt reports whether the promotion is active at a time.
+func (p Promotion) appliesAt(t time.Time) bool {
+	return p.Active && (p.Window == nil || p.Window.Contains(t))
+}
+
+func (p Promotion) targets(item MenuItem) bool {
+	return len(p.MenuItemIDs) == 0 || slices.Contains(p.MenuItemIDs, item.ID)
+}
+
+// Discount is an amount taken off a bill, by a promotion or manually.
+type Discount struct {
+	// PromotionID is the promotion granting the discount, nil for a manual discount.
+	PromotionID id.ID
+	Name        string
+	// PreparationID is the item discounted, nil for a discount on the whole bill.
+	PreparationID id.ID
+	Amount        int
+	// Reason is why a manual discount was granted.
+	Reason string
+}
+
+func (d Discount) IsValid() bool {
+	return d.Name != "" && d.Amount > 0 && (d.PromotionID != id.NilID() || d.Reason != "")
+}
+
+type PromotionRepository interface {
+	Save(ctx context.Context, promotion Promotion) error
+	FindByID(ctx context.Context, id id.ID) (Promotion, error)
+	FindAll(ctx context.Context) ([]Promotion, error)
+}
+
+type PromotionService struct {
+	repo   PromotionRepository
+	menu   MenuRepository
+	events EventPublisher
+}
+
+// NewPromotionService creates a new promotion service.
+// The service is responsible for the promotions that the bill service applies.
+func NewPromotionService(repo PromotionRepository, menu MenuRepository, events EventPublisher) *PromotionService {
+	return &PromotionService{repo: repo, menu: menu, events: events}
+}
+
+// CreatePromotion registers an active promotion, its ID is assigned by the service.
+// Possible errors:
+// - EINVALID if the promotion is invalid.
+// - ENOTFOUND if a targeted menu item could not be found.
+// - Any error returned by the repository when saving the promotion.
+func (s *PromotionService) CreatePromotion(ctx context.Context, promotion Promotion) (Promotion, error) {
+	promotion.ID = id.New()
+	promotion.Active = true
+	if promotion.MenuItemIDs == nil {
+		promotion.MenuItemIDs = make([]id.ID, 0)
+	}
+
+	if !promotion.IsValid() {
+		return Promotion{}, Errorf(EINVALID, "invalid promotion")
+	}
+
+	if len(promotion.MenuItemIDs) > 0 {
+		if _, err := s.menu.FindItems(ctx, promotion.MenuItemIDs); err != nil {
+			return Promotion{}, err
+		}
+	}
+
+	if err := s.repo.Save(ctx, promotion); err != nil {
+		return Promotion{}, err
+	}
+
+	publish(ctx, s.events, PromotionCreated{Promotion: promotion})
+
+	return promotion, nil
+}
+
+// FindPromotion returns a promotion by its ID.
+// Possible errors:
+// - ENOTFOUND if the promotion could not be found.
+func (s *PromotionService) FindPromotion(ctx context.Context, promotionID id.ID) (Promotion, error) {
+	return s.repo.FindByID(ctx, promotionID)
+}
+
+// FindAllPromotions returns all the promotions in the order they are applied.
+// Possible errors:
+// - Any error returned by the repository when fetching the promotions.
+func (s *PromotionService) FindAllPromotions(ctx context.Context) ([]Promotion, error) {
+	promotions, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sortPromotions(promotions)
+	return promotions, nil
+}
+
+// SetPromotionActive enables or disables a promotion for the bills generated from now on.
+// Possible errors:
+// - ENOTFOUND if the promotion could not be found.
+// - Any error returned by the repository when saving the promotion.
+func (s *PromotionService) SetPromotionActive(ctx context.Context, promotionID id.ID, active bool) (Promotion, error) {
+	promotion, err := s.repo.FindByID(ctx, promotionID)
+	if err != nil {
+		return Promotion{}, err
+	}
+
+	if promotion.Active == active {
+		return promotion, nil
+	}
+
+	promotion.Active = active
+	if err := s.repo.Save(ctx, promotion); err != nil {
+		return Promotion{}, err
+	}
+
+	publish(ctx, s.events, PromotionUpdated{Promotion: promotion})
+
+	return promotion, nil
+}
+
+// sortPromotions sorts the promotions in the order they are applied,
+// the IDs being time ordered, the oldest promotion wins a tie.
+func sortPromotions(promotions []Promotion) {
+	slices.SortStableFunc(promotions, func(a, b Promotion) int {
+		if a.Scope != b.Scope {
+			if a.Scope == PromotionScopeLine {
+				return -1
+			}
+			return 1
+		}
+
+		if a.Priority != b.Priority {
+			return b.Priority - a.Priority
+		}
+
+		return cmp.Compare(a.ID.String(), b.ID.String())
+	})
+}
+
+// applyPromotions applies the promotions active at a time to the items, adding to the discount of every item.
+// It returns the discount lines, one per promotion and item for the line promotions and one per promotion
+// for the bill promotions.
+func applyPromotions(items []BillItem, promotions []Promotion, at time.Time) []Discount {
+	applicable := make([]Promotion, 0, len(promotions))
+	for _, promotion := range promotions {
+		if promotion.appliesAt(at) {
+			applicable = append(applicable, promotion)
+		}
+	}
+	sortPromotions(applicable)
+
+	discounts := make([]Discount, 0)
+	discounted := make([]bool, len(items))
+	exclusive := make([]bool, len(items))
+	for _, promotion := range applicable {
+		targets := make([]int, 0)
+		for i, item := range items {
+			if exclusive[i] || (discounted[i] && !promotion.Stackable) || item.Amount == item.Discount {
+				continue
+			}
+
+			if promotion.targets(item.MenuItem) {
+				targets = append(targets, i)
+			}
+		}
+
+		amounts := promotion.discounts(items, targets)
+		total := 0
+		for k, i := range targets {
+			if amounts[k] == 0 {
+				continue
+			}
+
+			items[i].Discount += amounts[k]
+			discounted[i] = true
+			exclusive[i] = !promotion.Stackable
+			total += amounts[k]
+
+			if promotion.Scope == PromotionScopeLine {
+				discounts = append(discounts, Discount{PromotionID: promotion.ID, Name: promotion.Name, PreparationID: items[i].PreparationID, Amount: amounts[k]})
+			}
+		}
+
+		if promotion.Scope == PromotionScopeBill && total > 0 {
+			discounts = append(discounts, Discount{PromotionID: promotion.ID, Name: promotion.Name, Amount: total})
+		}
+	}
+
+	return discounts
+}
+
+// discounts returns the discount of the promotion on every targeted item, never more than what is left to pay on it.
+// The discounts of the bill promotions are spread on the items in proportion to what is left to pay on them.
+func (p Promotion) discounts(items []BillItem, targets []int) []int {
+	amounts := make([]int, len(targets))
+	left := make([]int, len(targets))
+	sum := 0
+	for k, i := range targets {
+		left[k] = items[i].Amount - items[i].Discount
+		sum += left[k]
+	}
+
+	if p.Scope == PromotionScopeBill {
+		discount := min(p.Amount, sum)
+		if p.Kind == PromotionPercentage {
+			discount = int(math.Round(float64(sum) * p.Percent / 100))
+		}
+
+		return distribute(discount, left)
+	}
+
+	switch p.Kind {
+	case PromotionPercentage:
+		for k := range targets {
+			amounts[k] = int(math.Round(float64(left[k]) * p.Percent / 100))
+		}
+	case PromotionFixedAmount:
+		for k := range targets {
+			amounts[k] = min(p.Amount, left[k])
+		}
+	case PromotionFixedPrice:
+		for k, i := range targets {
+			amounts[k] = min(max(items[i].MenuItem.Price-p.Amount, 0), left[k])
+		}
+	case PromotionBuyXGetY:
+		cheapest := make([]int, len(targets))
+		for k := range cheapest {
+			cheapest[k] = k
+		}
+		slices.SortStableFunc(cheapest, func(a, b int) int { return left[a] - left[b] })
+
+		free := len(targets) / (p.BuyQuantity + p.FreeQuantity) * p.FreeQuantity
+		for _, k := range cheapest[:free] {
+			amounts[k] = left[k]
+		}
+	}
+
+	return amounts
+}
@@ -0,0 +1,401 @@
+package domain_test
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"order_manager/internal/inmem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+func TestIsPromotionValid(t *testing.T) {
+	itemID := id.New()
+	tt := []struct {
+		testName  string
+		promotion domain.Promotion
+		valid     bool
+	}{
+		{testName: "percentage on items", promotion: domain.Promotion{ID: id.New(), Name: "p", Kind: domain.PromotionPercentage, Scope: domain.PromotionScopeLine, Percent: 10, MenuItemIDs: []id.ID{itemID}}, valid: true},
+		{testName: "fixed amount off the bill", promotion: domain.Promotion{ID: id.New(), Name: "p", Kind: domain.PromotionFixedAmount, Scope: domain.PromotionScopeBill, Amount: 100}, valid: true},
+		{testName: "happy hour spanning midnight", promotion: domain.Promotion{ID: id.New(), Name: "p", Kind: domain.PromotionFixedPrice, Scope: domain.PromotionScopeLine, Window: &domain.TimeWindow{Start: 22 * time.Hour, End: time.Hour}}, valid: true},
+		{testName: "buy two get one", promotion: domain.Promotion{ID: id.New(), Name: "p", Kind: domain.PromotionBuyXGetY, Scope: domain.PromotionScopeLine, BuyQuantity: 2, FreeQuantity: 1}, valid: true},
+		{testName: "empty name", promotion: domain.Promotion{ID: id.New(), Kind: domain.PromotionPercentage, Scope: domain.PromotionScopeLine, Percent: 10}, valid: false},
+		{testName: "percent over 100", promotion: domain.Promotion{ID: id.New(), Name: "p", Kind: domain.PromotionPercentage, Scope: domain.PromotionScopeLine, Percent: 110}, valid: false},
+		{testName: "bill promotion targeting items", promotion: domain.Promotion{ID: id.New(), Name: "p", Kind: domain.PromotionPercentage, Scope: domain.PromotionScopeBill, Percent: 10, MenuItemIDs: []id.ID{itemID}}, valid: false},
+		{testName: "fixed price on the bill", promotion: domain.Promotion{ID: id.New(), Name: "p", Kind: domain.PromotionFixedPrice, Scope: domain.PromotionScopeBill}, valid: false},
+		{testName: "buy nothing", promotion: domain.Promotion{ID: id.New(), Name: "p", Kind: domain.PromotionBuyXGetY, Scope: domain.PromotionScopeLine, FreeQuantity: 1}, valid: false},
+		{testName: "empty window", promotion: domain.Promotion{ID: id.New(), Name: "p", Kind: domain.PromotionFixedPrice, Scope: domain.PromotionScopeLine, Window: &domain.TimeWindow{Start: time.Hour, End: time.Hour}}, valid: false},
+		{testName: "duplicated items", promotion: domain.Promotion{ID: id.New(), Name: "p", Kind: domain.PromotionPercentage, Scope: domain.PromotionScopeLine, Percent: 10, MenuItemIDs: []id.ID{itemID, itemID}}, valid: false},
+		{testName: "unknown kind", promotion: domain.Promotion{ID: id.New(), Name: "p", Kind: "free", Scope: domain.PromotionScopeLine}, valid: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.valid, tc.promotion.IsValid())
+		})
+	}
+}
+
+func TestCreatePromotion(t *testing.T) {
+	menuRepo := inmem.NewMenu()
+	promotionRepo := inmem.NewPromotion()
+	publisher := &recordingPublisher{}
+	promotionService := domain.NewPromotionService(promotionRepo, menuRepo, publisher)
+	item := domain.MenuItem{ID: id.New(), Name: "Beer", Price: 80}
+	require.NoError(t, menuRepo.SaveItem(context.Background(), item), "initial setup failed")
+
+	t.Run("Success", func(t *testing.T) {
+		promotion, err := promotionService.CreatePromotion(context.Background(), domain.Promotion{
+			Name:        "Happy hour",
+			Kind:        domain.PromotionFixedPrice,
+			Scope:       domain.PromotionScopeLine,
+			Amount:      50,
+			MenuItemIDs: []id.ID{item.ID},
+			Window:      &domain.TimeWindow{Start: 17 * time.Hour, End: 19 * time.Hour},
+		})
+		require.NoError(t, err, "promotion creation failed")
+
+		assert.NotEqual(t, id.NilID(), promotion.ID, "generated ID is nil")
+		assert.True(t, promotion.Active, "promotion should be active")
+		savedPromotion, err := promotionRepo.FindByID(context.Background(), promotion.ID)
+		require.NoError(t, err, "promotion not saved")
+		assert.Equal(t, promotion, savedPromotion)
+		assert.Equal(t, []string{"promotion.created"}, publisher.names())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		tt := []struct {
+			testName  string
+			promotion domain.Promotion
+			errCode   string
+		}{
+			{testName: "invalid promotion", promotion: domain.Promotion{Name: "Half price", Kind: domain.PromotionPercentage, Scope: domain.PromotionScopeLine}, errCode: domain.EINVALID},
+			{testName: "unknown menu item", promotion: domain.Promotion{Name: "Half price", Kind: domain.PromotionPercentage, Scope: domain.PromotionScopeLine, Percent: 50, MenuItemIDs: []id.ID{id.New()}}, errCode: domain.ENOTFOUND},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				_, err := promotionService.CreatePromotion(context.Background(), tc.promotion)
+				assert.Equal(t, tc.errCode, domain.ErrorCode(err))
+			})
+		}
+	})
+}
+
+func TestSetPromotionActive(t *testing.T) {
+	promotionRepo := inmem.NewPromotion()
+	publisher := &recordingPublisher{}
+	promotionService := domain.NewPromotionService(promotionRepo, inmem.NewMenu(), publisher)
+	promotion, err := promotionService.CreatePromotion(context.Background(), domain.Promotion{Name: "Ten off", Kind: domain.PromotionFixedAmount, Scope: domain.PromotionScopeBill, Amount: 10})
+	require.NoError(t, err, "initial setup failed")
+
+	promotion, err = promotionService.SetPromotionActive(context.Background(), promotion.ID, false)
+	require.NoError(t, err, "failed to disable promotion")
+	assert.False(t, promotion.Active, "promotion should be disabled")
+
+	_, err = promotionService.SetPromotionActive(context.Background(), id.New(), false)
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+	assert.Equal(t, []string{"promotion.created", "promotion.updated"}, publisher.names())
+}
+
+func TestGenerateBillWithPromotions(t *testing.T) {
+	beer := domain.MenuItem{ID: id.New(), Name: "Beer", Price: 80}
+	pizza := domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 1000}
+	salad := domain.MenuItem{ID: id.New(), Name: "Salad", Price: 600}
+	preparations := []domain.Preparation{
+		{ID: id.New(), MenuItem: beer},
+		{ID: id.New(), MenuItem: beer},
+		{ID: id.New(), MenuItem: beer},
+		{ID: id.New(), MenuItem: pizza},
+		{ID: id.New(), MenuItem: salad},
+	}
+	table := domain.Table{
+		ID:     id.New(),
+		Status: domain.TableStatusClosed,
+		Orders: []domain.Order{{ID: id.New(), Preparations: preparations}},
+	}
+	happyHourTime := fixedClock(time.Date(2026, 10, 17, 18, 0, 0, 0, time.UTC))
+	eveningTime := fixedClock(time.Date(2026, 10, 17, 21, 0, 0, 0, time.UTC))
+
+	tenPercentPizza := domain.Promotion{ID: id.New(), Name: "Pizza -10%", Kind: domain.PromotionPercentage, Scope: domain.PromotionScopeLine, Percent: 10, MenuItemIDs: []id.ID{pizza.ID}, Priority: 1, Stackable: true, Active: true}
+	halfPricePizza := domain.Promotion{ID: id.New(), Name: "Pizza -50%", Kind: domain.PromotionPercentage, Scope: domain.PromotionScopeLine, Percent: 50, MenuItemIDs: []id.ID{pizza.ID}, Priority: 2, Stackable: true, Active: true}
+	threeHundredOffPizza := domain.Promotion{ID: id.New(), Name: "Pizza -300", Kind: domain.PromotionFixedAmount, Scope: domain.PromotionScopeLine, Amount: 300, MenuItemIDs: []id.ID{pizza.ID}, Priority: 2, Active: true}
+	pizzaAtFiveHundred := domain.Promotion{ID: id.New(), Name: "Pizza at 500", Kind: domain.PromotionFixedPrice, Scope: domain.PromotionScopeLine, Amount: 500, MenuItemIDs: []id.ID{pizza.ID}, Active: true}
+	twoHundredOff := domain.Promotion{ID: id.New(), Name: "200 off", Kind: domain.PromotionFixedAmount, Scope: domain.PromotionScopeBill, Amount: 200, Active: true}
+	tenPercentOff := domain.Promotion{ID: id.New(), Name: "10% off", Kind: domain.PromotionPercentage, Scope: domain.PromotionScopeBill, Percent: 10, Priority: 5, Stackable: true, Active: true}
+	happyHour := domain.Promotion{ID: id.New(), Name: "Happy hour", Kind: domain.PromotionFixedPrice, Scope: domain.PromotionScopeLine, Amount: 50, MenuItemIDs: []id.ID{beer.ID}, Window: &domain.TimeWindow{Start: 17 * time.Hour, End: 19 * time.Hour}, Active: true}
+	threeForTwo := domain.Promotion{ID: id.New(), Name: "3 for 2", Kind: domain.PromotionBuyXGetY, Scope: domain.PromotionScopeLine, BuyQuantity: 2, FreeQuantity: 1, MenuItemIDs: []id.ID{beer.ID}, Active: true}
+	inactive := tenPercentPizza
+	inactive.ID = id.New()
+	inactive.Active = false
+
+	tt := []struct {
+		testName    string
+		promotions  []domain.Promotion
+		clock       domain.Clock
+		discounts   []domain.Discount
+		totalAmount int
+	}{
+		{
+			testName:    "no promotion",
+			clock:       happyHourTime,
+			discounts:   []domain.Discount{},
+			totalAmount: 1840,
+		},
+		{
+			testName:    "percentage on an item",
+			promotions:  []domain.Promotion{tenPercentPizza, inactive},
+			clock:       happyHourTime,
+			discounts:   []domain.Discount{{PromotionID: tenPercentPizza.ID, Name: "Pizza -10%", PreparationID: preparations[3].ID, Amount: 100}},
+			totalAmount: 1740,
+		},
+		{
+			testName:    "fixed amount off the bill",
+			promotions:  []domain.Promotion{twoHundredOff},
+			clock:       happyHourTime,
+			discounts:   []domain.Discount{{PromotionID: twoHundredOff.ID, Name: "200 off", Amount: 200}},
+			totalAmount: 1640,
+		},
+		{
+			testName:   "happy hour within its window",
+			promotions: []domain.Promotion{happyHour},
+			clock:      happyHourTime,
+			discounts: []domain.Discount{
+				{PromotionID: happyHour.ID, Name: "Happy hour", PreparationID: preparations[0].ID, Amount: 30},
+				{PromotionID: happyHour.ID, Name: "Happy hour", PreparationID: preparations[1].ID, Amount: 30},
+				{PromotionID: happyHour.ID, Name: "Happy hour", PreparationID: preparations[2].ID, Amount: 30},
+			},
+			totalAmount: 1750,
+		},
+		{
+			testName:    "happy hour out of its window",
+			promotions:  []domain.Promotion{happyHour},
+			clock:       eveningTime,
+			discounts:   []domain.Discount{},
+			totalAmount: 1840,
+		},
+		{
+			testName:    "buy two get one free",
+			promotions:  []domain.Promotion{threeForTwo},
+			clock:       happyHourTime,
+			discounts:   []domain.Discount{{PromotionID: threeForTwo.ID, Name: "3 for 2", PreparationID: preparations[0].ID, Amount: 80}},
+			totalAmount: 1760,
+		},
+		{
+			testName:    "promotion that does not stack keeps the others off",
+			promotions:  []domain.Promotion{tenPercentPizza, threeHundredOffPizza},
+			clock:       happyHourTime,
+			discounts:   []domain.Discount{{PromotionID: threeHundredOffPizza.ID, Name: "Pizza -300", PreparationID: preparations[3].ID, Amount: 300}},
+			totalAmount: 1540,
+		},
+		{
+			testName:   "stackable promotions apply on what is left by priority",
+			promotions: []domain.Promotion{tenPercentPizza, halfPricePizza},
+			clock:      happyHourTime,
+			discounts: []domain.Discount{
+				{PromotionID: halfPricePizza.ID, Name: "Pizza -50%", PreparationID: preparations[3].ID, Amount: 500},
+				{PromotionID: tenPercentPizza.ID, Name: "Pizza -10%", PreparationID: preparations[3].ID, Amount: 50},
+			},
+			totalAmount: 1290,
+		},
+		{
+			testName:   "bill promotions apply after line promotions",
+			promotions: []domain.Promotion{tenPercentOff, pizzaAtFiveHundred},
+			clock:      happyHourTime,
+			discounts: []domain.Discount{
+				{PromotionID: pizzaAtFiveHundred.ID, Name: "Pizza at 500", PreparationID: preparations[3].ID, Amount: 500},
+				{PromotionID: tenPercentOff.ID, Name: "10% off", Amount: 84},
+			},
+			totalAmount: 1256,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+
+			promotionRepo := inmem.NewPromotion()
+			for _, promotion := range tc.promotions {
+				require.NoError(t, promotionRepo.Save(context.Background(), promotion), "initial setup failed")
+			}
+			billService := domain.NewBillService(inmem.NewBill(), nil).WithPromotions(promotionRepo).WithClock(tc.clock)
+
+			bill, err := billService.GenerateBill(context.Background(), table)
+
+			require.NoError(t, err, "failed to generate bill")
+			assert.Equal(t, tc.discounts, bill.Discounts, "invalid discounts")
+			assert.Equal(t, tc.totalAmount, bill.TotalAmount, "invalid total amount")
+			assert.Equal(t, 1840-tc.totalAmount, bill.Discount(), "invalid discount")
+		})
+	}
+}
+
+func TestGenerateBillChargesTaxesAndServiceOnDiscountedAmounts(t *testing.T) {
+	promotionRepo := inmem.NewPromotion()
+	halfPrice := domain.Promotion{ID: id.New(), Name: "Half price", Kind: domain.PromotionPercentage, Scope: domain.PromotionScopeBill, Percent: 50, Active: true}
+	require.NoError(t, promotionRepo.Save(context.Background(), halfPrice), "initial setup failed")
+	billService := domain.NewBillService(inmem.NewBill(), nil).
+		WithPromotions(promotionRepo).
+		WithTaxPolicy(domain.TaxPolicy{Exclusive: true, Rates: map[string]float64{"food": 10}}).
+		WithServiceCharge(domain.ServiceChargePolicy{Percent: 10})
+	table := domain.Table{
+		ID:     id.New(),
+		Status: domain.TableStatusClosed,
+		Orders: []domain.Order{{ID: id.New(), Preparations: []domain.Preparation{
+			{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 1000, TaxCategory: "food"}},
+		}}},
+	}
+
+	bill, err := billService.GenerateBill(context.Background(), table)
+
+	require.NoError(t, err, "failed to generate bill")
+	assert.Equal(t, 500, bill.Items[0].Discount, "invalid item discount")
+	assert.Equal(t, []domain.TaxLine{{Rate: 10, Net: 500, Tax: 50, Gross: 550}}, bill.Taxes, "invalid tax breakdown")
+	assert.Equal(t, 50, bill.ServiceCharge, "invalid service charge")
+	assert.Equal(t, 600, bill.TotalAmount, "invalid total amount")
+	assert.Equal(t, 500, bill.Subtotal(), "invalid subtotal")
+}
+
+func TestDiscountBill(t *testing.T) {
+	billRepo := inmem.NewBill()
+	publisher := &recordingPublisher{}
+	billService := domain.NewBillService(billRepo, publisher)
+
+	t.Run("Success", func(t *testing.T) {
+		tt := []struct {
+			testName      string
+			percent       float64
+			amount        int
+			discount      int
+			itemDiscounts []int
+		}{
+			{testName: "percent of the subtotal", percent: 10, discount: 30, itemDiscounts: []int{10, 20}},
+			{testName: "fixed amount", amount: 100, discount: 100, itemDiscounts: []int{34, 66}},
+			{testName: "amount capped to the subtotal", amount: 500, discount: 300, itemDiscounts: []int{100, 200}},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				bill := generateSplittableBill(t, billRepo, 1, 2)
+
+				discounted, err := billService.DiscountBill(context.Background(), bill.ID, tc.percent, tc.amount, "complaint")
+				require.NoError(t, err, "failed to discount bill")
+
+				assert.Equal(t, []domain.Discount{{Name: "Manual discount", Amount: tc.discount, Reason: "complaint"}}, discounted.Discounts, "invalid discounts")
+				assert.Equal(t, 300-tc.discount, discounted.TotalAmount, "invalid total amount")
+				for i, itemDiscount := range tc.itemDiscounts {
+					assert.Equal(t, itemDiscount, discounted.Items[i].Discount, "invalid item discount")
+				}
+
+				saved, err := billRepo.FindByID(context.Background(), bill.ID)
+				require.NoError(t, err, "bill not saved")
+				assert.Equal(t, discounted, saved)
+			})
+		}
+
+		assert.Equal(t, "bill.discounted", publisher.names()[len(publisher.names())-1])
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		pending := generateSplittableBill(t, billRepo, 1, 2)
+		paid := generateSplittableBill(t, billRepo, 1)
+		mustPayBill(t, billService, paid.ID, paid.TotalAmount, 0)
+		discounted := generateSplittableBill(t, billRepo, 1)
+		_, err := billService.DiscountBill(context.Background(), discounted.ID, 100, 0, "on the house")
+		require.NoError(t, err, "initial setup failed")
+
+		tt := []struct {
+			testName string
+			billID   id.ID
+			percent  float64
+			amount   int
+			reason   string
+			errCode  string
+		}{
+			{testName: "no reason", billID: pending.ID, percent: 10, errCode: domain.EINVALID},
+			{testName: "no discount", billID: pending.ID, reason: "complaint", errCode: domain.EINVALID},
+			{testName: "both percent and amount", billID: pending.ID, percent: 10, amount: 10, reason: "complaint", errCode: domain.EINVALID},
+			{testName: "percent over 100", billID: pending.ID, percent: 150, reason: "complaint", errCode: domain.EINVALID},
+			{testName: "bill not found", billID: id.New(), percent: 10, reason: "complaint", errCode: domain.ENOTFOUND},
+			{testName: "bill paid", billID: paid.ID, percent: 10, reason: "complaint", errCode: domain.ECONFLICT},
+			{testName: "nothing left to discount", billID: discounted.ID, amount: 10, reason: "complaint", errCode: domain.EINVALID},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				_, err := billService.DiscountBill(context.Background(), tc.billID, tc.percent, tc.amount, tc.reason)
+				assert.Equal(t, tc.errCode, domain.ErrorCode(err))
+			})
+		}
+	})
+}
+
+func TestSplitBillSharesDiscounts(t *testing.T) {
+	promotionRepo := inmem.NewPromotion()
+	pizza := domain.MenuItem{ID: id.New(), Name: "Pizza", Price: 1000}
+	halfPricePizza := domain.Promotion{ID: id.New(), Name: "Pizza -50%", Kind: domain.PromotionPercentage, Scope: domain.PromotionScopeLine, Percent: 50, MenuItemIDs: []id.ID{pizza.ID}, Stackable: true, Active: true}
+	hundredOff := domain.Promotion{ID: id.New(), Name: "100 off", Kind: domain.PromotionFixedAmount, Scope: domain.PromotionScopeBill, Amount: 100, Stackable: true, Active: true}
+	for _, promotion := range []domain.Promotion{halfPricePizza, hundredOff} {
+		require.NoError(t, promotionRepo.Save(context.Background(), promotion), "initial setup failed")
+	}
+	billService := domain.NewBillService(inmem.NewBill(), nil).WithPromotions(promotionRepo)
+	preparations := []domain.Preparation{
+		{ID: id.New(), MenuItem: pizza, Seat: 1},
+		{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "Pasta", Price: 1500}, Seat: 2},
+	}
+	table := domain.Table{
+		ID:     id.New(),
+		Status: domain.TableStatusClosed,
+		Orders: []domain.Order{{ID: id.New(), Preparations: preparations}},
+	}
+
+	t.Run("by seat", func(t *testing.T) {
+		table := table
+		table.ID = id.New()
+
+		bill, err := billService.GenerateBill(context.Background(), table)
+		require.NoError(t, err, "initial setup failed")
+
+		parts, err := billService.SplitBillBySeat(context.Background(), bill.ID)
+		require.NoError(t, err, "failed to split bill")
+
+		// The 100 off the bill were spread as 25 on the pizza left at 500 and 75 on the pasta.
+		assert.Equal(t, []domain.Discount{
+			{PromotionID: halfPricePizza.ID, Name: "Pizza -50%", PreparationID: preparations[0].ID, Amount: 500},
+			{PromotionID: hundredOff.ID, Name: "100 off", Amount: 25},
+		}, parts[0].Discounts)
+		assert.Equal(t, []domain.Discount{{PromotionID: hundredOff.ID, Name: "100 off", Amount: 75}}, parts[1].Discounts)
+		assert.Equal(t, 475, parts[0].TotalAmount, "invalid first part amount")
+		assert.Equal(t, 1425, parts[1].TotalAmount, "invalid second part amount")
+	})
+
+	t.Run("equally", func(t *testing.T) {
+		table := table
+		table.ID = id.New()
+
+		bill, err := billService.GenerateBill(context.Background(), table)
+		require.NoError(t, err, "initial setup failed")
+
+		parts, err := billService.SplitBillEqually(context.Background(), bill.ID, 2)
+		require.NoError(t, err, "failed to split bill")
+
+		for _, part := range parts {
+			assert.Equal(t, []domain.Discount{
+				{PromotionID: halfPricePizza.ID, Name: "Pizza -50%", Amount: 250},
+				{PromotionID: hundredOff.ID, Name: "100 off", Amount: 50},
+			}, part.Discounts)
+			assert.Equal(t, 950, part.TotalAmount, "invalid part amount")
+		}
+	})
+}
@@ -0,0 +1,371 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"order_manager/internal/id"
+	"slices"
+	"time"
+)
+
+type ReservationStatus string
+
+const (
+	ReservationStatusBooked    ReservationStatus = "booked"
+	ReservationStatusConfirmed ReservationStatus = "confirmed"
+	ReservationStatusSeated    ReservationStatus = "seated"
+	ReservationStatusNoShow    ReservationStatus = "no show"
+	ReservationStatusCancelled ReservationStatus = "cancelled"
+)
+
+func (s ReservationStatus) IsValid() bool {
+	switch s {
+	case ReservationStatusBooked, ReservationStatusConfirmed, ReservationStatusSeated, ReservationStatusNoShow, ReservationStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// Reservation is a booking of a party for a time of a day.
+type Reservation struct {
+	ID        id.ID
+	GuestName string
+	Contact   string
+	PartySize int
+	StartsAt  time.Time
+	Duration  time.Duration
+	// DiningTableID is the dining table assigned to the party, nil until one is.
+	DiningTableID id.ID
+	// TableID is the table opened when the party was seated, nil until it is.
+	TableID id.ID
+	Status  ReservationStatus
+}
+
+func (r Reservation) IsValid() bool {
+	if r.ID == id.NilID() || r.GuestName == "" || r.PartySize <= 0 || r.StartsAt.IsZero() || r.Duration <= 0 || !r.Status.IsValid() {
+		return false
+	}
+
+	return (r.Status == ReservationStatusSeated) == (r.TableID != id.NilID()) && (r.TableID == id.NilID() || r.DiningTableID != id.NilID())
+}
+
+// EndsAt is the time the dining table of the reservation is free again.
+func (r Reservation) EndsAt() time.Time {
+	return r.StartsAt.Add(r.Duration)
+}
+
+// isPending reports whether the party is still expected, booked or confirmed.
+func (r Reservation) isPending() bool {
+	return r.Status == ReservationStatusBooked || r.Status == ReservationStatusConfirmed
+}
+
+// holds reports whether the reservation keeps its dining table from a time until another, excluded.
+// The no-shows and the cancelled reservations hold no table.
+func (r Reservation) holds(diningTableID id.ID, from time.Time, to time.Time) bool {
+	if r.DiningTableID != diningTableID || !(r.isPending() || r.Status == ReservationStatusSeated) {
+		return false
+	}
+
+	return r.StartsAt.Before(to) && from.Before(r.EndsAt())
+}
+
+// Overlaps reports whether another reservation holds the dining table of the reservation at an overlapping time,
+// in which case they cannot both be kept.
+func (r Reservation) Overlaps(other Reservation) bool {
+	if r.ID == other.ID || r.DiningTableID == id.NilID() || !r.holds(r.DiningTableID, r.StartsAt, r.EndsAt()) {
+		return false
+	}
+
+	return other.holds(r.DiningTableID, r.StartsAt, r.EndsAt())
+}
+
+type ReservationRepository interface {
+	// Save saves a reservation. The reservations holding a dining table are checked against the others
+	// along with the write, so that two reservations overlapping on a dining table are never both saved.
+	// Possible errors:
+	// - ECONFLICT if the reservation overlaps another one.
+	Save(ctx context.Context, reservation Reservation) error
+	FindByID(ctx context.Context, id id.ID) (Reservation, error)
+	// FindBetween returns the reservations overlapping a time range, the end excluded, ordered by their start.
+	FindBetween(ctx context.Context, from time.Time, to time.Time) ([]Reservation, error)
+}
+
+type ReservationService struct {
+	repo             ReservationRepository
+	diningTablesRepo DiningTableRepository
+	tables           *TableService
+	events           EventPublisher
+	clock            Clock
+}
+
+// NewReservationService creates a new reservation service.
+// The service is responsible for the bookings of the parties, from the availability of the dining tables
+// to the opening of their table when they are seated.
+func NewReservationService(repo ReservationRepository, diningTablesRepo DiningTableRepository, tables *TableService, events EventPublisher) *ReservationService {
+	return &ReservationService{repo: repo, diningTablesRepo: diningTablesRepo, tables: tables, events: events, clock: SystemClock{}}
+}
+
+// WithClock replaces the clock telling the past bookings and the no-shows apart.
+func (s *ReservationService) WithClock(clock Clock) *ReservationService {
+	s.clock = clock
+	return s
+}
+
+// BookReservation books a party, its ID and status are assigned by the service.
+// The dining table is optional, it can be assigned later on.
+// Possible errors:
+// - EINVALID if the reservation is invalid or starts in the past.
+// - ENOTFOUND if the dining table could not be found.
+// - EINVALID if the dining table seats less guests than the party.
+// - ECONFLICT if the dining table is held by another reservation at that time, including one booked concurrently.
+// - Any error returned by the repository when saving the reservation.
+func (s *ReservationService) BookReservation(ctx context.Context, reservation Reservation) (Reservation, error) {
+	reservation.ID = id.New()
+	reservation.TableID = id.NilID()
+	reservation.Status = ReservationStatusBooked
+
+	if !reservation.IsValid() {
+		return Reservation{}, Errorf(EINVALID, "invalid reservation")
+	}
+
+	if reservation.StartsAt.Before(s.clock.Now()) {
+		return Reservation{}, Errorf(EINVALID, "reservation starts in the past at %s", reservation.StartsAt)
+	}
+
+	if reservation.DiningTableID != id.NilID() {
+		if err := s.checkAvailable(ctx, reservation, reservation.DiningTableID); err != nil {
+			return Reservation{}, err
+		}
+	}
+
+	if err := s.repo.Save(ctx, reservation); err != nil {
+		return Reservation{}, err
+	}
+
+	publish(ctx, s.events, ReservationBooked{Reservation: reservation})
+
+	return reservation, nil
+}
+
+// FindReservation returns a reservation by its ID.
+// Possible errors:
+// - ENOTFOUND if the reservation could not be found.
+func (s *ReservationService) FindReservation(ctx context.Context, reservationID id.ID) (Reservation, error) {
+	return s.repo.FindByID(ctx, reservationID)
+}
+
+// FindReservations returns the reservations overlapping a time range, the end excluded, ordered by their start.
+// Possible errors:
+// - EINVALID if the time range is empty.
+// - Any error returned by the repository when fetching the reservations.
+func (s *ReservationService) FindReservations(ctx context.Context, from time.Time, to time.Time) ([]Reservation, error) {
+	if !from.Before(to) {
+		return nil, Errorf(EINVALID, "invalid time range from %s to %s", from, to)
+	}
+
+	return s.repo.FindBetween(ctx, from, to)
+}
+
+// FindAvailableDiningTables returns the dining tables seating a party that no reservation holds
+// for the time of a booking, the smallest first.
+// Possible errors:
+// - EINVALID if the party size or the duration is not positive.
+// - Any error returned by the repositories when fetching the dining tables or the reservations.
+func (s *ReservationService) FindAvailableDiningTables(ctx context.Context, partySize int, startsAt time.Time, duration time.Duration) ([]DiningTable, error) {
+	if partySize <= 0 || duration <= 0 {
+		return nil, Errorf(EINVALID, "party size and duration must be positive")
+	}
+
+	diningTables, err := s.diningTablesRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endsAt := startsAt.Add(duration)
+	reservations, err := s.repo.FindBetween(ctx, startsAt, endsAt)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make([]DiningTable, 0)
+	for _, diningTable := range diningTables {
+		if diningTable.Capacity < partySize {
+			continue
+		}
+
+		if slices.ContainsFunc(reservations, func(r Reservation) bool { return r.holds(diningTable.ID, startsAt, endsAt) }) {
+			continue
+		}
+
+		available = append(available, diningTable)
+	}
+
+	slices.SortStableFunc(available, func(a, b DiningTable) int { return a.Capacity - b.Capacity })
+
+	return available, nil
+}
+
+// AssignDiningTable assigns a dining table to a reservation still expected, replacing the one assigned.
+// Possible errors:
+// - ENOTFOUND if the reservation or the dining table could not be found.
+// - EINVALID if the party is seated, did not show up or cancelled.
+// - EINVALID if the dining table seats less guests than the party.
+// - ECONFLICT if the dining table is held by another reservation at that time, including one booked concurrently.
+// - Any error returned by the repository when saving the reservation.
+func (s *ReservationService) AssignDiningTable(ctx context.Context, reservationID id.ID, diningTableID id.ID) (Reservation, error) {
+	reservation, err := s.repo.FindByID(ctx, reservationID)
+	if err != nil {
+		return Reservation{}, err
+	}
+
+	if !reservation.isPending() {
+		return Reservation{}, Errorf(EINVALID, "reservation %s is %s", reservationID, reservation.Status)
+	}
+
+	if err := s.checkAvailable(ctx, reservation, diningTableID); err != nil {
+		return Reservation{}, err
+	}
+
+	reservation.DiningTableID = diningTableID
+
+	return s.update(ctx, reservation)
+}
+
+// ConfirmReservation confirms a booked reservation.
+// Possible errors:
+// - ENOTFOUND if the reservation could not be found.
+// - EINVALID if the reservation is not booked.
+// - Any error returned by the repository when saving the reservation.
+func (s *ReservationService) ConfirmReservation(ctx context.Context, reservationID id.ID) (Reservation, error) {
+	reservation, err := s.repo.FindByID(ctx, reservationID)
+	if err != nil {
+		return Reservation{}, err
+	}
+
+	if reservation.Status != ReservationStatusBooked {
+		return Reservation{}, Errorf(EINVALID, "reservation %s is %s", reservationID, reservation.Status)
+	}
+
+	reservation.Status = ReservationStatusConfirmed
+
+	return s.update(ctx, reservation)
+}
+
+// SeatReservation seats the party of a reservation still expected by opening a table on its dining table,
+// the table is linked to the reservation.
+// Possible errors:
+// - ENOTFOUND if the reservation could not be found.
+// - EINVALID if the party is seated, did not show up or cancelled.
+// - EINVALID if no dining table is assigned to the reservation.
+// - Any error returned by the table service when opening the table.
+// - Any error returned by the repository when saving the reservation, the table being discarded.
+func (s *ReservationService) SeatReservation(ctx context.Context, reservationID id.ID) (Reservation, error) {
+	reservation, err := s.repo.FindByID(ctx, reservationID)
+	if err != nil {
+		return Reservation{}, err
+	}
+
+	if !reservation.isPending() {
+		return Reservation{}, Errorf(EINVALID, "reservation %s is %s", reservationID, reservation.Status)
+	}
+
+	if reservation.DiningTableID == id.NilID() {
+		return Reservation{}, Errorf(EINVALID, "no dining table is assigned to reservation %s", reservationID)
+	}
+
+	table, err := s.tables.OpenTable(ctx, reservation.DiningTableID, reservation.PartySize)
+	if err != nil {
+		return Reservation{}, err
+	}
+
+	reservation.TableID = table.ID
+	reservation.Status = ReservationStatusSeated
+
+	seated, err := s.update(ctx, reservation)
+	if err != nil {
+		return Reservation{}, errors.Join(err, s.tables.discardTable(ctx, table))
+	}
+
+	return seated, nil
+}
+
+// MarkNoShow records that the party of a reservation still expected did not show up.
+// Possible errors:
+// - ENOTFOUND if the reservation could not be found.
+// - EINVALID if the party is seated, did not show up or cancelled.
+// - EINVALID if the reservation has not started yet.
+// - Any error returned by the repository when saving the reservation.
+func (s *ReservationService) MarkNoShow(ctx context.Context, reservationID id.ID) (Reservation, error) {
+	reservation, err := s.repo.FindByID(ctx, reservationID)
+	if err != nil {
+		return Reservation{}, err
+	}
+
+	if !reservation.isPending() {
+		return Reservation{}, Errorf(EINVALID, "reservation %s is %s", reservationID, reservation.Status)
+	}
+
+	if s.clock.Now().Before(reservation.StartsAt) {
+		return Reservation{}, Errorf(EINVALID, "reservation %s starts at %s", reservationID, reservation.StartsAt)
+	}
+
+	reservation.Status = ReservationStatusNoShow
+
+	return s.update(ctx, reservation)
+}
+
+// CancelReservation cancels a reservation still expected, freeing its dining table.
+// Possible errors:
+// - ENOTFOUND if the reservation could not be found.
+// - EINVALID if the party is seated, did not show up or cancelled.
+// - Any error returned by the repository when saving the reservation.
+func (s *ReservationService) CancelReservation(ctx context.Context, reservationID id.ID) (Reservation, error) {
+	reservation, err := s.repo.FindByID(ctx, reservationID)
+	if err != nil {
+		return Reservation{}, err
+	}
+
+	if !reservation.isPending() {
+		return Reservation{}, Errorf(EINVALID, "reservation %s is %s", reservationID, reservation.Status)
+	}
+
+	reservation.Status = ReservationStatusCancelled
+
+	return s.update(ctx, reservation)
+}
+
+// checkAvailable checks that a dining table seats the party of a reservation
+// and that no other reservation holds it for the time of the reservation.
+func (s *ReservationService) checkAvailable(ctx context.Context, reservation Reservation, diningTableID id.ID) error {
+	diningTable, err := s.diningTablesRepo.FindByID(ctx, diningTableID)
+	if err != nil {
+		return err
+	}
+
+	if reservation.PartySize > diningTable.Capacity {
+		return Errorf(EINVALID, "dining table %s seats %d guests, got %d", diningTable.Name, diningTable.Capacity, reservation.PartySize)
+	}
+
+	reservations, err := s.repo.FindBetween(ctx, reservation.StartsAt, reservation.EndsAt())
+	if err != nil {
+		return err
+	}
+
+	for _, r := range reservations {
+		if r.ID != reservation.ID && r.holds(diningTableID, reservation.StartsAt, reservation.EndsAt()) {
+			return Errorf(ECONFLICT, "dining table %s is reserved from %s to %s", diningTable.Name, r.StartsAt, r.EndsAt())
+		}
+	}
+
+	return nil
+}
+
+func (s *ReservationService) update(ctx context.Context, reservation Reservation) (Reservation, error) {
+	if err := s.repo.Save(ctx, reservation); err != nil {
+		return Reservation{}, err
+	}
+
+	publish(ctx, s.events, ReservationUpdated{Reservation: reservation})
+
+	return reservation, nil
+}
@@ -0,0 +1,298 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"order_manager/internal/inmem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookReservation(t *testing.T) {
+	ctx := context.Background()
+	diningTableRepo := inmem.NewDiningTable()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	publisher := &recordingPublisher{}
+	tableService := domain.NewTableService(inmem.NewTable(), diningTableRepo, nil)
+	reservationService := domain.NewReservationService(inmem.NewReservation(), diningTableRepo, tableService, publisher).WithClock(fixedClock(now))
+
+	small := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 2}
+	large := domain.DiningTable{ID: id.New(), Name: "T2", Capacity: 6}
+	require.NoError(t, diningTableRepo.Save(ctx, small), "Initial setup failed")
+	require.NoError(t, diningTableRepo.Save(ctx, large), "Initial setup failed")
+
+	dinner := now.Add(8 * time.Hour)
+	reservation, err := reservationService.BookReservation(ctx, domain.Reservation{GuestName: "Smith", Contact: "555-0100", PartySize: 4, StartsAt: dinner, Duration: 2 * time.Hour, DiningTableID: large.ID})
+	require.NoError(t, err, "book reservation failed")
+	assert.NotEqual(t, id.NilID(), reservation.ID, "generated reservation ID is nil")
+	assert.Equal(t, domain.ReservationStatusBooked, reservation.Status)
+	assert.Equal(t, dinner.Add(2*time.Hour), reservation.EndsAt())
+	assert.Equal(t, []string{"reservation.booked"}, publisher.names())
+
+	saved, err := reservationService.FindReservation(ctx, reservation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, reservation, saved, "reservation not correctly saved")
+
+	tt := []struct {
+		testName    string
+		reservation domain.Reservation
+		code        string
+	}{
+		{testName: "No guest name", reservation: domain.Reservation{PartySize: 2, StartsAt: dinner, Duration: time.Hour}, code: domain.EINVALID},
+		{testName: "No party", reservation: domain.Reservation{GuestName: "Doe", StartsAt: dinner, Duration: time.Hour}, code: domain.EINVALID},
+		{testName: "No duration", reservation: domain.Reservation{GuestName: "Doe", PartySize: 2, StartsAt: dinner}, code: domain.EINVALID},
+		{testName: "In the past", reservation: domain.Reservation{GuestName: "Doe", PartySize: 2, StartsAt: now.Add(-time.Hour), Duration: time.Hour}, code: domain.EINVALID},
+		{testName: "Unknown dining table", reservation: domain.Reservation{GuestName: "Doe", PartySize: 2, StartsAt: dinner, Duration: time.Hour, DiningTableID: id.New()}, code: domain.ENOTFOUND},
+		{testName: "Party too large", reservation: domain.Reservation{GuestName: "Doe", PartySize: 3, StartsAt: dinner, Duration: time.Hour, DiningTableID: small.ID}, code: domain.EINVALID},
+		{testName: "Overlapping booking", reservation: domain.Reservation{GuestName: "Doe", PartySize: 2, StartsAt: dinner.Add(time.Hour), Duration: time.Hour, DiningTableID: large.ID}, code: domain.ECONFLICT},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			_, err := reservationService.BookReservation(ctx, tc.reservation)
+
+			assert.Equal(t, tc.code, domain.ErrorCode(err), "invalid error code")
+		})
+	}
+
+	// A booking starting when the other ends does not overlap it.
+	_, err = reservationService.BookReservation(ctx, domain.Reservation{GuestName: "Doe", PartySize: 2, StartsAt: dinner.Add(2 * time.Hour), Duration: time.Hour, DiningTableID: large.ID})
+	assert.NoError(t, err)
+}
+
+func TestSaveOverlappingReservation(t *testing.T) {
+	ctx := context.Background()
+	diningTableRepo := inmem.NewDiningTable()
+	reservationRepo := inmem.NewReservation()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	tableService := domain.NewTableService(inmem.NewTable(), diningTableRepo, nil)
+	reservationService := domain.NewReservationService(reservationRepo, diningTableRepo, tableService, nil).WithClock(fixedClock(now))
+
+	diningTable := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 4}
+	require.NoError(t, diningTableRepo.Save(ctx, diningTable), "Initial setup failed")
+
+	dinner := now.Add(8 * time.Hour)
+	booked, err := reservationService.BookReservation(ctx, domain.Reservation{GuestName: "Smith", PartySize: 4, StartsAt: dinner, Duration: 2 * time.Hour, DiningTableID: diningTable.ID})
+	require.NoError(t, err, "Initial setup failed")
+
+	// A booking checked before the other one was saved is still rejected when saving it.
+	concurrent := domain.Reservation{ID: id.New(), GuestName: "Doe", PartySize: 2, StartsAt: dinner.Add(time.Hour), Duration: time.Hour, DiningTableID: diningTable.ID, Status: domain.ReservationStatusBooked}
+	err = reservationRepo.Save(ctx, concurrent)
+	assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "overlapping reservation saved")
+
+	_, err = reservationService.FindReservation(ctx, concurrent.ID)
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "overlapping reservation saved")
+
+	assert.True(t, concurrent.Overlaps(booked))
+	concurrent.Status = domain.ReservationStatusCancelled
+	assert.False(t, concurrent.Overlaps(booked), "cancelled reservation holds the dining table")
+	assert.False(t, booked.Overlaps(booked), "reservation overlaps itself")
+}
+
+func TestFindAvailableDiningTables(t *testing.T) {
+	ctx := context.Background()
+	diningTableRepo := inmem.NewDiningTable()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	tableService := domain.NewTableService(inmem.NewTable(), diningTableRepo, nil)
+	reservationService := domain.NewReservationService(inmem.NewReservation(), diningTableRepo, tableService, nil).WithClock(fixedClock(now))
+
+	two := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 2}
+	six := domain.DiningTable{ID: id.New(), Name: "T2", Capacity: 6}
+	four := domain.DiningTable{ID: id.New(), Name: "T3", Capacity: 4}
+	for _, diningTable := range []domain.DiningTable{two, six, four} {
+		require.NoError(t, diningTableRepo.Save(ctx, diningTable), "Initial setup failed")
+	}
+
+	dinner := now.Add(8 * time.Hour)
+	booked, err := reservationService.BookReservation(ctx, domain.Reservation{GuestName: "Smith", PartySize: 4, StartsAt: dinner, Duration: 2 * time.Hour, DiningTableID: four.ID})
+	require.NoError(t, err, "Initial setup failed")
+
+	available, err := reservationService.FindAvailableDiningTables(ctx, 3, dinner.Add(time.Hour), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.DiningTable{six}, available, "reserved or too small dining tables are available")
+
+	available, err = reservationService.FindAvailableDiningTables(ctx, 2, dinner.Add(2*time.Hour), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.DiningTable{two, four, six}, available, "dining tables not sorted by capacity")
+
+	// A cancelled reservation frees its dining table.
+	_, err = reservationService.CancelReservation(ctx, booked.ID)
+	require.NoError(t, err)
+	available, err = reservationService.FindAvailableDiningTables(ctx, 3, dinner, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.DiningTable{four, six}, available)
+
+	_, err = reservationService.FindAvailableDiningTables(ctx, 0, dinner, time.Hour)
+	assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+}
+
+func TestReservationLifecycle(t *testing.T) {
+	ctx := context.Background()
+	diningTableRepo := inmem.NewDiningTable()
+	tableRepo := inmem.NewTable()
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	clock := &manualClock{now: now}
+	publisher := &recordingPublisher{}
+	tableService := domain.NewTableService(tableRepo, diningTableRepo, publisher)
+	reservationService := domain.NewReservationService(inmem.NewReservation(), diningTableRepo, tableService, publisher).WithClock(clock)
+
+	first := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 4}
+	second := domain.DiningTable{ID: id.New(), Name: "T2", Capacity: 4}
+	require.NoError(t, diningTableRepo.Save(ctx, first), "Initial setup failed")
+	require.NoError(t, diningTableRepo.Save(ctx, second), "Initial setup failed")
+
+	dinner := now.Add(8 * time.Hour)
+	book := func() domain.Reservation {
+		reservation, err := reservationService.BookReservation(ctx, domain.Reservation{GuestName: "Smith", PartySize: 3, StartsAt: dinner, Duration: 2 * time.Hour})
+		require.NoError(t, err, "Initial setup failed")
+		return reservation
+	}
+
+	t.Run("Seat", func(t *testing.T) {
+		reservation := book()
+
+		_, err := reservationService.SeatReservation(ctx, reservation.ID)
+		assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "seated without a dining table")
+
+		reservation, err = reservationService.ConfirmReservation(ctx, reservation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReservationStatusConfirmed, reservation.Status)
+
+		_, err = reservationService.AssignDiningTable(ctx, reservation.ID, first.ID)
+		require.NoError(t, err)
+
+		publisher.events = nil
+		seated, err := reservationService.SeatReservation(ctx, reservation.ID)
+		require.NoError(t, err, "seat reservation failed")
+		assert.Equal(t, domain.ReservationStatusSeated, seated.Status)
+		assert.Equal(t, []string{"table.opened", "reservation.updated"}, publisher.names())
+
+		table, err := tableRepo.FindByID(ctx, seated.TableID)
+		require.NoError(t, err, "seated table not opened")
+		assert.Equal(t, first.ID, table.DiningTableID)
+		assert.Equal(t, 3, table.GuestCount)
+		assert.Equal(t, domain.TableStatusOpened, table.Status)
+
+		tt := []struct {
+			testName string
+			update   func(ctx context.Context, reservationID id.ID) (domain.Reservation, error)
+		}{
+			{testName: "Confirm", update: reservationService.ConfirmReservation},
+			{testName: "Seat again", update: reservationService.SeatReservation},
+			{testName: "No-show", update: reservationService.MarkNoShow},
+			{testName: "Cancel", update: reservationService.CancelReservation},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				_, err := tc.update(ctx, seated.ID)
+
+				assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+	})
+
+	t.Run("Seat on an occupied dining table", func(t *testing.T) {
+		reservation := book()
+		_, err := reservationService.AssignDiningTable(ctx, reservation.ID, first.ID)
+		assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "assigned a reserved dining table")
+
+		_, err = reservationService.AssignDiningTable(ctx, reservation.ID, second.ID)
+		require.NoError(t, err)
+		_, err = tableService.OpenTable(ctx, second.ID, 2)
+		require.NoError(t, err, "Initial setup failed")
+
+		_, err = reservationService.SeatReservation(ctx, reservation.ID)
+		assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "invalid error code")
+
+		saved, err := reservationService.FindReservation(ctx, reservation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReservationStatusBooked, saved.Status, "reservation seated on an occupied dining table")
+	})
+
+	t.Run("No-show", func(t *testing.T) {
+		reservation := book()
+
+		_, err := reservationService.MarkNoShow(ctx, reservation.ID)
+		assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "no-show before the reservation started")
+
+		clock.advance(8*time.Hour + 15*time.Minute)
+		defer func() { clock.now = now }()
+
+		reservation, err = reservationService.MarkNoShow(ctx, reservation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReservationStatusNoShow, reservation.Status)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		reservation, err := reservationService.CancelReservation(ctx, book().ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ReservationStatusCancelled, reservation.Status)
+
+		_, err = reservationService.ConfirmReservation(ctx, reservation.ID)
+		assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "confirmed a cancelled reservation")
+	})
+
+	t.Run("Unknown reservation", func(t *testing.T) {
+		_, err := reservationService.CancelReservation(ctx, id.New())
+		assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
+	})
+}
+
+// failingReservationRepo fails the saves once fail is set, as a lost database would.
+type failingReservationRepo struct {
+	*inmem.Reservation
+	fail bool
+}
+
+func (r *failingReservationRepo) Save(ctx context.Context, reservation domain.Reservation) error {
+	if r.fail {
+		return errors.New("database is gone")
+	}
+
+	return r.Reservation.Save(ctx, reservation)
+}
+
+func TestSeatReservationDiscardsTableOnFailure(t *testing.T) {
+	ctx := context.Background()
+	diningTableRepo := inmem.NewDiningTable()
+	tableRepo := inmem.NewTable()
+	reservationRepo := &failingReservationRepo{Reservation: inmem.NewReservation()}
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	publisher := &recordingPublisher{}
+	tableService := domain.NewTableService(tableRepo, diningTableRepo, publisher).WithClock(fixedClock(now))
+	waitlistService := domain.NewWaitlistService(inmem.NewWaitlist(), tableRepo, diningTableRepo, tableService, publisher).WithClock(fixedClock(now))
+	tableService.WithWaitlist(waitlistService)
+	reservationService := domain.NewReservationService(reservationRepo, diningTableRepo, tableService, publisher).WithClock(fixedClock(now))
+
+	diningTable := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 4}
+	require.NoError(t, diningTableRepo.Save(ctx, diningTable), "Initial setup failed")
+	reservation, err := reservationService.BookReservation(ctx, domain.Reservation{GuestName: "Smith", PartySize: 4, StartsAt: now.Add(10 * time.Minute), Duration: time.Hour, DiningTableID: diningTable.ID})
+	require.NoError(t, err, "Initial setup failed")
+	_, err = waitlistService.JoinWaitlist(ctx, "Doe", "", 2)
+	require.NoError(t, err, "Initial setup failed")
+
+	reservationRepo.fail = true
+	publisher.events = nil
+	_, err = reservationService.SeatReservation(ctx, reservation.ID)
+	require.Error(t, err, "seat reservation did not fail")
+
+	// The table is discarded without closing it: no party is suggested and no turn time is recorded.
+	assert.Equal(t, []string{"table.opened"}, publisher.names())
+	opened, err := tableRepo.FindByStatus(ctx, domain.TableStatusOpened)
+	require.NoError(t, err)
+	assert.Empty(t, opened, "table left opened")
+	closed, err := tableRepo.FindClosedBetween(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, closed, "discarded table timed as a closed one")
+
+	reservationRepo.fail = false
+	seated, err := reservationService.SeatReservation(ctx, reservation.ID)
+	require.NoError(t, err, "dining table not freed by the discarded table")
+	assert.Equal(t, domain.ReservationStatusSeated, seated.Status)
+}
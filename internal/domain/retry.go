@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RetryPolicy controls how commands failing with ESTALE are retried.
+// Commands are only retried when they re-read and re-validate the aggregate on every attempt.
+type RetryPolicy struct {
+	// Attempts is the maximum number of attempts, 1 or less disables retries.
+	Attempts int
+	// Backoff is the wait before the second attempt, it grows linearly with each attempt.
+	Backoff time.Duration
+}
+
+func retry(ctx context.Context, policy RetryPolicy, fn func() error) error {
+	err := fn()
+	for attempt := 1; attempt < policy.Attempts && ErrorCode(err) == ESTALE; attempt++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * policy.Backoff):
+		}
+
+		err = fn()
+	}
+
+	return err
+}
@@ -0,0 +1,234 @@
+package domain
+
+import (
+	"context"
+	"order_manager/internal/id"
+	"slices"
+)
+
+// Station is a part of the kitchen, or the bar, working only the preparations routed to it.
+type Station struct {
+	ID   id.ID
+	Name string
+	// MenuItemIDs are the menu items routed to the station.
+	MenuItemIDs []id.ID
+	// CategoryIDs route the items of the menu categories to the station, unless an item is routed to a station itself.
+	CategoryIDs []id.ID
+}
+
+func (s Station) IsValid() bool {
+	if s.ID == id.NilID() || s.Name == "" {
+		return false
+	}
+
+	for _, ids := range [][]id.ID{s.MenuItemIDs, s.CategoryIDs} {
+		for i, itemID := range ids {
+			if itemID == id.NilID() || slices.Contains(ids[:i], itemID) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// StationProgress is the progress of a station on the preparations of an order routed to it.
+type StationProgress struct {
+	// StationID is nil for the preparations routed to no station.
+	StationID id.ID
+	// Remaining is the number of preparations the station has yet to finish, held, pending or in progress.
+	Remaining int
+}
+
+// Stations rolls the preparations of the order up per station, in the order the stations first appear.
+// The aborted preparations are left out.
+func (o Order) Stations() []StationProgress {
+	stations := make([]StationProgress, 0)
+	for _, prep := range o.Preparations {
+		if prep.Status == PreparationStatusAborted {
+			continue
+		}
+
+		idx := slices.IndexFunc(stations, func(s StationProgress) bool { return s.StationID == prep.StationID })
+		if idx == -1 {
+			stations = append(stations, StationProgress{StationID: prep.StationID})
+			idx = len(stations) - 1
+		}
+
+		if prep.Status == PreparationStatusHeld || prep.Status == PreparationStatusPending || prep.Status == PreparationStatusInProgress {
+			stations[idx].Remaining++
+		}
+	}
+
+	return stations
+}
+
+// IsReady reports whether every station is done with the order, all its preparations that were not aborted
+// being ready or served. An order of which every preparation was aborted is not ready.
+func (o Order) IsReady() bool {
+	stations := o.Stations()
+	for _, station := range stations {
+		if station.Remaining > 0 {
+			return false
+		}
+	}
+
+	return len(stations) > 0
+}
+
+type StationRepository interface {
+	// Save persists the station with its routes, replacing the ones saved.
+	Save(ctx context.Context, station Station) error
+	FindByID(ctx context.Context, id id.ID) (Station, error)
+	FindAll(ctx context.Context) ([]Station, error)
+}
+
+type StationService struct {
+	repo   StationRepository
+	menu   MenuRepository
+	events EventPublisher
+}
+
+// NewStationService creates a new station service.
+// The service is responsible for the stations of the kitchen and for routing the ordered menu items to them.
+func NewStationService(repo StationRepository, menu MenuRepository, events EventPublisher) *StationService {
+	return &StationService{repo: repo, menu: menu, events: events}
+}
+
+// CreateStation creates a station routed no menu item, its ID is assigned by the service.
+// Possible errors:
+// - EINVALID if the name is empty.
+// - Any error returned by the repository when saving the station.
+func (s *StationService) CreateStation(ctx context.Context, name string) (Station, error) {
+	station := Station{ID: id.New(), Name: name, MenuItemIDs: make([]id.ID, 0), CategoryIDs: make([]id.ID, 0)}
+	if !station.IsValid() {
+		return Station{}, Errorf(EINVALID, "invalid station")
+	}
+
+	if err := s.repo.Save(ctx, station); err != nil {
+		return Station{}, err
+	}
+
+	publish(ctx, s.events, StationCreated{Station: station})
+
+	return station, nil
+}
+
+// FindStation returns a station with its routes.
+// Possible errors:
+// - ENOTFOUND if the station could not be found.
+func (s *StationService) FindStation(ctx context.Context, stationID id.ID) (Station, error) {
+	return s.repo.FindByID(ctx, stationID)
+}
+
+// FindAllStations returns all the stations with their routes.
+// Possible errors:
+// - Any error returned by the repository when fetching the stations.
+func (s *StationService) FindAllStations(ctx context.Context) ([]Station, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// RouteToStation replaces the menu items and the menu categories routed to a station.
+// A menu item or a category is routed to one station at most, the orders taken before keep their routing.
+// Possible errors:
+// - EINVALID if an ID is nil or repeated.
+// - ENOTFOUND if the station, a menu item or a category could not be found.
+// - ECONFLICT if a menu item or a category is routed to another station.
+// - Any error returned by the repository when saving the station.
+func (s *StationService) RouteToStation(ctx context.Context, stationID id.ID, menuItemIDs []id.ID, categoryIDs []id.ID) (Station, error) {
+	station, err := s.repo.FindByID(ctx, stationID)
+	if err != nil {
+		return Station{}, err
+	}
+
+	station.MenuItemIDs = menuItemIDs
+	station.CategoryIDs = categoryIDs
+	if !station.IsValid() {
+		return Station{}, Errorf(EINVALID, "invalid routes for station %s", stationID)
+	}
+
+	if _, err := s.menu.FindItems(ctx, menuItemIDs); err != nil {
+		return Station{}, err
+	}
+
+	for _, categoryID := range categoryIDs {
+		if _, err := s.menu.FindCategory(ctx, categoryID); err != nil {
+			return Station{}, err
+		}
+	}
+
+	stations, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return Station{}, err
+	}
+
+	for _, other := range stations {
+		if other.ID == station.ID {
+			continue
+		}
+
+		for _, itemID := range menuItemIDs {
+			if slices.Contains(other.MenuItemIDs, itemID) {
+				return Station{}, Errorf(ECONFLICT, "menu item %s is routed to station %s", itemID, other.Name)
+			}
+		}
+
+		for _, categoryID := range categoryIDs {
+			if slices.Contains(other.CategoryIDs, categoryID) {
+				return Station{}, Errorf(ECONFLICT, "menu category %s is routed to station %s", categoryID, other.Name)
+			}
+		}
+	}
+
+	if err := s.repo.Save(ctx, station); err != nil {
+		return Station{}, err
+	}
+
+	publish(ctx, s.events, StationRouted{Station: station})
+
+	return station, nil
+}
+
+// route sets the station of the preparations, from the station of their menu item or else from that of
+// one of its categories. The preparations of menu items routed nowhere are left without station.
+func (s *StationService) route(ctx context.Context, preparations []Preparation) error {
+	stations, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	var categories []MenuCategory
+	if slices.ContainsFunc(stations, func(station Station) bool { return len(station.CategoryIDs) > 0 }) {
+		if categories, err = s.menu.FindAllCategories(ctx); err != nil {
+			return err
+		}
+	}
+
+	for i, prep := range preparations {
+		preparations[i].StationID = routeMenuItem(stations, categories, prep.MenuItem.ID)
+	}
+
+	return nil
+}
+
+func routeMenuItem(stations []Station, categories []MenuCategory, menuItemID id.ID) id.ID {
+	for _, station := range stations {
+		if slices.Contains(station.MenuItemIDs, menuItemID) {
+			return station.ID
+		}
+	}
+
+	for _, category := range categories {
+		if !slices.ContainsFunc(category.MenuItems, func(item MenuItem) bool { return item.ID == menuItemID }) {
+			continue
+		}
+
+		for _, station := range stations {
+			if slices.Contains(station.CategoryIDs, category.ID) {
+				return station.ID
+			}
+		}
+	}
+
+	return id.NilID()
+}
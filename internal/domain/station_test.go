@@ -0,0 +1,207 @@
+package domain_test
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"order_manager/internal/inmem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateStation(t *testing.T) {
+	stationService := domain.NewStationService(inmem.NewStation(), inmem.NewMenu(), nil)
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		station, err := stationService.CreateStation(context.Background(), "grill")
+
+		require.NoError(t, err, "create station failed")
+		assert.NotEqual(t, id.NilID(), station.ID, "generated station ID is nil")
+		assert.Empty(t, station.MenuItemIDs, "station has menu items")
+
+		saved, err := stationService.FindStation(context.Background(), station.ID)
+		require.NoError(t, err)
+		assert.Equal(t, station, saved, "station not correctly saved")
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := stationService.CreateStation(context.Background(), "")
+		assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+
+		_, err = stationService.FindStation(context.Background(), id.New())
+		assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
+	})
+}
+
+func TestRouteToStation(t *testing.T) {
+	ctx := context.Background()
+	menuRepo := inmem.NewMenu()
+	menuService := domain.NewMenuService(menuRepo, nil)
+	stationService := domain.NewStationService(inmem.NewStation(), menuRepo, nil)
+
+	burger, err := menuService.CreateMenuItem(ctx, "burger", 1200, "")
+	require.NoError(t, err, "Initial setup failed")
+	fries, err := menuService.CreateMenuItem(ctx, "fries", 400, "")
+	require.NoError(t, err, "Initial setup failed")
+	mains, err := menuService.CreateCategory(ctx, "mains")
+	require.NoError(t, err, "Initial setup failed")
+
+	grill, err := stationService.CreateStation(ctx, "grill")
+	require.NoError(t, err, "Initial setup failed")
+	fryer, err := stationService.CreateStation(ctx, "fryer")
+	require.NoError(t, err, "Initial setup failed")
+
+	station, err := stationService.RouteToStation(ctx, grill.ID, []id.ID{burger.ID}, []id.ID{mains.ID})
+	require.NoError(t, err, "route to station failed")
+	assert.Equal(t, []id.ID{burger.ID}, station.MenuItemIDs)
+	assert.Equal(t, []id.ID{mains.ID}, station.CategoryIDs)
+
+	saved, err := stationService.FindStation(ctx, grill.ID)
+	require.NoError(t, err)
+	assert.Equal(t, station, saved, "routes not correctly saved")
+
+	tt := []struct {
+		testName    string
+		stationID   id.ID
+		menuItemIDs []id.ID
+		categoryIDs []id.ID
+		code        string
+	}{
+		{testName: "Unknown station", stationID: id.New(), menuItemIDs: []id.ID{fries.ID}, code: domain.ENOTFOUND},
+		{testName: "Unknown menu item", stationID: fryer.ID, menuItemIDs: []id.ID{id.New()}, code: domain.ENOTFOUND},
+		{testName: "Unknown category", stationID: fryer.ID, categoryIDs: []id.ID{id.New()}, code: domain.ENOTFOUND},
+		{testName: "Repeated menu item", stationID: fryer.ID, menuItemIDs: []id.ID{fries.ID, fries.ID}, code: domain.EINVALID},
+		{testName: "Menu item of another station", stationID: fryer.ID, menuItemIDs: []id.ID{fries.ID, burger.ID}, code: domain.ECONFLICT},
+		{testName: "Category of another station", stationID: fryer.ID, categoryIDs: []id.ID{mains.ID}, code: domain.ECONFLICT},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			_, err := stationService.RouteToStation(ctx, tc.stationID, tc.menuItemIDs, tc.categoryIDs)
+
+			assert.Equal(t, tc.code, domain.ErrorCode(err), "invalid error code")
+		})
+	}
+
+	saved, err = stationService.FindStation(ctx, fryer.ID)
+	require.NoError(t, err)
+	assert.Empty(t, saved.MenuItemIDs, "failed routing was saved")
+}
+
+func TestStationRoutingAndQueues(t *testing.T) {
+	ctx := context.Background()
+	menuRepo := inmem.NewMenu()
+	diningTableRepo := inmem.NewDiningTable()
+	menuService := domain.NewMenuService(menuRepo, nil)
+	publisher := &recordingPublisher{}
+	stationService := domain.NewStationService(inmem.NewStation(), menuRepo, nil)
+	tableService := domain.NewTableService(inmem.NewTable(), diningTableRepo, publisher).WithMenu(menuRepo).WithStations(stationService)
+
+	burger, err := menuService.CreateMenuItem(ctx, "burger", 1200, "")
+	require.NoError(t, err, "Initial setup failed")
+	steak, err := menuService.CreateMenuItem(ctx, "steak", 2400, "")
+	require.NoError(t, err, "Initial setup failed")
+	beer, err := menuService.CreateMenuItem(ctx, "beer", 500, "")
+	require.NoError(t, err, "Initial setup failed")
+	bread, err := menuService.CreateMenuItem(ctx, "bread", 200, "")
+	require.NoError(t, err, "Initial setup failed")
+	mains, err := menuService.CreateCategory(ctx, "mains")
+	require.NoError(t, err, "Initial setup failed")
+	require.NoError(t, menuService.AddItemToCategory(ctx, mains.ID, steak.ID), "Initial setup failed")
+	require.NoError(t, menuService.AddItemToCategory(ctx, mains.ID, burger.ID), "Initial setup failed")
+
+	grill, err := stationService.CreateStation(ctx, "grill")
+	require.NoError(t, err, "Initial setup failed")
+	bar, err := stationService.CreateStation(ctx, "bar")
+	require.NoError(t, err, "Initial setup failed")
+	_, err = stationService.RouteToStation(ctx, grill.ID, []id.ID{}, []id.ID{mains.ID})
+	require.NoError(t, err, "Initial setup failed")
+	// An item routed to a station itself is not routed by its category.
+	_, err = stationService.RouteToStation(ctx, bar.ID, []id.ID{beer.ID, burger.ID}, []id.ID{})
+	require.NoError(t, err, "Initial setup failed")
+
+	diningTable := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 4}
+	require.NoError(t, diningTableRepo.Save(ctx, diningTable), "Initial setup failed")
+	table, err := tableService.OpenTable(ctx, diningTable.ID, 2)
+	require.NoError(t, err, "Initial setup failed")
+
+	order, err := tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: steak}, {MenuItem: beer}, {MenuItem: burger}, {MenuItem: bread}})
+	require.NoError(t, err, "take order failed")
+
+	stationIDs := make([]id.ID, 0, len(order.Preparations))
+	for _, prep := range order.Preparations {
+		stationIDs = append(stationIDs, prep.StationID)
+	}
+	assert.Equal(t, []id.ID{grill.ID, bar.ID, bar.ID, id.NilID()}, stationIDs, "preparations not correctly routed")
+
+	grillQueue, err := tableService.FindStationQueue(ctx, grill.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Preparation{order.Preparations[0]}, grillQueue)
+
+	barQueue, err := tableService.FindStationQueue(ctx, bar.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Preparation{order.Preparations[1], order.Preparations[2]}, barQueue)
+
+	assert.Equal(t, []domain.StationProgress{
+		{StationID: grill.ID, Remaining: 1},
+		{StationID: bar.ID, Remaining: 2},
+		{StationID: id.NilID(), Remaining: 1},
+	}, order.Stations())
+
+	require.NoError(t, tableService.AbortPreparation(ctx, order.Preparations[3].ID))
+	for _, prep := range order.Preparations[:3] {
+		require.NoError(t, tableService.StartPreparation(ctx, prep.ID))
+		require.NoError(t, tableService.FinishPreparation(ctx, prep.ID))
+	}
+
+	barQueue, err = tableService.FindStationQueue(ctx, bar.ID)
+	require.NoError(t, err)
+	assert.Empty(t, barQueue, "finished preparations are queued")
+
+	order, err = tableService.FindOrder(ctx, order.ID)
+	require.NoError(t, err)
+	assert.True(t, order.IsReady(), "order is not ready")
+	assert.Equal(t, []domain.StationProgress{{StationID: grill.ID}, {StationID: bar.ID}}, order.Stations())
+
+	ready := 0
+	for _, event := range publisher.events {
+		if e, ok := event.(domain.OrderReady); ok {
+			ready++
+			assert.Equal(t, order.ID, e.Order.ID)
+		}
+	}
+	assert.Equal(t, 1, ready, "order ready not published once")
+}
+
+func TestOrderIsReady(t *testing.T) {
+	stationID := id.New()
+	prep := func(status domain.PreparationStatus) domain.Preparation {
+		return domain.Preparation{ID: id.New(), StationID: stationID, Status: status}
+	}
+
+	tt := []struct {
+		testName     string
+		preparations []domain.Preparation
+		ready        bool
+	}{
+		{testName: "Pending", preparations: []domain.Preparation{prep(domain.PreparationStatusReady), prep(domain.PreparationStatusPending)}, ready: false},
+		{testName: "In progress", preparations: []domain.Preparation{prep(domain.PreparationStatusInProgress)}, ready: false},
+		{testName: "Ready and served", preparations: []domain.Preparation{prep(domain.PreparationStatusReady), prep(domain.PreparationStatusServed)}, ready: true},
+		{testName: "Ready and aborted", preparations: []domain.Preparation{prep(domain.PreparationStatusReady), prep(domain.PreparationStatusAborted)}, ready: true},
+		{testName: "All aborted", preparations: []domain.Preparation{prep(domain.PreparationStatusAborted)}, ready: false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			order := domain.Order{ID: id.New(), Preparations: tc.preparations}
+
+			assert.Equal(t, tc.ready, order.IsReady())
+		})
+	}
+}
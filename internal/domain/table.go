@@ -2,7 +2,11 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"order_manager/internal/id"
+	"slices"
+	"strings"
+	"time"
 )
 
 type TableStatus string
@@ -17,13 +21,21 @@ func (s TableStatus) IsValid() bool {
 }
 
 type Table struct {
-	ID     id.ID
-	Orders []Order
-	Status TableStatus
+	ID            id.ID
+	DiningTableID id.ID
+	GuestCount    int
+	Orders        []Order
+	Status        TableStatus
+	// OpenedAt and ClosedAt time the seating session, they are zero for the tables opened before they were recorded
+	// and ClosedAt is zero until the table is closed.
+	OpenedAt time.Time
+	ClosedAt time.Time
+	// Version is incremented on every save, a save based on an outdated version fails with ESTALE.
+	Version int
 }
 
 func (t *Table) IsValid() bool {
-	isValid := t.ID != id.NilID() && t.Status.IsValid() && t.Orders != nil
+	isValid := t.ID != id.NilID() && t.Status.IsValid() && t.Orders != nil && t.GuestCount >= 0 && t.Version >= 0
 
 	for _, order := range t.Orders {
 		if !order.IsValid() {
@@ -64,7 +76,7 @@ func (o *Order) IsValid() bool {
 			return false
 		}
 
-		if o.Status == OrderStatusDone && prep.Status != PreparationStatusServed {
+		if o.Status == OrderStatusDone && prep.Status != PreparationStatusServed && prep.Status != PreparationStatusAborted {
 			return false
 		}
 
@@ -79,6 +91,8 @@ func (o *Order) IsValid() bool {
 type PreparationStatus string
 
 const (
+	// PreparationStatusHeld is the status of the preparations of a course the kitchen must not start before it is fired.
+	PreparationStatusHeld       PreparationStatus = "held"
 	PreparationStatusPending    PreparationStatus = "pending"
 	PreparationStatusInProgress PreparationStatus = "in progress"
 	PreparationStatusReady      PreparationStatus = "ready"
@@ -87,7 +101,8 @@ const (
 )
 
 func (s PreparationStatus) IsValid() bool {
-	return s == PreparationStatusPending ||
+	return s == PreparationStatusHeld ||
+		s == PreparationStatusPending ||
 		s == PreparationStatusInProgress ||
 		s == PreparationStatusReady ||
 		s == PreparationStatusServed ||
@@ -95,31 +110,173 @@ func (s PreparationStatus) IsValid() bool {
 }
 
 type Preparation struct {
-	ID       id.ID
-	MenuItem MenuItem
-	Status   PreparationStatus
+	ID id.ID
+	// MenuItem is the menu item as it was when ordered, later changes to the menu do not alter it.
+	MenuItem  MenuItem
+	Modifiers []Modifier
+	// Seat is the guest seat the preparation is served to, 0 when it is shared by the table.
+	Seat int
+	// Note is the free-text instruction given with the order, such as an allergy.
+	Note string
+	// Course is the course the preparation is served with, from 1. The courses after the first are held until fired.
+	Course int
+	// StationID is the station the preparation was routed to when ordered, nil when it was routed nowhere.
+	StationID id.ID
+	Status    PreparationStatus
+	// History is the statuses the preparation went through, oldest first, starting with pending or held when ordered.
+	History []PreparationTransition
+}
+
+// TablePreparation is a preparation along with the table and the order it was taken with,
+// telling the kitchen where to serve it.
+type TablePreparation struct {
+	Preparation
+	TableID id.ID
+	OrderID id.ID
+}
+
+// PreparationTransition is a status a preparation entered and the time it entered it.
+type PreparationTransition struct {
+	Status PreparationStatus
+	At     time.Time
 }
 
 func (p *Preparation) IsValid() bool {
-	return p.ID != id.NilID() && p.Status.IsValid() && p.MenuItem.IsValid()
+	isValid := p.ID != id.NilID() && p.Status.IsValid() && p.MenuItem.IsValid() && p.Price() >= 0 && p.Seat >= 0 && p.Course >= 0
+
+	for _, modifier := range p.Modifiers {
+		if !modifier.IsValid() {
+			return false
+		}
+	}
+
+	return isValid
+}
+
+// Price returns the menu item price with the price deltas of the chosen modifiers.
+func (p *Preparation) Price() int {
+	price := p.MenuItem.Price
+	for _, modifier := range p.Modifiers {
+		price += modifier.PriceDelta
+	}
+
+	return price
+}
+
+// Modifier is a modifier option chosen when ordering a menu item.
+// The names and price delta are copied from the menu at order time.
+type Modifier struct {
+	GroupID    id.ID
+	GroupName  string
+	OptionID   id.ID
+	OptionName string
+	PriceDelta int
+}
+
+func (m Modifier) IsValid() bool {
+	return m.GroupID != id.NilID() && m.GroupName != "" && m.OptionID != id.NilID() && m.OptionName != ""
+}
+
+// OrderItem is a menu item to order with the IDs of its chosen modifier options
+// and the seat it is for, 0 when it is shared by the table.
+type OrderItem struct {
+	MenuItem  MenuItem
+	OptionIDs []id.ID
+	Seat      int
+	// Quantity is the number of preparations of the item to make, a zero quantity makes one.
+	Quantity int
+	// Note is copied onto every preparation of the item.
+	Note string
+	// Course is the course the item is served with, a zero course being the first one.
+	Course int
 }
 
 type TableRepository interface {
+	// Save persists the table if the stored version is the one preceding table.Version,
+	// otherwise it fails with ESTALE. A table that was never saved is stored as is.
+	// An opened table is only saved when no other opened table occupies its dining table, otherwise it fails with ECONFLICT.
 	Save(ctx context.Context, table Table) error
+	// Delete removes a table if the stored version is table.Version, otherwise it fails with ESTALE.
+	// Possible errors:
+	// - ENOTFOUND if the table could not be found.
+	Delete(ctx context.Context, table Table) error
 	FindByID(ctx context.Context, id id.ID) (Table, error)
 	FindByPreparationID(ctx context.Context, preparationID id.ID) (Table, error)
+	FindByOrderID(ctx context.Context, orderID id.ID) (Table, error)
 	FindByStatus(ctx context.Context, status TableStatus) ([]Table, error)
+	// FindOrderedBetween returns the tables, opened or closed, having a preparation ordered from the given time
+	// until the other, excluded.
+	FindOrderedBetween(ctx context.Context, from time.Time, to time.Time) ([]Table, error)
+	// FindClosedBetween returns the tables closed from the given time until the other, excluded.
+	FindClosedBetween(ctx context.Context, from time.Time, to time.Time) ([]Table, error)
 }
 
 type TableService struct {
-	repo TableRepository
+	repo             TableRepository
+	diningTablesRepo DiningTableRepository
+	events           EventPublisher
+	retryPolicy      RetryPolicy
+	menu             MenuRepository
+	inventory        *InventoryService
+	stations         *StationService
+	waitlist         *WaitlistService
+	clock            Clock
 }
 
 // NewTableService creates a new table service.
 // The service is responsible for handling table related operations:
 // such as opening and closing tables, taking orders, and managing preparations.
-func NewTableService(repo TableRepository) *TableService {
-	return &TableService{repo: repo}
+// The events publisher is optional and receives an event for every saved change.
+func NewTableService(repo TableRepository, diningTablesRepo DiningTableRepository, events EventPublisher) *TableService {
+	return &TableService{repo: repo, diningTablesRepo: diningTablesRepo, events: events, clock: SystemClock{}}
+}
+
+// WithRetry makes the service retry the commands failing with ESTALE because
+// the table was modified concurrently. Retried commands re-read the table and
+// re-run their checks on every attempt.
+func (s *TableService) WithRetry(policy RetryPolicy) *TableService {
+	s.retryPolicy = policy
+	return s
+}
+
+// WithMenu makes the service take the portions of the ordered items from the menu,
+// rejecting the orders of items that are unavailable or have no portion left.
+func (s *TableService) WithMenu(menu MenuRepository) *TableService {
+	s.menu = menu
+	return s
+}
+
+// WithInventory makes the service take the stock used by the preparations, on orders or when they start,
+// and give it back when they are aborted, following the policy of the inventory.
+func (s *TableService) WithInventory(inventory *InventoryService) *TableService {
+	s.inventory = inventory
+	return s
+}
+
+// WithStations makes the service route every ordered preparation to the station of its menu item.
+func (s *TableService) WithStations(stations *StationService) *TableService {
+	s.stations = stations
+	return s
+}
+
+// WithWaitlist makes the service suggest the next party of the waitlist to seat on the dining table of every closed table.
+func (s *TableService) WithWaitlist(waitlist *WaitlistService) *TableService {
+	s.waitlist = waitlist
+	return s
+}
+
+// WithClock replaces the clock timing the tables and the status transitions of the preparations.
+func (s *TableService) WithClock(clock Clock) *TableService {
+	s.clock = clock
+	return s
+}
+
+// save bumps the table version and saves it.
+// Possible errors:
+// - ESTALE if the table was modified since it was read.
+func (s *TableService) save(ctx context.Context, table *Table) error {
+	table.Version++
+	return s.repo.Save(ctx, *table)
 }
 
 // FindTable returns a table by its ID.
@@ -136,30 +293,153 @@ func (s *TableService) FindOpenedTables(ctx context.Context) ([]Table, error) {
 	return s.repo.FindByStatus(ctx, TableStatusOpened)
 }
 
-// OpenTable creates a new table with an open status and saves it to the repository.
+// FindPreparationsByStatus returns the preparations of all opened tables with the given status,
+// each with the table and the order it belongs to.
+// Possible errors:
+// - EINVALID if the status is invalid.
+// - Any error returned by the repository when fetching the tables.
+func (s *TableService) FindPreparationsByStatus(ctx context.Context, status PreparationStatus) ([]TablePreparation, error) {
+	if !status.IsValid() {
+		return nil, Errorf(EINVALID, "invalid preparation status %s", status)
+	}
+
+	tables, err := s.repo.FindByStatus(ctx, TableStatusOpened)
+	if err != nil {
+		return nil, err
+	}
+
+	preparations := make([]TablePreparation, 0)
+	for _, table := range tables {
+		for _, order := range table.Orders {
+			for _, prep := range order.Preparations {
+				if prep.Status == status {
+					preparations = append(preparations, TablePreparation{Preparation: prep, TableID: table.ID, OrderID: order.ID})
+				}
+			}
+		}
+	}
+
+	return preparations, nil
+}
+
+// FindStationQueue returns the pending and in progress preparations of all opened tables routed to a station,
+// in the order they were taken.
 // Possible errors:
+// - Any error returned by the repository when fetching the tables.
+func (s *TableService) FindStationQueue(ctx context.Context, stationID id.ID) ([]Preparation, error) {
+	tables, err := s.repo.FindByStatus(ctx, TableStatusOpened)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]Order, 0)
+	for _, table := range tables {
+		orders = append(orders, table.Orders...)
+	}
+	// The order IDs are time-ordered.
+	slices.SortStableFunc(orders, func(a, b Order) int { return strings.Compare(a.ID.String(), b.ID.String()) })
+
+	preparations := make([]Preparation, 0)
+	for _, order := range orders {
+		for _, prep := range order.Preparations {
+			if prep.StationID == stationID && (prep.Status == PreparationStatusPending || prep.Status == PreparationStatusInProgress) {
+				preparations = append(preparations, prep)
+			}
+		}
+	}
+
+	return preparations, nil
+}
+
+// FindOrder returns an order with its preparations.
+// Possible errors:
+// - ENOTFOUND if the order could not be found.
+func (s *TableService) FindOrder(ctx context.Context, orderID id.ID) (Order, error) {
+	table, err := s.repo.FindByOrderID(ctx, orderID)
+	if err != nil {
+		return Order{}, err
+	}
+
+	return table.ExtractOrder(orderID)
+}
+
+// FindPreparation returns a preparation with its history.
+// Possible errors:
+// - ENOTFOUND if the preparation could not be found.
+func (s *TableService) FindPreparation(ctx context.Context, preparationID id.ID) (Preparation, error) {
+	table, err := s.repo.FindByPreparationID(ctx, preparationID)
+	if err != nil {
+		return Preparation{}, err
+	}
+
+	prep, _, err := table.ExtractPreparationWithOrder(preparationID)
+	return prep, err
+}
+
+// OpenTable opens a new table on a dining table for the given number of guests and saves it to the repository.
+// Possible errors:
+// - EINVALID if the guest count is not positive or exceeds the dining table capacity.
+// - ENOTFOUND if the dining table could not be found.
+// - ECONFLICT if the dining table is already occupied.
 // - Any error returned by the repository when saving the table.
-func (s *TableService) OpenTable(ctx context.Context) (Table, error) {
+func (s *TableService) OpenTable(ctx context.Context, diningTableID id.ID, guestCount int) (Table, error) {
+	if guestCount <= 0 {
+		return Table{}, Errorf(EINVALID, "guest count must be positive")
+	}
+
+	diningTable, err := s.diningTablesRepo.FindByID(ctx, diningTableID)
+	if err != nil {
+		return Table{}, err
+	}
+
+	if guestCount > diningTable.Capacity {
+		return Table{}, Errorf(EINVALID, "dining table %s seats %d guests, got %d", diningTable.Name, diningTable.Capacity, guestCount)
+	}
+
+	openedTables, err := s.repo.FindByStatus(ctx, TableStatusOpened)
+	if err != nil {
+		return Table{}, err
+	}
+
+	for _, t := range openedTables {
+		if t.DiningTableID == diningTableID {
+			return Table{}, Errorf(ECONFLICT, "dining table %s is already occupied", diningTable.Name)
+		}
+	}
+
 	table := Table{
-		ID:     id.New(),
-		Status: TableStatusOpened,
-		Orders: make([]Order, 0),
+		ID:            id.New(),
+		DiningTableID: diningTableID,
+		GuestCount:    guestCount,
+		Status:        TableStatusOpened,
+		Orders:        make([]Order, 0),
+		OpenedAt:      s.clock.Now(),
 	}
 
-	err := s.repo.Save(ctx, table)
+	err = s.save(ctx, &table)
 	if err != nil {
 		return Table{}, err
 	}
 
+	publish(ctx, s.events, TableOpened{Table: table})
+
 	return table, nil
 }
 
 // CloseTable closes a table by setting its status to closed.
+// When the service has a waitlist, the next party fitting the dining table of the table is suggested.
 // Possible errors:
 // - ENOTFOUND if the table could not be found.
 // - EINVALID if the table is already closed.
+// - ESTALE if the table was modified concurrently and the retries are exhausted.
 // - Any error returned by the repository when saving the table.
 func (s *TableService) CloseTable(ctx context.Context, tableID id.ID) error {
+	return retry(ctx, s.retryPolicy, func() error {
+		return s.closeTable(ctx, tableID)
+	})
+}
+
+func (s *TableService) closeTable(ctx context.Context, tableID id.ID) error {
 	table, err := s.repo.FindByID(ctx, tableID)
 	if err != nil {
 		return err
@@ -170,30 +450,103 @@ func (s *TableService) CloseTable(ctx context.Context, tableID id.ID) error {
 	}
 
 	table.Status = TableStatusClosed
+	table.ClosedAt = s.clock.Now()
 
-	err = s.repo.Save(ctx, table)
+	err = s.save(ctx, &table)
 	if err != nil {
 		return err
 	}
 
+	publish(ctx, s.events, TableClosed{TableID: table.ID})
+
+	if s.waitlist != nil {
+		s.waitlist.suggest(ctx, table)
+	}
+
 	return nil
 }
 
-// TakeOrder creates a new order for a table with the given menu items.
+// discardTable removes a table opened by a command that failed afterwards, as if it was never opened:
+// nothing is published, no party is suggested and the table is not timed in the turn times.
+// It fails with ESTALE when the table was changed since it was opened, the table being then left as it is.
+func (s *TableService) discardTable(ctx context.Context, table Table) error {
+	return s.repo.Delete(ctx, table)
+}
+
+// TakeOrder creates a new order for a table with the given menu items and their chosen modifier options.
 // Possible errors:
 // - ENOTFOUND if the table could not be found.
 // - EINVALID if the table is not open.
 // - EINVALID if any of the menu items are invalid or the slice is empty.
+// - EINVALID if the chosen options of an item do not match its modifier groups.
+// - EINVALID if an item is for a seat beyond the guest count of the table.
+// - EINVALID if the quantity of an item is negative.
+// - ESTALE if the table was modified concurrently and the retries are exhausted.
 // - Any error returned by the repository when saving the table.
-func (s *TableService) TakeOrder(ctx context.Context, tableID id.ID, menuItems []MenuItem) (Order, error) {
+func (s *TableService) TakeOrder(ctx context.Context, tableID id.ID, items []OrderItem) (Order, error) {
+	var order Order
+	err := retry(ctx, s.retryPolicy, func() (err error) {
+		order, err = s.takeOrder(ctx, tableID, items)
+		return err
+	})
 
-	if len(menuItems) == 0 {
+	return order, err
+}
+
+func (s *TableService) takeOrder(ctx context.Context, tableID id.ID, items []OrderItem) (Order, error) {
+	if len(items) == 0 {
 		return Order{}, Errorf(EINVALID, "no menu items provided")
 	}
 
-	for _, item := range menuItems {
-		if !item.IsValid() {
-			return Order{}, Errorf(EINVALID, "invalid menu item %s", item.ID)
+	order := Order{
+		ID:           id.New(),
+		Status:       OrderStatusTaken,
+		Preparations: make([]Preparation, 0, len(items)),
+	}
+	orderedAt := s.clock.Now()
+
+	for _, item := range items {
+		if !item.MenuItem.IsValid() {
+			return Order{}, Errorf(EINVALID, "invalid menu item %s", item.MenuItem.ID)
+		}
+
+		if !item.MenuItem.IsAvailable() {
+			return Order{}, Errorf(ECONFLICT, "%s is unavailable", item.MenuItem.Name)
+		}
+
+		modifiers, err := item.MenuItem.SelectModifiers(item.OptionIDs)
+		if err != nil {
+			return Order{}, err
+		}
+
+		// The preparation keeps the chosen modifiers, not the ones offered, nor the portions left.
+		menuItem := item.MenuItem
+		menuItem.ModifierGroups = nil
+		menuItem.Portions = nil
+
+		prep := Preparation{
+			MenuItem:  menuItem,
+			Modifiers: modifiers,
+			Seat:      item.Seat,
+			Note:      item.Note,
+			Course:    max(item.Course, 1),
+		}
+		if prep.Seat < 0 {
+			return Order{}, Errorf(EINVALID, "invalid seat %d for %s", prep.Seat, menuItem.Name)
+		}
+		if prep.Price() < 0 {
+			return Order{}, Errorf(EINVALID, "%s cannot have a negative price", menuItem.Name)
+		}
+		if item.Quantity < 0 {
+			return Order{}, Errorf(EINVALID, "invalid quantity %d for %s", item.Quantity, menuItem.Name)
+		}
+		if item.Course < 0 {
+			return Order{}, Errorf(EINVALID, "invalid course %d for %s", item.Course, menuItem.Name)
+		}
+
+		for range max(item.Quantity, 1) {
+			prep.ID = id.New()
+			order.Preparations = append(order.Preparations, prep)
 		}
 	}
 
@@ -206,31 +559,84 @@ func (s *TableService) TakeOrder(ctx context.Context, tableID id.ID, menuItems [
 		return Order{}, Errorf(EINVALID, "table %s is not open", tableID)
 	}
 
-	order := Order{
-		ID:           id.New(),
-		Status:       OrderStatusTaken,
-		Preparations: make([]Preparation, 0, len(menuItems)),
+	for i, prep := range order.Preparations {
+		if table.GuestCount > 0 && prep.Seat > table.GuestCount {
+			return Order{}, Errorf(EINVALID, "seat %d is beyond the %d guests of table %s", prep.Seat, table.GuestCount, tableID)
+		}
+
+		// The later courses are held, unless the table already fired them.
+		status := PreparationStatusPending
+		if !table.isCourseFired(prep.Course) {
+			status = PreparationStatusHeld
+		}
+		order.Preparations[i].Status = status
+		order.Preparations[i].History = []PreparationTransition{{Status: status, At: orderedAt}}
 	}
 
-	for _, item := range menuItems {
-		prep := Preparation{
-			ID:       id.New(),
-			MenuItem: item,
-			Status:   PreparationStatusPending,
+	if s.stations != nil {
+		if err := s.stations.route(ctx, order.Preparations); err != nil {
+			return Order{}, err
 		}
-		order.Preparations = append(order.Preparations, prep)
+	}
+
+	release, err := s.reserve(ctx, order.Preparations)
+	if err != nil {
+		return Order{}, err
 	}
 
 	table.Orders = append(table.Orders, order)
 
-	err = s.repo.Save(ctx, table)
+	err = s.save(ctx, &table)
 	if err != nil {
-		return Order{}, err
+		// The portions and the stock are given back so that a retry takes them again.
+		return Order{}, errors.Join(err, release(ctx))
 	}
 
+	publish(ctx, s.events, OrderTaken{TableID: table.ID, Order: order})
+
 	return order, nil
 }
 
+// reserve takes the portions of the ordered preparations from the menu and, when it is taken on orders, their stock.
+// It returns a function giving them back.
+func (s *TableService) reserve(ctx context.Context, preparations []Preparation) (func(context.Context) error, error) {
+	portions := make(map[id.ID]int)
+	for _, prep := range preparations {
+		portions[prep.MenuItem.ID]++
+	}
+
+	returnPortions := noUndo
+	if s.menu != nil {
+		if err := s.menu.TakePortions(ctx, portions); err != nil {
+			return nil, err
+		}
+		returnPortions = func(ctx context.Context) error { return s.menu.ReturnPortions(ctx, portions) }
+	}
+
+	returnStock := noUndo
+	if s.inventory != nil && s.inventory.policy.DeductOn == DeductOnOrder {
+		var err error
+		returnStock, err = s.inventory.consume(ctx, preparations)
+		if err != nil {
+			return nil, errors.Join(err, returnPortions(ctx))
+		}
+	}
+
+	return func(ctx context.Context) error {
+		return errors.Join(returnStock(ctx), returnPortions(ctx))
+	}, nil
+}
+
+// restock gives back the stock of the preparations about to be aborted, when the service has an inventory.
+// It returns a function taking it again.
+func (s *TableService) restock(ctx context.Context, preparations []Preparation) (func(context.Context) error, error) {
+	if s.inventory == nil {
+		return noUndo, nil
+	}
+
+	return s.inventory.restock(ctx, preparations)
+}
+
 // StartPreparation sets the status of a preparation to in progress.
 //
 // Possible errors:
@@ -239,8 +645,15 @@ func (s *TableService) TakeOrder(ctx context.Context, tableID id.ID, menuItems [
 // - ENOTFOUND if the preparation could not be found.
 // - EINVALID if the table is not open.
 // - EINVALID if the preparation is not pending.
+// - ESTALE if the table was modified concurrently and the retries are exhausted.
 // - Any error returned by the repository when saving the table.
 func (s *TableService) StartPreparation(ctx context.Context, preparationID id.ID) error {
+	return retry(ctx, s.retryPolicy, func() error {
+		return s.startPreparation(ctx, preparationID)
+	})
+}
+
+func (s *TableService) startPreparation(ctx context.Context, preparationID id.ID) error {
 	table, err := s.repo.FindByPreparationID(ctx, preparationID)
 	if err != nil {
 		return err
@@ -255,19 +668,33 @@ func (s *TableService) StartPreparation(ctx context.Context, preparationID id.ID
 		return err
 	}
 
+	if prep.Status == PreparationStatusHeld {
+		return Errorf(EINVALID, "preparation %s is held until course %d is fired", preparationID, prep.Course)
+	}
+
 	if prep.Status != PreparationStatusPending {
 		return Errorf(EINVALID, "preparation %s is not pending, preparation status is %s", preparationID, prep.Status)
 	}
 
-	prep.Status = PreparationStatusInProgress
+	undo := noUndo
+	if s.inventory != nil && s.inventory.policy.DeductOn == DeductOnPreparation {
+		undo, err = s.inventory.consume(ctx, []Preparation{prep})
+		if err != nil {
+			return err
+		}
+	}
+
+	prep.transition(PreparationStatusInProgress, s.clock.Now())
 	order.updatePreparation(prep)
 	table.updateOrder(order)
 
-	err = s.repo.Save(ctx, table)
+	err = s.save(ctx, &table)
 	if err != nil {
-		return err
+		return errors.Join(err, undo(ctx))
 	}
 
+	publish(ctx, s.events, PreparationStarted{TableID: table.ID, OrderID: order.ID, Preparation: prep})
+
 	return nil
 }
 
@@ -278,8 +705,15 @@ func (s *TableService) StartPreparation(ctx context.Context, preparationID id.ID
 // - ENOTFOUND if the preparation could not be found.
 // - EINVALID if the table is not open.
 // - EINVALID if the preparation is not in progress.
+// - ESTALE if the table was modified concurrently and the retries are exhausted.
 // - Any error returned by the repository when saving the table.
 func (s *TableService) FinishPreparation(ctx context.Context, preparationID id.ID) error {
+	return retry(ctx, s.retryPolicy, func() error {
+		return s.finishPreparation(ctx, preparationID)
+	})
+}
+
+func (s *TableService) finishPreparation(ctx context.Context, preparationID id.ID) error {
 	table, err := s.repo.FindByPreparationID(ctx, preparationID)
 	if err != nil {
 		return err
@@ -298,15 +732,20 @@ func (s *TableService) FinishPreparation(ctx context.Context, preparationID id.I
 		return Errorf(EINVALID, "preparation %s is not in progress, preparation status is %s", preparationID, prep.Status)
 	}
 
-	prep.Status = PreparationStatusReady
+	prep.transition(PreparationStatusReady, s.clock.Now())
 	order.updatePreparation(prep)
 	table.updateOrder(order)
 
-	err = s.repo.Save(ctx, table)
+	err = s.save(ctx, &table)
 	if err != nil {
 		return err
 	}
 
+	publish(ctx, s.events, PreparationFinished{TableID: table.ID, OrderID: order.ID, Preparation: prep})
+	if order.IsReady() {
+		publish(ctx, s.events, OrderReady{TableID: table.ID, Order: order})
+	}
+
 	return nil
 }
 
@@ -317,8 +756,15 @@ func (s *TableService) FinishPreparation(ctx context.Context, preparationID id.I
 // - ENOTFOUND if the preparation could not be found.
 // - EINVALID if the table is not open.
 // - EINVALID if the preparation is not ready.
+// - ESTALE if the table was modified concurrently and the retries are exhausted.
 // - Any error returned by the repository when saving the table.
 func (s *TableService) ServePreparation(ctx context.Context, preparationID id.ID) error {
+	return retry(ctx, s.retryPolicy, func() error {
+		return s.servePreparation(ctx, preparationID)
+	})
+}
+
+func (s *TableService) servePreparation(ctx context.Context, preparationID id.ID) error {
 	table, err := s.repo.FindByPreparationID(ctx, preparationID)
 	if err != nil {
 		return err
@@ -337,31 +783,232 @@ func (s *TableService) ServePreparation(ctx context.Context, preparationID id.ID
 		return Errorf(EINVALID, "preparation %s is not ready, preparation status is %s", preparationID, prep.Status)
 	}
 
-	prep.Status = PreparationStatusServed
+	prep.transition(PreparationStatusServed, s.clock.Now())
 	order.updatePreparation(prep)
+	order.refreshStatus()
+	table.updateOrder(order)
 
-	var allServed = true
-	for _, p := range order.Preparations {
-		if p.Status != PreparationStatusServed {
-			allServed = false
-			break
-		}
+	err = s.save(ctx, &table)
+	if err != nil {
+		return err
+	}
+
+	publish(ctx, s.events, PreparationServed{TableID: table.ID, OrderID: order.ID, Preparation: prep})
+
+	return nil
+}
+
+// AbortPreparation sets the status of a preparation to aborted.
+// Only pending, in progress and ready preparations can be aborted.
+// The order is marked as aborted once all its preparations are aborted,
+// or as done if all its remaining preparations are served.
+// Possible errors:
+// - ENOTFOUND if the table could not be found.
+// - ENOTFOUND if the preparation could not be found.
+// - EINVALID if the table is not open.
+// - EINVALID if the preparation is already served or aborted.
+// - ESTALE if the table was modified concurrently and the retries are exhausted.
+// - Any error returned by the repository when saving the table.
+func (s *TableService) AbortPreparation(ctx context.Context, preparationID id.ID) error {
+	return retry(ctx, s.retryPolicy, func() error {
+		return s.abortPreparation(ctx, preparationID)
+	})
+}
+
+func (s *TableService) abortPreparation(ctx context.Context, preparationID id.ID) error {
+	table, err := s.repo.FindByPreparationID(ctx, preparationID)
+	if err != nil {
+		return err
+	}
+
+	if table.Status != TableStatusOpened {
+		return Errorf(EINVALID, "table %s is not open", table.ID)
+	}
+
+	prep, order, err := table.ExtractPreparationWithOrder(preparationID)
+	if err != nil {
+		return err
 	}
 
-	if allServed {
-		order.Status = OrderStatusDone
+	if !prep.isAbortable() {
+		return Errorf(EINVALID, "preparation %s cannot be aborted, preparation status is %s", preparationID, prep.Status)
 	}
 
+	undo, err := s.restock(ctx, []Preparation{prep})
+	if err != nil {
+		return err
+	}
+
+	wasReady := order.IsReady()
+	prep.transition(PreparationStatusAborted, s.clock.Now())
+	order.updatePreparation(prep)
+	order.refreshStatus()
 	table.updateOrder(order)
 
-	err = s.repo.Save(ctx, table)
+	err = s.save(ctx, &table)
+	if err != nil {
+		return errors.Join(err, undo(ctx))
+	}
+
+	publish(ctx, s.events, PreparationAborted{TableID: table.ID, OrderID: order.ID, Preparation: prep})
+	if !wasReady && order.IsReady() {
+		publish(ctx, s.events, OrderReady{TableID: table.ID, Order: order})
+	}
+
+	return nil
+}
+
+// AbortOrder sets the status of an order and all its preparations to aborted.
+// Only taken orders without any served preparation can be aborted.
+// Possible errors:
+// - ENOTFOUND if the table could not be found.
+// - ENOTFOUND if the order could not be found.
+// - EINVALID if the table is not open.
+// - EINVALID if the order is not taken.
+// - EINVALID if any of the order's preparations has been served.
+// - ESTALE if the table was modified concurrently and the retries are exhausted.
+// - Any error returned by the repository when saving the table.
+func (s *TableService) AbortOrder(ctx context.Context, orderID id.ID) error {
+	return retry(ctx, s.retryPolicy, func() error {
+		return s.abortOrder(ctx, orderID)
+	})
+}
+
+func (s *TableService) abortOrder(ctx context.Context, orderID id.ID) error {
+	table, err := s.repo.FindByOrderID(ctx, orderID)
 	if err != nil {
 		return err
 	}
 
+	if table.Status != TableStatusOpened {
+		return Errorf(EINVALID, "table %s is not open", table.ID)
+	}
+
+	order, err := table.ExtractOrder(orderID)
+	if err != nil {
+		return err
+	}
+
+	if order.Status != OrderStatusTaken {
+		return Errorf(EINVALID, "order %s is not taken, order status is %s", orderID, order.Status)
+	}
+
+	preparations := make([]Preparation, 0, len(order.Preparations))
+	for _, prep := range order.Preparations {
+		if prep.Status == PreparationStatusServed {
+			return Errorf(EINVALID, "order %s has served preparation %s", orderID, prep.ID)
+		}
+	}
+
+	undo, err := s.restock(ctx, order.Preparations)
+	if err != nil {
+		return err
+	}
+
+	abortedAt := s.clock.Now()
+	for _, prep := range order.Preparations {
+		prep.transition(PreparationStatusAborted, abortedAt)
+		preparations = append(preparations, prep)
+	}
+
+	order.Preparations = preparations
+	order.Status = OrderStatusAborted
+	table.updateOrder(order)
+
+	err = s.save(ctx, &table)
+	if err != nil {
+		return errors.Join(err, undo(ctx))
+	}
+
+	publish(ctx, s.events, OrderAborted{TableID: table.ID, Order: order})
+
 	return nil
 }
 
+// FireCourse releases the held preparations of a course of a table to the kitchen, setting them pending.
+// The preparations of the course ordered afterwards are not held.
+// Possible errors:
+// - ENOTFOUND if the table could not be found.
+// - EINVALID if the table is not open.
+// - EINVALID if the table has no held preparation of the course.
+// - ESTALE if the table was modified concurrently and the retries are exhausted.
+// - Any error returned by the repository when saving the table.
+func (s *TableService) FireCourse(ctx context.Context, tableID id.ID, course int) ([]Preparation, error) {
+	var preparations []Preparation
+	err := retry(ctx, s.retryPolicy, func() (err error) {
+		preparations, err = s.fireCourse(ctx, tableID, course)
+		return err
+	})
+
+	return preparations, err
+}
+
+func (s *TableService) fireCourse(ctx context.Context, tableID id.ID, course int) ([]Preparation, error) {
+	table, err := s.repo.FindByID(ctx, tableID)
+	if err != nil {
+		return nil, err
+	}
+
+	if table.Status != TableStatusOpened {
+		return nil, Errorf(EINVALID, "table %s is not open", tableID)
+	}
+
+	firedAt := s.clock.Now()
+	preparations := make([]Preparation, 0)
+	for _, order := range table.Orders {
+		for _, prep := range order.Preparations {
+			if prep.Course != course || prep.Status != PreparationStatusHeld {
+				continue
+			}
+
+			prep.transition(PreparationStatusPending, firedAt)
+			order.updatePreparation(prep)
+			preparations = append(preparations, prep)
+		}
+		table.updateOrder(order)
+	}
+
+	if len(preparations) == 0 {
+		return nil, Errorf(EINVALID, "table %s has no held preparation of course %d", tableID, course)
+	}
+
+	err = s.save(ctx, &table)
+	if err != nil {
+		return nil, err
+	}
+
+	publish(ctx, s.events, CourseFired{TableID: table.ID, Course: course, Preparations: preparations})
+
+	return preparations, nil
+}
+
+// isCourseFired reports whether the preparations of a course are released to the kitchen as they are ordered,
+// the course being the first or having been fired.
+func (t *Table) isCourseFired(course int) bool {
+	if course <= 1 {
+		return true
+	}
+
+	for _, order := range t.Orders {
+		for _, prep := range order.Preparations {
+			if prep.Course == course && !prep.enteredAt(PreparationStatusPending).IsZero() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (t *Table) ExtractOrder(orderID id.ID) (Order, error) {
+	for _, order := range t.Orders {
+		if order.ID == orderID {
+			return order, nil
+		}
+	}
+	return Order{}, Errorf(ENOTFOUND, "order with id %s not found", orderID)
+}
+
 func (t *Table) ExtractPreparationWithOrder(preparationID id.ID) (Preparation, Order, error) {
 	for _, order := range t.Orders {
 		for _, prep := range order.Preparations {
@@ -390,3 +1037,38 @@ func (o *Order) updatePreparation(prep Preparation) {
 		}
 	}
 }
+
+// refreshStatus derives the order status from its preparations.
+func (o *Order) refreshStatus() {
+	allAborted, allDone := true, true
+	for _, p := range o.Preparations {
+		if p.Status != PreparationStatusAborted {
+			allAborted = false
+		}
+		if p.Status != PreparationStatusServed && p.Status != PreparationStatusAborted {
+			allDone = false
+		}
+	}
+
+	switch {
+	case allAborted:
+		o.Status = OrderStatusAborted
+	case allDone:
+		o.Status = OrderStatusDone
+	default:
+		o.Status = OrderStatusTaken
+	}
+}
+
+// transition sets the status of the preparation and records it in its history.
+func (p *Preparation) transition(status PreparationStatus, at time.Time) {
+	p.Status = status
+	p.History = append(slices.Clone(p.History), PreparationTransition{Status: status, At: at})
+}
+
+func (p *Preparation) isAbortable() bool {
+	return p.Status == PreparationStatusHeld ||
+		p.Status == PreparationStatusPending ||
+		p.Status == PreparationStatusInProgress ||
+		p.Status == PreparationStatusReady
+}
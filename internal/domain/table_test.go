@@ -199,16 +199,28 @@ func TestIsTableValid(t *testing.T) {
 
 func TestCreateTable(t *testing.T) {
 	tableRepo := inmem.NewTable()
-	tableService := domain.NewTableService(tableRepo)
+	diningTableRepo := inmem.NewDiningTable()
+	tableService := domain.NewTableService(tableRepo, diningTableRepo, nil)
+
+	newDiningTable := func(t *testing.T, capacity int) domain.DiningTable {
+		diningTable := domain.DiningTable{ID: id.New(), Name: id.New().String(), Capacity: capacity}
+		err := diningTableRepo.Save(context.Background(), diningTable)
+		require.NoError(t, err, "Initial setup failed")
+		return diningTable
+	}
 
 	t.Run("Success", func(t *testing.T) {
 		t.Parallel()
 
-		table, err := tableService.OpenTable(context.Background())
+		diningTable := newDiningTable(t, 4)
+
+		table, err := tableService.OpenTable(context.Background(), diningTable.ID, 3)
 		require.NoError(t, err, "table creation failed")
 
 		assert.NotEqual(t, table.ID, id.NilID(), "generated table ID is nil")
 		assert.Equal(t, domain.TableStatusOpened, table.Status, "invalid table status")
+		assert.Equal(t, diningTable.ID, table.DiningTableID, "invalid dining table")
+		assert.Equal(t, 3, table.GuestCount, "invalid guest count")
 		assert.NotNil(t, table.Orders, "orders not initialized")
 		assert.Empty(t, table.Orders, "orders not empty")
 
@@ -217,22 +229,93 @@ func TestCreateTable(t *testing.T) {
 		assert.Equal(t, table, tableRepoTable, "table not correctly saved")
 	})
 
+	t.Run("Reopen after close", func(t *testing.T) {
+		t.Parallel()
+
+		diningTable := newDiningTable(t, 2)
+
+		table, err := tableService.OpenTable(context.Background(), diningTable.ID, 2)
+		require.NoError(t, err, "table creation failed")
+		err = tableService.CloseTable(context.Background(), table.ID)
+		require.NoError(t, err, "table closing failed")
+
+		_, err = tableService.OpenTable(context.Background(), diningTable.ID, 1)
+		require.NoError(t, err, "reopening table failed")
+	})
+
 	t.Run("Failure", func(t *testing.T) {
+		t.Run("Dining table not found", func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tableService.OpenTable(context.Background(), id.New(), 2)
+			assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
+		})
+
+		t.Run("Invalid guest count", func(t *testing.T) {
+			t.Parallel()
+
+			diningTable := newDiningTable(t, 2)
+
+			_, err := tableService.OpenTable(context.Background(), diningTable.ID, 0)
+			assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+
+			_, err = tableService.OpenTable(context.Background(), diningTable.ID, 3)
+			assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+		})
+
+		t.Run("Dining table occupied", func(t *testing.T) {
+			t.Parallel()
+
+			diningTable := newDiningTable(t, 2)
+			_, err := tableService.OpenTable(context.Background(), diningTable.ID, 2)
+			require.NoError(t, err, "table creation failed")
+
+			_, err = tableService.OpenTable(context.Background(), diningTable.ID, 2)
+			assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "invalid error code")
+		})
+
 		t.Run("Canceled Context", func(t *testing.T) {
 			t.Parallel()
 
 			ctx, cancel := context.WithCancel(context.Background())
 			cancel()
 
-			_, err := tableService.OpenTable(ctx)
+			_, err := tableService.OpenTable(ctx, id.New(), 2)
 			assert.Equal(t, domain.ErrorCode(err), domain.ECANCELED, "invalid error code")
 		})
 	})
 }
 
+func TestSaveTableOnOccupiedDiningTable(t *testing.T) {
+	ctx := context.Background()
+	tableRepo := inmem.NewTable()
+	diningTableID := id.New()
+
+	table := domain.Table{ID: id.New(), DiningTableID: diningTableID, GuestCount: 2, Status: domain.TableStatusOpened, Orders: []domain.Order{}}
+	require.NoError(t, tableRepo.Save(ctx, table), "Initial setup failed")
+
+	// A table opened concurrently on the dining table is rejected even though it was not there when checked.
+	other := domain.Table{ID: id.New(), DiningTableID: diningTableID, GuestCount: 4, Status: domain.TableStatusOpened, Orders: []domain.Order{}}
+	err := tableRepo.Save(ctx, other)
+	assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "invalid error code")
+
+	_, err = tableRepo.FindByID(ctx, other.ID)
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "rejected table saved")
+
+	// Saving the table again does not conflict with itself, and the dining table is free once it is closed.
+	table.Version++
+	table.GuestCount = 3
+	require.NoError(t, tableRepo.Save(ctx, table), "update of the opened table rejected")
+
+	table.Version++
+	table.Status = domain.TableStatusClosed
+	require.NoError(t, tableRepo.Save(ctx, table), "Initial setup failed")
+	assert.NoError(t, tableRepo.Save(ctx, other), "table rejected on a free dining table")
+}
+
 func TestCloseTable(t *testing.T) {
 	tableRepo := inmem.NewTable()
-	tableService := domain.NewTableService(tableRepo)
+	tableService := domain.NewTableService(tableRepo, inmem.NewDiningTable(), nil)
 
 	t.Run("Success", func(t *testing.T) {
 		t.Parallel()
@@ -347,18 +430,18 @@ func TestCloseTable(t *testing.T) {
 
 func TestTakeOrder(t *testing.T) {
 	tableRepo := inmem.NewTable()
-	tableService := domain.NewTableService(tableRepo)
+	tableService := domain.NewTableService(tableRepo, inmem.NewDiningTable(), nil)
 
 	t.Run("Success", func(t *testing.T) {
 		tt := []struct {
 			testName string
 			table    domain.Table
-			items    []domain.MenuItem
+			items    []domain.OrderItem
 		}{
 			{
 				testName: "First order 1 item",
 				table:    domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened},
-				items:    []domain.MenuItem{{ID: id.New(), Name: "item 1", Price: 100}},
+				items:    []domain.OrderItem{{MenuItem: domain.MenuItem{ID: id.New(), Name: "item 1", Price: 100}}},
 			},
 			{
 				testName: "Second order 2 items",
@@ -374,9 +457,9 @@ func TestTakeOrder(t *testing.T) {
 						},
 					},
 				},
-				items: []domain.MenuItem{
-					{ID: id.New(), Name: "item 3", Price: 300},
-					{ID: id.New(), Name: "item 4", Price: 400},
+				items: []domain.OrderItem{
+					{MenuItem: domain.MenuItem{ID: id.New(), Name: "item 3", Price: 300}},
+					{MenuItem: domain.MenuItem{ID: id.New(), Name: "item 4", Price: 400}},
 				},
 			},
 		}
@@ -394,9 +477,9 @@ func TestTakeOrder(t *testing.T) {
 				assert.NotEqual(t, id.NilID(), order.ID, "generated order ID is nil")
 				assert.Equal(t, domain.OrderStatusTaken, order.Status, "invalid order status")
 				assert.Len(t, order.Preparations, len(tc.items), "invalid number of preparations")
-				for _, p := range order.Preparations {
+				for i, p := range order.Preparations {
 					assert.Equal(t, domain.PreparationStatusPending, p.Status, "invalid preparation status")
-					assert.Contains(t, tc.items, p.MenuItem, "invalid preparation item")
+					assert.Equal(t, tc.items[i].MenuItem, p.MenuItem, "invalid preparation item")
 				}
 
 				updatedTable, err := tableRepo.FindByID(context.Background(), tc.table.ID)
@@ -406,29 +489,82 @@ func TestTakeOrder(t *testing.T) {
 		}
 	})
 
+	t.Run("Quantities and notes", func(t *testing.T) {
+		t.Parallel()
+
+		table := domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened}
+		require.NoError(t, tableRepo.Save(context.Background(), table), "initial setup failed")
+
+		beer := domain.MenuItem{ID: id.New(), Name: "beer", Price: 500}
+		salad := domain.MenuItem{ID: id.New(), Name: "salad", Price: 900}
+		items := []domain.OrderItem{
+			{MenuItem: beer, Quantity: 3},
+			{MenuItem: salad, Note: "allergy: nuts"},
+			{MenuItem: salad, Quantity: 2, Note: "sauce on side"},
+		}
+
+		order, err := tableService.TakeOrder(context.Background(), table.ID, items)
+		require.NoError(t, err, "take order failed")
+		require.Len(t, order.Preparations, 6, "invalid number of preparations")
+
+		notes := make([]string, 0, len(order.Preparations))
+		ids := make(map[id.ID]bool)
+		for _, p := range order.Preparations {
+			notes = append(notes, p.Note)
+			ids[p.ID] = true
+		}
+		assert.Equal(t, []string{"", "", "", "allergy: nuts", "sauce on side", "sauce on side"}, notes)
+		assert.Len(t, ids, 6, "preparations share an ID")
+		assert.Equal(t, beer, order.Preparations[2].MenuItem)
+	})
+
 	t.Run("Failures", func(t *testing.T) {
 		tt := []struct {
 			testName string
 			table    domain.Table
-			items    []domain.MenuItem
+			items    []domain.OrderItem
 			errCode  string
 		}{
 			{
 				testName: "Empty items",
 				table:    domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened},
-				items:    make([]domain.MenuItem, 0),
+				items:    make([]domain.OrderItem, 0),
 				errCode:  domain.EINVALID,
 			},
 			{
 				testName: "Invalid item",
 				table:    domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened},
-				items:    []domain.MenuItem{{ID: id.NilID(), Name: "test", Price: 100}},
+				items:    []domain.OrderItem{{MenuItem: domain.MenuItem{ID: id.NilID(), Name: "test", Price: 100}}},
 				errCode:  domain.EINVALID,
 			},
 			{
 				testName: "Table closed",
 				table:    domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusClosed},
-				items:    []domain.MenuItem{{ID: id.New(), Name: "test", Price: 100}},
+				items:    []domain.OrderItem{{MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}}},
+				errCode:  domain.EINVALID,
+			},
+			{
+				testName: "Negative seat",
+				table:    domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened},
+				items:    []domain.OrderItem{{MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}, Seat: -1}},
+				errCode:  domain.EINVALID,
+			},
+			{
+				testName: "Seat beyond guest count",
+				table:    domain.Table{ID: id.New(), GuestCount: 2, Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened},
+				items:    []domain.OrderItem{{MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}, Seat: 3}},
+				errCode:  domain.EINVALID,
+			},
+			{
+				testName: "Negative quantity",
+				table:    domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened},
+				items:    []domain.OrderItem{{MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}, Quantity: -1}},
+				errCode:  domain.EINVALID,
+			},
+			{
+				testName: "Negative course",
+				table:    domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened},
+				items:    []domain.OrderItem{{MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}, Course: -1}},
 				errCode:  domain.EINVALID,
 			},
 		}
@@ -449,7 +585,7 @@ func TestTakeOrder(t *testing.T) {
 		t.Run("Canceled Context", func(t *testing.T) {
 			t.Parallel()
 
-			items := []domain.MenuItem{{ID: id.New(), Name: "test", Price: 100}}
+			items := []domain.OrderItem{{MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}}}
 			ctx, cancel := context.WithCancel(context.Background())
 			cancel()
 
@@ -461,7 +597,7 @@ func TestTakeOrder(t *testing.T) {
 		t.Run("Table Not Found", func(t *testing.T) {
 			t.Parallel()
 
-			items := []domain.MenuItem{{ID: id.New(), Name: "test", Price: 100}}
+			items := []domain.OrderItem{{MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}}}
 
 			_, err := tableService.TakeOrder(context.Background(), id.New(), items)
 
@@ -470,9 +606,250 @@ func TestTakeOrder(t *testing.T) {
 	})
 }
 
+func TestTakeOrderTakesPortions(t *testing.T) {
+	tableRepo := inmem.NewTable()
+	menuRepo := inmem.NewMenu()
+	tableService := domain.NewTableService(tableRepo, inmem.NewDiningTable(), nil).WithMenu(menuRepo)
+
+	portions := func(n int) *int { return &n }
+	saveItem := func(t *testing.T, item domain.MenuItem) domain.MenuItem {
+		t.Helper()
+		item.ID = id.New()
+		require.NoError(t, menuRepo.SaveItem(context.Background(), item), "initial setup failed")
+		return item
+	}
+	openTable := func(t *testing.T) domain.Table {
+		t.Helper()
+		table := domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened}
+		require.NoError(t, tableRepo.Save(context.Background(), table), "initial setup failed")
+		return table
+	}
+	portionsLeft := func(t *testing.T, itemID id.ID) *int {
+		t.Helper()
+		item, err := menuRepo.FindItem(context.Background(), itemID)
+		require.NoError(t, err)
+		return item.Portions
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		soup := saveItem(t, domain.MenuItem{Name: "soup", Price: 500, Portions: portions(2)})
+		bread := saveItem(t, domain.MenuItem{Name: "bread", Price: 100})
+		table := openTable(t)
+
+		order, err := tableService.TakeOrder(context.Background(), table.ID, []domain.OrderItem{{MenuItem: soup}, {MenuItem: soup}, {MenuItem: bread}})
+
+		require.NoError(t, err, "take order failed")
+		assert.Nil(t, order.Preparations[0].MenuItem.Portions, "the preparation keeps the portions left")
+		assert.Equal(t, portions(0), portionsLeft(t, soup.ID), "portions not taken")
+		assert.Nil(t, portionsLeft(t, bread.ID), "portions counted for an item without")
+	})
+
+	t.Run("Failures", func(t *testing.T) {
+		t.Parallel()
+
+		tt := []struct {
+			testName string
+			items    []domain.MenuItem
+			errCode  string
+		}{
+			{
+				testName: "Item marked unavailable",
+				items:    []domain.MenuItem{{Name: "soup", Price: 500, Unavailable: true}},
+				errCode:  domain.ECONFLICT,
+			},
+			{
+				testName: "No portion left",
+				items:    []domain.MenuItem{{Name: "soup", Price: 500, Portions: portions(0)}},
+				errCode:  domain.ECONFLICT,
+			},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				table := openTable(t)
+				items := make([]domain.OrderItem, 0, len(tc.items))
+				for _, item := range tc.items {
+					items = append(items, domain.OrderItem{MenuItem: saveItem(t, item)})
+				}
+
+				_, err := tableService.TakeOrder(context.Background(), table.ID, items)
+
+				assert.Equal(t, tc.errCode, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+
+		t.Run("Item run out since read", func(t *testing.T) {
+			soup := saveItem(t, domain.MenuItem{Name: "soup", Price: 500, Portions: portions(1)})
+			bread := saveItem(t, domain.MenuItem{Name: "bread", Price: 100, Portions: portions(5)})
+			table := openTable(t)
+
+			// The items were read with a portion left, the order asks for more than that.
+			_, err := tableService.TakeOrder(context.Background(), table.ID, []domain.OrderItem{{MenuItem: bread}, {MenuItem: soup}, {MenuItem: soup}})
+
+			assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "invalid error code")
+			assert.Equal(t, portions(1), portionsLeft(t, soup.ID), "portions taken on failure")
+			assert.Equal(t, portions(5), portionsLeft(t, bread.ID), "portions taken on failure")
+
+			saved, err := tableRepo.FindByID(context.Background(), table.ID)
+			require.NoError(t, err)
+			assert.Empty(t, saved.Orders, "order saved on failure")
+		})
+
+		t.Run("Table closed", func(t *testing.T) {
+			soup := saveItem(t, domain.MenuItem{Name: "soup", Price: 500, Portions: portions(1)})
+			table := domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusClosed}
+			require.NoError(t, tableRepo.Save(context.Background(), table), "initial setup failed")
+
+			_, err := tableService.TakeOrder(context.Background(), table.ID, []domain.OrderItem{{MenuItem: soup}})
+
+			assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+			assert.Equal(t, portions(1), portionsLeft(t, soup.ID), "portions taken on failure")
+		})
+	})
+}
+
+func TestTakeOrderWithModifiers(t *testing.T) {
+	tableRepo := inmem.NewTable()
+	tableService := domain.NewTableService(tableRepo, inmem.NewDiningTable(), nil)
+
+	bacon := domain.ModifierOption{ID: id.New(), Name: "Bacon", PriceDelta: 200}
+	onions := domain.ModifierOption{ID: id.New(), Name: "No onions"}
+	extras := domain.ModifierGroup{ID: id.New(), Name: "Extras", MinSelections: 0, MaxSelections: 2, Options: []domain.ModifierOption{bacon, onions}}
+	item := domain.MenuItem{ID: id.New(), Name: "Burger", Price: 1000, ModifierGroups: []domain.ModifierGroup{extras}}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		table := domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened}
+		require.NoError(t, tableRepo.Save(context.Background(), table), "initial setup failed")
+
+		order, err := tableService.TakeOrder(context.Background(), table.ID, []domain.OrderItem{{MenuItem: item, OptionIDs: []id.ID{bacon.ID, onions.ID}}})
+
+		require.NoError(t, err, "take order failed")
+		require.Len(t, order.Preparations, 1, "invalid number of preparations")
+		prep := order.Preparations[0]
+		assert.Nil(t, prep.MenuItem.ModifierGroups, "offered modifier groups kept on the preparation")
+		assert.Equal(t, []domain.Modifier{
+			{GroupID: extras.ID, GroupName: "Extras", OptionID: bacon.ID, OptionName: "Bacon", PriceDelta: 200},
+			{GroupID: extras.ID, GroupName: "Extras", OptionID: onions.ID, OptionName: "No onions"},
+		}, prep.Modifiers, "invalid modifiers")
+		assert.Equal(t, 1200, prep.Price(), "invalid preparation price")
+
+		updatedTable, err := tableRepo.FindByID(context.Background(), table.ID)
+		require.NoError(t, err)
+		assert.Equal(t, prep.Modifiers, updatedTable.Orders[0].Preparations[0].Modifiers, "modifiers not correctly saved")
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		t.Parallel()
+
+		side := domain.MenuItem{
+			ID:    id.New(),
+			Name:  "Side",
+			Price: 100,
+			ModifierGroups: []domain.ModifierGroup{{
+				ID: id.New(), Name: "Size", MinSelections: 1, MaxSelections: 1,
+				Options: []domain.ModifierOption{{ID: id.New(), Name: "Small", PriceDelta: -200}},
+			}},
+		}
+
+		tt := []struct {
+			testName string
+			items    []domain.OrderItem
+		}{
+			{testName: "option not offered", items: []domain.OrderItem{{MenuItem: item, OptionIDs: []id.ID{id.New()}}}},
+			{testName: "negative price", items: []domain.OrderItem{{MenuItem: side, OptionIDs: []id.ID{side.ModifierGroups[0].Options[0].ID}}}},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				t.Parallel()
+
+				table := domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened}
+				require.NoError(t, tableRepo.Save(context.Background(), table), "initial setup failed")
+
+				_, err := tableService.TakeOrder(context.Background(), table.ID, tc.items)
+
+				assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+	})
+}
+
+func TestFindPreparationsByStatus(t *testing.T) {
+	tableRepo := inmem.NewTable()
+	tableService := domain.NewTableService(tableRepo, inmem.NewDiningTable(), nil)
+
+	pending := domain.Preparation{ID: id.New(), Status: domain.PreparationStatusPending, MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}}
+	ready := domain.Preparation{ID: id.New(), Status: domain.PreparationStatusReady, MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}}
+	served := domain.Preparation{ID: id.New(), Status: domain.PreparationStatusServed, MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}}
+
+	tables := []domain.Table{
+		{
+			ID:     id.New(),
+			Status: domain.TableStatusOpened,
+			Orders: []domain.Order{
+				{ID: id.New(), Status: domain.OrderStatusTaken, Preparations: []domain.Preparation{pending}},
+				{ID: id.New(), Status: domain.OrderStatusTaken, Preparations: []domain.Preparation{ready}},
+			},
+		},
+		{
+			ID:     id.New(),
+			Status: domain.TableStatusClosed,
+			Orders: []domain.Order{
+				{ID: id.New(), Status: domain.OrderStatusDone, Preparations: []domain.Preparation{served}},
+			},
+		},
+	}
+	for _, table := range tables {
+		err := tableRepo.Save(context.Background(), table)
+		require.NoError(t, err, "Initial setup failed")
+	}
+
+	opened := tables[0]
+
+	t.Run("Success", func(t *testing.T) {
+		tt := []struct {
+			testName     string
+			status       domain.PreparationStatus
+			preparations []domain.TablePreparation
+		}{
+			{
+				testName:     "pending",
+				status:       domain.PreparationStatusPending,
+				preparations: []domain.TablePreparation{{Preparation: pending, TableID: opened.ID, OrderID: opened.Orders[0].ID}},
+			},
+			{
+				testName:     "ready",
+				status:       domain.PreparationStatusReady,
+				preparations: []domain.TablePreparation{{Preparation: ready, TableID: opened.ID, OrderID: opened.Orders[1].ID}},
+			},
+			{testName: "served on closed table", status: domain.PreparationStatusServed, preparations: []domain.TablePreparation{}},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				t.Parallel()
+
+				preparations, err := tableService.FindPreparationsByStatus(context.Background(), tc.status)
+
+				require.NoError(t, err, "find preparations failed")
+				assert.ElementsMatch(t, tc.preparations, preparations)
+			})
+		}
+	})
+
+	t.Run("Invalid status", func(t *testing.T) {
+		_, err := tableService.FindPreparationsByStatus(context.Background(), "unknown")
+		assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+	})
+}
+
 func TestStartPreparation(t *testing.T) {
 	tableRepo := inmem.NewTable()
-	tableService := domain.NewTableService(tableRepo)
+	tableService := domain.NewTableService(tableRepo, inmem.NewDiningTable(), nil)
 
 	t.Run("Success", func(t *testing.T) {
 		tt := []struct {
@@ -643,7 +1020,7 @@ func TestStartPreparation(t *testing.T) {
 
 func TestFinishPreparation(t *testing.T) {
 	tableRepo := inmem.NewTable()
-	tableService := domain.NewTableService(tableRepo)
+	tableService := domain.NewTableService(tableRepo, inmem.NewDiningTable(), nil)
 
 	t.Run("Success", func(t *testing.T) {
 		tt := []struct {
@@ -710,7 +1087,7 @@ func TestFinishPreparation(t *testing.T) {
 						Orders: make([]domain.Order, 0),
 					}
 				},
-				preparation: domain.Preparation{id.NilID(), domain.MenuItem{}, domain.PreparationStatusPending},
+				preparation: domain.Preparation{ID: id.NilID(), MenuItem: domain.MenuItem{}, Status: domain.PreparationStatusPending},
 				errCode:     domain.ENOTFOUND,
 			},
 			{
@@ -768,7 +1145,7 @@ func TestFinishPreparation(t *testing.T) {
 
 func TestServePreparation(t *testing.T) {
 	tableRepo := inmem.NewTable()
-	tableService := domain.NewTableService(tableRepo)
+	tableService := domain.NewTableService(tableRepo, inmem.NewDiningTable(), nil)
 
 	t.Run("Success", func(t *testing.T) {
 		tt := []struct {
@@ -920,3 +1297,480 @@ func TestServePreparation(t *testing.T) {
 		})
 	})
 }
+
+func TestAbortPreparation(t *testing.T) {
+	tableRepo := inmem.NewTable()
+	tableService := domain.NewTableService(tableRepo, inmem.NewDiningTable(), nil)
+
+	newPreparation := func(status domain.PreparationStatus) domain.Preparation {
+		return domain.Preparation{ID: id.New(), Status: status, MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		tt := []struct {
+			testName            string
+			preparation         domain.Preparation
+			others              []domain.Preparation
+			expectedOrderStatus domain.OrderStatus
+		}{
+			{
+				testName:            "Pending preparation",
+				preparation:         newPreparation(domain.PreparationStatusPending),
+				others:              []domain.Preparation{newPreparation(domain.PreparationStatusPending)},
+				expectedOrderStatus: domain.OrderStatusTaken,
+			},
+			{
+				testName:            "In progress preparation",
+				preparation:         newPreparation(domain.PreparationStatusInProgress),
+				others:              []domain.Preparation{newPreparation(domain.PreparationStatusReady)},
+				expectedOrderStatus: domain.OrderStatusTaken,
+			},
+			{
+				testName:            "Last preparation",
+				preparation:         newPreparation(domain.PreparationStatusReady),
+				others:              []domain.Preparation{},
+				expectedOrderStatus: domain.OrderStatusAborted,
+			},
+			{
+				testName:            "Other preparations served",
+				preparation:         newPreparation(domain.PreparationStatusPending),
+				others:              []domain.Preparation{newPreparation(domain.PreparationStatusServed)},
+				expectedOrderStatus: domain.OrderStatusDone,
+			},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				t.Parallel()
+
+				table := domain.Table{
+					ID:     id.New(),
+					Status: domain.TableStatusOpened,
+					Orders: []domain.Order{
+						{ID: id.New(), Status: domain.OrderStatusTaken, Preparations: append([]domain.Preparation{tc.preparation}, tc.others...)},
+					},
+				}
+				err := tableRepo.Save(context.Background(), table)
+				require.NoError(t, err, "Initial setup failed")
+
+				err = tableService.AbortPreparation(context.Background(), tc.preparation.ID)
+
+				require.NoError(t, err, "abort preparation failed")
+				updatedTable, err := tableRepo.FindByID(context.Background(), table.ID)
+				require.NoError(t, err, "table not correctly saved")
+				preparation, order, err := updatedTable.ExtractPreparationWithOrder(tc.preparation.ID)
+				require.NoError(t, err, "preparation not found")
+				assert.Equal(t, domain.PreparationStatusAborted, preparation.Status, "preparation status not updated")
+				assert.Equal(t, tc.expectedOrderStatus, order.Status, "order status not updated")
+				assert.True(t, updatedTable.IsValid(), "table should be valid")
+			})
+		}
+	})
+
+	t.Run("Failures", func(t *testing.T) {
+		tt := []struct {
+			testName    string
+			status      domain.PreparationStatus
+			tableStatus domain.TableStatus
+		}{
+			{testName: "Served preparation", status: domain.PreparationStatusServed, tableStatus: domain.TableStatusOpened},
+			{testName: "Aborted preparation", status: domain.PreparationStatusAborted, tableStatus: domain.TableStatusOpened},
+			{testName: "Closed table", status: domain.PreparationStatusServed, tableStatus: domain.TableStatusClosed},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				t.Parallel()
+
+				preparation := newPreparation(tc.status)
+				orderStatus := domain.OrderStatusDone
+				if tc.status == domain.PreparationStatusAborted {
+					orderStatus = domain.OrderStatusAborted
+				}
+				table := domain.Table{
+					ID:     id.New(),
+					Status: tc.tableStatus,
+					Orders: []domain.Order{
+						{ID: id.New(), Status: orderStatus, Preparations: []domain.Preparation{preparation}},
+					},
+				}
+				err := tableRepo.Save(context.Background(), table)
+				require.NoError(t, err, "Initial setup failed")
+
+				err = tableService.AbortPreparation(context.Background(), preparation.ID)
+
+				require.Error(t, err, "abort preparation should fail")
+				assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+
+		t.Run("Preparation not found", func(t *testing.T) {
+			t.Parallel()
+
+			err := tableService.AbortPreparation(context.Background(), id.New())
+			assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
+		})
+	})
+}
+
+func TestAbortOrder(t *testing.T) {
+	tableRepo := inmem.NewTable()
+	tableService := domain.NewTableService(tableRepo, inmem.NewDiningTable(), nil)
+
+	newPreparation := func(status domain.PreparationStatus) domain.Preparation {
+		return domain.Preparation{ID: id.New(), Status: status, MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		t.Parallel()
+
+		order := domain.Order{
+			ID:     id.New(),
+			Status: domain.OrderStatusTaken,
+			Preparations: []domain.Preparation{
+				newPreparation(domain.PreparationStatusPending),
+				newPreparation(domain.PreparationStatusInProgress),
+				newPreparation(domain.PreparationStatusReady),
+			},
+		}
+		table := domain.Table{ID: id.New(), Status: domain.TableStatusOpened, Orders: []domain.Order{order}}
+		err := tableRepo.Save(context.Background(), table)
+		require.NoError(t, err, "Initial setup failed")
+
+		err = tableService.AbortOrder(context.Background(), order.ID)
+
+		require.NoError(t, err, "abort order failed")
+		updatedTable, err := tableRepo.FindByID(context.Background(), table.ID)
+		require.NoError(t, err, "table not correctly saved")
+		updatedOrder, err := updatedTable.ExtractOrder(order.ID)
+		require.NoError(t, err, "order not found")
+		assert.Equal(t, domain.OrderStatusAborted, updatedOrder.Status, "order status not updated")
+		for _, prep := range updatedOrder.Preparations {
+			assert.Equal(t, domain.PreparationStatusAborted, prep.Status, "preparation status not updated")
+		}
+	})
+
+	t.Run("Failures", func(t *testing.T) {
+		tt := []struct {
+			testName     string
+			orderStatus  domain.OrderStatus
+			preparations []domain.Preparation
+			tableStatus  domain.TableStatus
+		}{
+			{
+				testName:     "All preparations served",
+				orderStatus:  domain.OrderStatusDone,
+				preparations: []domain.Preparation{newPreparation(domain.PreparationStatusServed)},
+				tableStatus:  domain.TableStatusOpened,
+			},
+			{
+				testName:     "Some preparations served",
+				orderStatus:  domain.OrderStatusTaken,
+				preparations: []domain.Preparation{newPreparation(domain.PreparationStatusServed), newPreparation(domain.PreparationStatusPending)},
+				tableStatus:  domain.TableStatusOpened,
+			},
+			{
+				testName:     "Already aborted",
+				orderStatus:  domain.OrderStatusAborted,
+				preparations: []domain.Preparation{newPreparation(domain.PreparationStatusAborted)},
+				tableStatus:  domain.TableStatusOpened,
+			},
+			{
+				testName:     "Closed table",
+				orderStatus:  domain.OrderStatusDone,
+				preparations: []domain.Preparation{newPreparation(domain.PreparationStatusServed)},
+				tableStatus:  domain.TableStatusClosed,
+			},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				t.Parallel()
+
+				order := domain.Order{ID: id.New(), Status: tc.orderStatus, Preparations: tc.preparations}
+				table := domain.Table{ID: id.New(), Status: tc.tableStatus, Orders: []domain.Order{order}}
+				err := tableRepo.Save(context.Background(), table)
+				require.NoError(t, err, "Initial setup failed")
+
+				err = tableService.AbortOrder(context.Background(), order.ID)
+
+				require.Error(t, err, "abort order should fail")
+				assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+			})
+		}
+
+		t.Run("Order not found", func(t *testing.T) {
+			t.Parallel()
+
+			err := tableService.AbortOrder(context.Background(), id.New())
+			assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
+		})
+	})
+}
+
+func TestFireCourse(t *testing.T) {
+	tableRepo := inmem.NewTable()
+	publisher := &recordingPublisher{}
+	tableService := domain.NewTableService(tableRepo, inmem.NewDiningTable(), publisher)
+	ctx := context.Background()
+
+	table := domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened}
+	require.NoError(t, tableRepo.Save(ctx, table), "Initial setup failed")
+
+	starter := domain.MenuItem{ID: id.New(), Name: "soup", Price: 700}
+	main := domain.MenuItem{ID: id.New(), Name: "steak", Price: 2400}
+	dessert := domain.MenuItem{ID: id.New(), Name: "cake", Price: 600}
+	order, err := tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{
+		{MenuItem: starter},
+		{MenuItem: main, Course: 2, Quantity: 2},
+		{MenuItem: dessert, Course: 3},
+	})
+	require.NoError(t, err, "take order failed")
+
+	statuses := func(preparations []domain.Preparation) []domain.PreparationStatus {
+		statuses := make([]domain.PreparationStatus, 0, len(preparations))
+		for _, prep := range preparations {
+			statuses = append(statuses, prep.Status)
+		}
+		return statuses
+	}
+
+	assert.Equal(t, []domain.PreparationStatus{
+		domain.PreparationStatusPending,
+		domain.PreparationStatusHeld,
+		domain.PreparationStatusHeld,
+		domain.PreparationStatusHeld,
+	}, statuses(order.Preparations))
+	assert.Equal(t, 1, order.Preparations[0].Course, "first course not set")
+
+	pending, err := tableService.FindPreparationsByStatus(ctx, domain.PreparationStatusPending)
+	require.NoError(t, err)
+	assert.Len(t, pending, 1, "held preparations are queued")
+
+	err = tableService.StartPreparation(ctx, order.Preparations[1].ID)
+	assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "held preparation started")
+
+	fired, err := tableService.FireCourse(ctx, table.ID, 2)
+	require.NoError(t, err, "fire course failed")
+	require.Len(t, fired, 2)
+	for _, prep := range fired {
+		assert.Equal(t, main.ID, prep.MenuItem.ID)
+		assert.Equal(t, domain.PreparationStatusPending, prep.Status)
+	}
+	require.NoError(t, tableService.StartPreparation(ctx, order.Preparations[1].ID), "fired preparation not started")
+
+	// The items of a course already fired are not held.
+	second, err := tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: main, Course: 2}, {MenuItem: dessert, Course: 3}})
+	require.NoError(t, err, "take order failed")
+	assert.Equal(t, []domain.PreparationStatus{domain.PreparationStatusPending, domain.PreparationStatusHeld}, statuses(second.Preparations))
+
+	fired, err = tableService.FireCourse(ctx, table.ID, 3)
+	require.NoError(t, err, "fire course failed")
+	assert.Len(t, fired, 2, "held preparations of every order not fired")
+
+	firedEvents := 0
+	for _, event := range publisher.events {
+		if e, ok := event.(domain.CourseFired); ok {
+			firedEvents++
+			assert.Equal(t, table.ID, e.TableID)
+		}
+	}
+	assert.Equal(t, 2, firedEvents, "course fired not published")
+
+	t.Run("Failures", func(t *testing.T) {
+		_, err := tableService.FireCourse(ctx, table.ID, 3)
+		assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "course fired twice")
+
+		_, err = tableService.FireCourse(ctx, table.ID, 1)
+		assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "first course fired")
+
+		_, err = tableService.FireCourse(ctx, id.New(), 2)
+		assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
+	})
+
+	t.Run("Abort held preparation", func(t *testing.T) {
+		order, err := tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: dessert, Course: 4}})
+		require.NoError(t, err, "take order failed")
+
+		require.NoError(t, tableService.AbortPreparation(ctx, order.Preparations[0].ID), "held preparation not aborted")
+	})
+}
+
+type recordingPublisher struct {
+	events []domain.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, events ...domain.Event) {
+	p.events = append(p.events, events...)
+}
+
+func (p *recordingPublisher) names() []string {
+	names := make([]string, 0, len(p.events))
+	for _, event := range p.events {
+		names = append(names, event.EventName())
+	}
+	return names
+}
+
+func TestTablePublishesEvents(t *testing.T) {
+	tableRepo := inmem.NewTable()
+	diningTableRepo := inmem.NewDiningTable()
+	publisher := &recordingPublisher{}
+	tableService := domain.NewTableService(tableRepo, diningTableRepo, publisher)
+	ctx := context.Background()
+
+	diningTable := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 4}
+	require.NoError(t, diningTableRepo.Save(ctx, diningTable), "Initial setup failed")
+
+	table, err := tableService.OpenTable(ctx, diningTable.ID, 2)
+	require.NoError(t, err)
+	order, err := tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}}, {MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}}})
+	require.NoError(t, err)
+	first, second := order.Preparations[0].ID, order.Preparations[1].ID
+	require.NoError(t, tableService.StartPreparation(ctx, first))
+	require.NoError(t, tableService.FinishPreparation(ctx, first))
+	require.NoError(t, tableService.ServePreparation(ctx, first))
+	require.NoError(t, tableService.AbortPreparation(ctx, second))
+	require.NoError(t, tableService.CloseTable(ctx, table.ID))
+
+	// A failed operation publishes nothing.
+	require.Error(t, tableService.CloseTable(ctx, table.ID))
+
+	assert.Equal(t, []string{
+		"table.opened",
+		"order.taken",
+		"preparation.started",
+		"preparation.finished",
+		"preparation.served",
+		"preparation.aborted",
+		"order.ready",
+		"table.closed",
+	}, publisher.names())
+
+	opened := publisher.events[0].(domain.TableOpened)
+	assert.Equal(t, table, opened.Table, "invalid opened table")
+	taken := publisher.events[1].(domain.OrderTaken)
+	assert.Equal(t, table.ID, taken.TableID, "invalid table ID")
+	assert.Equal(t, order, taken.Order, "invalid order")
+	started := publisher.events[2].(domain.PreparationStarted)
+	assert.Equal(t, order.ID, started.OrderID, "invalid order ID")
+	assert.Equal(t, domain.PreparationStatusInProgress, started.Preparation.Status, "invalid preparation status")
+
+	t.Run("Abort order", func(t *testing.T) {
+		publisher.events = nil
+
+		table, err := tableService.OpenTable(ctx, diningTable.ID, 2)
+		require.NoError(t, err)
+		order, err := tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}}})
+		require.NoError(t, err)
+		require.NoError(t, tableService.AbortOrder(ctx, order.ID))
+
+		require.Equal(t, []string{"table.opened", "order.taken", "order.aborted"}, publisher.names())
+		aborted := publisher.events[2].(domain.OrderAborted)
+		assert.Equal(t, domain.OrderStatusAborted, aborted.Order.Status, "invalid order status")
+	})
+}
+
+// racingTableRepo simulates a concurrent order taken on the table
+// between the first read of a command and its save.
+type racingTableRepo struct {
+	*inmem.Table
+	raced bool
+}
+
+func (r *racingTableRepo) FindByID(ctx context.Context, tableID id.ID) (domain.Table, error) {
+	table, err := r.Table.FindByID(ctx, tableID)
+	return r.race(ctx, table, err)
+}
+
+func (r *racingTableRepo) FindByPreparationID(ctx context.Context, preparationID id.ID) (domain.Table, error) {
+	table, err := r.Table.FindByPreparationID(ctx, preparationID)
+	return r.race(ctx, table, err)
+}
+
+func (r *racingTableRepo) race(ctx context.Context, table domain.Table, err error) (domain.Table, error) {
+	if err != nil || r.raced {
+		return table, err
+	}
+
+	r.raced = true
+	concurrent := table
+	concurrent.Version++
+	concurrent.Orders = append(concurrent.Orders, domain.Order{
+		ID:           id.New(),
+		Status:       domain.OrderStatusTaken,
+		Preparations: []domain.Preparation{{ID: id.New(), Status: domain.PreparationStatusPending, MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}}},
+	})
+	if err := r.Table.Save(ctx, concurrent); err != nil {
+		return domain.Table{}, err
+	}
+
+	return table, nil
+}
+
+func TestTakeOrderConcurrently(t *testing.T) {
+	items := []domain.OrderItem{{MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}}}
+
+	newRepo := func(t *testing.T) (*racingTableRepo, domain.Table) {
+		repo := &racingTableRepo{Table: inmem.NewTable()}
+		table := domain.Table{ID: id.New(), Status: domain.TableStatusOpened, Orders: make([]domain.Order, 0)}
+		require.NoError(t, repo.Save(context.Background(), table), "Initial setup failed")
+		return repo, table
+	}
+
+	t.Run("Stale write", func(t *testing.T) {
+		t.Parallel()
+
+		repo, table := newRepo(t)
+		tableService := domain.NewTableService(repo, inmem.NewDiningTable(), nil)
+
+		_, err := tableService.TakeOrder(context.Background(), table.ID, items)
+
+		assert.Equal(t, domain.ESTALE, domain.ErrorCode(err), "invalid error code")
+		updatedTable, err := repo.Table.FindByID(context.Background(), table.ID)
+		require.NoError(t, err)
+		assert.Len(t, updatedTable.Orders, 1, "concurrent order lost")
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		t.Parallel()
+
+		repo, table := newRepo(t)
+		tableService := domain.NewTableService(repo, inmem.NewDiningTable(), nil).WithRetry(domain.RetryPolicy{Attempts: 2})
+
+		order, err := tableService.TakeOrder(context.Background(), table.ID, items)
+
+		require.NoError(t, err, "take order failed")
+		updatedTable, err := repo.Table.FindByID(context.Background(), table.ID)
+		require.NoError(t, err)
+		assert.Len(t, updatedTable.Orders, 2, "an order was lost")
+		assert.Equal(t, 2, updatedTable.Version, "invalid table version")
+		_, err = updatedTable.ExtractOrder(order.ID)
+		assert.NoError(t, err, "order not saved")
+	})
+}
+
+func TestStartPreparationConcurrently(t *testing.T) {
+	ctx := context.Background()
+	repo := &racingTableRepo{Table: inmem.NewTable()}
+	tableService := domain.NewTableService(repo, inmem.NewDiningTable(), nil)
+
+	prep := domain.Preparation{ID: id.New(), Status: domain.PreparationStatusPending, MenuItem: domain.MenuItem{ID: id.New(), Name: "test", Price: 100}}
+	table := domain.Table{
+		ID:     id.New(),
+		Status: domain.TableStatusOpened,
+		Orders: []domain.Order{{ID: id.New(), Status: domain.OrderStatusTaken, Preparations: []domain.Preparation{prep}}},
+	}
+	require.NoError(t, repo.Save(ctx, table), "Initial setup failed")
+
+	err := tableService.StartPreparation(ctx, prep.ID)
+	assert.Equal(t, domain.ESTALE, domain.ErrorCode(err), "invalid error code")
+
+	// The stale save changes nothing of the stored table, the preparation can still be started.
+	updatedTable, err := repo.Table.FindByID(ctx, table.ID)
+	require.NoError(t, err)
+	assert.Len(t, updatedTable.Orders, 2, "concurrent order lost")
+	assert.Equal(t, prep, updatedTable.Orders[0].Preparations[0], "stale save changed the stored preparation")
+
+	require.NoError(t, tableService.StartPreparation(ctx, prep.ID), "start preparation after the stale save failed")
+}
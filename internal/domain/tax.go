@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"math"
+	"slices"
+)
+
+// TaxPolicy controls the taxes charged on the bills.
+type TaxPolicy struct {
+	// Exclusive is set when the menu prices exclude the taxes, which are then added on the bills.
+	// Otherwise the menu prices include the taxes.
+	Exclusive bool
+	// Rates maps the tax categories to their rate in percent, the items of no category are not taxed.
+	Rates map[string]float64
+}
+
+// HasCategory reports whether a menu item can be assigned the tax category.
+// The empty category, of the items that are not taxed, is always accepted.
+func (p TaxPolicy) HasCategory(category string) bool {
+	if category == "" {
+		return true
+	}
+
+	_, ok := p.Rates[category]
+	return ok
+}
+
+// TaxLine is the breakdown of the amounts charged on a bill at a tax rate.
+type TaxLine struct {
+	Rate  float64
+	Net   int
+	Tax   int
+	Gross int
+}
+
+func (l TaxLine) IsValid() bool {
+	return l.Rate >= 0 && l.Net >= 0 && l.Tax >= 0 && l.Net+l.Tax == l.Gross
+}
+
+// computeTaxes returns the tax lines of the items, one per rate in ascending rate order.
+// The tax of a rate is computed once on the sum of the items at that rate, rounded half away from zero to the unit:
+// - when the taxes are included, the amounts are gross, the tax is gross * rate / (100 + rate) and the net is the rest.
+// - when the taxes are excluded, the amounts are net, the tax is net * rate / 100 and the gross is the sum of both.
+func computeTaxes(exclusive bool, items []BillItem) []TaxLine {
+	lines := make([]TaxLine, 0)
+	for _, item := range items {
+		i := slices.IndexFunc(lines, func(line TaxLine) bool { return line.Rate == item.TaxRate })
+		if i < 0 {
+			lines = append(lines, TaxLine{Rate: item.TaxRate})
+			i = len(lines) - 1
+		}
+		lines[i].Gross += item.DiscountedAmount()
+	}
+
+	slices.SortFunc(lines, func(a, b TaxLine) int {
+		switch {
+		case a.Rate < b.Rate:
+			return -1
+		case a.Rate > b.Rate:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	for i, line := range lines {
+		amount := line.Gross
+		if exclusive {
+			lines[i].Net = amount
+			lines[i].Tax = int(math.Round(float64(amount) * line.Rate / 100))
+			lines[i].Gross = amount + lines[i].Tax
+		} else {
+			lines[i].Tax = int(math.Round(float64(amount) * line.Rate / (100 + line.Rate)))
+			lines[i].Net = amount - lines[i].Tax
+		}
+	}
+
+	return lines
+}
+
+// shareTaxes charges the tax lines of a bill on its parts, every rate in proportion
+// to the weight of the parts at that rate. When the taxes are excluded from the prices,
+// the shared taxes are added to the amount of the parts.
+func shareTaxes(bill Bill, parts []Bill, weight func(part Bill, rate float64) int) {
+	for _, line := range bill.Taxes {
+		weights := make([]int, len(parts))
+		for i, part := range parts {
+			weights[i] = weight(part, line.Rate)
+		}
+
+		nets := distribute(line.Net, weights)
+		taxes := distribute(line.Tax, weights)
+		for i := range parts {
+			if nets[i] == 0 && taxes[i] == 0 {
+				continue
+			}
+
+			parts[i].Taxes = append(parts[i].Taxes, TaxLine{Rate: line.Rate, Net: nets[i], Tax: taxes[i], Gross: nets[i] + taxes[i]})
+			if bill.TaxExclusive {
+				parts[i].TotalAmount += taxes[i]
+			}
+		}
+	}
+}
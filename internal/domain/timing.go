@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"context"
+	"order_manager/internal/id"
+	"slices"
+	"time"
+)
+
+// PreparationTimings are the times a preparation spent at each step of the kitchen.
+// A step the preparation did not go through, or not yet, has no timing.
+type PreparationTimings struct {
+	PreparationID id.ID
+	MenuItemID    id.ID
+	MenuItemName  string
+	StationID     id.ID
+	OrderedAt     time.Time
+	// Queue is the time from the order, or the firing of its course, to the start of the preparation.
+	Queue *time.Duration
+	// Cook is the time from the start of the preparation until it was ready.
+	Cook *time.Duration
+	// Pass is the time the ready preparation waited to be served.
+	Pass *time.Duration
+}
+
+// Timings computes the timings of the preparation from its history.
+func (p Preparation) Timings() PreparationTimings {
+	timings := PreparationTimings{
+		PreparationID: p.ID,
+		MenuItemID:    p.MenuItem.ID,
+		MenuItemName:  p.MenuItem.Name,
+		StationID:     p.StationID,
+	}
+	if len(p.History) > 0 {
+		timings.OrderedAt = p.History[0].At
+	}
+
+	timings.Queue = between(p.enteredAt(PreparationStatusPending), p.enteredAt(PreparationStatusInProgress))
+	timings.Cook = between(p.enteredAt(PreparationStatusInProgress), p.enteredAt(PreparationStatusReady))
+	timings.Pass = between(p.enteredAt(PreparationStatusReady), p.enteredAt(PreparationStatusServed))
+
+	return timings
+}
+
+// enteredAt returns the time the preparation entered a status, zero if it never did.
+func (p Preparation) enteredAt(status PreparationStatus) time.Time {
+	idx := slices.IndexFunc(p.History, func(t PreparationTransition) bool { return t.Status == status })
+	if idx == -1 {
+		return time.Time{}
+	}
+
+	return p.History[idx].At
+}
+
+func between(from time.Time, to time.Time) *time.Duration {
+	if from.IsZero() || to.IsZero() {
+		return nil
+	}
+
+	d := to.Sub(from)
+	return &d
+}
+
+// TimingStats sums up a step over the preparations that went through it.
+type TimingStats struct {
+	Count   int
+	Average time.Duration
+	Max     time.Duration
+}
+
+func (s *TimingStats) add(d *time.Duration) {
+	if d == nil {
+		return
+	}
+
+	s.Average = (s.Average*time.Duration(s.Count) + *d) / time.Duration(s.Count+1)
+	s.Max = max(s.Max, *d)
+	s.Count++
+}
+
+// KitchenTimings sums up the queue, cook and pass times of preparations.
+type KitchenTimings struct {
+	Queue TimingStats
+	Cook  TimingStats
+	Pass  TimingStats
+}
+
+func (t *KitchenTimings) add(timings PreparationTimings) {
+	t.Queue.add(timings.Queue)
+	t.Cook.add(timings.Cook)
+	t.Pass.add(timings.Pass)
+}
+
+type MenuItemTimings struct {
+	MenuItemID   id.ID
+	MenuItemName string
+	Timings      KitchenTimings
+}
+
+type StationTimings struct {
+	// StationID is nil for the preparations routed to no station.
+	StationID id.ID
+	Timings   KitchenTimings
+}
+
+// TimingReport is the timings of the preparations ordered from a time until another, excluded,
+// per preparation, per menu item and per station.
+type TimingReport struct {
+	From         time.Time
+	To           time.Time
+	Preparations []PreparationTimings
+	MenuItems    []MenuItemTimings
+	Stations     []StationTimings
+}
+
+// NewTimingReport sums up the timings of the preparations ordered in the time range of the report,
+// in the order they were ordered. The menu items and the stations come in the order they first appear.
+func NewTimingReport(from time.Time, to time.Time, preparations []Preparation) TimingReport {
+	report := TimingReport{
+		From:         from,
+		To:           to,
+		Preparations: make([]PreparationTimings, 0),
+		MenuItems:    make([]MenuItemTimings, 0),
+		Stations:     make([]StationTimings, 0),
+	}
+
+	for _, prep := range preparations {
+		timings := prep.Timings()
+		if timings.OrderedAt.Before(from) || !timings.OrderedAt.Before(to) {
+			continue
+		}
+		report.Preparations = append(report.Preparations, timings)
+	}
+	slices.SortStableFunc(report.Preparations, func(a, b PreparationTimings) int { return a.OrderedAt.Compare(b.OrderedAt) })
+
+	for _, timings := range report.Preparations {
+		idx := slices.IndexFunc(report.MenuItems, func(t MenuItemTimings) bool { return t.MenuItemID == timings.MenuItemID })
+		if idx == -1 {
+			report.MenuItems = append(report.MenuItems, MenuItemTimings{MenuItemID: timings.MenuItemID, MenuItemName: timings.MenuItemName})
+			idx = len(report.MenuItems) - 1
+		}
+		report.MenuItems[idx].Timings.add(timings)
+
+		idx = slices.IndexFunc(report.Stations, func(t StationTimings) bool { return t.StationID == timings.StationID })
+		if idx == -1 {
+			report.Stations = append(report.Stations, StationTimings{StationID: timings.StationID})
+			idx = len(report.Stations) - 1
+		}
+		report.Stations[idx].Timings.add(timings)
+	}
+
+	return report
+}
+
+// FindKitchenTimings reports the timings of the preparations ordered from a time until another, excluded.
+// Possible errors:
+// - EINVALID if the time range is empty.
+// - Any error returned by the repository when fetching the tables.
+func (s *TableService) FindKitchenTimings(ctx context.Context, from time.Time, to time.Time) (TimingReport, error) {
+	if !from.Before(to) {
+		return TimingReport{}, Errorf(EINVALID, "invalid time range from %s to %s", from, to)
+	}
+
+	tables, err := s.repo.FindOrderedBetween(ctx, from, to)
+	if err != nil {
+		return TimingReport{}, err
+	}
+
+	preparations := make([]Preparation, 0)
+	for _, table := range tables {
+		for _, order := range table.Orders {
+			preparations = append(preparations, order.Preparations...)
+		}
+	}
+
+	return NewTimingReport(from, to, preparations), nil
+}
@@ -0,0 +1,224 @@
+package domain_test
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"order_manager/internal/inmem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// manualClock tells the time it was last set to.
+type manualClock struct {
+	now time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	return c.now
+}
+
+func (c *manualClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func duration(d time.Duration) *time.Duration {
+	return &d
+}
+
+func TestPreparationHistory(t *testing.T) {
+	ctx := context.Background()
+	diningTableRepo := inmem.NewDiningTable()
+	start := time.Date(2026, 10, 17, 19, 0, 0, 0, time.UTC)
+	clock := &manualClock{now: start}
+	tableService := domain.NewTableService(inmem.NewTable(), diningTableRepo, nil).WithClock(clock)
+
+	diningTable := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 4}
+	require.NoError(t, diningTableRepo.Save(ctx, diningTable), "Initial setup failed")
+	table, err := tableService.OpenTable(ctx, diningTable.ID, 2)
+	require.NoError(t, err, "Initial setup failed")
+
+	order, err := tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: domain.MenuItem{ID: id.New(), Name: "burger", Price: 1200}, Quantity: 2}})
+	require.NoError(t, err)
+	served, aborted := order.Preparations[0].ID, order.Preparations[1].ID
+
+	clock.advance(2 * time.Minute)
+	require.NoError(t, tableService.StartPreparation(ctx, served))
+	clock.advance(10 * time.Minute)
+	require.NoError(t, tableService.FinishPreparation(ctx, served))
+	clock.advance(time.Minute)
+	require.NoError(t, tableService.ServePreparation(ctx, served))
+	require.NoError(t, tableService.AbortPreparation(ctx, aborted))
+
+	prep, err := tableService.FindPreparation(ctx, served)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.PreparationTransition{
+		{Status: domain.PreparationStatusPending, At: start},
+		{Status: domain.PreparationStatusInProgress, At: start.Add(2 * time.Minute)},
+		{Status: domain.PreparationStatusReady, At: start.Add(12 * time.Minute)},
+		{Status: domain.PreparationStatusServed, At: start.Add(13 * time.Minute)},
+	}, prep.History)
+
+	timings := prep.Timings()
+	assert.Equal(t, start, timings.OrderedAt)
+	assert.Equal(t, duration(2*time.Minute), timings.Queue)
+	assert.Equal(t, duration(10*time.Minute), timings.Cook)
+	assert.Equal(t, duration(time.Minute), timings.Pass)
+
+	prep, err = tableService.FindPreparation(ctx, aborted)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.PreparationTransition{
+		{Status: domain.PreparationStatusPending, At: start},
+		{Status: domain.PreparationStatusAborted, At: start.Add(13 * time.Minute)},
+	}, prep.History)
+
+	timings = prep.Timings()
+	assert.Nil(t, timings.Queue, "aborted preparation has a queue time")
+	assert.Nil(t, timings.Cook, "aborted preparation has a cook time")
+	assert.Nil(t, timings.Pass, "aborted preparation has a pass time")
+
+	_, err = tableService.FindPreparation(ctx, id.New())
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
+}
+
+func TestFindKitchenTimings(t *testing.T) {
+	ctx := context.Background()
+	diningTableRepo := inmem.NewDiningTable()
+	start := time.Date(2026, 10, 17, 19, 0, 0, 0, time.UTC)
+	clock := &manualClock{now: start}
+	tableService := domain.NewTableService(inmem.NewTable(), diningTableRepo, nil).WithClock(clock)
+
+	diningTable := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 4}
+	require.NoError(t, diningTableRepo.Save(ctx, diningTable), "Initial setup failed")
+	table, err := tableService.OpenTable(ctx, diningTable.ID, 2)
+	require.NoError(t, err, "Initial setup failed")
+
+	burger := domain.MenuItem{ID: id.New(), Name: "burger", Price: 1200}
+	beer := domain.MenuItem{ID: id.New(), Name: "beer", Price: 500}
+
+	cook := func(order domain.Order, queue time.Duration, cook time.Duration) {
+		for _, prep := range order.Preparations {
+			clock.advance(queue)
+			require.NoError(t, tableService.StartPreparation(ctx, prep.ID))
+			clock.advance(cook)
+			require.NoError(t, tableService.FinishPreparation(ctx, prep.ID))
+		}
+	}
+
+	// The order taken the day before is out of the range.
+	yesterday, err := tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: burger}})
+	require.NoError(t, err)
+	cook(yesterday, time.Hour, time.Hour)
+
+	clock.now = start.Add(24 * time.Hour)
+	first, err := tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: burger}, {MenuItem: beer}})
+	require.NoError(t, err)
+	cook(first, time.Minute, 4*time.Minute)
+
+	clock.advance(time.Minute)
+	second, err := tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: burger}})
+	require.NoError(t, err)
+	cook(second, 3*time.Minute, 8*time.Minute)
+	clock.advance(time.Minute)
+	require.NoError(t, tableService.ServePreparation(ctx, second.Preparations[0].ID))
+
+	from, to := start.Add(24*time.Hour), start.Add(48*time.Hour)
+	report, err := tableService.FindKitchenTimings(ctx, from, to)
+	require.NoError(t, err)
+
+	assert.Equal(t, from, report.From)
+	assert.Equal(t, to, report.To)
+	require.Len(t, report.Preparations, 3)
+	assert.Equal(t, first.Preparations[0].ID, report.Preparations[0].PreparationID)
+	assert.Equal(t, second.Preparations[0].ID, report.Preparations[2].PreparationID)
+
+	require.Len(t, report.MenuItems, 2)
+	assert.Equal(t, domain.MenuItemTimings{
+		MenuItemID:   burger.ID,
+		MenuItemName: "burger",
+		Timings: domain.KitchenTimings{
+			Queue: domain.TimingStats{Count: 2, Average: 2 * time.Minute, Max: 3 * time.Minute},
+			Cook:  domain.TimingStats{Count: 2, Average: 6 * time.Minute, Max: 8 * time.Minute},
+			Pass:  domain.TimingStats{Count: 1, Average: time.Minute, Max: time.Minute},
+		},
+	}, report.MenuItems[0])
+	assert.Equal(t, beer.ID, report.MenuItems[1].MenuItemID)
+	// The beer is started once the burger is ready, five minutes after the order.
+	assert.Equal(t, domain.TimingStats{Count: 1, Average: 6 * time.Minute, Max: 6 * time.Minute}, report.MenuItems[1].Timings.Queue)
+
+	require.Len(t, report.Stations, 1)
+	assert.Equal(t, id.NilID(), report.Stations[0].StationID)
+	assert.Equal(t, 3, report.Stations[0].Timings.Cook.Count)
+
+	t.Run("Empty range", func(t *testing.T) {
+		_, err := tableService.FindKitchenTimings(ctx, to, from)
+
+		assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+	})
+}
+
+func TestTimingReportPerStation(t *testing.T) {
+	start := time.Date(2026, 10, 17, 19, 0, 0, 0, time.UTC)
+	grillID, barID := id.New(), id.New()
+	prep := func(stationID id.ID, queue time.Duration) domain.Preparation {
+		return domain.Preparation{
+			ID:        id.New(),
+			MenuItem:  domain.MenuItem{ID: id.New(), Name: "item", Price: 100},
+			StationID: stationID,
+			Status:    domain.PreparationStatusInProgress,
+			History: []domain.PreparationTransition{
+				{Status: domain.PreparationStatusPending, At: start},
+				{Status: domain.PreparationStatusInProgress, At: start.Add(queue)},
+			},
+		}
+	}
+
+	report := domain.NewTimingReport(start, start.Add(time.Hour), []domain.Preparation{
+		prep(grillID, time.Minute),
+		prep(barID, 30*time.Second),
+		prep(grillID, 3*time.Minute),
+		// The preparations taken before the history was recorded are left out.
+		{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "item", Price: 100}, StationID: grillID, Status: domain.PreparationStatusServed},
+	})
+
+	require.Len(t, report.Stations, 2)
+	assert.Equal(t, domain.StationTimings{
+		StationID: grillID,
+		Timings:   domain.KitchenTimings{Queue: domain.TimingStats{Count: 2, Average: 2 * time.Minute, Max: 3 * time.Minute}},
+	}, report.Stations[0])
+	assert.Equal(t, domain.StationTimings{
+		StationID: barID,
+		Timings:   domain.KitchenTimings{Queue: domain.TimingStats{Count: 1, Average: 30 * time.Second, Max: 30 * time.Second}},
+	}, report.Stations[1])
+	assert.Len(t, report.MenuItems, 3)
+}
+
+func TestQueueTimeOfHeldCourse(t *testing.T) {
+	ctx := context.Background()
+	tableRepo := inmem.NewTable()
+	start := time.Date(2026, 10, 17, 19, 0, 0, 0, time.UTC)
+	clock := &manualClock{now: start}
+	tableService := domain.NewTableService(tableRepo, inmem.NewDiningTable(), nil).WithClock(clock)
+
+	table := domain.Table{ID: id.New(), Orders: make([]domain.Order, 0), Status: domain.TableStatusOpened}
+	require.NoError(t, tableRepo.Save(ctx, table), "Initial setup failed")
+	order, err := tableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: domain.MenuItem{ID: id.New(), Name: "steak", Price: 2400}, Course: 2}})
+	require.NoError(t, err)
+
+	clock.advance(30 * time.Minute)
+	_, err = tableService.FireCourse(ctx, table.ID, 2)
+	require.NoError(t, err)
+	clock.advance(time.Minute)
+	require.NoError(t, tableService.StartPreparation(ctx, order.Preparations[0].ID))
+
+	prep, err := tableService.FindPreparation(ctx, order.Preparations[0].ID)
+	require.NoError(t, err)
+
+	// The queue time runs from the firing of the course, the order time from the order.
+	timings := prep.Timings()
+	assert.Equal(t, start, timings.OrderedAt)
+	assert.Equal(t, duration(time.Minute), timings.Queue)
+}
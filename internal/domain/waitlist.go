@@ -0,0 +1,342 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"order_manager/internal/id"
+	"slices"
+	"time"
+)
+
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting WaitlistStatus = "waiting"
+	WaitlistStatusSeated  WaitlistStatus = "seated"
+	WaitlistStatusLeft    WaitlistStatus = "left"
+)
+
+func (s WaitlistStatus) IsValid() bool {
+	switch s {
+	case WaitlistStatusWaiting, WaitlistStatusSeated, WaitlistStatusLeft:
+		return true
+	}
+	return false
+}
+
+// WaitlistEntry is a walk-in party waiting for a dining table.
+type WaitlistEntry struct {
+	ID        id.ID
+	Name      string
+	Contact   string
+	PartySize int
+	JoinedAt  time.Time
+	// QuotedWait is the wait estimated for the party when it joined the waitlist.
+	QuotedWait time.Duration
+	// TableID is the table opened when the party was seated, nil until it is.
+	TableID id.ID
+	Status  WaitlistStatus
+}
+
+func (e WaitlistEntry) IsValid() bool {
+	if e.ID == id.NilID() || e.Name == "" || e.PartySize <= 0 || e.JoinedAt.IsZero() || e.QuotedWait < 0 || !e.Status.IsValid() {
+		return false
+	}
+
+	return (e.Status == WaitlistStatusSeated) == (e.TableID != id.NilID())
+}
+
+type WaitlistRepository interface {
+	Save(ctx context.Context, entry WaitlistEntry) error
+	FindByID(ctx context.Context, id id.ID) (WaitlistEntry, error)
+	// FindByStatus returns the entries of a status in the order the parties joined the waitlist.
+	FindByStatus(ctx context.Context, status WaitlistStatus) ([]WaitlistEntry, error)
+}
+
+// TurnTimePolicy tells how the turn time of the dining tables, from the opening of a table to its closing, is estimated.
+type TurnTimePolicy struct {
+	// Lookback is how far back the closed tables are timed.
+	Lookback time.Duration
+	// Default is the turn time of the dining tables on which no table was closed within the lookback.
+	Default time.Duration
+}
+
+type WaitlistService struct {
+	repo             WaitlistRepository
+	tablesRepo       TableRepository
+	diningTablesRepo DiningTableRepository
+	tables           *TableService
+	events           EventPublisher
+	clock            Clock
+	policy           TurnTimePolicy
+}
+
+// NewWaitlistService creates a new waitlist service.
+// The service is responsible for the queue of the walk-in parties, from the wait quoted to them
+// to the opening of their table when they are seated.
+// The turn times are estimated from the tables closed in the last four weeks, one hour when none was.
+func NewWaitlistService(repo WaitlistRepository, tablesRepo TableRepository, diningTablesRepo DiningTableRepository, tables *TableService, events EventPublisher) *WaitlistService {
+	return &WaitlistService{
+		repo:             repo,
+		tablesRepo:       tablesRepo,
+		diningTablesRepo: diningTablesRepo,
+		tables:           tables,
+		events:           events,
+		clock:            SystemClock{},
+		policy:           TurnTimePolicy{Lookback: 28 * 24 * time.Hour, Default: time.Hour},
+	}
+}
+
+// WithTurnTimePolicy replaces the way the turn times of the dining tables are estimated.
+func (s *WaitlistService) WithTurnTimePolicy(policy TurnTimePolicy) *WaitlistService {
+	s.policy = policy
+	return s
+}
+
+// WithClock replaces the clock timing the parties and the occupancy of the dining tables.
+func (s *WaitlistService) WithClock(clock Clock) *WaitlistService {
+	s.clock = clock
+	return s
+}
+
+// EstimateWait estimates the wait of a party joining the waitlist now.
+// The dining tables seating the party free up as their tables reach the turn time, the free ones right away,
+// and go to the waiting parties in turn, the parties too large for all of them excepted.
+// Possible errors:
+// - EINVALID if the party size is not positive or no dining table seats the party.
+// - Any error returned by the repositories when fetching the entries, the tables or the dining tables.
+func (s *WaitlistService) EstimateWait(ctx context.Context, partySize int) (time.Duration, error) {
+	waiting, err := s.repo.FindByStatus(ctx, WaitlistStatusWaiting)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.estimateWait(ctx, partySize, waiting)
+}
+
+// JoinWaitlist puts a party at the end of the waitlist and quotes it the estimated wait.
+// Possible errors:
+// - EINVALID if the name is empty, the party size is not positive or no dining table seats the party.
+// - Any error returned by the repository when saving the entry.
+func (s *WaitlistService) JoinWaitlist(ctx context.Context, name string, contact string, partySize int) (WaitlistEntry, error) {
+	entry := WaitlistEntry{
+		ID:        id.New(),
+		Name:      name,
+		Contact:   contact,
+		PartySize: partySize,
+		JoinedAt:  s.clock.Now(),
+		Status:    WaitlistStatusWaiting,
+	}
+
+	if !entry.IsValid() {
+		return WaitlistEntry{}, Errorf(EINVALID, "invalid waitlist entry")
+	}
+
+	wait, err := s.EstimateWait(ctx, partySize)
+	if err != nil {
+		return WaitlistEntry{}, err
+	}
+	entry.QuotedWait = wait
+
+	if err := s.repo.Save(ctx, entry); err != nil {
+		return WaitlistEntry{}, err
+	}
+
+	publish(ctx, s.events, WaitlistJoined{Entry: entry})
+
+	return entry, nil
+}
+
+// FindWaitlist returns the waiting parties in the order they joined the waitlist.
+// Possible errors:
+// - Any error returned by the repository when fetching the entries.
+func (s *WaitlistService) FindWaitlist(ctx context.Context) ([]WaitlistEntry, error) {
+	return s.repo.FindByStatus(ctx, WaitlistStatusWaiting)
+}
+
+// FindWaitlistEntry returns an entry of the waitlist by its ID.
+// Possible errors:
+// - ENOTFOUND if the entry could not be found.
+func (s *WaitlistService) FindWaitlistEntry(ctx context.Context, entryID id.ID) (WaitlistEntry, error) {
+	return s.repo.FindByID(ctx, entryID)
+}
+
+// SuggestNextParty returns the party to seat next on a dining table, the first waiting party it seats.
+// Possible errors:
+// - ENOTFOUND if the dining table could not be found.
+// - ENOTFOUND if no waiting party fits the dining table.
+// - Any error returned by the repository when fetching the entries.
+func (s *WaitlistService) SuggestNextParty(ctx context.Context, diningTableID id.ID) (WaitlistEntry, error) {
+	diningTable, err := s.diningTablesRepo.FindByID(ctx, diningTableID)
+	if err != nil {
+		return WaitlistEntry{}, err
+	}
+
+	waiting, err := s.repo.FindByStatus(ctx, WaitlistStatusWaiting)
+	if err != nil {
+		return WaitlistEntry{}, err
+	}
+
+	idx := slices.IndexFunc(waiting, func(e WaitlistEntry) bool { return e.PartySize <= diningTable.Capacity })
+	if idx == -1 {
+		return WaitlistEntry{}, Errorf(ENOTFOUND, "no waiting party fits dining table %s", diningTable.Name)
+	}
+
+	return waiting[idx], nil
+}
+
+// SeatParty seats a waiting party by opening a table on a dining table, the table is linked to the entry.
+// Possible errors:
+// - ENOTFOUND if the entry could not be found.
+// - EINVALID if the party is not waiting.
+// - Any error returned by the table service when opening the table.
+// - Any error returned by the repository when saving the entry, the table being discarded.
+func (s *WaitlistService) SeatParty(ctx context.Context, entryID id.ID, diningTableID id.ID) (WaitlistEntry, error) {
+	entry, err := s.repo.FindByID(ctx, entryID)
+	if err != nil {
+		return WaitlistEntry{}, err
+	}
+
+	if entry.Status != WaitlistStatusWaiting {
+		return WaitlistEntry{}, Errorf(EINVALID, "party %s is %s", entryID, entry.Status)
+	}
+
+	table, err := s.tables.OpenTable(ctx, diningTableID, entry.PartySize)
+	if err != nil {
+		return WaitlistEntry{}, err
+	}
+
+	entry.TableID = table.ID
+	entry.Status = WaitlistStatusSeated
+
+	seated, err := s.update(ctx, entry)
+	if err != nil {
+		return WaitlistEntry{}, errors.Join(err, s.tables.discardTable(ctx, table))
+	}
+
+	return seated, nil
+}
+
+// LeaveWaitlist takes a waiting party that left off the waitlist.
+// Possible errors:
+// - ENOTFOUND if the entry could not be found.
+// - EINVALID if the party is not waiting.
+// - Any error returned by the repository when saving the entry.
+func (s *WaitlistService) LeaveWaitlist(ctx context.Context, entryID id.ID) (WaitlistEntry, error) {
+	entry, err := s.repo.FindByID(ctx, entryID)
+	if err != nil {
+		return WaitlistEntry{}, err
+	}
+
+	if entry.Status != WaitlistStatusWaiting {
+		return WaitlistEntry{}, Errorf(EINVALID, "party %s is %s", entryID, entry.Status)
+	}
+
+	entry.Status = WaitlistStatusLeft
+
+	return s.update(ctx, entry)
+}
+
+// suggest publishes the next party to seat on the dining table of a closed table.
+// The table is closed whatever the waitlist tells, so nothing is published when no party fits or the suggestion fails.
+func (s *WaitlistService) suggest(ctx context.Context, table Table) {
+	if table.DiningTableID == id.NilID() {
+		return
+	}
+
+	entry, err := s.SuggestNextParty(ctx, table.DiningTableID)
+	if err != nil {
+		return
+	}
+
+	publish(ctx, s.events, PartySuggested{TableID: table.ID, DiningTableID: table.DiningTableID, Entry: entry})
+}
+
+// estimateWait estimates the wait of a party queued behind the waiting parties.
+func (s *WaitlistService) estimateWait(ctx context.Context, partySize int, waiting []WaitlistEntry) (time.Duration, error) {
+	if partySize <= 0 {
+		return 0, Errorf(EINVALID, "party size must be positive")
+	}
+
+	diningTables, err := s.diningTablesRepo.FindAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	candidates := slices.DeleteFunc(diningTables, func(t DiningTable) bool { return t.Capacity < partySize })
+	if len(candidates) == 0 {
+		return 0, Errorf(EINVALID, "no dining table seats %d guests", partySize)
+	}
+
+	now := s.clock.Now()
+	turn, err := s.turnTime(ctx, candidates, now)
+	if err != nil {
+		return 0, err
+	}
+
+	opened, err := s.tablesRepo.FindByStatus(ctx, TableStatusOpened)
+	if err != nil {
+		return 0, err
+	}
+
+	largest := 0
+	releases := make([]time.Duration, 0, len(candidates))
+	for _, diningTable := range candidates {
+		largest = max(largest, diningTable.Capacity)
+
+		idx := slices.IndexFunc(opened, func(t Table) bool { return t.DiningTableID == diningTable.ID })
+		switch {
+		case idx == -1:
+			releases = append(releases, 0)
+		case opened[idx].OpenedAt.IsZero():
+			releases = append(releases, turn)
+		default:
+			releases = append(releases, max(0, turn-now.Sub(opened[idx].OpenedAt)))
+		}
+	}
+	slices.Sort(releases)
+
+	ahead := 0
+	for _, entry := range waiting {
+		if entry.PartySize <= largest {
+			ahead++
+		}
+	}
+
+	return releases[ahead%len(releases)] + time.Duration(ahead/len(releases))*turn, nil
+}
+
+// turnTime averages the turn times of the tables closed on the dining tables within the lookback.
+func (s *WaitlistService) turnTime(ctx context.Context, diningTables []DiningTable, now time.Time) (time.Duration, error) {
+	closed, err := s.tablesRepo.FindClosedBetween(ctx, now.Add(-s.policy.Lookback), now)
+	if err != nil {
+		return 0, err
+	}
+
+	var total time.Duration
+	count := 0
+	for _, table := range closed {
+		if table.OpenedAt.IsZero() || !slices.ContainsFunc(diningTables, func(t DiningTable) bool { return t.ID == table.DiningTableID }) {
+			continue
+		}
+
+		total += table.ClosedAt.Sub(table.OpenedAt)
+		count++
+	}
+
+	if count == 0 {
+		return s.policy.Default, nil
+	}
+
+	return total / time.Duration(count), nil
+}
+
+func (s *WaitlistService) update(ctx context.Context, entry WaitlistEntry) (WaitlistEntry, error) {
+	if err := s.repo.Save(ctx, entry); err != nil {
+		return WaitlistEntry{}, err
+	}
+
+	publish(ctx, s.events, WaitlistUpdated{Entry: entry})
+
+	return entry, nil
+}
@@ -0,0 +1,268 @@
+package domain_test
+
+import (
+	"context"
+	"errors"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"order_manager/internal/inmem"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateWait(t *testing.T) {
+	ctx := context.Background()
+	diningTableRepo := inmem.NewDiningTable()
+	tableRepo := inmem.NewTable()
+	clock := &manualClock{now: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)}
+	tableService := domain.NewTableService(tableRepo, diningTableRepo, nil).WithClock(clock)
+	waitlistService := domain.NewWaitlistService(inmem.NewWaitlist(), tableRepo, diningTableRepo, tableService, nil).WithClock(clock)
+
+	four := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 4}
+	six := domain.DiningTable{ID: id.New(), Name: "T2", Capacity: 6}
+	require.NoError(t, diningTableRepo.Save(ctx, four), "Initial setup failed")
+	require.NoError(t, diningTableRepo.Save(ctx, six), "Initial setup failed")
+
+	estimate := func(partySize int) time.Duration {
+		wait, err := waitlistService.EstimateWait(ctx, partySize)
+		require.NoError(t, err, "estimate wait failed")
+		return wait
+	}
+
+	assert.Equal(t, time.Duration(0), estimate(4), "free dining table not available right away")
+
+	_, err := tableService.OpenTable(ctx, four.ID, 4)
+	require.NoError(t, err, "Initial setup failed")
+	clock.advance(20 * time.Minute)
+	_, err = tableService.OpenTable(ctx, six.ID, 6)
+	require.NoError(t, err, "Initial setup failed")
+
+	// The table opened first frees up first, after the default turn time.
+	assert.Equal(t, 40*time.Minute, estimate(4))
+
+	first, err := waitlistService.JoinWaitlist(ctx, "Smith", "555-0100", 3)
+	require.NoError(t, err, "join waitlist failed")
+	assert.Equal(t, 40*time.Minute, first.QuotedWait, "invalid quoted wait")
+	assert.Equal(t, domain.WaitlistStatusWaiting, first.Status)
+
+	// The next party waits for the next dining table to free up.
+	assert.Equal(t, time.Hour, estimate(4))
+
+	clock.advance(10 * time.Minute)
+	second, err := waitlistService.JoinWaitlist(ctx, "Doe", "", 5)
+	require.NoError(t, err, "join waitlist failed")
+	assert.Equal(t, 110*time.Minute, second.QuotedWait, "party not queued behind the party ahead on the only dining table it fits")
+
+	// Both parties ahead are seated first, one in a second turn of the dining table freeing up first.
+	assert.Equal(t, 90*time.Minute, estimate(2))
+
+	waitlist, err := waitlistService.FindWaitlist(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.WaitlistEntry{first, second}, waitlist, "waitlist not ordered by joining time")
+
+	tt := []struct {
+		testName  string
+		name      string
+		partySize int
+	}{
+		{testName: "No name", partySize: 2},
+		{testName: "No party", name: "Roe"},
+		{testName: "Party too large", name: "Roe", partySize: 8},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			_, err := waitlistService.JoinWaitlist(ctx, tc.name, "", tc.partySize)
+
+			assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "invalid error code")
+		})
+	}
+}
+
+func TestEstimateWaitFromTurnTimes(t *testing.T) {
+	ctx := context.Background()
+	diningTableRepo := inmem.NewDiningTable()
+	tableRepo := inmem.NewTable()
+	clock := &manualClock{now: time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)}
+	tableService := domain.NewTableService(tableRepo, diningTableRepo, nil).WithClock(clock)
+	waitlistService := domain.NewWaitlistService(inmem.NewWaitlist(), tableRepo, diningTableRepo, tableService, nil).
+		WithClock(clock).
+		WithTurnTimePolicy(domain.TurnTimePolicy{Lookback: 24 * time.Hour, Default: 45 * time.Minute})
+
+	two := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 2}
+	four := domain.DiningTable{ID: id.New(), Name: "T2", Capacity: 4}
+	require.NoError(t, diningTableRepo.Save(ctx, two), "Initial setup failed")
+	require.NoError(t, diningTableRepo.Save(ctx, four), "Initial setup failed")
+
+	table, err := tableService.OpenTable(ctx, two.ID, 2)
+	require.NoError(t, err, "Initial setup failed")
+	clock.advance(30 * time.Minute)
+	require.NoError(t, tableService.CloseTable(ctx, table.ID), "Initial setup failed")
+	_, err = tableService.OpenTable(ctx, four.ID, 3)
+	require.NoError(t, err, "Initial setup failed")
+	clock.advance(10 * time.Minute)
+
+	// The table closed on a dining table too small for the party does not time the ones seating it.
+	wait, err := waitlistService.EstimateWait(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 35*time.Minute, wait, "default turn time not used")
+
+	_, err = tableService.OpenTable(ctx, two.ID, 2)
+	require.NoError(t, err, "Initial setup failed")
+
+	wait, err = waitlistService.EstimateWait(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 20*time.Minute, wait, "turn time not averaged from the closed tables")
+
+	// Tables closed before the lookback are not timed.
+	waitlistService.WithTurnTimePolicy(domain.TurnTimePolicy{Lookback: 5 * time.Minute, Default: 45 * time.Minute})
+	wait, err = waitlistService.EstimateWait(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 35*time.Minute, wait, "table closed before the lookback timed")
+}
+
+func TestWaitlistLifecycle(t *testing.T) {
+	ctx := context.Background()
+	diningTableRepo := inmem.NewDiningTable()
+	tableRepo := inmem.NewTable()
+	publisher := &recordingPublisher{}
+	tableService := domain.NewTableService(tableRepo, diningTableRepo, publisher)
+	waitlistService := domain.NewWaitlistService(inmem.NewWaitlist(), tableRepo, diningTableRepo, tableService, publisher)
+	tableService.WithWaitlist(waitlistService)
+
+	two := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 2}
+	four := domain.DiningTable{ID: id.New(), Name: "T2", Capacity: 4}
+	require.NoError(t, diningTableRepo.Save(ctx, two), "Initial setup failed")
+	require.NoError(t, diningTableRepo.Save(ctx, four), "Initial setup failed")
+
+	occupied, err := tableService.OpenTable(ctx, four.ID, 4)
+	require.NoError(t, err, "Initial setup failed")
+
+	large, err := waitlistService.JoinWaitlist(ctx, "Smith", "555-0100", 4)
+	require.NoError(t, err, "Initial setup failed")
+	small, err := waitlistService.JoinWaitlist(ctx, "Doe", "", 2)
+	require.NoError(t, err, "Initial setup failed")
+
+	suggested, err := waitlistService.SuggestNextParty(ctx, two.ID)
+	require.NoError(t, err)
+	assert.Equal(t, small, suggested, "party too large for the dining table suggested")
+
+	_, err = waitlistService.SuggestNextParty(ctx, id.New())
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
+
+	t.Run("Suggest on close", func(t *testing.T) {
+		publisher.events = nil
+		require.NoError(t, tableService.CloseTable(ctx, occupied.ID))
+
+		require.Equal(t, []string{"table.closed", "waitlist.party_suggested"}, publisher.names())
+		assert.Equal(t, domain.PartySuggested{TableID: occupied.ID, DiningTableID: four.ID, Entry: large}, publisher.events[1])
+	})
+
+	t.Run("Seat", func(t *testing.T) {
+		publisher.events = nil
+		seated, err := waitlistService.SeatParty(ctx, large.ID, four.ID)
+		require.NoError(t, err, "seat party failed")
+		assert.Equal(t, domain.WaitlistStatusSeated, seated.Status)
+		assert.Equal(t, []string{"table.opened", "waitlist.updated"}, publisher.names())
+
+		table, err := tableRepo.FindByID(ctx, seated.TableID)
+		require.NoError(t, err, "seated table not opened")
+		assert.Equal(t, four.ID, table.DiningTableID)
+		assert.Equal(t, 4, table.GuestCount)
+
+		_, err = waitlistService.SeatParty(ctx, large.ID, two.ID)
+		assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "party seated twice")
+	})
+
+	t.Run("Seat on an occupied dining table", func(t *testing.T) {
+		_, err := waitlistService.SeatParty(ctx, small.ID, four.ID)
+		assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "invalid error code")
+
+		saved, err := waitlistService.FindWaitlistEntry(ctx, small.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.WaitlistStatusWaiting, saved.Status, "party seated on an occupied dining table")
+	})
+
+	t.Run("Leave", func(t *testing.T) {
+		left, err := waitlistService.LeaveWaitlist(ctx, small.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.WaitlistStatusLeft, left.Status)
+
+		waitlist, err := waitlistService.FindWaitlist(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, waitlist)
+
+		_, err = waitlistService.SeatParty(ctx, small.ID, two.ID)
+		assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "party seated after leaving")
+	})
+
+	t.Run("Close without waiting party", func(t *testing.T) {
+		seated, err := waitlistService.FindWaitlistEntry(ctx, large.ID)
+		require.NoError(t, err)
+
+		publisher.events = nil
+		require.NoError(t, tableService.CloseTable(ctx, seated.TableID))
+		assert.Equal(t, []string{"table.closed"}, publisher.names())
+	})
+
+	t.Run("Unknown entry", func(t *testing.T) {
+		_, err := waitlistService.LeaveWaitlist(ctx, id.New())
+		assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "invalid error code")
+	})
+}
+
+// failingWaitlistRepo fails the saves once fail is set, as a lost database would.
+type failingWaitlistRepo struct {
+	*inmem.Waitlist
+	fail bool
+}
+
+func (r *failingWaitlistRepo) Save(ctx context.Context, entry domain.WaitlistEntry) error {
+	if r.fail {
+		return errors.New("database is gone")
+	}
+
+	return r.Waitlist.Save(ctx, entry)
+}
+
+func TestSeatPartyDiscardsTableOnFailure(t *testing.T) {
+	ctx := context.Background()
+	diningTableRepo := inmem.NewDiningTable()
+	tableRepo := inmem.NewTable()
+	waitlistRepo := &failingWaitlistRepo{Waitlist: inmem.NewWaitlist()}
+	clock := fixedClock(time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC))
+	publisher := &recordingPublisher{}
+	tableService := domain.NewTableService(tableRepo, diningTableRepo, publisher).WithClock(clock)
+	waitlistService := domain.NewWaitlistService(waitlistRepo, tableRepo, diningTableRepo, tableService, publisher).WithClock(clock)
+	tableService.WithWaitlist(waitlistService)
+
+	diningTable := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 4}
+	require.NoError(t, diningTableRepo.Save(ctx, diningTable), "Initial setup failed")
+	first, err := waitlistService.JoinWaitlist(ctx, "Smith", "555-0100", 4)
+	require.NoError(t, err, "Initial setup failed")
+	_, err = waitlistService.JoinWaitlist(ctx, "Doe", "", 2)
+	require.NoError(t, err, "Initial setup failed")
+
+	waitlistRepo.fail = true
+	publisher.events = nil
+	_, err = waitlistService.SeatParty(ctx, first.ID, diningTable.ID)
+	require.Error(t, err, "seat party did not fail")
+
+	// The table is discarded without closing it: no party is suggested and no turn time is recorded.
+	assert.Equal(t, []string{"table.opened"}, publisher.names())
+	opened, err := tableRepo.FindByStatus(ctx, domain.TableStatusOpened)
+	require.NoError(t, err)
+	assert.Empty(t, opened, "table left opened")
+
+	closed, err := tableRepo.FindClosedBetween(ctx, clock.Now().Add(-time.Hour), clock.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, closed, "discarded table timed as a closed one")
+
+	waitlistRepo.fail = false
+	seated, err := waitlistService.SeatParty(ctx, first.ID, diningTable.ID)
+	require.NoError(t, err, "dining table not freed by the discarded table")
+	assert.Equal(t, domain.WaitlistStatusSeated, seated.Status)
+}
@@ -0,0 +1,115 @@
+package event
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"sync"
+)
+
+// Handler reacts to a published domain event.
+// Handlers must not publish events themselves.
+type Handler func(ctx context.Context, event domain.Event)
+
+// On adapts a handler of a single event type, other events are ignored.
+func On[E domain.Event](handler func(ctx context.Context, event E)) Handler {
+	return func(ctx context.Context, event domain.Event) {
+		if e, ok := event.(E); ok {
+			handler(ctx, e)
+		}
+	}
+}
+
+type published struct {
+	ctx   context.Context
+	event domain.Event
+}
+
+type asyncSubscriber struct {
+	handler Handler
+	queue   chan published
+}
+
+// Bus is an in-process domain.EventPublisher.
+// Synchronous handlers run in the publishing goroutine before the service call returns.
+// Asynchronous handlers each run on their own goroutine and receive the events in publication order.
+type Bus struct {
+	mu     sync.RWMutex
+	sync   []Handler
+	async  []*asyncSubscriber
+	wg     sync.WaitGroup
+	closed bool
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler called synchronously on every publication.
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sync = append(b.sync, handler)
+}
+
+// SubscribeAsync registers a handler called on its own goroutine.
+// Publishing blocks once bufferSize events are waiting for the handler.
+func (b *Bus) SubscribeAsync(handler Handler, bufferSize int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	sub := &asyncSubscriber{handler: handler, queue: make(chan published, bufferSize)}
+	b.async = append(b.async, sub)
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+
+		for p := range sub.queue {
+			sub.handler(p.ctx, p.event)
+		}
+	}()
+}
+
+func (b *Bus) Publish(ctx context.Context, events ...domain.Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return
+	}
+
+	// Asynchronous handlers outlive the request that published the events.
+	asyncCtx := context.WithoutCancel(ctx)
+
+	for _, event := range events {
+		for _, handler := range b.sync {
+			handler(ctx, event)
+		}
+
+		for _, sub := range b.async {
+			sub.queue <- published{ctx: asyncCtx, event: event}
+		}
+	}
+}
+
+// Close stops accepting events and waits for the asynchronous handlers to drain their queue.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+
+	b.closed = true
+	for _, sub := range b.async {
+		close(sub.queue)
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
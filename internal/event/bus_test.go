@@ -0,0 +1,69 @@
+package event_test
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/event"
+	"order_manager/internal/id"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncSubscribers(t *testing.T) {
+	bus := event.NewBus()
+	defer bus.Close()
+
+	var first, second []string
+	bus.Subscribe(func(ctx context.Context, e domain.Event) { first = append(first, e.EventName()) })
+	bus.Subscribe(func(ctx context.Context, e domain.Event) { second = append(second, e.EventName()) })
+
+	bus.Publish(context.Background(), domain.TableOpened{}, domain.TableClosed{})
+
+	want := []string{"table.opened", "table.closed"}
+	assert.Equal(t, want, first)
+	assert.Equal(t, want, second)
+}
+
+func TestAsyncSubscribers(t *testing.T) {
+	bus := event.NewBus()
+
+	var mu sync.Mutex
+	var names []string
+	var canceled bool
+	bus.SubscribeAsync(func(ctx context.Context, e domain.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		names = append(names, e.EventName())
+		canceled = canceled || ctx.Err() != nil
+	}, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	bus.Publish(ctx, domain.TableOpened{}, domain.OrderTaken{}, domain.TableClosed{})
+	cancel()
+
+	bus.Close()
+
+	assert.Equal(t, []string{"table.opened", "order.taken", "table.closed"}, names)
+	assert.False(t, canceled, "async handler received a canceled context")
+
+	// Publishing after close is a no-op.
+	bus.Publish(context.Background(), domain.TableOpened{})
+	assert.Len(t, names, 3)
+}
+
+func TestOn(t *testing.T) {
+	bus := event.NewBus()
+	defer bus.Close()
+
+	var closed []id.ID
+	bus.Subscribe(event.On(func(ctx context.Context, e domain.TableClosed) {
+		closed = append(closed, e.TableID)
+	}))
+
+	tableID := id.New()
+	bus.Publish(context.Background(), domain.TableOpened{}, domain.TableClosed{TableID: tableID})
+
+	assert.Equal(t, []id.ID{tableID}, closed)
+}
@@ -3,16 +3,47 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"order_manager/internal/domain"
 	"order_manager/internal/id"
 )
 
 func (s *Server) registerBillRoutes(r *router) {
 	billRouter := r.group("/bill")
 
-	billRouter.HandleFunc("POST /", s.handleGenerateBill)
+	billRouter.HandleFunc("POST /", s.HandleGenerateBill)
+	billRouter.HandleFunc("GET /{id}", s.HandleGetBill)
+	billRouter.HandleFunc("POST /{id}/payment", s.HandlePayBill)
+	billRouter.HandleFunc("POST /{id}/refund", s.HandleRefundPayment)
+	billRouter.HandleFunc("POST /{id}/split/items", s.HandleSplitBillByItems)
+	billRouter.HandleFunc("POST /{id}/split/seats", s.HandleSplitBillBySeat)
+	billRouter.HandleFunc("POST /{id}/split/equal", s.HandleSplitBillEqually)
+	billRouter.HandleFunc("POST /{id}/discount", s.HandleDiscountBill)
 }
 
-func (s *Server) handleGenerateBill(w http.ResponseWriter, r *http.Request) {
+type billResponse struct {
+	domain.Bill
+	Lines           []domain.BillLine
+	Subtotal        int
+	Paid            int
+	Tips            int
+	Refunded        int
+	RemainingAmount int
+}
+
+func newBillResponse(bill domain.Bill) billResponse {
+	return billResponse{Bill: bill, Lines: bill.Lines(), Subtotal: bill.Subtotal(), Paid: bill.Paid(), Tips: bill.Tips(), Refunded: bill.Refunded(), RemainingAmount: bill.RemainingAmount()}
+}
+
+func newBillResponses(bills []domain.Bill) []billResponse {
+	res := make([]billResponse, 0, len(bills))
+	for _, bill := range bills {
+		res = append(res, newBillResponse(bill))
+	}
+
+	return res
+}
+
+func (s *Server) HandleGenerateBill(w http.ResponseWriter, r *http.Request) {
 	type reqBody struct {
 		TableID id.ID `json:"table_id"`
 	}
@@ -27,16 +58,315 @@ func (s *Server) handleGenerateBill(w http.ResponseWriter, r *http.Request) {
 	table, err := s.TableService.FindTable(r.Context(), req.TableID)
 	if err != nil {
 		s.logger.Errorf("error finding table: %s\n", err)
-		writeError(w, http.StatusInternalServerError, err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
 		return
 	}
 
 	bill, err := s.BillService.GenerateBill(r.Context(), table)
 	if err != nil {
 		s.logger.Errorf("error generating bill: %s\n", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newBillResponse(bill))
+}
+
+func (s *Server) HandleGetBill(w http.ResponseWriter, r *http.Request) {
+	billID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing bill id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	bill, err := s.BillService.FindBill(r.Context(), billID)
+	if err != nil {
+		s.logger.Errorf("error finding bill: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newBillResponse(bill))
+}
+
+func (s *Server) HandleGetTableBills(w http.ResponseWriter, r *http.Request) {
+	tableID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing table id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := s.TableService.FindTable(r.Context(), tableID); err != nil {
+		s.logger.Errorf("error finding table: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	bills, err := s.BillService.FindTableBills(r.Context(), tableID)
+	if err != nil {
+		s.logger.Errorf("error finding bills: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newBillResponses(bills))
+}
+
+func (s *Server) HandleGetTableSettlement(w http.ResponseWriter, r *http.Request) {
+	type resBody struct {
+		TableID id.ID `json:"table_id"`
+		Settled bool  `json:"settled"`
+	}
+
+	tableID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing table id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := s.TableService.FindTable(r.Context(), tableID); err != nil {
+		s.logger.Errorf("error finding table: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	settled, err := s.BillService.IsTableSettled(r.Context(), tableID)
+	if err != nil {
+		s.logger.Errorf("error checking table settlement: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, resBody{TableID: tableID, Settled: settled})
+}
+
+func (s *Server) HandleGetTableSales(w http.ResponseWriter, r *http.Request) {
+	type resBody struct {
+		TableID        id.ID `json:"table_id"`
+		Sales          int   `json:"sales"`
+		Discounts      int   `json:"discounts"`
+		Taxes          int   `json:"taxes"`
+		ServiceCharges int   `json:"service_charges"`
+		Tips           int   `json:"tips"`
+		Gratuities     int   `json:"gratuities"`
+		Refunds        int   `json:"refunds"`
+		Cash           int   `json:"cash"`
+	}
+
+	tableID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing table id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := s.TableService.FindTable(r.Context(), tableID); err != nil {
+		s.logger.Errorf("error finding table: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	report, err := s.BillService.TableSalesReport(r.Context(), tableID)
+	if err != nil {
+		s.logger.Errorf("error reporting table sales: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, resBody{
+		TableID:        tableID,
+		Sales:          report.Sales,
+		Discounts:      report.Discounts,
+		Taxes:          report.Taxes,
+		ServiceCharges: report.ServiceCharges,
+		Tips:           report.Tips,
+		Gratuities:     report.Gratuities(),
+		Refunds:        report.Refunds,
+		Cash:           report.Cash,
+	})
+}
+
+func (s *Server) HandlePayBill(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		Tender   domain.TenderType `json:"tender"`
+		Amount   int               `json:"amount"`
+		Tip      int               `json:"tip"`
+		Tendered int               `json:"tendered"`
+	}
+
+	billID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing bill id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := s.BillService.PayBill(r.Context(), billID, req.Tender, req.Amount, req.Tip, req.Tendered); err != nil {
+		s.logger.Errorf("error paying bill: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	bill, err := s.BillService.FindBill(r.Context(), billID)
+	if err != nil {
+		s.logger.Errorf("error finding bill: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newBillResponse(bill))
+}
+
+func (s *Server) HandleRefundPayment(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		PaymentID     id.ID  `json:"payment_id"`
+		PreparationID id.ID  `json:"preparation_id"`
+		Reason        string `json:"reason"`
+	}
+
+	billID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing bill id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := s.BillService.RefundPayment(r.Context(), billID, req.PaymentID, req.PreparationID, req.Reason); err != nil {
+		s.logger.Errorf("error refunding payment: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	bill, err := s.BillService.FindBill(r.Context(), billID)
+	if err != nil {
+		s.logger.Errorf("error finding bill: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newBillResponse(bill))
+}
+
+func (s *Server) HandleSplitBillByItems(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		Groups [][]id.ID `json:"groups"`
+	}
+
+	billID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing bill id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	bills, err := s.BillService.SplitBillByItems(r.Context(), billID, req.Groups)
+	if err != nil {
+		s.logger.Errorf("error splitting bill: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusCreated, newBillResponses(bills))
+}
+
+func (s *Server) HandleSplitBillBySeat(w http.ResponseWriter, r *http.Request) {
+	billID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing bill id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	bills, err := s.BillService.SplitBillBySeat(r.Context(), billID)
+	if err != nil {
+		s.logger.Errorf("error splitting bill: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusCreated, newBillResponses(bills))
+}
+
+func (s *Server) HandleSplitBillEqually(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		Shares int `json:"shares"`
+	}
+
+	billID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing bill id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	bills, err := s.BillService.SplitBillEqually(r.Context(), billID, req.Shares)
+	if err != nil {
+		s.logger.Errorf("error splitting bill: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusCreated, newBillResponses(bills))
+}
+
+func (s *Server) HandleDiscountBill(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		Percent float64 `json:"percent"`
+		Amount  int     `json:"amount"`
+		Reason  string  `json:"reason"`
+	}
+
+	billID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing bill id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	bill, err := s.BillService.DiscountBill(r.Context(), billID, req.Percent, req.Amount, req.Reason)
+	if err != nil {
+		s.logger.Errorf("error discounting bill: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
 		return
 	}
 
-	writeJSONBody(w, http.StatusOK, bill)
+	writeJSONBody(w, http.StatusOK, newBillResponse(bill))
 }
@@ -0,0 +1,571 @@
+package http_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"order_manager/internal/domain"
+	domainHttp "order_manager/internal/http"
+	"order_manager/internal/id"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type billResponse struct {
+	domain.Bill
+	Lines           []domain.BillLine
+	Subtotal        int
+	Paid            int
+	Tips            int
+	Refunded        int
+	RemainingAmount int
+}
+
+func MustPresaveBill(t *testing.T, repos repositories, paid int) domain.Bill {
+	t.Helper()
+
+	item := domain.MenuItem{ID: id.New(), Name: "item", Price: 100}
+	preparation := domain.Preparation{ID: id.New(), MenuItem: item, Status: domain.PreparationStatusServed}
+	table := domain.Table{
+		ID:     id.New(),
+		Status: domain.TableStatusClosed,
+		Orders: []domain.Order{
+			{
+				ID:           id.New(),
+				Status:       domain.OrderStatusDone,
+				Preparations: []domain.Preparation{preparation},
+			},
+		},
+	}
+	MustPresaveTables(t, repos, []domain.Table{table})
+
+	status := domain.BillStatusPending
+	if paid == item.Price {
+		status = domain.BillStatusPaid
+	} else if paid > 0 {
+		status = domain.BillPartiallyPaid
+	}
+
+	bill := domain.Bill{
+		ID:          id.New(),
+		TableID:     table.ID,
+		Items:       []domain.BillItem{{PreparationID: preparation.ID, MenuItem: item, Amount: item.Price}},
+		Status:      status,
+		TotalAmount: item.Price,
+		Payments:    make([]domain.Payment, 0),
+	}
+	if paid > 0 {
+		bill.Payments = append(bill.Payments, domain.Payment{ID: id.New(), Tender: domain.TenderCard, Amount: paid, PaidAt: time.Now()})
+	}
+	err := repos.Bill.Save(context.Background(), bill)
+	require.NoError(t, err)
+
+	return bill
+}
+
+func TestGenerateBill(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		item := domain.MenuItem{ID: id.New(), Name: "item", Price: 100}
+		table := domain.Table{
+			ID:     id.New(),
+			Status: domain.TableStatusClosed,
+			Orders: []domain.Order{
+				{
+					ID:           id.New(),
+					Status:       domain.OrderStatusDone,
+					Preparations: []domain.Preparation{{ID: id.New(), MenuItem: item, Status: domain.PreparationStatusServed}},
+				},
+			},
+		}
+		MustPresaveTables(t, repos, []domain.Table{table})
+
+		reqBody := fmt.Sprintf(`{"table_id": %q}`, table.ID)
+		r := httptest.NewRequest(http.MethodPost, "/bill", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		s.HandleGenerateBill(w, r)
+
+		body, statusCode := MustParseReponse[billResponse](t, w)
+
+		require.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, table.ID, body.TableID)
+		assert.Equal(t, 100, body.RemainingAmount)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		tt := []struct {
+			testName           string
+			tableID            func(t *testing.T, repos repositories) id.ID
+			expectedStatusCode int
+		}{
+			{
+				testName:           "table not found",
+				tableID:            func(t *testing.T, repos repositories) id.ID { return id.New() },
+				expectedStatusCode: http.StatusNotFound,
+			},
+			{
+				testName: "table not closed",
+				tableID: func(t *testing.T, repos repositories) id.ID {
+					table := domain.Table{ID: id.New(), Status: domain.TableStatusOpened, Orders: make([]domain.Order, 0)}
+					MustPresaveTables(t, repos, []domain.Table{table})
+					return table.ID
+				},
+				expectedStatusCode: http.StatusForbidden,
+			},
+			{
+				testName:           "table already billed",
+				tableID:            func(t *testing.T, repos repositories) id.ID { return MustPresaveBill(t, repos, 0).TableID },
+				expectedStatusCode: http.StatusConflict,
+			},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				repos := MustNewRepositories(t)
+				s := MustNewServer(t, repos)
+
+				reqBody := fmt.Sprintf(`{"table_id": %q}`, tc.tableID(t, repos))
+				r := httptest.NewRequest(http.MethodPost, "/bill", strings.NewReader(reqBody))
+				w := httptest.NewRecorder()
+
+				s.HandleGenerateBill(w, r)
+
+				body, statusCode := MustParseReponse[map[string]string](t, w)
+
+				require.Equal(t, tc.expectedStatusCode, statusCode)
+				assert.NotEmpty(t, body["error"], "the error is not described")
+			})
+		}
+	})
+}
+
+func TestGetBill(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		bill := MustPresaveBill(t, repos, 40)
+
+		r := httptest.NewRequest(http.MethodGet, "/bill/"+bill.ID.String(), nil)
+		r.SetPathValue("id", bill.ID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleGetBill(w, r)
+
+		body, statusCode := MustParseReponse[billResponse](t, w)
+
+		require.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, bill.ID, body.ID)
+		assert.Equal(t, 40, body.Paid)
+		assert.Equal(t, 60, body.RemainingAmount)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		tt := []struct {
+			testName           string
+			billID             string
+			expectedStatusCode int
+		}{
+			{testName: "bill not found", billID: id.New().String(), expectedStatusCode: http.StatusNotFound},
+			{testName: "invalid bill id", billID: "invalid", expectedStatusCode: http.StatusBadRequest},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				repos := MustNewRepositories(t)
+				s := MustNewServer(t, repos)
+
+				r := httptest.NewRequest(http.MethodGet, "/bill/"+tc.billID, nil)
+				r.SetPathValue("id", tc.billID)
+				w := httptest.NewRecorder()
+
+				s.HandleGetBill(w, r)
+
+				require.Equal(t, tc.expectedStatusCode, w.Result().StatusCode)
+			})
+		}
+	})
+}
+
+func TestGetTableBills(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		bill := MustPresaveBill(t, repos, 0)
+
+		r := httptest.NewRequest(http.MethodGet, "/table/"+bill.TableID.String()+"/bills", nil)
+		r.SetPathValue("id", bill.TableID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleGetTableBills(w, r)
+
+		body, statusCode := MustParseReponse[[]billResponse](t, w)
+
+		require.Equal(t, http.StatusOK, statusCode)
+		require.Len(t, body, 1)
+		assert.Equal(t, bill.ID, body[0].ID)
+		assert.Equal(t, 100, body[0].RemainingAmount)
+	})
+
+	t.Run("table not found", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		tableID := id.New().String()
+
+		r := httptest.NewRequest(http.MethodGet, "/table/"+tableID+"/bills", nil)
+		r.SetPathValue("id", tableID)
+		w := httptest.NewRecorder()
+
+		s.HandleGetTableBills(w, r)
+
+		require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestPayBill(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		tt := []struct {
+			testName          string
+			alreadyPaid       int
+			tender            domain.TenderType
+			amount            int
+			tip               int
+			tendered          int
+			expectedStatus    domain.BillStatus
+			expectedRemaining int
+			expectedChange    int
+		}{
+			{testName: "partial payment", alreadyPaid: 0, tender: domain.TenderCard, amount: 30, expectedStatus: domain.BillPartiallyPaid, expectedRemaining: 70},
+			{testName: "second partial payment", alreadyPaid: 30, tender: domain.TenderVoucher, amount: 30, expectedStatus: domain.BillPartiallyPaid, expectedRemaining: 40},
+			{testName: "full payment", alreadyPaid: 0, tender: domain.TenderCard, amount: 100, expectedStatus: domain.BillStatusPaid, expectedRemaining: 0},
+			{testName: "full payment with tip", alreadyPaid: 0, tender: domain.TenderCard, amount: 100, tip: 20, expectedStatus: domain.BillStatusPaid, expectedRemaining: 0},
+			{testName: "cash payment with change", alreadyPaid: 0, tender: domain.TenderCash, amount: 100, tip: 10, tendered: 150, expectedStatus: domain.BillStatusPaid, expectedRemaining: 0, expectedChange: 40},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				repos := MustNewRepositories(t)
+				s := MustNewServer(t, repos)
+				bill := MustPresaveBill(t, repos, tc.alreadyPaid)
+
+				reqBody := fmt.Sprintf(`{"tender": %q, "amount": %d, "tip": %d, "tendered": %d}`, tc.tender, tc.amount, tc.tip, tc.tendered)
+				r := httptest.NewRequest(http.MethodPost, "/bill/"+bill.ID.String()+"/payment", strings.NewReader(reqBody))
+				r.SetPathValue("id", bill.ID.String())
+				w := httptest.NewRecorder()
+
+				s.HandlePayBill(w, r)
+
+				body, statusCode := MustParseReponse[billResponse](t, w)
+
+				require.Equal(t, http.StatusOK, statusCode)
+				assert.Equal(t, tc.expectedStatus, body.Status)
+				assert.Equal(t, tc.expectedRemaining, body.RemainingAmount)
+				assert.Equal(t, tc.tip, body.Tips)
+				require.NotEmpty(t, body.Payments)
+				payment := body.Payments[len(body.Payments)-1]
+				assert.Equal(t, tc.tender, payment.Tender)
+				assert.Equal(t, tc.expectedChange, payment.Change)
+			})
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		tt := []struct {
+			testName           string
+			alreadyPaid        int
+			tender             domain.TenderType
+			amount             int
+			tip                int
+			tendered           int
+			unknownBill        bool
+			expectedStatusCode int
+		}{
+			{testName: "overpayment", alreadyPaid: 50, tender: domain.TenderCard, amount: 60, expectedStatusCode: http.StatusConflict},
+			{testName: "negative tip", alreadyPaid: 0, tender: domain.TenderCard, amount: 100, tip: -5, expectedStatusCode: http.StatusForbidden},
+			{testName: "already paid", alreadyPaid: 100, tender: domain.TenderCard, amount: 10, expectedStatusCode: http.StatusConflict},
+			{testName: "unknown bill", tender: domain.TenderCard, amount: 10, unknownBill: true, expectedStatusCode: http.StatusNotFound},
+			{testName: "unknown tender", tender: "cheque", amount: 10, expectedStatusCode: http.StatusForbidden},
+			{testName: "not enough cash tendered", tender: domain.TenderCash, amount: 100, tendered: 50, expectedStatusCode: http.StatusForbidden},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				repos := MustNewRepositories(t)
+				s := MustNewServer(t, repos)
+				billID := MustPresaveBill(t, repos, tc.alreadyPaid).ID
+				if tc.unknownBill {
+					billID = id.New()
+				}
+
+				reqBody := fmt.Sprintf(`{"tender": %q, "amount": %d, "tip": %d, "tendered": %d}`, tc.tender, tc.amount, tc.tip, tc.tendered)
+				r := httptest.NewRequest(http.MethodPost, "/bill/"+billID.String()+"/payment", strings.NewReader(reqBody))
+				r.SetPathValue("id", billID.String())
+				w := httptest.NewRecorder()
+
+				s.HandlePayBill(w, r)
+
+				require.Equal(t, tc.expectedStatusCode, w.Result().StatusCode)
+			})
+		}
+	})
+}
+
+func TestRefundPayment(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		tt := []struct {
+			testName          string
+			refundItem        bool
+			expectedStatus    domain.BillStatus
+			expectedRefunded  int
+			expectedRemaining int
+		}{
+			{testName: "full refund", expectedStatus: domain.BillStatusRefunded, expectedRefunded: 100},
+			{testName: "item refund", refundItem: true, expectedStatus: domain.BillStatusRefunded, expectedRefunded: 100},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				repos := MustNewRepositories(t)
+				s := MustNewServer(t, repos)
+				bill := MustPresaveBill(t, repos, 100)
+
+				preparationID := ""
+				if tc.refundItem {
+					preparationID = fmt.Sprintf(`, "preparation_id": %q`, bill.Items[0].PreparationID)
+				}
+				reqBody := fmt.Sprintf(`{"payment_id": %q, "reason": "cold dish"%s}`, bill.Payments[0].ID, preparationID)
+				r := httptest.NewRequest(http.MethodPost, "/bill/"+bill.ID.String()+"/refund", strings.NewReader(reqBody))
+				r.SetPathValue("id", bill.ID.String())
+				w := httptest.NewRecorder()
+
+				s.HandleRefundPayment(w, r)
+
+				body, statusCode := MustParseReponse[billResponse](t, w)
+
+				require.Equal(t, http.StatusOK, statusCode)
+				assert.Equal(t, tc.expectedStatus, body.Status)
+				assert.Equal(t, tc.expectedRefunded, body.Refunded)
+				assert.Equal(t, tc.expectedRemaining, body.RemainingAmount)
+				require.Len(t, body.Payments, 2)
+				assert.Equal(t, bill.Payments[0].ID, body.Payments[1].RefundOf)
+				assert.Equal(t, domain.TenderCard, body.Payments[1].Tender)
+			})
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		tt := []struct {
+			testName           string
+			alreadyPaid        int
+			unknownPayment     bool
+			reason             string
+			expectedStatusCode int
+		}{
+			{testName: "no reason", alreadyPaid: 100, expectedStatusCode: http.StatusForbidden},
+			{testName: "unpaid bill", alreadyPaid: 40, reason: "cold dish", expectedStatusCode: http.StatusConflict},
+			{testName: "unknown payment", alreadyPaid: 100, unknownPayment: true, reason: "cold dish", expectedStatusCode: http.StatusNotFound},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				repos := MustNewRepositories(t)
+				s := MustNewServer(t, repos)
+				bill := MustPresaveBill(t, repos, tc.alreadyPaid)
+				paymentID := bill.Payments[0].ID
+				if tc.unknownPayment {
+					paymentID = id.New()
+				}
+
+				reqBody := fmt.Sprintf(`{"payment_id": %q, "reason": %q}`, paymentID, tc.reason)
+				r := httptest.NewRequest(http.MethodPost, "/bill/"+bill.ID.String()+"/refund", strings.NewReader(reqBody))
+				r.SetPathValue("id", bill.ID.String())
+				w := httptest.NewRecorder()
+
+				s.HandleRefundPayment(w, r)
+
+				require.Equal(t, tc.expectedStatusCode, w.Result().StatusCode)
+			})
+		}
+	})
+}
+
+func TestGetTableSales(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		bill := MustPresaveBill(t, repos, 0)
+		_, err := s.BillService.PayBill(context.Background(), bill.ID, domain.TenderCard, bill.TotalAmount, 15, 0)
+		require.NoError(t, err)
+
+		r := httptest.NewRequest(http.MethodGet, "/table/"+bill.TableID.String()+"/sales", nil)
+		r.SetPathValue("id", bill.TableID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleGetTableSales(w, r)
+
+		body, statusCode := MustParseReponse[map[string]any](t, w)
+		require.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, float64(100), body["sales"])
+		assert.Equal(t, float64(0), body["discounts"])
+		assert.Equal(t, float64(0), body["taxes"])
+		assert.Equal(t, float64(0), body["service_charges"])
+		assert.Equal(t, float64(15), body["tips"])
+		assert.Equal(t, float64(15), body["gratuities"])
+		assert.Equal(t, float64(0), body["refunds"])
+		assert.Equal(t, float64(0), body["cash"])
+	})
+
+	t.Run("Unknown table", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		tableID := id.New()
+
+		r := httptest.NewRequest(http.MethodGet, "/table/"+tableID.String()+"/sales", nil)
+		r.SetPathValue("id", tableID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleGetTableSales(w, r)
+
+		require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestDiscountBill(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		bill := MustPresaveBill(t, repos, 0)
+
+		r := httptest.NewRequest(http.MethodPost, "/bill/"+bill.ID.String()+"/discount", strings.NewReader(`{"percent": 25, "reason": "late order"}`))
+		r.SetPathValue("id", bill.ID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleDiscountBill(w, r)
+
+		body, statusCode := MustParseReponse[billResponse](t, w)
+
+		require.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, []domain.Discount{{Name: "Manual discount", Amount: 25, Reason: "late order"}}, body.Discounts)
+		assert.Equal(t, 75, body.TotalAmount)
+		assert.Equal(t, 75, body.RemainingAmount)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		tt := []struct {
+			testName           string
+			alreadyPaid        int
+			reqBody            string
+			unknownBill        bool
+			expectedStatusCode int
+		}{
+			{testName: "no reason", reqBody: `{"amount": 10}`, expectedStatusCode: http.StatusForbidden},
+			{testName: "no discount", reqBody: `{"reason": "late order"}`, expectedStatusCode: http.StatusForbidden},
+			{testName: "bill with payments", alreadyPaid: 50, reqBody: `{"amount": 10, "reason": "late order"}`, expectedStatusCode: http.StatusConflict},
+			{testName: "unknown bill", unknownBill: true, reqBody: `{"amount": 10, "reason": "late order"}`, expectedStatusCode: http.StatusNotFound},
+			{testName: "invalid body", reqBody: `{"amount": "ten"}`, expectedStatusCode: http.StatusBadRequest},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				repos := MustNewRepositories(t)
+				s := MustNewServer(t, repos)
+				billID := MustPresaveBill(t, repos, tc.alreadyPaid).ID
+				if tc.unknownBill {
+					billID = id.New()
+				}
+
+				r := httptest.NewRequest(http.MethodPost, "/bill/"+billID.String()+"/discount", strings.NewReader(tc.reqBody))
+				r.SetPathValue("id", billID.String())
+				w := httptest.NewRecorder()
+
+				s.HandleDiscountBill(w, r)
+
+				require.Equal(t, tc.expectedStatusCode, w.Result().StatusCode)
+			})
+		}
+	})
+}
+
+func TestSplitBill(t *testing.T) {
+	split := func(s *domainHttp.Server, mode string, billID string, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/bill/"+billID+"/split/"+mode, strings.NewReader(body))
+		r.SetPathValue("id", billID)
+		w := httptest.NewRecorder()
+
+		switch mode {
+		case "items":
+			s.HandleSplitBillByItems(w, r)
+		case "seats":
+			s.HandleSplitBillBySeat(w, r)
+		default:
+			s.HandleSplitBillEqually(w, r)
+		}
+
+		return w
+	}
+
+	t.Run("Split equally and settle", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		bill := MustPresaveBill(t, repos, 0)
+
+		parts, statusCode := MustParseReponse[[]billResponse](t, split(s, "equal", bill.ID.String(), `{"shares": 3}`))
+
+		require.Equal(t, http.StatusCreated, statusCode)
+		require.Len(t, parts, 3)
+		assert.Equal(t, []int{34, 33, 33}, []int{parts[0].RemainingAmount, parts[1].RemainingAmount, parts[2].RemainingAmount})
+		for _, part := range parts {
+			assert.Equal(t, bill.ID, part.ParentID)
+			_, err := s.BillService.PayBill(context.Background(), part.ID, domain.TenderCard, part.TotalAmount, 0, 0)
+			require.NoError(t, err)
+		}
+
+		r := httptest.NewRequest(http.MethodGet, "/table/"+bill.TableID.String()+"/settlement", nil)
+		r.SetPathValue("id", bill.TableID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleGetTableSettlement(w, r)
+
+		body, statusCode := MustParseReponse[map[string]any](t, w)
+		require.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, true, body["settled"])
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		tt := []struct {
+			testName           string
+			mode               string
+			billID             string
+			body               string
+			expectedStatusCode int
+		}{
+			{testName: "single group", mode: "items", body: `{"groups": [[]]}`, expectedStatusCode: http.StatusForbidden},
+			{testName: "single seat", mode: "seats", expectedStatusCode: http.StatusForbidden},
+			{testName: "single share", mode: "equal", body: `{"shares": 1}`, expectedStatusCode: http.StatusForbidden},
+			{testName: "bill not found", mode: "equal", billID: id.New().String(), body: `{"shares": 2}`, expectedStatusCode: http.StatusNotFound},
+			{testName: "invalid bill id", mode: "equal", billID: "invalid", body: `{"shares": 2}`, expectedStatusCode: http.StatusBadRequest},
+			{testName: "invalid body", mode: "equal", body: `{"shares": "two"}`, expectedStatusCode: http.StatusBadRequest},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				repos := MustNewRepositories(t)
+				s := MustNewServer(t, repos)
+				bill := MustPresaveBill(t, repos, 0)
+				if tc.billID == "" {
+					tc.billID = bill.ID.String()
+				}
+
+				w := split(s, tc.mode, tc.billID, tc.body)
+
+				require.Equal(t, tc.expectedStatusCode, w.Result().StatusCode)
+			})
+		}
+	})
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+func (s *Server) registerDiningTableRoutes(r *router) {
+	diningTableRouter := r.group("/dining-table")
+
+	diningTableRouter.HandleFunc("POST /", s.HandleAddDiningTable)
+	diningTableRouter.HandleFunc("GET /", s.HandleGetDiningTables)
+	diningTableRouter.HandleFunc("GET /{id}", s.HandleGetDiningTable)
+}
+
+func (s *Server) HandleAddDiningTable(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		Name     string `json:"name"`
+		Capacity int    `json:"capacity"`
+		Zone     string `json:"zone"`
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Name == "" {
+		s.logger.Errorf("empty name\n")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if req.Capacity <= 0 {
+		s.logger.Errorf("non positive capacity\n")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	table, err := s.DiningTableService.CreateDiningTable(r.Context(), req.Name, req.Capacity, req.Zone)
+	if err != nil {
+		s.logger.Errorf("error creating dining table: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusCreated, table)
+}
+
+func (s *Server) HandleGetDiningTables(w http.ResponseWriter, r *http.Request) {
+	tables, err := s.DiningTableService.FindAllDiningTables(r.Context())
+	if err != nil {
+		s.logger.Errorf("error finding dining tables: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, tables)
+}
+
+func (s *Server) HandleGetDiningTable(w http.ResponseWriter, r *http.Request) {
+	tableID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing dining table id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	table, err := s.DiningTableService.FindDiningTable(r.Context(), tableID)
+	if err != nil {
+		s.logger.Errorf("error finding dining table: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, table)
+}
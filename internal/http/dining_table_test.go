@@ -0,0 +1,85 @@
+package http_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDiningTable(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+
+	tt := []struct {
+		testName string
+		name     string
+		capacity int
+		status   int
+	}{
+		{testName: "valid table", name: "T1", capacity: 4, status: http.StatusCreated},
+		{testName: "duplicated name", name: "T1", capacity: 2, status: http.StatusConflict},
+		{testName: "empty name", name: "", capacity: 4, status: http.StatusBadRequest},
+		{testName: "zero capacity", name: "T2", capacity: 0, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			body := fmt.Sprintf(`{"name":"%s","capacity":%d,"zone":"terrace"}`, tc.name, tc.capacity)
+			r := httptest.NewRequest(http.MethodPost, "/dining-table", strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			s.HandleAddDiningTable(w, r)
+
+			require.Equal(t, tc.status, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestGetDiningTables(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	diningTable := MustPresaveDiningTable(t, repos, 4)
+
+	t.Run("all tables", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/dining-table", nil)
+		w := httptest.NewRecorder()
+
+		s.HandleGetDiningTables(w, r)
+
+		body, statusCode := MustParseReponse[[]domain.DiningTable](t, w)
+
+		require.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, []domain.DiningTable{diningTable}, body)
+	})
+
+	t.Run("one table", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/dining-table/"+diningTable.ID.String(), nil)
+		r.SetPathValue("id", diningTable.ID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleGetDiningTable(w, r)
+
+		body, statusCode := MustParseReponse[domain.DiningTable](t, w)
+
+		require.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, diningTable, body)
+	})
+
+	t.Run("table not found", func(t *testing.T) {
+		tableID := id.New().String()
+		r := httptest.NewRequest(http.MethodGet, "/dining-table/"+tableID, nil)
+		r.SetPathValue("id", tableID)
+		w := httptest.NewRecorder()
+
+		s.HandleGetDiningTable(w, r)
+
+		require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
@@ -0,0 +1,345 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// eventHistorySize is the number of events kept for Last-Event-ID resumption.
+	eventHistorySize = 256
+	// eventBufferSize is the number of events a subscriber may lag behind before being dropped.
+	eventBufferSize = 64
+	// eventKeepAlive is the interval between keep-alive comments on idle streams.
+	eventKeepAlive = 15 * time.Second
+)
+
+type streamEventType string
+
+const (
+	streamEventTableOpened        streamEventType = "table_opened"
+	streamEventTableClosed        streamEventType = "table_closed"
+	streamEventOrderTaken         streamEventType = "order_taken"
+	streamEventPreparationUpdated streamEventType = "preparation_updated"
+	streamEventOrderReady         streamEventType = "order_ready"
+	streamEventPartySuggested     streamEventType = "party_suggested"
+)
+
+type streamEvent struct {
+	id         uint64
+	kind       streamEventType
+	tableID    id.ID
+	statuses   []domain.PreparationStatus
+	stationIDs []id.ID
+	data       []byte
+}
+
+type streamEventData struct {
+	TableID     id.ID               `json:"table_id"`
+	Order       *domain.Order       `json:"order,omitempty"`
+	Preparation *domain.Preparation `json:"preparation,omitempty"`
+	// Party is the waiting party suggested for the dining table freed by the closed table.
+	Party *domain.WaitlistEntry `json:"party,omitempty"`
+}
+
+type eventFilter struct {
+	tableID   id.ID
+	stationID id.ID
+	statuses  []domain.PreparationStatus
+}
+
+// match reports whether the event concerns the filtered table, at least one preparation routed to the filtered station
+// and, when statuses are given, at least one preparation in one of those statuses.
+func (f eventFilter) match(e streamEvent) bool {
+	if f.tableID != id.NilID() && f.tableID != e.tableID {
+		return false
+	}
+
+	if f.stationID != id.NilID() && !slices.Contains(e.stationIDs, f.stationID) {
+		return false
+	}
+
+	if len(f.statuses) == 0 {
+		return true
+	}
+
+	for _, status := range e.statuses {
+		if slices.Contains(f.statuses, status) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type eventSubscriber struct {
+	filter eventFilter
+	events chan streamEvent
+}
+
+// EventStream fans out table changes to the clients of the events endpoint.
+// It subscribes to the domain events with HandleEvent and keeps a bounded history of events
+// so that reconnecting clients can resume from their last received event.
+type EventStream struct {
+	mu          sync.Mutex
+	lastID      uint64
+	history     []streamEvent
+	subscribers map[*eventSubscriber]struct{}
+	closed      bool
+}
+
+func NewEventStream() *EventStream {
+	return &EventStream{subscribers: make(map[*eventSubscriber]struct{})}
+}
+
+// HandleEvent is the event subscriber feeding the stream with table and preparation changes.
+func (s *EventStream) HandleEvent(ctx context.Context, event domain.Event) {
+	switch e := event.(type) {
+	case domain.TableOpened:
+		s.push(streamEventTableOpened, streamEventData{TableID: e.Table.ID})
+	case domain.TableClosed:
+		s.push(streamEventTableClosed, streamEventData{TableID: e.TableID})
+	case domain.OrderTaken:
+		s.push(streamEventOrderTaken, streamEventData{TableID: e.TableID, Order: &e.Order})
+	case domain.OrderReady:
+		s.push(streamEventOrderReady, streamEventData{TableID: e.TableID, Order: &e.Order})
+	case domain.PartySuggested:
+		s.push(streamEventPartySuggested, streamEventData{TableID: e.TableID, Party: &e.Entry})
+	case domain.OrderAborted:
+		for _, prep := range e.Order.Preparations {
+			s.push(streamEventPreparationUpdated, streamEventData{TableID: e.TableID, Preparation: &prep})
+		}
+	case domain.CourseFired:
+		for _, prep := range e.Preparations {
+			s.push(streamEventPreparationUpdated, streamEventData{TableID: e.TableID, Preparation: &prep})
+		}
+	case domain.PreparationStarted:
+		s.push(streamEventPreparationUpdated, streamEventData{TableID: e.TableID, Preparation: &e.Preparation})
+	case domain.PreparationFinished:
+		s.push(streamEventPreparationUpdated, streamEventData{TableID: e.TableID, Preparation: &e.Preparation})
+	case domain.PreparationServed:
+		s.push(streamEventPreparationUpdated, streamEventData{TableID: e.TableID, Preparation: &e.Preparation})
+	case domain.PreparationAborted:
+		s.push(streamEventPreparationUpdated, streamEventData{TableID: e.TableID, Preparation: &e.Preparation})
+	}
+}
+
+func (s *EventStream) push(kind streamEventType, payload streamEventData) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	e := streamEvent{kind: kind, tableID: payload.TableID, data: data}
+	if payload.Order != nil {
+		for _, prep := range payload.Order.Preparations {
+			e.statuses = append(e.statuses, prep.Status)
+			e.stationIDs = append(e.stationIDs, prep.StationID)
+		}
+	}
+	if payload.Preparation != nil {
+		e.statuses = append(e.statuses, payload.Preparation.Status)
+		e.stationIDs = append(e.stationIDs, payload.Preparation.StationID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	s.lastID++
+	e.id = s.lastID
+
+	s.history = append(s.history, e)
+	if len(s.history) > eventHistorySize {
+		s.history = slices.Delete(s.history, 0, len(s.history)-eventHistorySize)
+	}
+
+	for sub := range s.subscribers {
+		if !sub.filter.match(e) {
+			continue
+		}
+
+		select {
+		case sub.events <- e:
+		default:
+			// The client is too slow, it will resume from its last event on reconnection.
+			delete(s.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// subscribe registers a new subscriber and returns the events following lastEventID
+// that are still in the history. A nil subscriber is returned once the stream is closed.
+func (s *EventStream) subscribe(filter eventFilter, lastEventID uint64, resume bool) (*eventSubscriber, []streamEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, nil
+	}
+
+	var backlog []streamEvent
+	if resume {
+		// An ID ahead of the stream means the server restarted: replay everything kept.
+		if lastEventID > s.lastID {
+			lastEventID = 0
+		}
+
+		for _, e := range s.history {
+			if e.id > lastEventID && filter.match(e) {
+				backlog = append(backlog, e)
+			}
+		}
+	}
+
+	sub := &eventSubscriber{filter: filter, events: make(chan streamEvent, eventBufferSize)}
+	s.subscribers[sub] = struct{}{}
+
+	return sub, backlog
+}
+
+func (s *EventStream) unsubscribe(sub *eventSubscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscribers[sub]; !ok {
+		return
+	}
+
+	delete(s.subscribers, sub)
+	close(sub.events)
+}
+
+// Close ends all the open streams and stops accepting new subscribers.
+func (s *EventStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for sub := range s.subscribers {
+		delete(s.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+func (s *Server) registerEventRoutes(r *router) {
+	r.HandleFunc("GET /events", s.HandleEvents)
+}
+
+func (s *Server) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseEventFilter(r)
+	if err != nil {
+		s.logger.Errorf("%s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var lastEventID uint64
+	header := r.Header.Get("Last-Event-ID")
+	resume := header != ""
+	if resume {
+		lastEventID, err = strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			err = fmt.Errorf("invalid Last-Event-ID: %q", header)
+			s.logger.Errorf("%s\n", err)
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	sub, backlog := s.events.subscribe(filter, lastEventID, resume)
+	if sub == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("server is shutting down"))
+		return
+	}
+	defer s.events.unsubscribe(sub)
+
+	// The stream outlives the server write timeout.
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		s.logger.Errorf("error clearing write deadline: %s\n", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for _, e := range backlog {
+		writeStreamEvent(w, e)
+	}
+	if err := rc.Flush(); err != nil {
+		s.logger.Errorf("error flushing events: %s\n", err)
+		return
+	}
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-sub.events:
+			if !ok {
+				return
+			}
+			writeStreamEvent(w, e)
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func parseEventFilter(r *http.Request) (eventFilter, error) {
+	var filter eventFilter
+
+	query := r.URL.Query()
+	if value := query.Get("table_id"); value != "" {
+		tableID, err := id.Parse(value)
+		if err != nil {
+			return eventFilter{}, fmt.Errorf("invalid table_id: %q", value)
+		}
+		filter.tableID = tableID
+	}
+
+	if value := query.Get("station_id"); value != "" {
+		stationID, err := id.Parse(value)
+		if err != nil {
+			return eventFilter{}, fmt.Errorf("invalid station_id: %q", value)
+		}
+		filter.stationID = stationID
+	}
+
+	for _, value := range query["status"] {
+		status := domain.PreparationStatus(value)
+		if !status.IsValid() {
+			return eventFilter{}, fmt.Errorf("invalid preparation status: %q", value)
+		}
+		filter.statuses = append(filter.statuses, status)
+	}
+
+	return filter, nil
+}
+
+func writeStreamEvent(w http.ResponseWriter, e streamEvent) {
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.id, e.kind, e.data)
+}
@@ -0,0 +1,294 @@
+package http_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"order_manager/internal/domain"
+	domainHttp "order_manager/internal/http"
+	"order_manager/internal/id"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type streamMessage struct {
+	ID    string
+	Event string
+	Data  string
+}
+
+type streamData struct {
+	TableID     id.ID                 `json:"table_id"`
+	Order       *domain.Order         `json:"order"`
+	Preparation *domain.Preparation   `json:"preparation"`
+	Party       *domain.WaitlistEntry `json:"party"`
+}
+
+func MustOpenStream(t *testing.T, s *domainHttp.Server, query string, lastEventID string) *bufio.Reader {
+	t.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(s.HandleEvents))
+	t.Cleanup(ts.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/events"+query, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		r.Header.Set("Last-Event-ID", lastEventID)
+	}
+
+	res, err := http.DefaultClient.Do(r)
+	require.NoError(t, err)
+	t.Cleanup(func() { res.Body.Close() })
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	return bufio.NewReader(res.Body)
+}
+
+func MustReadMessage(t *testing.T, reader *bufio.Reader) streamMessage {
+	t.Helper()
+
+	var msg streamMessage
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && msg.Event != "":
+			return msg
+		case strings.HasPrefix(line, "id: "):
+			msg.ID = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			msg.Event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			msg.Data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func MustParseData(t *testing.T, msg streamMessage) streamData {
+	t.Helper()
+
+	var data streamData
+	require.NoError(t, json.Unmarshal([]byte(msg.Data), &data))
+
+	return data
+}
+
+func TestEventsHandler(t *testing.T) {
+	t.Run("Stream table changes", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		ctx := context.Background()
+		diningTable := MustPresaveDiningTable(t, repos, 4)
+		otherDiningTable := MustPresaveDiningTable(t, repos, 4)
+		item, err := s.MenuService.CreateMenuItem(ctx, "item", 100, "")
+		require.NoError(t, err)
+
+		table, err := s.TableService.OpenTable(ctx, diningTable.ID, 2)
+		require.NoError(t, err)
+
+		stream := MustOpenStream(t, s, "?table_id="+table.ID.String(), "")
+
+		otherTable, err := s.TableService.OpenTable(ctx, otherDiningTable.ID, 2)
+		require.NoError(t, err)
+		_, err = s.TableService.TakeOrder(ctx, otherTable.ID, []domain.OrderItem{{MenuItem: item}})
+		require.NoError(t, err)
+
+		order, err := s.TableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: item}})
+		require.NoError(t, err)
+		prepID := order.Preparations[0].ID
+		require.NoError(t, s.TableService.StartPreparation(ctx, prepID))
+		require.NoError(t, s.TableService.AbortOrder(ctx, order.ID))
+		require.NoError(t, s.TableService.CloseTable(ctx, table.ID))
+
+		msg := MustReadMessage(t, stream)
+		assert.Equal(t, "order_taken", msg.Event)
+		assert.Equal(t, order.ID, MustParseData(t, msg).Order.ID)
+
+		msg = MustReadMessage(t, stream)
+		assert.Equal(t, "preparation_updated", msg.Event)
+		data := MustParseData(t, msg)
+		assert.Equal(t, table.ID, data.TableID)
+		assert.Equal(t, prepID, data.Preparation.ID)
+		assert.Equal(t, domain.PreparationStatusInProgress, data.Preparation.Status)
+
+		msg = MustReadMessage(t, stream)
+		assert.Equal(t, "preparation_updated", msg.Event)
+		assert.Equal(t, domain.PreparationStatusAborted, MustParseData(t, msg).Preparation.Status)
+
+		msg = MustReadMessage(t, stream)
+		assert.Equal(t, "table_closed", msg.Event)
+	})
+
+	t.Run("Filter by preparation status", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		ctx := context.Background()
+		diningTable := MustPresaveDiningTable(t, repos, 4)
+		item, err := s.MenuService.CreateMenuItem(ctx, "item", 100, "")
+		require.NoError(t, err)
+
+		stream := MustOpenStream(t, s, "?status=ready", "")
+
+		table, err := s.TableService.OpenTable(ctx, diningTable.ID, 2)
+		require.NoError(t, err)
+		order, err := s.TableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: item}})
+		require.NoError(t, err)
+		prepID := order.Preparations[0].ID
+		require.NoError(t, s.TableService.StartPreparation(ctx, prepID))
+		require.NoError(t, s.TableService.FinishPreparation(ctx, prepID))
+
+		msg := MustReadMessage(t, stream)
+		assert.Equal(t, "preparation_updated", msg.Event)
+		assert.Equal(t, domain.PreparationStatusReady, MustParseData(t, msg).Preparation.Status)
+	})
+
+	t.Run("Filter by station", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		ctx := context.Background()
+		diningTable := MustPresaveDiningTable(t, repos, 4)
+		burger, err := s.MenuService.CreateMenuItem(ctx, "burger", 1200, "")
+		require.NoError(t, err)
+		beer, err := s.MenuService.CreateMenuItem(ctx, "beer", 500, "")
+		require.NoError(t, err)
+		grill, err := s.StationService.CreateStation(ctx, "grill")
+		require.NoError(t, err)
+		_, err = s.StationService.RouteToStation(ctx, grill.ID, []id.ID{burger.ID}, []id.ID{})
+		require.NoError(t, err)
+
+		stream := MustOpenStream(t, s, "?station_id="+grill.ID.String(), "")
+
+		table, err := s.TableService.OpenTable(ctx, diningTable.ID, 2)
+		require.NoError(t, err)
+		drinks, err := s.TableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: beer}})
+		require.NoError(t, err)
+		require.NoError(t, s.TableService.StartPreparation(ctx, drinks.Preparations[0].ID))
+		order, err := s.TableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: burger}})
+		require.NoError(t, err)
+		prepID := order.Preparations[0].ID
+		require.NoError(t, s.TableService.StartPreparation(ctx, prepID))
+		require.NoError(t, s.TableService.FinishPreparation(ctx, prepID))
+
+		msg := MustReadMessage(t, stream)
+		assert.Equal(t, "order_taken", msg.Event)
+		assert.Equal(t, order.ID, MustParseData(t, msg).Order.ID)
+
+		msg = MustReadMessage(t, stream)
+		assert.Equal(t, "preparation_updated", msg.Event)
+		assert.Equal(t, domain.PreparationStatusInProgress, MustParseData(t, msg).Preparation.Status)
+
+		msg = MustReadMessage(t, stream)
+		assert.Equal(t, "preparation_updated", msg.Event)
+		assert.Equal(t, domain.PreparationStatusReady, MustParseData(t, msg).Preparation.Status)
+
+		msg = MustReadMessage(t, stream)
+		assert.Equal(t, "order_ready", msg.Event)
+		assert.Equal(t, order.ID, MustParseData(t, msg).Order.ID)
+	})
+
+	t.Run("Resume from Last-Event-ID", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		ctx := context.Background()
+		diningTable := MustPresaveDiningTable(t, repos, 4)
+
+		table, err := s.TableService.OpenTable(ctx, diningTable.ID, 2)
+		require.NoError(t, err)
+		require.NoError(t, s.TableService.CloseTable(ctx, table.ID))
+
+		stream := MustOpenStream(t, s, "", "1")
+
+		msg := MustReadMessage(t, stream)
+		assert.Equal(t, "2", msg.ID)
+		assert.Equal(t, "table_closed", msg.Event)
+	})
+
+	t.Run("Suggest waiting party", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		ctx := context.Background()
+		diningTable := MustPresaveDiningTable(t, repos, 4)
+
+		table, err := s.TableService.OpenTable(ctx, diningTable.ID, 2)
+		require.NoError(t, err)
+		entry, err := s.WaitlistService.JoinWaitlist(ctx, "Smith", "555-0100", 3)
+		require.NoError(t, err)
+
+		stream := MustOpenStream(t, s, "?table_id="+table.ID.String(), "")
+
+		require.NoError(t, s.TableService.CloseTable(ctx, table.ID))
+
+		msg := MustReadMessage(t, stream)
+		assert.Equal(t, "table_closed", msg.Event)
+
+		msg = MustReadMessage(t, stream)
+		assert.Equal(t, "party_suggested", msg.Event)
+		data := MustParseData(t, msg)
+		assert.Equal(t, table.ID, data.TableID)
+		require.NotNil(t, data.Party)
+		assert.Equal(t, entry.ID, data.Party.ID)
+		assert.Equal(t, 3, data.Party.PartySize)
+	})
+
+	t.Run("Invalid filters", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+
+		tt := []struct {
+			testName    string
+			query       string
+			lastEventID string
+		}{
+			{testName: "invalid table id", query: "?table_id=invalid"},
+			{testName: "invalid station id", query: "?station_id=invalid"},
+			{testName: "invalid status", query: "?status=invalid"},
+			{testName: "invalid last event id", lastEventID: "invalid"},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				r := httptest.NewRequest(http.MethodGet, "/events"+tc.query, nil)
+				if tc.lastEventID != "" {
+					r.Header.Set("Last-Event-ID", tc.lastEventID)
+				}
+				w := httptest.NewRecorder()
+
+				s.HandleEvents(w, r)
+
+				require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+			})
+		}
+	})
+
+	t.Run("Close ends the streams", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		events := domainHttp.NewEventStream()
+		tableService := domain.NewTableService(repos.Table, repos.DiningTable, nil)
+		s := domainHttp.NewServer(domainHttp.Config{Addr: ":8080"}, nopLogger{}, domainHttp.Services{TableService: tableService}, events)
+
+		stream := MustOpenStream(t, s, "", "")
+
+		events.Close()
+
+		_, err := stream.ReadString('\n')
+		assert.ErrorIs(t, err, io.EOF)
+
+		r := httptest.NewRequest(http.MethodGet, "/events", nil)
+		w := httptest.NewRecorder()
+		s.HandleEvents(w, r)
+		assert.Equal(t, http.StatusServiceUnavailable, w.Result().StatusCode)
+	})
+}
@@ -0,0 +1,255 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+)
+
+func (s *Server) registerInventoryRoutes(r *router) {
+	inventoryRouter := r.group("/inventory")
+
+	inventoryRouter.HandleFunc("POST /ingredient", s.HandleAddIngredient)
+	inventoryRouter.HandleFunc("GET /ingredient", s.HandleGetIngredients)
+	inventoryRouter.HandleFunc("GET /ingredient/{id}", s.HandleGetIngredient)
+	inventoryRouter.HandleFunc("POST /ingredient/{id}/count", s.HandleCountStock)
+	inventoryRouter.HandleFunc("POST /ingredient/{id}/delivery", s.HandleReceiveDelivery)
+	inventoryRouter.HandleFunc("POST /ingredient/{id}/adjustment", s.HandleAdjustStock)
+	inventoryRouter.HandleFunc("GET /ingredient/{id}/movements", s.HandleGetStockMovements)
+	inventoryRouter.HandleFunc("PUT /recipe/{menu_item_id}", s.HandleSetRecipe)
+	inventoryRouter.HandleFunc("GET /recipe/{menu_item_id}", s.HandleGetRecipe)
+}
+
+// ingredientResponse tells whether the stock of the ingredient is low.
+type ingredientResponse struct {
+	domain.Ingredient
+	Low bool
+}
+
+func newIngredientResponse(ingredient domain.Ingredient) ingredientResponse {
+	return ingredientResponse{Ingredient: ingredient, Low: ingredient.IsLow()}
+}
+
+func (s *Server) HandleAddIngredient(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		Name              string `json:"name"`
+		Unit              string `json:"unit"`
+		Stock             int    `json:"stock"`
+		LowStockThreshold *int   `json:"low_stock_threshold"`
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ingredient, err := s.InventoryService.CreateIngredient(r.Context(), req.Name, domain.Unit(req.Unit), req.Stock, req.LowStockThreshold)
+	if err != nil {
+		s.logger.Errorf("error creating ingredient: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusCreated, newIngredientResponse(ingredient))
+}
+
+func (s *Server) HandleGetIngredients(w http.ResponseWriter, r *http.Request) {
+	ingredients, err := s.InventoryService.FindAllIngredients(r.Context())
+	if err != nil {
+		s.logger.Errorf("error finding ingredients: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	res := make([]ingredientResponse, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		res = append(res, newIngredientResponse(ingredient))
+	}
+
+	writeJSONBody(w, http.StatusOK, res)
+}
+
+func (s *Server) HandleGetIngredient(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing ingredient id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ingredient, err := s.InventoryService.FindIngredient(r.Context(), ingredientID)
+	if err != nil {
+		s.logger.Errorf("error finding ingredient: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newIngredientResponse(ingredient))
+}
+
+func (s *Server) HandleCountStock(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		Stock int `json:"stock"`
+	}
+
+	ingredientID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing ingredient id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ingredient, err := s.InventoryService.CountStock(r.Context(), ingredientID, req.Stock)
+	if err != nil {
+		s.logger.Errorf("error counting stock: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newIngredientResponse(ingredient))
+}
+
+func (s *Server) HandleReceiveDelivery(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		Quantity int `json:"quantity"`
+	}
+
+	ingredientID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing ingredient id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ingredient, err := s.InventoryService.ReceiveDelivery(r.Context(), ingredientID, req.Quantity)
+	if err != nil {
+		s.logger.Errorf("error receiving delivery: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newIngredientResponse(ingredient))
+}
+
+func (s *Server) HandleAdjustStock(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		Quantity int    `json:"quantity"`
+		Reason   string `json:"reason"`
+	}
+
+	ingredientID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing ingredient id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ingredient, err := s.InventoryService.AdjustStock(r.Context(), ingredientID, req.Quantity, req.Reason)
+	if err != nil {
+		s.logger.Errorf("error adjusting stock: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newIngredientResponse(ingredient))
+}
+
+// HandleGetStockMovements returns the movements of the stock of an ingredient, oldest first.
+func (s *Server) HandleGetStockMovements(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing ingredient id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	movements, err := s.InventoryService.FindStockMovements(r.Context(), ingredientID)
+	if err != nil {
+		s.logger.Errorf("error finding stock movements: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, movements)
+}
+
+func (s *Server) HandleSetRecipe(w http.ResponseWriter, r *http.Request) {
+	type line struct {
+		IngredientID id.ID `json:"ingredient_id"`
+		Quantity     int   `json:"quantity"`
+	}
+
+	type reqBody struct {
+		Lines []line `json:"lines"`
+	}
+
+	menuItemID, err := parsePathID(r, "menu_item_id")
+	if err != nil {
+		s.logger.Errorf("error parsing menu item id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	lines := make([]domain.RecipeLine, 0, len(req.Lines))
+	for _, l := range req.Lines {
+		lines = append(lines, domain.RecipeLine{IngredientID: l.IngredientID, Quantity: l.Quantity})
+	}
+
+	recipe, err := s.InventoryService.SetRecipe(r.Context(), menuItemID, lines)
+	if err != nil {
+		s.logger.Errorf("error setting recipe: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, recipe)
+}
+
+func (s *Server) HandleGetRecipe(w http.ResponseWriter, r *http.Request) {
+	menuItemID, err := parsePathID(r, "menu_item_id")
+	if err != nil {
+		s.logger.Errorf("error parsing menu item id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	recipe, err := s.InventoryService.FindRecipe(r.Context(), menuItemID)
+	if err != nil {
+		s.logger.Errorf("error finding recipe: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, recipe)
+}
@@ -0,0 +1,194 @@
+package http_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ingredientResponse struct {
+	domain.Ingredient
+	Low bool
+}
+
+func TestAddAndGetIngredients(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+
+	tt := []struct {
+		testName string
+		body     string
+		status   int
+	}{
+		{testName: "valid ingredient", body: `{"name":"flour","unit":"g","stock":5000,"low_stock_threshold":500}`, status: http.StatusCreated},
+		{testName: "without threshold", body: `{"name":"eggs","unit":"piece","stock":60}`, status: http.StatusCreated},
+		{testName: "unknown unit", body: `{"name":"flour","unit":"kg","stock":5}`, status: http.StatusForbidden},
+		{testName: "negative stock", body: `{"name":"flour","unit":"g","stock":-5}`, status: http.StatusForbidden},
+		{testName: "malformed body", body: `{"name":`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/inventory/ingredient", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+
+			s.HandleAddIngredient(w, r)
+
+			require.Equal(t, tc.status, w.Result().StatusCode)
+		})
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/inventory/ingredient", nil)
+	w := httptest.NewRecorder()
+
+	s.HandleGetIngredients(w, r)
+
+	ingredients, statusCode := MustParseReponse[[]ingredientResponse](t, w)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, ingredients, 2)
+	assert.Equal(t, "eggs", ingredients[0].Name)
+	assert.Nil(t, ingredients[0].LowStockThreshold)
+	assert.Equal(t, "flour", ingredients[1].Name)
+	assert.Equal(t, 5000, ingredients[1].Stock)
+}
+
+func TestStockMovements(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+
+	ingredient, err := s.InventoryService.CreateIngredient(context.Background(), "milk", domain.UnitMillilitre, 1000, nil)
+	require.NoError(t, err)
+
+	move := func(ingredientID id.ID, movement string, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/inventory/ingredient/"+ingredientID.String()+"/"+movement, strings.NewReader(body))
+		r.SetPathValue("id", ingredientID.String())
+		w := httptest.NewRecorder()
+
+		switch movement {
+		case "count":
+			s.HandleCountStock(w, r)
+		case "delivery":
+			s.HandleReceiveDelivery(w, r)
+		case "adjustment":
+			s.HandleAdjustStock(w, r)
+		}
+
+		return w
+	}
+
+	res, statusCode := MustParseReponse[ingredientResponse](t, move(ingredient.ID, "delivery", `{"quantity": 500}`))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 1500, res.Stock)
+
+	res, statusCode = MustParseReponse[ingredientResponse](t, move(ingredient.ID, "adjustment", `{"quantity": -100, "reason": "spilled"}`))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 1400, res.Stock)
+
+	res, statusCode = MustParseReponse[ingredientResponse](t, move(ingredient.ID, "count", `{"stock": 1350}`))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 1350, res.Stock)
+
+	require.Equal(t, http.StatusForbidden, move(ingredient.ID, "adjustment", `{"quantity": -100}`).Result().StatusCode)
+	require.Equal(t, http.StatusForbidden, move(ingredient.ID, "delivery", `{"quantity": 0}`).Result().StatusCode)
+	require.Equal(t, http.StatusNotFound, move(id.New(), "count", `{"stock": 10}`).Result().StatusCode)
+	require.Equal(t, http.StatusBadRequest, move(ingredient.ID, "count", `{"stock": "ten"}`).Result().StatusCode)
+
+	r := httptest.NewRequest(http.MethodGet, "/inventory/ingredient/"+ingredient.ID.String(), nil)
+	r.SetPathValue("id", ingredient.ID.String())
+	w := httptest.NewRecorder()
+
+	s.HandleGetIngredient(w, r)
+
+	res, statusCode = MustParseReponse[ingredientResponse](t, w)
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 1350, res.Stock)
+
+	getMovements := func(ingredientID id.ID) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/inventory/ingredient/"+ingredientID.String()+"/movements", nil)
+		r.SetPathValue("id", ingredientID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleGetStockMovements(w, r)
+
+		return w
+	}
+
+	movements, statusCode := MustParseReponse[[]domain.StockMovement](t, getMovements(ingredient.ID))
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, movements, 3)
+	assert.Equal(t, domain.StockMovementDelivery, movements[0].Kind)
+	assert.Equal(t, "spilled", movements[1].Reason)
+	assert.Equal(t, -50, movements[2].Delta, "count not recorded as the difference with the stock")
+
+	require.Equal(t, http.StatusNotFound, getMovements(id.New()).Result().StatusCode)
+}
+
+func TestRecipeDeductsStockOnOrders(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	ctx := context.Background()
+
+	item, err := s.MenuService.CreateMenuItem(ctx, "omelette", 900, "")
+	require.NoError(t, err)
+	threshold := 4
+	eggs, err := s.InventoryService.CreateIngredient(ctx, "eggs", domain.UnitPiece, 7, &threshold)
+	require.NoError(t, err)
+
+	setRecipe := func(menuItemID id.ID, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPut, "/inventory/recipe/"+menuItemID.String(), strings.NewReader(body))
+		r.SetPathValue("menu_item_id", menuItemID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleSetRecipe(w, r)
+
+		return w
+	}
+
+	body := fmt.Sprintf(`{"lines": [{"ingredient_id": "%s", "quantity": 3}]}`, eggs.ID)
+	recipe, statusCode := MustParseReponse[domain.Recipe](t, setRecipe(item.ID, body))
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, recipe.Lines, 1)
+
+	require.Equal(t, http.StatusNotFound, setRecipe(id.New(), body).Result().StatusCode)
+	require.Equal(t, http.StatusForbidden, setRecipe(item.ID, fmt.Sprintf(`{"lines": [{"ingredient_id": "%s", "quantity": 0}]}`, eggs.ID)).Result().StatusCode)
+
+	r := httptest.NewRequest(http.MethodGet, "/inventory/recipe/"+item.ID.String(), nil)
+	r.SetPathValue("menu_item_id", item.ID.String())
+	w := httptest.NewRecorder()
+
+	s.HandleGetRecipe(w, r)
+
+	got, statusCode := MustParseReponse[domain.Recipe](t, w)
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, recipe, got)
+
+	table := domain.Table{ID: id.New(), Status: domain.TableStatusOpened, Orders: []domain.Order{}}
+	MustPresaveTables(t, repos, []domain.Table{table})
+
+	takeOrder := func() int {
+		reqBody := fmt.Sprintf(`{"table_id": "%s", "menu_item_ids": ["%s"]}`, table.ID, item.ID)
+		r := httptest.NewRequest(http.MethodPost, "/table/order", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		s.HandleTakeOrder(w, r)
+
+		return w.Result().StatusCode
+	}
+
+	// The order leaves 4 eggs, at the threshold, which makes the omelette unavailable.
+	require.Equal(t, http.StatusOK, takeOrder())
+	require.Equal(t, http.StatusConflict, takeOrder())
+
+	ingredient, err := s.InventoryService.FindIngredient(ctx, eggs.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 4, ingredient.Stock)
+	assert.True(t, ingredient.IsLow())
+}
@@ -2,7 +2,11 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
 )
 
 func (s *Server) registerMenuRoutes(r *router) {
@@ -10,12 +14,41 @@ func (s *Server) registerMenuRoutes(r *router) {
 
 	menuRouter.HandleFunc("POST /item", s.HandleAddMenuItem)
 	menuRouter.HandleFunc("GET /item", s.HandleGetMenuItems)
+	menuRouter.HandleFunc("POST /item/{id}/available", s.HandleMarkMenuItemAvailable)
+	menuRouter.HandleFunc("POST /item/{id}/unavailable", s.HandleMarkMenuItemUnavailable)
+	menuRouter.HandleFunc("POST /item/{id}/modifier-group", s.HandleAddModifierGroup)
+	menuRouter.HandleFunc("DELETE /item/{id}/modifier-group/{group_id}", s.HandleRemoveModifierGroup)
+	menuRouter.HandleFunc("POST /category", s.HandleAddCategory)
+	menuRouter.HandleFunc("GET /category", s.HandleGetCategories)
+	menuRouter.HandleFunc("GET /category/{id}", s.HandleGetCategory)
+	menuRouter.HandleFunc("POST /category/{id}/item", s.HandleAddItemToCategory)
+	menuRouter.HandleFunc("DELETE /category/{id}/item/{item_id}", s.HandleRemoveItemFromCategory)
+}
+
+// menuItemResponse tells the ordering clients whether the item can be ordered.
+type menuItemResponse struct {
+	domain.MenuItem
+	Available bool
+}
+
+func newMenuItemResponse(item domain.MenuItem) menuItemResponse {
+	return menuItemResponse{MenuItem: item, Available: item.IsAvailable()}
+}
+
+func newMenuItemResponses(items []domain.MenuItem) []menuItemResponse {
+	res := make([]menuItemResponse, 0, len(items))
+	for _, item := range items {
+		res = append(res, newMenuItemResponse(item))
+	}
+
+	return res
 }
 
 func (s *Server) HandleAddMenuItem(w http.ResponseWriter, r *http.Request) {
 	type addMenuItemRequest struct {
-		Name  string `json:"name"`
-		Price int    `json:"price"`
+		Name        string `json:"name"`
+		Price       int    `json:"price"`
+		TaxCategory string `json:"tax_category"`
 	}
 
 	var req addMenuItemRequest
@@ -37,7 +70,7 @@ func (s *Server) HandleAddMenuItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	item, err := s.MenuService.CreateMenuItem(r.Context(), req.Name, req.Price)
+	item, err := s.MenuService.CreateMenuItem(r.Context(), req.Name, req.Price, req.TaxCategory)
 	if err != nil {
 		s.logger.Errorf("error creating menu item: %s\n", err)
 		writeError(w, domainErrorToHTTPStatus(err), err)
@@ -55,5 +88,228 @@ func (s *Server) HandleGetMenuItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSONBody(w, http.StatusOK, items)
+	writeJSONBody(w, http.StatusOK, newMenuItemResponses(items))
+}
+
+func (s *Server) HandleMarkMenuItemAvailable(w http.ResponseWriter, r *http.Request) {
+	// The body is optional, the portions are not counted without it.
+	type reqBody struct {
+		Portions *int `json:"portions"`
+	}
+
+	itemID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing item id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	item, err := s.MenuService.MarkMenuItemAvailable(r.Context(), itemID, req.Portions)
+	if err != nil {
+		s.logger.Errorf("error marking menu item available: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newMenuItemResponse(item))
+}
+
+func (s *Server) HandleMarkMenuItemUnavailable(w http.ResponseWriter, r *http.Request) {
+	itemID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing item id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	item, err := s.MenuService.MarkMenuItemUnavailable(r.Context(), itemID)
+	if err != nil {
+		s.logger.Errorf("error marking menu item unavailable: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newMenuItemResponse(item))
+}
+
+func (s *Server) HandleAddModifierGroup(w http.ResponseWriter, r *http.Request) {
+	type option struct {
+		Name       string `json:"name"`
+		PriceDelta int    `json:"price_delta"`
+	}
+
+	type reqBody struct {
+		Name          string   `json:"name"`
+		MinSelections int      `json:"min_selections"`
+		MaxSelections int      `json:"max_selections"`
+		Options       []option `json:"options"`
+	}
+
+	itemID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing item id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	options := make([]domain.ModifierOption, 0, len(req.Options))
+	for _, o := range req.Options {
+		options = append(options, domain.ModifierOption{Name: o.Name, PriceDelta: o.PriceDelta})
+	}
+
+	group, err := s.MenuService.AddModifierGroup(r.Context(), itemID, req.Name, req.MinSelections, req.MaxSelections, options)
+	if err != nil {
+		s.logger.Errorf("error adding modifier group: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusCreated, group)
+}
+
+func (s *Server) HandleRemoveModifierGroup(w http.ResponseWriter, r *http.Request) {
+	itemID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing item id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	groupID, err := parsePathID(r, "group_id")
+	if err != nil {
+		s.logger.Errorf("error parsing modifier group id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.MenuService.RemoveModifierGroup(r.Context(), itemID, groupID); err != nil {
+		s.logger.Errorf("error removing modifier group: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) HandleAddCategory(w http.ResponseWriter, r *http.Request) {
+	type addCategoryRequest struct {
+		Name string `json:"name"`
+	}
+
+	var req addCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.Name == "" {
+		s.logger.Errorf("empty name\n")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	category, err := s.MenuService.CreateCategory(r.Context(), req.Name)
+	if err != nil {
+		s.logger.Errorf("error creating category: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusCreated, category)
+}
+
+func (s *Server) HandleGetCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := s.MenuService.FindAllCategories(r.Context())
+	if err != nil {
+		s.logger.Errorf("error finding categories: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, categories)
+}
+
+func (s *Server) HandleGetCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing category id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	category, err := s.MenuService.FindCategory(r.Context(), categoryID)
+	if err != nil {
+		s.logger.Errorf("error finding category: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, category)
+}
+
+func (s *Server) HandleAddItemToCategory(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		ItemID id.ID `json:"item_id"`
+	}
+
+	categoryID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing category id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.MenuService.AddItemToCategory(r.Context(), categoryID, req.ItemID); err != nil {
+		s.logger.Errorf("error adding item to category: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) HandleRemoveItemFromCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing category id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	itemID, err := parsePathID(r, "item_id")
+	if err != nil {
+		s.logger.Errorf("error parsing item id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := s.MenuService.RemoveItemFromCategory(r.Context(), categoryID, itemID); err != nil {
+		s.logger.Errorf("error removing item from category: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -20,19 +20,21 @@ func TestCreateMenuItem(t *testing.T) {
 	s := MustNewServer(t, repos)
 
 	tt := []struct {
-		testName string
-		name     string
-		price    int
-		status   int
+		testName    string
+		name        string
+		price       int
+		taxCategory string
+		status      int
 	}{
 		{testName: "valid item", name: "item1", price: 100, status: http.StatusCreated},
+		{testName: "item with tax category", name: "item3", price: 100, taxCategory: "food", status: http.StatusCreated},
 		{testName: "empty name", name: "", price: 100, status: http.StatusBadRequest},
 		{testName: "negative price", name: "item2", price: -1, status: http.StatusBadRequest},
 	}
 
 	for _, tc := range tt {
 		t.Run(tc.testName, func(t *testing.T) {
-			body := fmt.Sprintf(`{"name":"%s","price":%d}`, tc.name, tc.price)
+			body := fmt.Sprintf(`{"name":"%s","price":%d,"tax_category":"%s"}`, tc.name, tc.price, tc.taxCategory)
 			r := httptest.NewRequest(http.MethodPost, "/menu/item", strings.NewReader(body))
 			w := httptest.NewRecorder()
 
@@ -53,6 +55,7 @@ func TestCreateMenuItem(t *testing.T) {
 
 			assert.Equal(t, tc.name, item.Name)
 			assert.Equal(t, tc.price, item.Price)
+			assert.Equal(t, tc.taxCategory, item.TaxCategory)
 			assert.NotEqual(t, id.NilID(), item.ID)
 		})
 	}
@@ -82,10 +85,10 @@ func TestGetMenuItems(t *testing.T) {
 
 	t.Run("with items", func(t *testing.T) {
 		ctx := context.Background()
-		item1, err := s.MenuService.CreateMenuItem(ctx, "item1", 100)
+		item1, err := s.MenuService.CreateMenuItem(ctx, "item1", 100, "")
 		require.NoError(t, err)
 
-		item2, err := s.MenuService.CreateMenuItem(ctx, "item2", 200)
+		item2, err := s.MenuService.CreateMenuItem(ctx, "item2", 200, "")
 		require.NoError(t, err)
 
 		r := httptest.NewRequest(http.MethodGet, "/menu/item", nil)
@@ -105,3 +108,269 @@ func TestGetMenuItems(t *testing.T) {
 		assert.ElementsMatch(t, []domain.MenuItem{item1, item2}, items)
 	})
 }
+
+func TestCreateCategory(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+
+	tt := []struct {
+		testName string
+		name     string
+		status   int
+	}{
+		{testName: "valid category", name: "starters", status: http.StatusCreated},
+		{testName: "empty name", name: "", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			body := fmt.Sprintf(`{"name":"%s"}`, tc.name)
+			r := httptest.NewRequest(http.MethodPost, "/menu/category", strings.NewReader(body))
+			w := httptest.NewRecorder()
+
+			s.HandleAddCategory(w, r)
+			res := w.Result()
+			defer res.Body.Close()
+
+			require.Equal(t, tc.status, res.StatusCode)
+
+			if tc.status != http.StatusCreated {
+				return
+			}
+
+			var category domain.MenuCategory
+			if err := json.NewDecoder(res.Body).Decode(&category); err != nil {
+				t.Fatalf("failed to decode response: %s", err)
+			}
+
+			assert.Equal(t, tc.name, category.Name)
+			assert.Empty(t, category.MenuItems)
+			assert.NotEqual(t, id.NilID(), category.ID)
+		})
+	}
+}
+
+func TestGetCategories(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	ctx := context.Background()
+
+	item, err := s.MenuService.CreateMenuItem(ctx, "item", 100, "")
+	require.NoError(t, err)
+	starters, err := s.MenuService.CreateCategory(ctx, "starters")
+	require.NoError(t, err)
+	mains, err := s.MenuService.CreateCategory(ctx, "mains")
+	require.NoError(t, err)
+	err = s.MenuService.AddItemToCategory(ctx, starters.ID, item.ID)
+	require.NoError(t, err)
+	starters.MenuItems = []domain.MenuItem{item}
+
+	t.Run("all categories", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/menu/category", nil)
+		w := httptest.NewRecorder()
+
+		s.HandleGetCategories(w, r)
+
+		body, statusCode := MustParseReponse[[]domain.MenuCategory](t, w)
+
+		require.Equal(t, http.StatusOK, statusCode)
+		assert.ElementsMatch(t, []domain.MenuCategory{starters, mains}, body)
+	})
+
+	t.Run("one category", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/menu/category/"+starters.ID.String(), nil)
+		r.SetPathValue("id", starters.ID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleGetCategory(w, r)
+
+		body, statusCode := MustParseReponse[domain.MenuCategory](t, w)
+
+		require.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, starters, body)
+	})
+
+	t.Run("category not found", func(t *testing.T) {
+		categoryID := id.New().String()
+		r := httptest.NewRequest(http.MethodGet, "/menu/category/"+categoryID, nil)
+		r.SetPathValue("id", categoryID)
+		w := httptest.NewRecorder()
+
+		s.HandleGetCategory(w, r)
+
+		require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestAddAndRemoveItemFromCategory(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	ctx := context.Background()
+
+	item, err := s.MenuService.CreateMenuItem(ctx, "item", 100, "")
+	require.NoError(t, err)
+	category, err := s.MenuService.CreateCategory(ctx, "starters")
+	require.NoError(t, err)
+
+	addItem := func(itemID id.ID) int {
+		body := fmt.Sprintf(`{"item_id":"%s"}`, itemID)
+		r := httptest.NewRequest(http.MethodPost, "/menu/category/"+category.ID.String()+"/item", strings.NewReader(body))
+		r.SetPathValue("id", category.ID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleAddItemToCategory(w, r)
+
+		return w.Result().StatusCode
+	}
+
+	removeItem := func(itemID id.ID) int {
+		r := httptest.NewRequest(http.MethodDelete, "/menu/category/"+category.ID.String()+"/item/"+itemID.String(), nil)
+		r.SetPathValue("id", category.ID.String())
+		r.SetPathValue("item_id", itemID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleRemoveItemFromCategory(w, r)
+
+		return w.Result().StatusCode
+	}
+
+	require.Equal(t, http.StatusNoContent, addItem(item.ID))
+	require.Equal(t, http.StatusConflict, addItem(item.ID))
+	require.Equal(t, http.StatusNotFound, addItem(id.New()))
+
+	got, err := s.MenuService.FindCategory(ctx, category.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.MenuItem{item}, got.MenuItems)
+
+	require.Equal(t, http.StatusNoContent, removeItem(item.ID))
+	require.Equal(t, http.StatusNotFound, removeItem(item.ID))
+
+	got, err = s.MenuService.FindCategory(ctx, category.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.MenuItems)
+}
+
+func TestAddAndRemoveModifierGroup(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	ctx := context.Background()
+
+	item, err := s.MenuService.CreateMenuItem(ctx, "burger", 1000, "")
+	require.NoError(t, err)
+
+	addGroup := func(itemID id.ID, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/menu/item/"+itemID.String()+"/modifier-group", strings.NewReader(body))
+		r.SetPathValue("id", itemID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleAddModifierGroup(w, r)
+
+		return w
+	}
+
+	removeGroup := func(groupID id.ID) int {
+		r := httptest.NewRequest(http.MethodDelete, "/menu/item/"+item.ID.String()+"/modifier-group/"+groupID.String(), nil)
+		r.SetPathValue("id", item.ID.String())
+		r.SetPathValue("group_id", groupID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleRemoveModifierGroup(w, r)
+
+		return w.Result().StatusCode
+	}
+
+	body := `{"name":"Extras","min_selections":0,"max_selections":2,"options":[{"name":"Bacon","price_delta":200},{"name":"No onions"}]}`
+	group, statusCode := MustParseReponse[domain.ModifierGroup](t, addGroup(item.ID, body))
+	require.Equal(t, http.StatusCreated, statusCode)
+	assert.Equal(t, "Extras", group.Name)
+	require.Len(t, group.Options, 2)
+	assert.Equal(t, 200, group.Options[0].PriceDelta)
+
+	require.Equal(t, http.StatusNotFound, addGroup(id.New(), body).Result().StatusCode)
+	require.Equal(t, http.StatusForbidden, addGroup(item.ID, `{"name":"Extras","min_selections":0,"max_selections":3,"options":[{"name":"Bacon"}]}`).Result().StatusCode)
+
+	got, err := repos.Menu.FindItem(ctx, item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ModifierGroup{group}, got.ModifierGroups)
+
+	require.Equal(t, http.StatusNoContent, removeGroup(group.ID))
+	require.Equal(t, http.StatusNotFound, removeGroup(group.ID))
+}
+
+func TestMarkMenuItemAvailability(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	ctx := context.Background()
+
+	type menuItemResponse struct {
+		domain.MenuItem
+		Available bool
+	}
+
+	item, err := s.MenuService.CreateMenuItem(ctx, "soup", 500, "")
+	require.NoError(t, err)
+
+	mark := func(itemID id.ID, availability string, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/menu/item/"+itemID.String()+"/"+availability, strings.NewReader(body))
+		r.SetPathValue("id", itemID.String())
+		w := httptest.NewRecorder()
+
+		if availability == "available" {
+			s.HandleMarkMenuItemAvailable(w, r)
+		} else {
+			s.HandleMarkMenuItemUnavailable(w, r)
+		}
+
+		return w
+	}
+
+	getItems := func() []menuItemResponse {
+		r := httptest.NewRequest(http.MethodGet, "/menu/item", nil)
+		w := httptest.NewRecorder()
+
+		s.HandleGetMenuItems(w, r)
+
+		items, statusCode := MustParseReponse[[]menuItemResponse](t, w)
+		require.Equal(t, http.StatusOK, statusCode)
+		return items
+	}
+
+	require.True(t, getItems()[0].Available)
+
+	res, statusCode := MustParseReponse[menuItemResponse](t, mark(item.ID, "unavailable", ""))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.False(t, res.Available)
+	assert.False(t, getItems()[0].Available)
+
+	res, statusCode = MustParseReponse[menuItemResponse](t, mark(item.ID, "available", `{"portions": 1}`))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.True(t, res.Available)
+	require.NotNil(t, res.Portions)
+	assert.Equal(t, 1, *res.Portions)
+
+	table := domain.Table{ID: id.New(), Status: domain.TableStatusOpened, Orders: []domain.Order{}}
+	MustPresaveTables(t, repos, []domain.Table{table})
+
+	takeOrder := func() int {
+		reqBody := fmt.Sprintf(`{"table_id": "%s", "menu_item_ids": ["%s"]}`, table.ID, item.ID)
+		r := httptest.NewRequest(http.MethodPost, "/table/order", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		s.HandleTakeOrder(w, r)
+
+		return w.Result().StatusCode
+	}
+
+	require.Equal(t, http.StatusOK, takeOrder())
+	assert.False(t, getItems()[0].Available, "the last portion was ordered")
+	require.Equal(t, http.StatusConflict, takeOrder())
+
+	res, statusCode = MustParseReponse[menuItemResponse](t, mark(item.ID, "available", ""))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.True(t, res.Available)
+	assert.Nil(t, res.Portions)
+
+	require.Equal(t, http.StatusForbidden, mark(item.ID, "available", `{"portions": -1}`).Result().StatusCode)
+	require.Equal(t, http.StatusNotFound, mark(id.New(), "unavailable", "").Result().StatusCode)
+	require.Equal(t, http.StatusBadRequest, mark(item.ID, "available", `{"portions": "many"}`).Result().StatusCode)
+}
@@ -0,0 +1,184 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"time"
+)
+
+func (s *Server) registerPreparationRoutes(r *router) {
+	preparationRouter := r.group("/preparation")
+
+	preparationRouter.HandleFunc("GET /", s.HandleGetPreparations)
+	preparationRouter.HandleFunc("POST /start", s.HandleStartPreparation)
+	preparationRouter.HandleFunc("POST /finish", s.HandleFinishPreparation)
+	preparationRouter.HandleFunc("POST /serve", s.HandleServePreparation)
+	preparationRouter.HandleFunc("POST /abort", s.HandleAbortPreparation)
+	preparationRouter.HandleFunc("GET /{id}/history", s.HandleGetPreparationHistory)
+	preparationRouter.HandleFunc("GET /timings", s.HandleGetKitchenTimings)
+}
+
+func (s *Server) HandleGetPreparations(w http.ResponseWriter, r *http.Request) {
+	status := domain.PreparationStatus(r.URL.Query().Get("status"))
+	if !status.IsValid() {
+		err := fmt.Errorf("invalid preparation status: %q", status)
+		s.logger.Errorf("%s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	preparations, err := s.TableService.FindPreparationsByStatus(r.Context(), status)
+	if err != nil {
+		s.logger.Errorf("error finding preparations: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, preparations)
+}
+
+func (s *Server) HandleStartPreparation(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		PreparationID id.ID `json:"preparation_id"`
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err := s.TableService.StartPreparation(r.Context(), req.PreparationID)
+	if err != nil {
+		s.logger.Errorf("error starting preparation: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) HandleFinishPreparation(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		PreparationID id.ID `json:"preparation_id"`
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err := s.TableService.FinishPreparation(r.Context(), req.PreparationID)
+	if err != nil {
+		s.logger.Errorf("error finishing preparation: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) HandleServePreparation(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		PreparationID id.ID `json:"preparation_id"`
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err := s.TableService.ServePreparation(r.Context(), req.PreparationID)
+	if err != nil {
+		s.logger.Errorf("error serving preparation: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) HandleAbortPreparation(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		PreparationID id.ID `json:"preparation_id"`
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err := s.TableService.AbortPreparation(r.Context(), req.PreparationID)
+	if err != nil {
+		s.logger.Errorf("error aborting preparation: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) HandleGetPreparationHistory(w http.ResponseWriter, r *http.Request) {
+	preparationID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing preparation id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	prep, err := s.TableService.FindPreparation(r.Context(), preparationID)
+	if err != nil {
+		s.logger.Errorf("error finding preparation: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	// The preparations taken before the history was recorded have none.
+	history := prep.History
+	if history == nil {
+		history = make([]domain.PreparationTransition, 0)
+	}
+
+	writeJSONBody(w, http.StatusOK, history)
+}
+
+// HandleGetKitchenTimings reports the timings of the preparations ordered from the from query parameter
+// until the to one, excluded, both in RFC 3339.
+func (s *Server) HandleGetKitchenTimings(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	from, err := time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		err := fmt.Errorf("invalid from: %q", query.Get("from"))
+		s.logger.Errorf("%s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	to, err := time.Parse(time.RFC3339, query.Get("to"))
+	if err != nil {
+		err := fmt.Errorf("invalid to: %q", query.Get("to"))
+		s.logger.Errorf("%s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := s.TableService.FindKitchenTimings(r.Context(), from, to)
+	if err != nil {
+		s.logger.Errorf("error finding kitchen timings: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, report)
+}
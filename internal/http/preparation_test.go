@@ -0,0 +1,407 @@
+package http_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartPreparation(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		tt := []struct {
+			testName             string
+			tableFromPreparation func(prep domain.Preparation) domain.Table
+			preparation          domain.Preparation
+		}{
+			{
+				testName: "one table with one order and one preparation",
+				tableFromPreparation: func(prep domain.Preparation) domain.Table {
+					return domain.Table{
+						ID:     id.New(),
+						Status: domain.TableStatusOpened,
+						Orders: []domain.Order{
+							{
+								ID:           id.New(),
+								Status:       domain.OrderStatusTaken,
+								Preparations: []domain.Preparation{prep},
+							},
+						},
+					}
+				},
+				preparation: domain.Preparation{
+					ID:       id.New(),
+					MenuItem: domain.MenuItem{ID: id.New(), Name: "item", Price: 100},
+					Status:   domain.PreparationStatusPending,
+				},
+			},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				repos := MustNewRepositories(t)
+				s := MustNewServer(t, repos)
+				table := tc.tableFromPreparation(tc.preparation)
+				MustPresaveTables(t, repos, []domain.Table{table})
+
+				reqBody := fmt.Sprintf(`{"preparation_id": "%s"}`, tc.preparation.ID)
+				r := httptest.NewRequest(http.MethodPost, "/preparation/start", strings.NewReader(reqBody))
+				w := httptest.NewRecorder()
+
+				s.HandleStartPreparation(w, r)
+
+				res := w.Result()
+
+				require.Equal(t, http.StatusNoContent, res.StatusCode)
+			})
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		tt := []struct {
+			testName             string
+			tableFromPreparation func(prep domain.Preparation) domain.Table
+			preparation          domain.Preparation
+			expectedStatusCode   int
+		}{
+			{
+				testName: "preparation not found",
+				tableFromPreparation: func(prep domain.Preparation) domain.Table {
+					return domain.Table{
+						ID:     id.New(),
+						Status: domain.TableStatusOpened,
+						Orders: make([]domain.Order, 0),
+					}
+				},
+				preparation:        domain.Preparation{ID: id.NilID()},
+				expectedStatusCode: http.StatusNotFound,
+			},
+			{
+				testName: "preparation already started",
+				tableFromPreparation: func(prep domain.Preparation) domain.Table {
+					return domain.Table{
+						ID:     id.New(),
+						Status: domain.TableStatusOpened,
+						Orders: []domain.Order{
+							{
+								ID:           id.New(),
+								Status:       domain.OrderStatusTaken,
+								Preparations: []domain.Preparation{prep},
+							},
+						},
+					}
+				},
+				preparation: domain.Preparation{
+					ID:       id.New(),
+					MenuItem: domain.MenuItem{ID: id.New(), Name: "item", Price: 100},
+					Status:   domain.PreparationStatusInProgress,
+				},
+				expectedStatusCode: http.StatusForbidden,
+			},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				repos := MustNewRepositories(t)
+				s := MustNewServer(t, repos)
+				table := tc.tableFromPreparation(tc.preparation)
+				MustPresaveTables(t, repos, []domain.Table{table})
+
+				reqBody := fmt.Sprintf(`{"preparation_id": "%s"}`, tc.preparation.ID)
+				r := httptest.NewRequest(http.MethodPost, "/preparation/start", strings.NewReader(reqBody))
+				w := httptest.NewRecorder()
+
+				s.HandleStartPreparation(w, r)
+
+				res := w.Result()
+
+				require.Equal(t, tc.expectedStatusCode, res.StatusCode)
+			})
+		}
+	})
+}
+
+func TestFinishPreparation(t *testing.T) {
+	tt := []struct {
+		testName           string
+		status             domain.PreparationStatus
+		expectedStatusCode int
+	}{
+		{testName: "preparation in progress", status: domain.PreparationStatusInProgress, expectedStatusCode: http.StatusNoContent},
+		{testName: "preparation pending", status: domain.PreparationStatusPending, expectedStatusCode: http.StatusForbidden},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			repos := MustNewRepositories(t)
+			s := MustNewServer(t, repos)
+			preparation := domain.Preparation{
+				ID:       id.New(),
+				MenuItem: domain.MenuItem{ID: id.New(), Name: "item", Price: 100},
+				Status:   tc.status,
+			}
+			table := domain.Table{
+				ID:     id.New(),
+				Status: domain.TableStatusOpened,
+				Orders: []domain.Order{
+					{ID: id.New(), Status: domain.OrderStatusTaken, Preparations: []domain.Preparation{preparation}},
+				},
+			}
+			MustPresaveTables(t, repos, []domain.Table{table})
+
+			reqBody := fmt.Sprintf(`{"preparation_id": "%s"}`, preparation.ID)
+			r := httptest.NewRequest(http.MethodPost, "/preparation/finish", strings.NewReader(reqBody))
+			w := httptest.NewRecorder()
+
+			s.HandleFinishPreparation(w, r)
+
+			res := w.Result()
+
+			require.Equal(t, tc.expectedStatusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestServePreparation(t *testing.T) {
+	tt := []struct {
+		testName           string
+		status             domain.PreparationStatus
+		expectedStatusCode int
+	}{
+		{testName: "preparation ready", status: domain.PreparationStatusReady, expectedStatusCode: http.StatusNoContent},
+		{testName: "preparation in progress", status: domain.PreparationStatusInProgress, expectedStatusCode: http.StatusForbidden},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			repos := MustNewRepositories(t)
+			s := MustNewServer(t, repos)
+			preparation := domain.Preparation{
+				ID:       id.New(),
+				MenuItem: domain.MenuItem{ID: id.New(), Name: "item", Price: 100},
+				Status:   tc.status,
+			}
+			table := domain.Table{
+				ID:     id.New(),
+				Status: domain.TableStatusOpened,
+				Orders: []domain.Order{
+					{ID: id.New(), Status: domain.OrderStatusTaken, Preparations: []domain.Preparation{preparation}},
+				},
+			}
+			MustPresaveTables(t, repos, []domain.Table{table})
+
+			reqBody := fmt.Sprintf(`{"preparation_id": "%s"}`, preparation.ID)
+			r := httptest.NewRequest(http.MethodPost, "/preparation/serve", strings.NewReader(reqBody))
+			w := httptest.NewRecorder()
+
+			s.HandleServePreparation(w, r)
+
+			res := w.Result()
+
+			require.Equal(t, tc.expectedStatusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestGetPreparations(t *testing.T) {
+	pending := domain.Preparation{
+		ID:       id.New(),
+		MenuItem: domain.MenuItem{ID: id.New(), Name: "item", Price: 100},
+		Status:   domain.PreparationStatusPending,
+	}
+	ready := domain.Preparation{
+		ID:       id.New(),
+		MenuItem: domain.MenuItem{ID: id.New(), Name: "item", Price: 100},
+		Status:   domain.PreparationStatusReady,
+	}
+	tables := []domain.Table{
+		{
+			ID:     id.New(),
+			Status: domain.TableStatusOpened,
+			Orders: []domain.Order{
+				{ID: id.New(), Status: domain.OrderStatusTaken, Preparations: []domain.Preparation{pending}},
+			},
+		},
+		{
+			ID:     id.New(),
+			Status: domain.TableStatusOpened,
+			Orders: []domain.Order{
+				{ID: id.New(), Status: domain.OrderStatusTaken, Preparations: []domain.Preparation{ready}},
+			},
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		tt := []struct {
+			testName     string
+			status       string
+			preparations []domain.TablePreparation
+		}{
+			{
+				testName:     "pending preparations",
+				status:       "pending",
+				preparations: []domain.TablePreparation{{Preparation: pending, TableID: tables[0].ID, OrderID: tables[0].Orders[0].ID}},
+			},
+			{
+				testName:     "ready preparations",
+				status:       "ready",
+				preparations: []domain.TablePreparation{{Preparation: ready, TableID: tables[1].ID, OrderID: tables[1].Orders[0].ID}},
+			},
+			{testName: "no served preparations", status: "served", preparations: []domain.TablePreparation{}},
+		}
+
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		MustPresaveTables(t, repos, tables)
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				r := httptest.NewRequest(http.MethodGet, "/preparation/?status="+tc.status, nil)
+				w := httptest.NewRecorder()
+
+				s.HandleGetPreparations(w, r)
+
+				body, statusCode := MustParseReponse[[]domain.TablePreparation](t, w)
+
+				require.Equal(t, http.StatusOK, statusCode)
+				require.ElementsMatch(t, tc.preparations, body)
+			})
+		}
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+
+		r := httptest.NewRequest(http.MethodGet, "/preparation/?status=unknown", nil)
+		w := httptest.NewRecorder()
+
+		s.HandleGetPreparations(w, r)
+
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+	})
+}
+
+func TestAbortPreparation(t *testing.T) {
+	tt := []struct {
+		testName           string
+		status             domain.PreparationStatus
+		orderStatus        domain.OrderStatus
+		expectedStatusCode int
+	}{
+		{testName: "preparation pending", status: domain.PreparationStatusPending, orderStatus: domain.OrderStatusTaken, expectedStatusCode: http.StatusNoContent},
+		{testName: "preparation served", status: domain.PreparationStatusServed, orderStatus: domain.OrderStatusDone, expectedStatusCode: http.StatusForbidden},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			repos := MustNewRepositories(t)
+			s := MustNewServer(t, repos)
+			preparation := domain.Preparation{
+				ID:       id.New(),
+				MenuItem: domain.MenuItem{ID: id.New(), Name: "item", Price: 100},
+				Status:   tc.status,
+			}
+			table := domain.Table{
+				ID:     id.New(),
+				Status: domain.TableStatusOpened,
+				Orders: []domain.Order{
+					{ID: id.New(), Status: tc.orderStatus, Preparations: []domain.Preparation{preparation}},
+				},
+			}
+			MustPresaveTables(t, repos, []domain.Table{table})
+
+			reqBody := fmt.Sprintf(`{"preparation_id": "%s"}`, preparation.ID)
+			r := httptest.NewRequest(http.MethodPost, "/preparation/abort", strings.NewReader(reqBody))
+			w := httptest.NewRecorder()
+
+			s.HandleAbortPreparation(w, r)
+
+			res := w.Result()
+
+			require.Equal(t, tc.expectedStatusCode, res.StatusCode)
+		})
+	}
+}
+
+func TestGetPreparationHistoryAndTimings(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	ctx := context.Background()
+	diningTable := MustPresaveDiningTable(t, repos, 4)
+
+	item, err := s.MenuService.CreateMenuItem(ctx, "burger", 1200, "")
+	require.NoError(t, err)
+	table, err := s.TableService.OpenTable(ctx, diningTable.ID, 2)
+	require.NoError(t, err)
+	order, err := s.TableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: item, Quantity: 2}})
+	require.NoError(t, err)
+	prepID := order.Preparations[0].ID
+	require.NoError(t, s.TableService.StartPreparation(ctx, prepID))
+	require.NoError(t, s.TableService.FinishPreparation(ctx, prepID))
+
+	getHistory := func(preparationID string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/preparation/"+preparationID+"/history", nil)
+		r.SetPathValue("id", preparationID)
+		w := httptest.NewRecorder()
+
+		s.HandleGetPreparationHistory(w, r)
+
+		return w
+	}
+
+	history, statusCode := MustParseReponse[[]domain.PreparationTransition](t, getHistory(prepID.String()))
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, history, 3)
+	require.Equal(t, domain.PreparationStatusPending, history[0].Status)
+	require.Equal(t, domain.PreparationStatusInProgress, history[1].Status)
+	require.Equal(t, domain.PreparationStatusReady, history[2].Status)
+	require.False(t, history[2].At.Before(history[0].At))
+
+	require.Equal(t, http.StatusNotFound, getHistory(id.New().String()).Result().StatusCode)
+	require.Equal(t, http.StatusBadRequest, getHistory("invalid").Result().StatusCode)
+
+	now := time.Now()
+	tt := []struct {
+		testName string
+		from     string
+		to       string
+		status   int
+	}{
+		{testName: "valid range", from: now.Add(-time.Hour).Format(time.RFC3339), to: now.Add(time.Hour).Format(time.RFC3339), status: http.StatusOK},
+		{testName: "empty range", from: now.Format(time.RFC3339), to: now.Add(-time.Hour).Format(time.RFC3339), status: http.StatusForbidden},
+		{testName: "missing to", from: now.Format(time.RFC3339), status: http.StatusBadRequest},
+		{testName: "invalid from", from: "yesterday", to: now.Format(time.RFC3339), status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			query := url.Values{"from": {tc.from}, "to": {tc.to}}
+			r := httptest.NewRequest(http.MethodGet, "/preparation/timings?"+query.Encode(), nil)
+			w := httptest.NewRecorder()
+
+			s.HandleGetKitchenTimings(w, r)
+
+			require.Equal(t, tc.status, w.Result().StatusCode)
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			report, _ := MustParseReponse[domain.TimingReport](t, w)
+			require.Len(t, report.Preparations, 2)
+			require.NotNil(t, report.Preparations[0].Cook)
+			require.Nil(t, report.Preparations[1].Queue)
+			require.Len(t, report.MenuItems, 1)
+			require.Equal(t, item.ID, report.MenuItems[0].MenuItemID)
+			require.Equal(t, 1, report.MenuItems[0].Timings.Cook.Count)
+		})
+	}
+}
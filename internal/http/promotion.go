@@ -0,0 +1,176 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"time"
+)
+
+func (s *Server) registerPromotionRoutes(r *router) {
+	promotionRouter := r.group("/promotion")
+
+	promotionRouter.HandleFunc("POST /", s.HandleAddPromotion)
+	promotionRouter.HandleFunc("GET /", s.HandleGetPromotions)
+	promotionRouter.HandleFunc("GET /{id}", s.HandleGetPromotion)
+	promotionRouter.HandleFunc("POST /{id}/activate", s.HandleActivatePromotion)
+	promotionRouter.HandleFunc("POST /{id}/deactivate", s.HandleDeactivatePromotion)
+}
+
+// timeWindow is a window of the day written as HH:MM times.
+type timeWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func formatTimeOfDay(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours())%24, int(d.Minutes())%60)
+}
+
+type promotionResponse struct {
+	domain.Promotion
+	Window *timeWindow
+}
+
+func newPromotionResponse(promotion domain.Promotion) promotionResponse {
+	res := promotionResponse{Promotion: promotion}
+	if promotion.Window != nil {
+		res.Window = &timeWindow{Start: formatTimeOfDay(promotion.Window.Start), End: formatTimeOfDay(promotion.Window.End)}
+	}
+
+	return res
+}
+
+func (s *Server) HandleAddPromotion(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		Name         string      `json:"name"`
+		Kind         string      `json:"kind"`
+		Scope        string      `json:"scope"`
+		Percent      float64     `json:"percent"`
+		Amount       int         `json:"amount"`
+		BuyQuantity  int         `json:"buy_quantity"`
+		FreeQuantity int         `json:"free_quantity"`
+		MenuItemIDs  []id.ID     `json:"menu_item_ids"`
+		Window       *timeWindow `json:"window"`
+		Priority     int         `json:"priority"`
+		Stackable    bool        `json:"stackable"`
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	promotion := domain.Promotion{
+		Name:         req.Name,
+		Kind:         domain.PromotionKind(req.Kind),
+		Scope:        domain.PromotionScope(req.Scope),
+		Percent:      req.Percent,
+		Amount:       req.Amount,
+		BuyQuantity:  req.BuyQuantity,
+		FreeQuantity: req.FreeQuantity,
+		MenuItemIDs:  req.MenuItemIDs,
+		Priority:     req.Priority,
+		Stackable:    req.Stackable,
+	}
+
+	if req.Window != nil {
+		start, err := parseTimeOfDay(req.Window.Start)
+		if err != nil {
+			s.logger.Errorf("error parsing window start: %s\n", err)
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		end, err := parseTimeOfDay(req.Window.End)
+		if err != nil {
+			s.logger.Errorf("error parsing window end: %s\n", err)
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		promotion.Window = &domain.TimeWindow{Start: start, End: end}
+	}
+
+	promotion, err := s.PromotionService.CreatePromotion(r.Context(), promotion)
+	if err != nil {
+		s.logger.Errorf("error creating promotion: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusCreated, newPromotionResponse(promotion))
+}
+
+func (s *Server) HandleGetPromotions(w http.ResponseWriter, r *http.Request) {
+	promotions, err := s.PromotionService.FindAllPromotions(r.Context())
+	if err != nil {
+		s.logger.Errorf("error finding promotions: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	res := make([]promotionResponse, 0, len(promotions))
+	for _, promotion := range promotions {
+		res = append(res, newPromotionResponse(promotion))
+	}
+
+	writeJSONBody(w, http.StatusOK, res)
+}
+
+func (s *Server) HandleGetPromotion(w http.ResponseWriter, r *http.Request) {
+	promotionID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing promotion id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	promotion, err := s.PromotionService.FindPromotion(r.Context(), promotionID)
+	if err != nil {
+		s.logger.Errorf("error finding promotion: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newPromotionResponse(promotion))
+}
+
+func (s *Server) HandleActivatePromotion(w http.ResponseWriter, r *http.Request) {
+	s.setPromotionActive(w, r, true)
+}
+
+func (s *Server) HandleDeactivatePromotion(w http.ResponseWriter, r *http.Request) {
+	s.setPromotionActive(w, r, false)
+}
+
+func (s *Server) setPromotionActive(w http.ResponseWriter, r *http.Request, active bool) {
+	promotionID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing promotion id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	promotion, err := s.PromotionService.SetPromotionActive(r.Context(), promotionID, active)
+	if err != nil {
+		s.logger.Errorf("error updating promotion: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newPromotionResponse(promotion))
+}
@@ -0,0 +1,138 @@
+package http_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type promotionResponse struct {
+	domain.Promotion
+	Window *struct {
+		Start string `json:"start"`
+		End   string `json:"end"`
+	}
+}
+
+func MustPresaveMenuItem(t *testing.T, repos repositories, price int) domain.MenuItem {
+	t.Helper()
+
+	item := domain.MenuItem{ID: id.New(), Name: "Beer", Price: price}
+	err := repos.Menu.SaveItem(context.Background(), item)
+	require.NoError(t, err)
+
+	return item
+}
+
+func TestCreatePromotion(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	item := MustPresaveMenuItem(t, repos, 80)
+
+	t.Run("Success", func(t *testing.T) {
+		reqBody := fmt.Sprintf(`{
+			"name": "Happy hour",
+			"kind": "fixed_price",
+			"scope": "line",
+			"amount": 50,
+			"menu_item_ids": ["%s"],
+			"window": {"start": "17:00", "end": "19:30"},
+			"priority": 2
+		}`, item.ID)
+		r := httptest.NewRequest(http.MethodPost, "/promotion", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		s.HandleAddPromotion(w, r)
+
+		body, statusCode := MustParseReponse[promotionResponse](t, w)
+
+		require.Equal(t, http.StatusCreated, statusCode)
+		assert.Equal(t, "Happy hour", body.Name)
+		assert.Equal(t, []id.ID{item.ID}, body.MenuItemIDs)
+		assert.True(t, body.Active)
+		require.NotNil(t, body.Window)
+		assert.Equal(t, "17:00", body.Window.Start)
+		assert.Equal(t, "19:30", body.Window.End)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		tt := []struct {
+			testName           string
+			reqBody            string
+			expectedStatusCode int
+		}{
+			{testName: "unknown kind", reqBody: `{"name": "Free", "kind": "free", "scope": "line"}`, expectedStatusCode: http.StatusForbidden},
+			{testName: "unknown menu item", reqBody: fmt.Sprintf(`{"name": "Half", "kind": "percentage", "scope": "line", "percent": 50, "menu_item_ids": ["%s"]}`, id.New()), expectedStatusCode: http.StatusNotFound},
+			{testName: "invalid window", reqBody: `{"name": "Late", "kind": "percentage", "scope": "bill", "percent": 10, "window": {"start": "5pm", "end": "19:00"}}`, expectedStatusCode: http.StatusBadRequest},
+		}
+
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				r := httptest.NewRequest(http.MethodPost, "/promotion", strings.NewReader(tc.reqBody))
+				w := httptest.NewRecorder()
+
+				s.HandleAddPromotion(w, r)
+
+				require.Equal(t, tc.expectedStatusCode, w.Result().StatusCode)
+			})
+		}
+	})
+}
+
+func TestDeactivatePromotion(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	promotion, err := s.PromotionService.CreatePromotion(context.Background(), domain.Promotion{Name: "Ten off", Kind: domain.PromotionFixedAmount, Scope: domain.PromotionScopeBill, Amount: 10})
+	require.NoError(t, err)
+
+	t.Run("Success", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/promotion/"+promotion.ID.String()+"/deactivate", nil)
+		r.SetPathValue("id", promotion.ID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleDeactivatePromotion(w, r)
+
+		body, statusCode := MustParseReponse[promotionResponse](t, w)
+
+		require.Equal(t, http.StatusOK, statusCode)
+		assert.False(t, body.Active)
+		assert.Nil(t, body.Window)
+	})
+
+	t.Run("Unknown promotion", func(t *testing.T) {
+		promotionID := id.New().String()
+		r := httptest.NewRequest(http.MethodPost, "/promotion/"+promotionID+"/deactivate", nil)
+		r.SetPathValue("id", promotionID)
+		w := httptest.NewRecorder()
+
+		s.HandleDeactivatePromotion(w, r)
+
+		require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestGetPromotions(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	promotion, err := s.PromotionService.CreatePromotion(context.Background(), domain.Promotion{Name: "Ten off", Kind: domain.PromotionFixedAmount, Scope: domain.PromotionScopeBill, Amount: 10})
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodGet, "/promotion", nil)
+	w := httptest.NewRecorder()
+
+	s.HandleGetPromotions(w, r)
+
+	body, statusCode := MustParseReponse[[]promotionResponse](t, w)
+
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, body, 1)
+	assert.Equal(t, promotion.ID, body[0].ID)
+}
@@ -0,0 +1,236 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"strconv"
+	"time"
+)
+
+func (s *Server) registerReservationRoutes(r *router) {
+	reservationRouter := r.group("/reservation")
+
+	reservationRouter.HandleFunc("POST /", s.HandleBookReservation)
+	reservationRouter.HandleFunc("GET /", s.HandleGetReservations)
+	reservationRouter.HandleFunc("GET /availability", s.HandleGetAvailability)
+	reservationRouter.HandleFunc("GET /{id}", s.HandleGetReservation)
+	reservationRouter.HandleFunc("PUT /{id}/dining_table", s.HandleAssignDiningTable)
+	reservationRouter.HandleFunc("POST /{id}/confirm", s.HandleConfirmReservation)
+	reservationRouter.HandleFunc("POST /{id}/seat", s.HandleSeatReservation)
+	reservationRouter.HandleFunc("POST /{id}/no_show", s.HandleMarkNoShow)
+	reservationRouter.HandleFunc("POST /{id}/cancel", s.HandleCancelReservation)
+}
+
+// reservationResponse writes the duration of the reservation as a Go duration, such as 1h30m.
+type reservationResponse struct {
+	domain.Reservation
+	Duration string
+	EndsAt   time.Time
+}
+
+func newReservationResponse(reservation domain.Reservation) reservationResponse {
+	return reservationResponse{Reservation: reservation, Duration: reservation.Duration.String(), EndsAt: reservation.EndsAt()}
+}
+
+func (s *Server) HandleBookReservation(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		GuestName     string    `json:"guest_name"`
+		Contact       string    `json:"contact"`
+		PartySize     int       `json:"party_size"`
+		StartsAt      time.Time `json:"starts_at"`
+		Duration      string    `json:"duration"`
+		DiningTableID id.ID     `json:"dining_table_id"`
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil {
+		err := fmt.Errorf("invalid duration: %q", req.Duration)
+		s.logger.Errorf("%s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	reservation, err := s.ReservationService.BookReservation(r.Context(), domain.Reservation{
+		GuestName:     req.GuestName,
+		Contact:       req.Contact,
+		PartySize:     req.PartySize,
+		StartsAt:      req.StartsAt,
+		Duration:      duration,
+		DiningTableID: req.DiningTableID,
+	})
+	if err != nil {
+		s.logger.Errorf("error booking reservation: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusCreated, newReservationResponse(reservation))
+}
+
+// HandleGetReservations returns the reservations overlapping the from query parameter
+// until the to one, excluded, both in RFC 3339.
+func (s *Server) HandleGetReservations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	from, err := time.Parse(time.RFC3339, query.Get("from"))
+	if err != nil {
+		err := fmt.Errorf("invalid from: %q", query.Get("from"))
+		s.logger.Errorf("%s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	to, err := time.Parse(time.RFC3339, query.Get("to"))
+	if err != nil {
+		err := fmt.Errorf("invalid to: %q", query.Get("to"))
+		s.logger.Errorf("%s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	reservations, err := s.ReservationService.FindReservations(r.Context(), from, to)
+	if err != nil {
+		s.logger.Errorf("error finding reservations: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	res := make([]reservationResponse, 0, len(reservations))
+	for _, reservation := range reservations {
+		res = append(res, newReservationResponse(reservation))
+	}
+
+	writeJSONBody(w, http.StatusOK, res)
+}
+
+// HandleGetAvailability returns the dining tables available for the party_size, starts_at and duration
+// query parameters, the start in RFC 3339 and the duration as a Go duration.
+func (s *Server) HandleGetAvailability(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	partySize, err := strconv.Atoi(query.Get("party_size"))
+	if err != nil {
+		err := fmt.Errorf("invalid party_size: %q", query.Get("party_size"))
+		s.logger.Errorf("%s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	startsAt, err := time.Parse(time.RFC3339, query.Get("starts_at"))
+	if err != nil {
+		err := fmt.Errorf("invalid starts_at: %q", query.Get("starts_at"))
+		s.logger.Errorf("%s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	duration, err := time.ParseDuration(query.Get("duration"))
+	if err != nil {
+		err := fmt.Errorf("invalid duration: %q", query.Get("duration"))
+		s.logger.Errorf("%s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	diningTables, err := s.ReservationService.FindAvailableDiningTables(r.Context(), partySize, startsAt, duration)
+	if err != nil {
+		s.logger.Errorf("error finding available dining tables: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, diningTables)
+}
+
+func (s *Server) HandleGetReservation(w http.ResponseWriter, r *http.Request) {
+	reservationID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing reservation id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	reservation, err := s.ReservationService.FindReservation(r.Context(), reservationID)
+	if err != nil {
+		s.logger.Errorf("error finding reservation: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newReservationResponse(reservation))
+}
+
+func (s *Server) HandleAssignDiningTable(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		DiningTableID id.ID `json:"dining_table_id"`
+	}
+
+	reservationID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing reservation id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	reservation, err := s.ReservationService.AssignDiningTable(r.Context(), reservationID, req.DiningTableID)
+	if err != nil {
+		s.logger.Errorf("error assigning dining table: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newReservationResponse(reservation))
+}
+
+func (s *Server) HandleConfirmReservation(w http.ResponseWriter, r *http.Request) {
+	s.updateReservation(w, r, s.ReservationService.ConfirmReservation)
+}
+
+// HandleSeatReservation opens a table on the dining table of the reservation, the response links it.
+func (s *Server) HandleSeatReservation(w http.ResponseWriter, r *http.Request) {
+	s.updateReservation(w, r, s.ReservationService.SeatReservation)
+}
+
+func (s *Server) HandleMarkNoShow(w http.ResponseWriter, r *http.Request) {
+	s.updateReservation(w, r, s.ReservationService.MarkNoShow)
+}
+
+func (s *Server) HandleCancelReservation(w http.ResponseWriter, r *http.Request) {
+	s.updateReservation(w, r, s.ReservationService.CancelReservation)
+}
+
+func (s *Server) updateReservation(w http.ResponseWriter, r *http.Request, update func(ctx context.Context, reservationID id.ID) (domain.Reservation, error)) {
+	reservationID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing reservation id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	reservation, err := update(r.Context(), reservationID)
+	if err != nil {
+		s.logger.Errorf("error updating reservation: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newReservationResponse(reservation))
+}
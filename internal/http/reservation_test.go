@@ -0,0 +1,216 @@
+package http_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reservationResponse struct {
+	ID            id.ID
+	GuestName     string
+	PartySize     int
+	StartsAt      time.Time
+	Duration      string
+	EndsAt        time.Time
+	DiningTableID id.ID
+	TableID       id.ID
+	Status        domain.ReservationStatus
+}
+
+func TestBookReservationHandler(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	diningTable := MustPresaveDiningTable(t, repos, 4)
+	dinner := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+
+	tt := []struct {
+		testName string
+		body     string
+		status   int
+	}{
+		{testName: "valid reservation", body: fmt.Sprintf(`{"guest_name":"Smith","contact":"555-0100","party_size":4,"starts_at":%q,"duration":"1h30m","dining_table_id":%q}`, dinner.Format(time.RFC3339), diningTable.ID), status: http.StatusCreated},
+		{testName: "without dining table", body: fmt.Sprintf(`{"guest_name":"Doe","party_size":6,"starts_at":%q,"duration":"2h"}`, dinner.Format(time.RFC3339)), status: http.StatusCreated},
+		{testName: "reserved dining table", body: fmt.Sprintf(`{"guest_name":"Roe","party_size":2,"starts_at":%q,"duration":"1h","dining_table_id":%q}`, dinner.Add(time.Hour).Format(time.RFC3339), diningTable.ID), status: http.StatusConflict},
+		{testName: "party too large", body: fmt.Sprintf(`{"guest_name":"Roe","party_size":5,"starts_at":%q,"duration":"1h","dining_table_id":%q}`, dinner.Add(3*time.Hour).Format(time.RFC3339), diningTable.ID), status: http.StatusForbidden},
+		{testName: "in the past", body: fmt.Sprintf(`{"guest_name":"Roe","party_size":2,"starts_at":%q,"duration":"1h"}`, dinner.Add(-48*time.Hour).Format(time.RFC3339)), status: http.StatusForbidden},
+		{testName: "invalid duration", body: fmt.Sprintf(`{"guest_name":"Roe","party_size":2,"starts_at":%q,"duration":"long"}`, dinner.Format(time.RFC3339)), status: http.StatusBadRequest},
+		{testName: "malformed body", body: `{"guest_name":`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/reservation", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+
+			s.HandleBookReservation(w, r)
+
+			require.Equal(t, tc.status, w.Result().StatusCode)
+		})
+	}
+
+	query := url.Values{"from": {dinner.Format(time.RFC3339)}, "to": {dinner.Add(24 * time.Hour).Format(time.RFC3339)}}
+	r := httptest.NewRequest(http.MethodGet, "/reservation?"+query.Encode(), nil)
+	w := httptest.NewRecorder()
+
+	s.HandleGetReservations(w, r)
+
+	reservations, statusCode := MustParseReponse[[]reservationResponse](t, w)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, reservations, 2)
+	assert.Equal(t, "Smith", reservations[0].GuestName)
+	assert.Equal(t, "1h30m0s", reservations[0].Duration)
+	assert.True(t, dinner.Add(90*time.Minute).Equal(reservations[0].EndsAt), "invalid end of the reservation")
+	assert.Equal(t, domain.ReservationStatusBooked, reservations[0].Status)
+
+	r = httptest.NewRequest(http.MethodGet, "/reservation?from=today", nil)
+	w = httptest.NewRecorder()
+
+	s.HandleGetReservations(w, r)
+
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+}
+
+func TestGetAvailabilityHandler(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	reserved := MustPresaveDiningTable(t, repos, 4)
+	free := MustPresaveDiningTable(t, repos, 6)
+	MustPresaveDiningTable(t, repos, 2)
+	ctx := context.Background()
+	dinner := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+
+	_, err := s.ReservationService.BookReservation(ctx, domain.Reservation{GuestName: "Smith", PartySize: 4, StartsAt: dinner, Duration: 2 * time.Hour, DiningTableID: reserved.ID})
+	require.NoError(t, err, "Initial setup failed")
+
+	getAvailability := func(query url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/reservation/availability?"+query.Encode(), nil)
+		w := httptest.NewRecorder()
+
+		s.HandleGetAvailability(w, r)
+
+		return w
+	}
+
+	diningTables, statusCode := MustParseReponse[[]domain.DiningTable](t, getAvailability(url.Values{"party_size": {"3"}, "starts_at": {dinner.Add(time.Hour).Format(time.RFC3339)}, "duration": {"2h"}}))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, []domain.DiningTable{free}, diningTables)
+
+	diningTables, statusCode = MustParseReponse[[]domain.DiningTable](t, getAvailability(url.Values{"party_size": {"3"}, "starts_at": {dinner.Add(2 * time.Hour).Format(time.RFC3339)}, "duration": {"2h"}}))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, []domain.DiningTable{reserved, free}, diningTables)
+
+	tt := []struct {
+		testName string
+		query    url.Values
+		status   int
+	}{
+		{testName: "invalid party size", query: url.Values{"party_size": {"many"}, "starts_at": {dinner.Format(time.RFC3339)}, "duration": {"2h"}}, status: http.StatusBadRequest},
+		{testName: "invalid start", query: url.Values{"party_size": {"2"}, "starts_at": {"tonight"}, "duration": {"2h"}}, status: http.StatusBadRequest},
+		{testName: "invalid duration", query: url.Values{"party_size": {"2"}, "starts_at": {dinner.Format(time.RFC3339)}}, status: http.StatusBadRequest},
+		{testName: "empty party", query: url.Values{"party_size": {"0"}, "starts_at": {dinner.Format(time.RFC3339)}, "duration": {"2h"}}, status: http.StatusForbidden},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			require.Equal(t, tc.status, getAvailability(tc.query).Result().StatusCode)
+		})
+	}
+}
+
+func TestReservationLifecycleHandlers(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	ctx := context.Background()
+	diningTable := MustPresaveDiningTable(t, repos, 4)
+	dinner := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+
+	reservation, err := s.ReservationService.BookReservation(ctx, domain.Reservation{GuestName: "Smith", PartySize: 3, StartsAt: dinner, Duration: 2 * time.Hour})
+	require.NoError(t, err, "Initial setup failed")
+	cancelled, err := s.ReservationService.BookReservation(ctx, domain.Reservation{GuestName: "Doe", PartySize: 2, StartsAt: dinner, Duration: time.Hour})
+	require.NoError(t, err, "Initial setup failed")
+
+	update := func(handler http.HandlerFunc, reservationID string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/reservation/"+reservationID, nil)
+		r.SetPathValue("id", reservationID)
+		w := httptest.NewRecorder()
+
+		handler(w, r)
+
+		return w
+	}
+
+	assign := func(reservationID id.ID, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPut, "/reservation/"+reservationID.String()+"/dining_table", strings.NewReader(body))
+		r.SetPathValue("id", reservationID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleAssignDiningTable(w, r)
+
+		return w
+	}
+
+	// The party cannot be seated until a dining table is assigned.
+	require.Equal(t, http.StatusForbidden, update(s.HandleSeatReservation, reservation.ID.String()).Result().StatusCode)
+
+	confirmed, statusCode := MustParseReponse[reservationResponse](t, update(s.HandleConfirmReservation, reservation.ID.String()))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, domain.ReservationStatusConfirmed, confirmed.Status)
+
+	assigned, statusCode := MustParseReponse[reservationResponse](t, assign(reservation.ID, fmt.Sprintf(`{"dining_table_id":%q}`, diningTable.ID)))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, diningTable.ID, assigned.DiningTableID)
+
+	require.Equal(t, http.StatusNotFound, assign(reservation.ID, fmt.Sprintf(`{"dining_table_id":%q}`, id.New())).Result().StatusCode)
+	require.Equal(t, http.StatusConflict, assign(cancelled.ID, fmt.Sprintf(`{"dining_table_id":%q}`, diningTable.ID)).Result().StatusCode)
+	require.Equal(t, http.StatusBadRequest, assign(reservation.ID, `{"dining_table_id":`).Result().StatusCode)
+
+	seated, statusCode := MustParseReponse[reservationResponse](t, update(s.HandleSeatReservation, reservation.ID.String()))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, domain.ReservationStatusSeated, seated.Status)
+
+	table, err := s.TableService.FindTable(ctx, seated.TableID)
+	require.NoError(t, err, "seated table not opened")
+	assert.Equal(t, diningTable.ID, table.DiningTableID)
+	assert.Equal(t, 3, table.GuestCount)
+
+	r := httptest.NewRequest(http.MethodGet, "/reservation/"+reservation.ID.String(), nil)
+	r.SetPathValue("id", reservation.ID.String())
+	w := httptest.NewRecorder()
+
+	s.HandleGetReservation(w, r)
+
+	saved, statusCode := MustParseReponse[reservationResponse](t, w)
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, seated, saved)
+
+	tt := []struct {
+		testName      string
+		handler       http.HandlerFunc
+		reservationID string
+		status        int
+	}{
+		{testName: "cancel", handler: s.HandleCancelReservation, reservationID: cancelled.ID.String(), status: http.StatusOK},
+		{testName: "cancel twice", handler: s.HandleCancelReservation, reservationID: cancelled.ID.String(), status: http.StatusForbidden},
+		{testName: "no-show of a seated party", handler: s.HandleMarkNoShow, reservationID: reservation.ID.String(), status: http.StatusForbidden},
+		{testName: "seat twice", handler: s.HandleSeatReservation, reservationID: reservation.ID.String(), status: http.StatusForbidden},
+		{testName: "unknown reservation", handler: s.HandleConfirmReservation, reservationID: id.New().String(), status: http.StatusNotFound},
+		{testName: "invalid reservation id", handler: s.HandleConfirmReservation, reservationID: "invalid", status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			require.Equal(t, tc.status, update(tc.handler, tc.reservationID).Result().StatusCode)
+		})
+	}
+}
@@ -22,25 +22,103 @@ type logger interface {
 type tableService interface {
 	FindTable(ctx context.Context, tableID id.ID) (domain.Table, error)
 	FindOpenedTables(ctx context.Context) ([]domain.Table, error)
-	OpenTable(ctx context.Context) (domain.Table, error)
+	FindPreparationsByStatus(ctx context.Context, status domain.PreparationStatus) ([]domain.TablePreparation, error)
+	FindStationQueue(ctx context.Context, stationID id.ID) ([]domain.Preparation, error)
+	FindOrder(ctx context.Context, orderID id.ID) (domain.Order, error)
+	FindPreparation(ctx context.Context, preparationID id.ID) (domain.Preparation, error)
+	FindKitchenTimings(ctx context.Context, from time.Time, to time.Time) (domain.TimingReport, error)
+	OpenTable(ctx context.Context, diningTableID id.ID, guestCount int) (domain.Table, error)
 	CloseTable(ctx context.Context, tableID id.ID) error
 	FinishPreparation(ctx context.Context, preparationID id.ID) error
 	ServePreparation(ctx context.Context, preparationID id.ID) error
 	StartPreparation(ctx context.Context, preparationID id.ID) error
-	TakeOrder(ctx context.Context, tableID id.ID, menuItems []domain.MenuItem) (domain.Order, error)
+	TakeOrder(ctx context.Context, tableID id.ID, items []domain.OrderItem) (domain.Order, error)
+	AbortOrder(ctx context.Context, orderID id.ID) error
+	FireCourse(ctx context.Context, tableID id.ID, course int) ([]domain.Preparation, error)
+	AbortPreparation(ctx context.Context, preparationID id.ID) error
 }
 
 type menuService interface {
 	FindAllMenuItems(ctx context.Context) ([]domain.MenuItem, error)
 	FindMenuItems(ctx context.Context, ids []id.ID) ([]domain.MenuItem, error)
 	AddItemToCategory(ctx context.Context, categoryID id.ID, itemID id.ID) error
+	RemoveItemFromCategory(ctx context.Context, categoryID id.ID, itemID id.ID) error
 	CreateCategory(ctx context.Context, name string) (domain.MenuCategory, error)
-	CreateMenuItem(ctx context.Context, name string, price int) (domain.MenuItem, error)
+	FindCategory(ctx context.Context, categoryID id.ID) (domain.MenuCategory, error)
+	FindAllCategories(ctx context.Context) ([]domain.MenuCategory, error)
+	CreateMenuItem(ctx context.Context, name string, price int, taxCategory string) (domain.MenuItem, error)
+	AddModifierGroup(ctx context.Context, itemID id.ID, name string, minSelections int, maxSelections int, options []domain.ModifierOption) (domain.ModifierGroup, error)
+	RemoveModifierGroup(ctx context.Context, itemID id.ID, groupID id.ID) error
+	MarkMenuItemAvailable(ctx context.Context, itemID id.ID, portions *int) (domain.MenuItem, error)
+	MarkMenuItemUnavailable(ctx context.Context, itemID id.ID) (domain.MenuItem, error)
 }
 
 type billService interface {
+	FindBill(ctx context.Context, billID id.ID) (domain.Bill, error)
+	FindTableBills(ctx context.Context, tableID id.ID) ([]domain.Bill, error)
 	GenerateBill(ctx context.Context, table domain.Table) (domain.Bill, error)
-	PayBill(ctx context.Context, billID id.ID, amount int) error
+	PayBill(ctx context.Context, billID id.ID, tender domain.TenderType, amount int, tip int, tendered int) (domain.Payment, error)
+	RefundPayment(ctx context.Context, billID id.ID, paymentID id.ID, preparationID id.ID, reason string) (domain.Payment, error)
+	SplitBillByItems(ctx context.Context, billID id.ID, groups [][]id.ID) ([]domain.Bill, error)
+	SplitBillBySeat(ctx context.Context, billID id.ID) ([]domain.Bill, error)
+	SplitBillEqually(ctx context.Context, billID id.ID, shares int) ([]domain.Bill, error)
+	DiscountBill(ctx context.Context, billID id.ID, percent float64, amount int, reason string) (domain.Bill, error)
+	TableSalesReport(ctx context.Context, tableID id.ID) (domain.SalesReport, error)
+	IsTableSettled(ctx context.Context, tableID id.ID) (bool, error)
+}
+
+type diningTableService interface {
+	CreateDiningTable(ctx context.Context, name string, capacity int, zone string) (domain.DiningTable, error)
+	FindDiningTable(ctx context.Context, tableID id.ID) (domain.DiningTable, error)
+	FindAllDiningTables(ctx context.Context) ([]domain.DiningTable, error)
+}
+
+type promotionService interface {
+	CreatePromotion(ctx context.Context, promotion domain.Promotion) (domain.Promotion, error)
+	FindPromotion(ctx context.Context, promotionID id.ID) (domain.Promotion, error)
+	FindAllPromotions(ctx context.Context) ([]domain.Promotion, error)
+	SetPromotionActive(ctx context.Context, promotionID id.ID, active bool) (domain.Promotion, error)
+}
+
+type inventoryService interface {
+	CreateIngredient(ctx context.Context, name string, unit domain.Unit, stock int, lowStockThreshold *int) (domain.Ingredient, error)
+	FindIngredient(ctx context.Context, ingredientID id.ID) (domain.Ingredient, error)
+	FindAllIngredients(ctx context.Context) ([]domain.Ingredient, error)
+	CountStock(ctx context.Context, ingredientID id.ID, stock int) (domain.Ingredient, error)
+	ReceiveDelivery(ctx context.Context, ingredientID id.ID, quantity int) (domain.Ingredient, error)
+	AdjustStock(ctx context.Context, ingredientID id.ID, quantity int, reason string) (domain.Ingredient, error)
+	FindStockMovements(ctx context.Context, ingredientID id.ID) ([]domain.StockMovement, error)
+	SetRecipe(ctx context.Context, menuItemID id.ID, lines []domain.RecipeLine) (domain.Recipe, error)
+	FindRecipe(ctx context.Context, menuItemID id.ID) (domain.Recipe, error)
+}
+
+type stationService interface {
+	CreateStation(ctx context.Context, name string) (domain.Station, error)
+	FindStation(ctx context.Context, stationID id.ID) (domain.Station, error)
+	FindAllStations(ctx context.Context) ([]domain.Station, error)
+	RouteToStation(ctx context.Context, stationID id.ID, menuItemIDs []id.ID, categoryIDs []id.ID) (domain.Station, error)
+}
+
+type reservationService interface {
+	BookReservation(ctx context.Context, reservation domain.Reservation) (domain.Reservation, error)
+	FindReservation(ctx context.Context, reservationID id.ID) (domain.Reservation, error)
+	FindReservations(ctx context.Context, from time.Time, to time.Time) ([]domain.Reservation, error)
+	FindAvailableDiningTables(ctx context.Context, partySize int, startsAt time.Time, duration time.Duration) ([]domain.DiningTable, error)
+	AssignDiningTable(ctx context.Context, reservationID id.ID, diningTableID id.ID) (domain.Reservation, error)
+	ConfirmReservation(ctx context.Context, reservationID id.ID) (domain.Reservation, error)
+	SeatReservation(ctx context.Context, reservationID id.ID) (domain.Reservation, error)
+	MarkNoShow(ctx context.Context, reservationID id.ID) (domain.Reservation, error)
+	CancelReservation(ctx context.Context, reservationID id.ID) (domain.Reservation, error)
+}
+
+type waitlistService interface {
+	EstimateWait(ctx context.Context, partySize int) (time.Duration, error)
+	JoinWaitlist(ctx context.Context, name string, contact string, partySize int) (domain.WaitlistEntry, error)
+	FindWaitlist(ctx context.Context) ([]domain.WaitlistEntry, error)
+	FindWaitlistEntry(ctx context.Context, entryID id.ID) (domain.WaitlistEntry, error)
+	SuggestNextParty(ctx context.Context, diningTableID id.ID) (domain.WaitlistEntry, error)
+	SeatParty(ctx context.Context, entryID id.ID, diningTableID id.ID) (domain.WaitlistEntry, error)
+	LeaveWaitlist(ctx context.Context, entryID id.ID) (domain.WaitlistEntry, error)
 }
 
 type middleware func(http.Handler) http.Handler
@@ -82,35 +160,72 @@ func (r *router) HandleFunc(pattern string, handler http.HandlerFunc) {
 	r.ServeMux.Handle(fullPattern, finalHandler)
 }
 
+// Config holds the settings of the underlying HTTP server.
+type Config struct {
+	Addr            string
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	IdleTimeout     time.Duration
+	ShutdownTimeout time.Duration
+}
+
+// Services holds the domain services the handlers of the server call.
+type Services struct {
+	TableService       tableService
+	MenuService        menuService
+	BillService        billService
+	DiningTableService diningTableService
+	PromotionService   promotionService
+	InventoryService   inventoryService
+	StationService     stationService
+	ReservationService reservationService
+	WaitlistService    waitlistService
+}
+
 type Server struct {
 	server *http.Server
 	router *router
 
+	shutdownTimeout time.Duration
+
 	logger logger
 
-	TableService tableService
-	MenuService  menuService
-	BillService  billService
+	Services
+
+	events *EventStream
 
 	URL string
 }
 
-func NewServer(logger logger, tableService tableService, menuService menuService, billService billService) *Server {
+func NewServer(config Config, logger logger, services Services, events *EventStream) *Server {
 	s := &Server{
-		logger:       logger,
-		TableService: tableService,
-		MenuService:  menuService,
-		BillService:  billService,
+		shutdownTimeout: config.ShutdownTimeout,
+		logger:          logger,
+		Services:        services,
+		events:          events,
 	}
 	router := newRouter().group("/api", s.logMiddleware)
 	s.registerTableRoutes(router)
+	s.registerPreparationRoutes(router)
 	s.registerMenuRoutes(router)
 	s.registerBillRoutes(router)
+	s.registerDiningTableRoutes(router)
+	s.registerPromotionRoutes(router)
+	s.registerInventoryRoutes(router)
+	s.registerStationRoutes(router)
+	s.registerReservationRoutes(router)
+	s.registerWaitlistRoutes(router)
+	s.registerEventRoutes(router)
 
 	server := &http.Server{
-		Addr:    ":8080",
-		Handler: router,
+		Addr:         config.Addr,
+		Handler:      router,
+		ReadTimeout:  config.ReadTimeout,
+		WriteTimeout: config.WriteTimeout,
+		IdleTimeout:  config.IdleTimeout,
 	}
+	// Event streams never end on their own, close them so that shutdown does not wait for them.
+	server.RegisterOnShutdown(events.Close)
 
 	s.server = server
 	s.router = router
@@ -136,7 +251,11 @@ func (s *Server) Run(ctx context.Context) error {
 
 	g.Go(func() error {
 		<-gCtx.Done()
-		return s.server.Shutdown(gCtx)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+		defer cancel()
+
+		return s.server.Shutdown(shutdownCtx)
 	})
 
 	return g.Wait()
@@ -158,11 +277,23 @@ func domainErrorToHTTPStatus(err error) int {
 		return http.StatusNotFound
 	case domain.EINVALID:
 		return http.StatusForbidden
+	case domain.ECONFLICT, domain.ESTALE:
+		return http.StatusConflict
 	default:
 		return http.StatusInternalServerError
 	}
 }
 
+func parsePathID(r *http.Request, name string) (id.ID, error) {
+	value := r.PathValue(name)
+	parsedID, err := id.Parse(value)
+	if err != nil {
+		return id.NilID(), fmt.Errorf("invalid %s: %q", name, value)
+	}
+
+	return parsedID, nil
+}
+
 func writeError(w http.ResponseWriter, status int, err error) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -180,6 +311,10 @@ func (w *logResponseWriter) WriteHeader(status int) {
 	w.ResponseWriter.WriteHeader(status)
 }
 
+func (w *logResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func (s *Server) logMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http/httptest"
 	"order_manager/internal/domain"
+	"order_manager/internal/event"
 	domainHttp "order_manager/internal/http"
 	"order_manager/internal/sqlite"
 	"testing"
@@ -19,9 +20,15 @@ func (nopLogger) Infof(format string, args ...interface{})  {}
 func (nopLogger) Errorf(format string, args ...interface{}) {}
 
 type repositories struct {
-	Table domain.TableRepository
-	Menu  domain.MenuRepository
-	Bill  domain.BillRepository
+	Table       domain.TableRepository
+	Menu        domain.MenuRepository
+	Bill        domain.BillRepository
+	DiningTable domain.DiningTableRepository
+	Promotion   domain.PromotionRepository
+	Inventory   domain.InventoryRepository
+	Station     domain.StationRepository
+	Reservation domain.ReservationRepository
+	Waitlist    domain.WaitlistRepository
 }
 
 func MustNewRepositories(t *testing.T) repositories {
@@ -36,11 +43,23 @@ func MustNewRepositories(t *testing.T) repositories {
 	tableRepo := sqlite.NewTable(db)
 	menuRepo := sqlite.NewMenu(db)
 	billRepo := sqlite.NewBill(db)
+	diningTableRepo := sqlite.NewDiningTable(db)
+	promotionRepo := sqlite.NewPromotion(db)
+	inventoryRepo := sqlite.NewInventory(db)
+	stationRepo := sqlite.NewStation(db)
+	reservationRepo := sqlite.NewReservation(db)
+	waitlistRepo := sqlite.NewWaitlist(db)
 
 	return repositories{
-		Table: tableRepo,
-		Menu:  menuRepo,
-		Bill:  billRepo,
+		Table:       tableRepo,
+		Menu:        menuRepo,
+		Bill:        billRepo,
+		DiningTable: diningTableRepo,
+		Promotion:   promotionRepo,
+		Inventory:   inventoryRepo,
+		Station:     stationRepo,
+		Reservation: reservationRepo,
+		Waitlist:    waitlistRepo,
 	}
 }
 
@@ -49,11 +68,38 @@ func MustNewServer(t *testing.T, repos repositories) *domainHttp.Server {
 
 	logger := nopLogger{}
 
-	tableService := domain.NewTableService(repos.Table)
-	menuService := domain.NewMenuService(repos.Menu)
-	billService := domain.NewBillService(repos.Bill)
+	bus := event.NewBus()
+	t.Cleanup(bus.Close)
+
+	events := domainHttp.NewEventStream()
+	bus.Subscribe(events.HandleEvent)
+
+	inventoryService := domain.NewInventoryService(repos.Inventory, repos.Menu, bus)
+	stationService := domain.NewStationService(repos.Station, repos.Menu, bus)
+	tableService := domain.NewTableService(repos.Table, repos.DiningTable, bus).WithMenu(repos.Menu).WithInventory(inventoryService).WithStations(stationService)
+	menuService := domain.NewMenuService(repos.Menu, bus)
+	billService := domain.NewBillService(repos.Bill, bus).WithPromotions(repos.Promotion)
+	diningTableService := domain.NewDiningTableService(repos.DiningTable, bus)
+	promotionService := domain.NewPromotionService(repos.Promotion, repos.Menu, bus)
+	reservationService := domain.NewReservationService(repos.Reservation, repos.DiningTable, tableService, bus)
+	waitlistService := domain.NewWaitlistService(repos.Waitlist, repos.Table, repos.DiningTable, tableService, bus)
+	tableService.WithWaitlist(waitlistService)
+
+	config := domainHttp.Config{Addr: ":8080"}
+
+	services := domainHttp.Services{
+		TableService:       tableService,
+		MenuService:        menuService,
+		BillService:        billService,
+		DiningTableService: diningTableService,
+		PromotionService:   promotionService,
+		InventoryService:   inventoryService,
+		StationService:     stationService,
+		ReservationService: reservationService,
+		WaitlistService:    waitlistService,
+	}
 
-	return domainHttp.NewServer(logger, tableService, menuService, billService)
+	return domainHttp.NewServer(config, logger, services, events)
 }
 
 func MustParseReponse[T any](t *testing.T, w *httptest.ResponseRecorder) (body T, statusCode int) {
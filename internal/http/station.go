@@ -0,0 +1,129 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"order_manager/internal/id"
+)
+
+func (s *Server) registerStationRoutes(r *router) {
+	stationRouter := r.group("/station")
+
+	stationRouter.HandleFunc("POST /", s.HandleAddStation)
+	stationRouter.HandleFunc("GET /", s.HandleGetStations)
+	stationRouter.HandleFunc("GET /{id}", s.HandleGetStation)
+	stationRouter.HandleFunc("PUT /{id}/routes", s.HandleRouteToStation)
+	stationRouter.HandleFunc("GET /{id}/queue", s.HandleGetStationQueue)
+}
+
+func (s *Server) HandleAddStation(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		Name string `json:"name"`
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	station, err := s.StationService.CreateStation(r.Context(), req.Name)
+	if err != nil {
+		s.logger.Errorf("error creating station: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusCreated, station)
+}
+
+func (s *Server) HandleGetStations(w http.ResponseWriter, r *http.Request) {
+	stations, err := s.StationService.FindAllStations(r.Context())
+	if err != nil {
+		s.logger.Errorf("error finding stations: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, stations)
+}
+
+func (s *Server) HandleGetStation(w http.ResponseWriter, r *http.Request) {
+	stationID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing station id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	station, err := s.StationService.FindStation(r.Context(), stationID)
+	if err != nil {
+		s.logger.Errorf("error finding station: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, station)
+}
+
+func (s *Server) HandleRouteToStation(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		MenuItemIDs []id.ID `json:"menu_item_ids"`
+		CategoryIDs []id.ID `json:"category_ids"`
+	}
+
+	stationID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing station id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if req.MenuItemIDs == nil {
+		req.MenuItemIDs = make([]id.ID, 0)
+	}
+	if req.CategoryIDs == nil {
+		req.CategoryIDs = make([]id.ID, 0)
+	}
+
+	station, err := s.StationService.RouteToStation(r.Context(), stationID, req.MenuItemIDs, req.CategoryIDs)
+	if err != nil {
+		s.logger.Errorf("error routing to station: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, station)
+}
+
+func (s *Server) HandleGetStationQueue(w http.ResponseWriter, r *http.Request) {
+	stationID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing station id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if _, err := s.StationService.FindStation(r.Context(), stationID); err != nil {
+		s.logger.Errorf("error finding station: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	preparations, err := s.TableService.FindStationQueue(r.Context(), stationID)
+	if err != nil {
+		s.logger.Errorf("error finding station queue: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, preparations)
+}
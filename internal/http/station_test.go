@@ -0,0 +1,209 @@
+package http_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stationProgressResponse struct {
+	StationID id.ID `json:"station_id"`
+	Remaining int   `json:"remaining"`
+}
+
+type orderStationsResponse struct {
+	OrderID  id.ID                     `json:"order_id"`
+	Stations []stationProgressResponse `json:"stations"`
+	Ready    bool                      `json:"ready"`
+}
+
+func TestAddAndGetStations(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+
+	tt := []struct {
+		testName string
+		body     string
+		status   int
+	}{
+		{testName: "valid station", body: `{"name":"grill"}`, status: http.StatusCreated},
+		{testName: "other station", body: `{"name":"bar"}`, status: http.StatusCreated},
+		{testName: "empty name", body: `{"name":""}`, status: http.StatusForbidden},
+		{testName: "malformed body", body: `{"name":`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/station", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+
+			s.HandleAddStation(w, r)
+
+			require.Equal(t, tc.status, w.Result().StatusCode)
+		})
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/station", nil)
+	w := httptest.NewRecorder()
+
+	s.HandleGetStations(w, r)
+
+	stations, statusCode := MustParseReponse[[]domain.Station](t, w)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, stations, 2)
+	assert.Equal(t, "bar", stations[0].Name)
+	assert.Equal(t, "grill", stations[1].Name)
+
+	r = httptest.NewRequest(http.MethodGet, "/station/"+stations[1].ID.String(), nil)
+	r.SetPathValue("id", stations[1].ID.String())
+	w = httptest.NewRecorder()
+
+	s.HandleGetStation(w, r)
+
+	station, statusCode := MustParseReponse[domain.Station](t, w)
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, stations[1], station)
+
+	r = httptest.NewRequest(http.MethodGet, "/station/"+id.New().String(), nil)
+	r.SetPathValue("id", id.New().String())
+	w = httptest.NewRecorder()
+
+	s.HandleGetStation(w, r)
+
+	require.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+}
+
+func TestRouteToStation(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	ctx := context.Background()
+
+	burger, err := s.MenuService.CreateMenuItem(ctx, "burger", 1200, "")
+	require.NoError(t, err)
+	mains, err := s.MenuService.CreateCategory(ctx, "mains")
+	require.NoError(t, err)
+	grill, err := s.StationService.CreateStation(ctx, "grill")
+	require.NoError(t, err)
+	bar, err := s.StationService.CreateStation(ctx, "bar")
+	require.NoError(t, err)
+
+	tt := []struct {
+		testName  string
+		stationID id.ID
+		body      string
+		status    int
+	}{
+		{testName: "valid routes", stationID: grill.ID, body: fmt.Sprintf(`{"menu_item_ids":["%s"],"category_ids":["%s"]}`, burger.ID, mains.ID), status: http.StatusOK},
+		{testName: "routed to another station", stationID: bar.ID, body: fmt.Sprintf(`{"menu_item_ids":["%s"]}`, burger.ID), status: http.StatusConflict},
+		{testName: "unknown menu item", stationID: bar.ID, body: fmt.Sprintf(`{"menu_item_ids":["%s"]}`, id.New()), status: http.StatusNotFound},
+		{testName: "unknown station", stationID: id.New(), body: `{}`, status: http.StatusNotFound},
+		{testName: "malformed body", stationID: bar.ID, body: `{"menu_item_ids":`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/station/"+tc.stationID.String()+"/routes", strings.NewReader(tc.body))
+			r.SetPathValue("id", tc.stationID.String())
+			w := httptest.NewRecorder()
+
+			s.HandleRouteToStation(w, r)
+
+			require.Equal(t, tc.status, w.Result().StatusCode)
+		})
+	}
+
+	saved, err := s.StationService.FindStation(ctx, grill.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []id.ID{burger.ID}, saved.MenuItemIDs)
+	assert.Equal(t, []id.ID{mains.ID}, saved.CategoryIDs)
+}
+
+func TestStationQueueAndOrderRollup(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	ctx := context.Background()
+	diningTable := MustPresaveDiningTable(t, repos, 4)
+
+	burger, err := s.MenuService.CreateMenuItem(ctx, "burger", 1200, "")
+	require.NoError(t, err)
+	beer, err := s.MenuService.CreateMenuItem(ctx, "beer", 500, "")
+	require.NoError(t, err)
+	grill, err := s.StationService.CreateStation(ctx, "grill")
+	require.NoError(t, err)
+	bar, err := s.StationService.CreateStation(ctx, "bar")
+	require.NoError(t, err)
+	_, err = s.StationService.RouteToStation(ctx, grill.ID, []id.ID{burger.ID}, []id.ID{})
+	require.NoError(t, err)
+	_, err = s.StationService.RouteToStation(ctx, bar.ID, []id.ID{beer.ID}, []id.ID{})
+	require.NoError(t, err)
+
+	table, err := s.TableService.OpenTable(ctx, diningTable.ID, 2)
+	require.NoError(t, err)
+	order, err := s.TableService.TakeOrder(ctx, table.ID, []domain.OrderItem{{MenuItem: burger, Quantity: 2}, {MenuItem: beer}})
+	require.NoError(t, err)
+
+	getQueue := func(stationID id.ID) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/station/"+stationID.String()+"/queue", nil)
+		r.SetPathValue("id", stationID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleGetStationQueue(w, r)
+
+		return w
+	}
+
+	getRollup := func(orderID id.ID) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/table/order/"+orderID.String()+"/stations", nil)
+		r.SetPathValue("id", orderID.String())
+		w := httptest.NewRecorder()
+
+		s.HandleGetOrderStations(w, r)
+
+		return w
+	}
+
+	queue, statusCode := MustParseReponse[[]domain.Preparation](t, getQueue(grill.ID))
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, queue, 2)
+	for _, prep := range queue {
+		assert.Equal(t, burger.ID, prep.MenuItem.ID)
+		assert.Equal(t, grill.ID, prep.StationID)
+	}
+
+	queue, statusCode = MustParseReponse[[]domain.Preparation](t, getQueue(bar.ID))
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, queue, 1)
+	assert.Equal(t, beer.ID, queue[0].MenuItem.ID)
+
+	require.Equal(t, http.StatusNotFound, getQueue(id.New()).Result().StatusCode)
+
+	rollup, statusCode := MustParseReponse[orderStationsResponse](t, getRollup(order.ID))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, order.ID, rollup.OrderID)
+	assert.Equal(t, []stationProgressResponse{{StationID: grill.ID, Remaining: 2}, {StationID: bar.ID, Remaining: 1}}, rollup.Stations)
+	assert.False(t, rollup.Ready)
+
+	for _, prep := range order.Preparations {
+		require.NoError(t, s.TableService.StartPreparation(ctx, prep.ID))
+		require.NoError(t, s.TableService.FinishPreparation(ctx, prep.ID))
+	}
+
+	queue, statusCode = MustParseReponse[[]domain.Preparation](t, getQueue(grill.ID))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Empty(t, queue)
+
+	rollup, statusCode = MustParseReponse[orderStationsResponse](t, getRollup(order.ID))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, []stationProgressResponse{{StationID: grill.ID}, {StationID: bar.ID}}, rollup.Stations)
+	assert.True(t, rollup.Ready)
+
+	require.Equal(t, http.StatusNotFound, getRollup(id.New()).Result().StatusCode)
+}
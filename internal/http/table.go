@@ -3,7 +3,9 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"order_manager/internal/domain"
 	"order_manager/internal/id"
+	"slices"
 )
 
 func (s *Server) registerTableRoutes(r *router) {
@@ -12,7 +14,13 @@ func (s *Server) registerTableRoutes(r *router) {
 	tableRouter.HandleFunc("GET /", s.HandleGetTables)
 	tableRouter.HandleFunc("POST /", s.HandleOpenTable)
 	tableRouter.HandleFunc("POST /order", s.HandleTakeOrder)
+	tableRouter.HandleFunc("POST /order/abort", s.HandleAbortOrder)
+	tableRouter.HandleFunc("GET /order/{id}/stations", s.HandleGetOrderStations)
+	tableRouter.HandleFunc("POST /course/fire", s.HandleFireCourse)
 	tableRouter.HandleFunc("POST /close", s.HandleCloseTable)
+	tableRouter.HandleFunc("GET /{id}/bills", s.HandleGetTableBills)
+	tableRouter.HandleFunc("GET /{id}/settlement", s.HandleGetTableSettlement)
+	tableRouter.HandleFunc("GET /{id}/sales", s.HandleGetTableSales)
 }
 
 func (s *Server) HandleGetTables(w http.ResponseWriter, r *http.Request) {
@@ -27,7 +35,19 @@ func (s *Server) HandleGetTables(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) HandleOpenTable(w http.ResponseWriter, r *http.Request) {
-	table, err := s.TableService.OpenTable(r.Context())
+	type reqBody struct {
+		DiningTableID id.ID `json:"dining_table_id"`
+		GuestCount    int   `json:"guest_count"`
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	table, err := s.TableService.OpenTable(r.Context(), req.DiningTableID, req.GuestCount)
 	if err != nil {
 		s.logger.Errorf("error creating table: %s\n", err)
 		writeError(w, domainErrorToHTTPStatus(err), err)
@@ -59,9 +79,20 @@ func (s *Server) HandleCloseTable(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) HandleTakeOrder(w http.ResponseWriter, r *http.Request) {
+	type orderItem struct {
+		MenuItemID id.ID   `json:"menu_item_id"`
+		OptionIDs  []id.ID `json:"option_ids"`
+		Seat       int     `json:"seat"`
+		Quantity   int     `json:"quantity"`
+		Note       string  `json:"note"`
+		Course     int     `json:"course"`
+	}
+
+	// Items without modifiers can be listed in menu_item_ids.
 	type reqBody struct {
-		TableID     id.ID   `json:"table_id"`
-		MenuItemIds []id.ID `json:"menu_item_ids"`
+		TableID     id.ID       `json:"table_id"`
+		MenuItemIds []id.ID     `json:"menu_item_ids"`
+		Items       []orderItem `json:"items"`
 	}
 
 	var req reqBody
@@ -71,14 +102,38 @@ func (s *Server) HandleTakeOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	menuItems, err := s.MenuService.FindMenuItems(r.Context(), req.MenuItemIds)
+	for _, itemID := range req.MenuItemIds {
+		req.Items = append(req.Items, orderItem{MenuItemID: itemID})
+	}
+
+	menuItemIDs := make([]id.ID, 0, len(req.Items))
+	for _, item := range req.Items {
+		if !slices.Contains(menuItemIDs, item.MenuItemID) {
+			menuItemIDs = append(menuItemIDs, item.MenuItemID)
+		}
+	}
+
+	menuItems, err := s.MenuService.FindMenuItems(r.Context(), menuItemIDs)
 	if err != nil {
 		s.logger.Errorf("error finding menu items: %s\n", err)
 		writeError(w, domainErrorToHTTPStatus(err), err)
 		return
 	}
 
-	order, err := s.TableService.TakeOrder(r.Context(), req.TableID, menuItems)
+	items := make([]domain.OrderItem, 0, len(req.Items))
+	for _, item := range req.Items {
+		idx := slices.IndexFunc(menuItems, func(menuItem domain.MenuItem) bool { return menuItem.ID == item.MenuItemID })
+		if idx == -1 {
+			err := domain.Errorf(domain.ENOTFOUND, "menu item %s not found", item.MenuItemID)
+			s.logger.Errorf("error finding menu items: %s\n", err)
+			writeError(w, domainErrorToHTTPStatus(err), err)
+			return
+		}
+
+		items = append(items, domain.OrderItem{MenuItem: menuItems[idx], OptionIDs: item.OptionIDs, Seat: item.Seat, Quantity: item.Quantity, Note: item.Note, Course: item.Course})
+	}
+
+	order, err := s.TableService.TakeOrder(r.Context(), req.TableID, items)
 	if err != nil {
 		s.logger.Errorf("error taking order: %s\n", err)
 		writeError(w, domainErrorToHTTPStatus(err), err)
@@ -88,9 +143,9 @@ func (s *Server) HandleTakeOrder(w http.ResponseWriter, r *http.Request) {
 	writeJSONBody(w, http.StatusOK, order)
 }
 
-func (s *Server) HandleStartPreparation(w http.ResponseWriter, r *http.Request) {
+func (s *Server) HandleAbortOrder(w http.ResponseWriter, r *http.Request) {
 	type reqBody struct {
-		PreparationID id.ID `json:"preparation_id"`
+		OrderID id.ID `json:"order_id"`
 	}
 
 	var req reqBody
@@ -100,9 +155,8 @@ func (s *Server) HandleStartPreparation(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := s.TableService.StartPreparation(r.Context(), req.PreparationID)
-	if err != nil {
-		s.logger.Errorf("error starting preparation: %s\n", err)
+	if err := s.TableService.AbortOrder(r.Context(), req.OrderID); err != nil {
+		s.logger.Errorf("error aborting order: %s\n", err)
 		writeError(w, domainErrorToHTTPStatus(err), err)
 		return
 	}
@@ -110,9 +164,10 @@ func (s *Server) HandleStartPreparation(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (s *Server) FinishPreparation(w http.ResponseWriter, r *http.Request) {
+func (s *Server) HandleFireCourse(w http.ResponseWriter, r *http.Request) {
 	type reqBody struct {
-		OrderID id.ID `json:"order_id"`
+		TableID id.ID `json:"table_id"`
+		Course  int   `json:"course"`
 	}
 
 	var req reqBody
@@ -122,34 +177,51 @@ func (s *Server) FinishPreparation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := s.TableService.FinishPreparation(r.Context(), req.OrderID)
+	preparations, err := s.TableService.FireCourse(r.Context(), req.TableID, req.Course)
 	if err != nil {
-		s.logger.Errorf("error finishing preparation: %s\n", err)
+		s.logger.Errorf("error firing course: %s\n", err)
 		writeError(w, domainErrorToHTTPStatus(err), err)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	writeJSONBody(w, http.StatusOK, preparations)
 }
 
-func (s *Server) HandleServePreparation(w http.ResponseWriter, r *http.Request) {
-	type reqBody struct {
-		PreparationID id.ID `json:"preparation_id"`
+type stationProgressResponse struct {
+	StationID id.ID `json:"station_id"`
+	Remaining int   `json:"remaining"`
+}
+
+// orderStationsResponse rolls the preparations of an order up per station.
+type orderStationsResponse struct {
+	OrderID  id.ID                     `json:"order_id"`
+	Stations []stationProgressResponse `json:"stations"`
+	Ready    bool                      `json:"ready"`
+}
+
+func newOrderStationsResponse(order domain.Order) orderStationsResponse {
+	res := orderStationsResponse{OrderID: order.ID, Stations: make([]stationProgressResponse, 0), Ready: order.IsReady()}
+	for _, station := range order.Stations() {
+		res.Stations = append(res.Stations, stationProgressResponse{StationID: station.StationID, Remaining: station.Remaining})
 	}
 
-	var req reqBody
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.logger.Errorf("error decoding request: %s\n", err)
+	return res
+}
+
+func (s *Server) HandleGetOrderStations(w http.ResponseWriter, r *http.Request) {
+	orderID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing order id: %s\n", err)
 		writeError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	err := s.TableService.ServePreparation(r.Context(), req.PreparationID)
+	order, err := s.TableService.FindOrder(r.Context(), orderID)
 	if err != nil {
-		s.logger.Errorf("error serving preparation: %s\n", err)
+		s.logger.Errorf("error finding order: %s\n", err)
 		writeError(w, domainErrorToHTTPStatus(err), err)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	writeJSONBody(w, http.StatusOK, newOrderStationsResponse(order))
 }
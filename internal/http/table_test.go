@@ -36,19 +36,71 @@ func MustPresaveTables(t *testing.T, repos repositories, tables []domain.Table)
 	}
 }
 
+func MustPresaveDiningTable(t *testing.T, repos repositories, capacity int) domain.DiningTable {
+	t.Helper()
+
+	diningTable := domain.DiningTable{ID: id.New(), Name: id.New().String(), Capacity: capacity}
+	err := repos.DiningTable.Save(context.Background(), diningTable)
+	require.NoError(t, err)
+
+	return diningTable
+}
+
 func TestOpenTableHandler(t *testing.T) {
-	repos := MustNewRepositories(t)
-	s := MustNewServer(t, repos)
+	t.Run("Success", func(t *testing.T) {
+		repos := MustNewRepositories(t)
+		s := MustNewServer(t, repos)
+		diningTable := MustPresaveDiningTable(t, repos, 4)
 
-	r := httptest.NewRequest(http.MethodPost, "/table", nil)
-	w := httptest.NewRecorder()
+		reqBody := fmt.Sprintf(`{"dining_table_id": "%s", "guest_count": 3}`, diningTable.ID)
+		r := httptest.NewRequest(http.MethodPost, "/table", strings.NewReader(reqBody))
+		w := httptest.NewRecorder()
+
+		s.HandleOpenTable(w, r)
+
+		body, statusCode := MustParseReponse[domain.Table](t, w)
+
+		require.Equal(t, http.StatusCreated, statusCode)
+		require.Equal(t, diningTable.ID, body.DiningTableID)
+		require.Equal(t, 3, body.GuestCount)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		tt := []struct {
+			testName           string
+			guestCount         int
+			unknownTable       bool
+			alreadyOpened      bool
+			expectedStatusCode int
+		}{
+			{testName: "dining table not found", guestCount: 2, unknownTable: true, expectedStatusCode: http.StatusNotFound},
+			{testName: "too many guests", guestCount: 5, expectedStatusCode: http.StatusForbidden},
+			{testName: "dining table occupied", guestCount: 2, alreadyOpened: true, expectedStatusCode: http.StatusConflict},
+		}
 
-	s.HandleOpenTable(w, r)
+		for _, tc := range tt {
+			t.Run(tc.testName, func(t *testing.T) {
+				repos := MustNewRepositories(t)
+				s := MustNewServer(t, repos)
+				diningTableID := MustPresaveDiningTable(t, repos, 4).ID
+				if tc.unknownTable {
+					diningTableID = id.New()
+				}
+				if tc.alreadyOpened {
+					_, err := s.TableService.OpenTable(context.Background(), diningTableID, 1)
+					require.NoError(t, err)
+				}
+
+				reqBody := fmt.Sprintf(`{"dining_table_id": "%s", "guest_count": %d}`, diningTableID, tc.guestCount)
+				r := httptest.NewRequest(http.MethodPost, "/table", strings.NewReader(reqBody))
+				w := httptest.NewRecorder()
 
-	res := w.Result()
-	defer res.Body.Close()
+				s.HandleOpenTable(w, r)
 
-	require.Equal(t, http.StatusCreated, res.StatusCode)
+				require.Equal(t, tc.expectedStatusCode, w.Result().StatusCode)
+			})
+		}
+	})
 }
 
 func TestGetTablesHandler(t *testing.T) {
@@ -310,9 +362,63 @@ func TestTakeOrder(t *testing.T) {
 				require.Equal(t, http.StatusOK, res.StatusCode)
 			})
 		}
+
+		t.Run("with modifier options", func(t *testing.T) {
+			repos := MustNewRepositories(t)
+			s := MustNewServer(t, repos)
+
+			table := domain.Table{ID: id.New(), Status: domain.TableStatusOpened, Orders: []domain.Order{}}
+			MustPresaveTables(t, repos, []domain.Table{table})
+
+			bacon := domain.ModifierOption{ID: id.New(), Name: "Bacon", PriceDelta: 200}
+			burger := domain.MenuItem{
+				ID:    id.New(),
+				Name:  "burger",
+				Price: 1000,
+				ModifierGroups: []domain.ModifierGroup{
+					{ID: id.New(), Name: "Extras", MinSelections: 0, MaxSelections: 1, Options: []domain.ModifierOption{bacon}},
+				},
+			}
+			fries := domain.MenuItem{ID: id.New(), Name: "fries", Price: 300}
+			require.NoError(t, repos.Menu.SaveItem(context.Background(), burger))
+			require.NoError(t, repos.Menu.SaveItem(context.Background(), fries))
+
+			reqBody := fmt.Sprintf(`{"table_id": "%s", "items": [{"menu_item_id": "%s", "option_ids": ["%s"]}], "menu_item_ids": ["%s"]}`, table.ID, burger.ID, bacon.ID, fries.ID)
+			r := httptest.NewRequest(http.MethodPost, "/table/order", strings.NewReader(reqBody))
+			w := httptest.NewRecorder()
+
+			s.HandleTakeOrder(w, r)
+
+			order, statusCode := MustParseReponse[domain.Order](t, w)
+			require.Equal(t, http.StatusOK, statusCode)
+			require.Len(t, order.Preparations, 2)
+			require.Equal(t, burger.ID, order.Preparations[0].MenuItem.ID)
+			require.Len(t, order.Preparations[0].Modifiers, 1)
+			require.Equal(t, "Bacon", order.Preparations[0].Modifiers[0].OptionName)
+			require.Equal(t, fries.ID, order.Preparations[1].MenuItem.ID)
+			require.Empty(t, order.Preparations[1].Modifiers)
+		})
 	})
 
 	t.Run("Failed", func(t *testing.T) {
+		t.Run("invalid modifier option", func(t *testing.T) {
+			repos := MustNewRepositories(t)
+			s := MustNewServer(t, repos)
+
+			table := domain.Table{ID: id.New(), Status: domain.TableStatusOpened, Orders: []domain.Order{}}
+			MustPresaveTables(t, repos, []domain.Table{table})
+
+			item := domain.MenuItem{ID: id.New(), Name: "item", Price: 100}
+			require.NoError(t, repos.Menu.SaveItem(context.Background(), item))
+
+			reqBody := fmt.Sprintf(`{"table_id": "%s", "items": [{"menu_item_id": "%s", "option_ids": ["%s"]}]}`, table.ID, item.ID, id.New())
+			r := httptest.NewRequest(http.MethodPost, "/table/order", strings.NewReader(reqBody))
+			w := httptest.NewRecorder()
+
+			s.HandleTakeOrder(w, r)
+
+			require.Equal(t, http.StatusForbidden, w.Result().StatusCode)
+		})
 
 		t.Run("menu item not found", func(t *testing.T) {
 			repos := MustNewRepositories(t)
@@ -417,116 +523,166 @@ func TestTakeOrder(t *testing.T) {
 	})
 }
 
-func TestStartPreparation(t *testing.T) {
-	t.Run("Success", func(t *testing.T) {
-		tt := []struct {
-			testName             string
-			tableFromPreparation func(prep domain.Preparation) domain.Table
-			preparation          domain.Preparation
-		}{
-			{
-				testName: "one table with one order and one preparation",
-				tableFromPreparation: func(prep domain.Preparation) domain.Table {
-					return domain.Table{
-						ID:     id.New(),
-						Status: domain.TableStatusOpened,
-						Orders: []domain.Order{
-							{
-								ID:           id.New(),
-								Status:       domain.OrderStatusTaken,
-								Preparations: []domain.Preparation{prep},
-							},
-						},
-					}
-				},
-				preparation: domain.Preparation{
-					ID:       id.New(),
-					MenuItem: domain.MenuItem{ID: id.New(), Name: "item", Price: 100},
-					Status:   domain.PreparationStatusPending,
-				},
-			},
-		}
+func TestTakeOrderWithQuantities(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	ctx := context.Background()
 
-		for _, tc := range tt {
-			t.Run(tc.testName, func(t *testing.T) {
-				repos := MustNewRepositories(t)
-				s := MustNewServer(t, repos)
-				table := tc.tableFromPreparation(tc.preparation)
-				MustPresaveTables(t, repos, []domain.Table{table})
+	table := domain.Table{ID: id.New(), Status: domain.TableStatusOpened, Orders: []domain.Order{}}
+	MustPresaveTables(t, repos, []domain.Table{table})
 
-				reqBody := fmt.Sprintf(`{"preparation_id": "%s"}`, tc.preparation.ID)
-				r := httptest.NewRequest(http.MethodPost, "/table/preparation", strings.NewReader(reqBody))
-				w := httptest.NewRecorder()
+	beer := domain.MenuItem{ID: id.New(), Name: "beer", Price: 500}
+	salad := domain.MenuItem{ID: id.New(), Name: "salad", Price: 900}
+	require.NoError(t, repos.Menu.SaveItem(ctx, beer))
+	require.NoError(t, repos.Menu.SaveItem(ctx, salad))
 
-				s.HandleStartPreparation(w, r)
+	reqBody := fmt.Sprintf(`{"table_id": "%s", "items": [{"menu_item_id": "%s", "quantity": 3}, {"menu_item_id": "%s", "note": "allergy: nuts"}], "menu_item_ids": ["%s"]}`, table.ID, beer.ID, salad.ID, beer.ID)
+	r := httptest.NewRequest(http.MethodPost, "/table/order", strings.NewReader(reqBody))
+	w := httptest.NewRecorder()
 
-				res := w.Result()
+	s.HandleTakeOrder(w, r)
 
-				require.Equal(t, http.StatusNoContent, res.StatusCode)
-			})
-		}
-	})
+	order, statusCode := MustParseReponse[domain.Order](t, w)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, order.Preparations, 5)
+	require.Equal(t, "allergy: nuts", order.Preparations[3].Note)
 
-	t.Run("Failed", func(t *testing.T) {
-		tt := []struct {
-			testName             string
-			tableFromPreparation func(prep domain.Preparation) domain.Table
-			preparation          domain.Preparation
-			expectedStatusCode   int
-		}{
-			{
-				testName: "preparation not found",
-				tableFromPreparation: func(prep domain.Preparation) domain.Table {
-					return domain.Table{
-						ID:     id.New(),
-						Status: domain.TableStatusOpened,
-						Orders: make([]domain.Order, 0),
-					}
-				},
-				preparation:        domain.Preparation{ID: id.NilID()},
-				expectedStatusCode: http.StatusNotFound,
-			},
-			{
-				testName: "preparation already started",
-				tableFromPreparation: func(prep domain.Preparation) domain.Table {
-					return domain.Table{
-						ID:     id.New(),
-						Status: domain.TableStatusOpened,
-						Orders: []domain.Order{
-							{
-								ID:           id.New(),
-								Status:       domain.OrderStatusTaken,
-								Preparations: []domain.Preparation{prep},
-							},
-						},
-					}
-				},
-				preparation: domain.Preparation{
-					ID:       id.New(),
-					MenuItem: domain.MenuItem{ID: id.New(), Name: "item", Price: 100},
-					Status:   domain.PreparationStatusInProgress,
+	reqBody = fmt.Sprintf(`{"table_id": "%s", "items": [{"menu_item_id": "%s", "quantity": -1}]}`, table.ID, beer.ID)
+	r = httptest.NewRequest(http.MethodPost, "/table/order", strings.NewReader(reqBody))
+	w = httptest.NewRecorder()
+
+	s.HandleTakeOrder(w, r)
+
+	require.Equal(t, http.StatusForbidden, w.Result().StatusCode)
+
+	saved, err := s.TableService.FindTable(ctx, table.ID)
+	require.NoError(t, err)
+	require.Equal(t, "allergy: nuts", saved.Orders[0].Preparations[3].Note)
+
+	saved.Status = domain.TableStatusClosed
+	bill, err := s.BillService.GenerateBill(ctx, saved)
+	require.NoError(t, err)
+
+	r = httptest.NewRequest(http.MethodGet, "/bill/"+bill.ID.String(), nil)
+	r.SetPathValue("id", bill.ID.String())
+	w = httptest.NewRecorder()
+
+	s.HandleGetBill(w, r)
+
+	res, statusCode := MustParseReponse[billResponse](t, w)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, res.Lines, 2)
+	require.Equal(t, beer.Name, res.Lines[0].MenuItem.Name)
+	require.Equal(t, 4, res.Lines[0].Quantity)
+	require.Equal(t, 2000, res.Lines[0].Amount)
+	require.Equal(t, 1, res.Lines[1].Quantity)
+	require.Equal(t, 2900, res.Subtotal)
+}
+
+func TestFireCourseHandler(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	ctx := context.Background()
+
+	table := domain.Table{ID: id.New(), Status: domain.TableStatusOpened, Orders: []domain.Order{}}
+	MustPresaveTables(t, repos, []domain.Table{table})
+
+	soup := domain.MenuItem{ID: id.New(), Name: "soup", Price: 700}
+	steak := domain.MenuItem{ID: id.New(), Name: "steak", Price: 2400}
+	require.NoError(t, repos.Menu.SaveItem(ctx, soup))
+	require.NoError(t, repos.Menu.SaveItem(ctx, steak))
+
+	reqBody := fmt.Sprintf(`{"table_id": "%s", "items": [{"menu_item_id": "%s"}, {"menu_item_id": "%s", "course": 2, "quantity": 2}]}`, table.ID, soup.ID, steak.ID)
+	r := httptest.NewRequest(http.MethodPost, "/table/order", strings.NewReader(reqBody))
+	w := httptest.NewRecorder()
+
+	s.HandleTakeOrder(w, r)
+
+	order, statusCode := MustParseReponse[domain.Order](t, w)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, order.Preparations, 3)
+	require.Equal(t, domain.PreparationStatusPending, order.Preparations[0].Status)
+	require.Equal(t, domain.PreparationStatusHeld, order.Preparations[1].Status)
+	require.Equal(t, 2, order.Preparations[1].Course)
+
+	r = httptest.NewRequest(http.MethodPost, "/preparation/start", strings.NewReader(fmt.Sprintf(`{"preparation_id": "%s"}`, order.Preparations[1].ID)))
+	w = httptest.NewRecorder()
+
+	s.HandleStartPreparation(w, r)
+
+	require.Equal(t, http.StatusForbidden, w.Result().StatusCode)
+
+	tt := []struct {
+		testName string
+		body     string
+		status   int
+	}{
+		{testName: "held course", body: fmt.Sprintf(`{"table_id": "%s", "course": 2}`, table.ID), status: http.StatusOK},
+		{testName: "course already fired", body: fmt.Sprintf(`{"table_id": "%s", "course": 2}`, table.ID), status: http.StatusForbidden},
+		{testName: "table not found", body: fmt.Sprintf(`{"table_id": "%s", "course": 2}`, id.New()), status: http.StatusNotFound},
+		{testName: "malformed body", body: `{"table_id":`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/table/course/fire", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+
+			s.HandleFireCourse(w, r)
+
+			require.Equal(t, tc.status, w.Result().StatusCode)
+		})
+	}
+
+	saved, err := s.TableService.FindTable(ctx, table.ID)
+	require.NoError(t, err)
+	for _, prep := range saved.Orders[0].Preparations {
+		require.Equal(t, domain.PreparationStatusPending, prep.Status)
+	}
+	require.NoError(t, s.TableService.StartPreparation(ctx, order.Preparations[1].ID))
+}
+
+func TestAbortOrderHandler(t *testing.T) {
+	tt := []struct {
+		testName           string
+		status             domain.PreparationStatus
+		orderStatus        domain.OrderStatus
+		unknownOrder       bool
+		expectedStatusCode int
+	}{
+		{testName: "order taken", status: domain.PreparationStatusPending, orderStatus: domain.OrderStatusTaken, expectedStatusCode: http.StatusNoContent},
+		{testName: "order done", status: domain.PreparationStatusServed, orderStatus: domain.OrderStatusDone, expectedStatusCode: http.StatusForbidden},
+		{testName: "order not found", status: domain.PreparationStatusPending, orderStatus: domain.OrderStatusTaken, unknownOrder: true, expectedStatusCode: http.StatusNotFound},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			repos := MustNewRepositories(t)
+			s := MustNewServer(t, repos)
+			order := domain.Order{
+				ID:     id.New(),
+				Status: tc.orderStatus,
+				Preparations: []domain.Preparation{
+					{ID: id.New(), MenuItem: domain.MenuItem{ID: id.New(), Name: "item", Price: 100}, Status: tc.status},
 				},
-				expectedStatusCode: http.StatusForbidden,
-			},
-		}
+			}
+			table := domain.Table{ID: id.New(), Status: domain.TableStatusOpened, Orders: []domain.Order{order}}
+			MustPresaveTables(t, repos, []domain.Table{table})
 
-		for _, tc := range tt {
-			t.Run(tc.testName, func(t *testing.T) {
-				repos := MustNewRepositories(t)
-				s := MustNewServer(t, repos)
-				table := tc.tableFromPreparation(tc.preparation)
-				MustPresaveTables(t, repos, []domain.Table{table})
+			orderID := order.ID
+			if tc.unknownOrder {
+				orderID = id.New()
+			}
 
-				reqBody := fmt.Sprintf(`{"preparation_id": "%s"}`, tc.preparation.ID)
-				r := httptest.NewRequest(http.MethodPost, "/table/preparation", strings.NewReader(reqBody))
-				w := httptest.NewRecorder()
+			reqBody := fmt.Sprintf(`{"order_id": "%s"}`, orderID)
+			r := httptest.NewRequest(http.MethodPost, "/table/order/abort", strings.NewReader(reqBody))
+			w := httptest.NewRecorder()
 
-				s.HandleStartPreparation(w, r)
+			s.HandleAbortOrder(w, r)
 
-				res := w.Result()
+			res := w.Result()
 
-				require.Equal(t, tc.expectedStatusCode, res.StatusCode)
-			})
-		}
-	})
+			require.Equal(t, tc.expectedStatusCode, res.StatusCode)
+		})
+	}
 }
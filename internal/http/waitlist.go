@@ -0,0 +1,183 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"strconv"
+)
+
+func (s *Server) registerWaitlistRoutes(r *router) {
+	waitlistRouter := r.group("/waitlist")
+
+	waitlistRouter.HandleFunc("POST /", s.HandleJoinWaitlist)
+	waitlistRouter.HandleFunc("GET /", s.HandleGetWaitlist)
+	waitlistRouter.HandleFunc("GET /estimate", s.HandleGetWaitEstimate)
+	waitlistRouter.HandleFunc("GET /suggestion", s.HandleGetPartySuggestion)
+	waitlistRouter.HandleFunc("GET /{id}", s.HandleGetWaitlistEntry)
+	waitlistRouter.HandleFunc("POST /{id}/seat", s.HandleSeatParty)
+	waitlistRouter.HandleFunc("POST /{id}/leave", s.HandleLeaveWaitlist)
+}
+
+// waitlistEntryResponse writes the quoted wait of the entry as a Go duration, such as 25m0s.
+type waitlistEntryResponse struct {
+	domain.WaitlistEntry
+	QuotedWait string
+}
+
+func newWaitlistEntryResponse(entry domain.WaitlistEntry) waitlistEntryResponse {
+	return waitlistEntryResponse{WaitlistEntry: entry, QuotedWait: entry.QuotedWait.String()}
+}
+
+func (s *Server) HandleJoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		Name      string `json:"name"`
+		Contact   string `json:"contact"`
+		PartySize int    `json:"party_size"`
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	entry, err := s.WaitlistService.JoinWaitlist(r.Context(), req.Name, req.Contact, req.PartySize)
+	if err != nil {
+		s.logger.Errorf("error joining waitlist: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusCreated, newWaitlistEntryResponse(entry))
+}
+
+func (s *Server) HandleGetWaitlist(w http.ResponseWriter, r *http.Request) {
+	entries, err := s.WaitlistService.FindWaitlist(r.Context())
+	if err != nil {
+		s.logger.Errorf("error finding waitlist: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	res := make([]waitlistEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		res = append(res, newWaitlistEntryResponse(entry))
+	}
+
+	writeJSONBody(w, http.StatusOK, res)
+}
+
+// HandleGetWaitEstimate estimates the wait of a party of the party_size query parameter joining the waitlist now.
+func (s *Server) HandleGetWaitEstimate(w http.ResponseWriter, r *http.Request) {
+	type resBody struct {
+		PartySize int
+		Wait      string
+	}
+
+	partySize, err := strconv.Atoi(r.URL.Query().Get("party_size"))
+	if err != nil {
+		err := fmt.Errorf("invalid party_size: %q", r.URL.Query().Get("party_size"))
+		s.logger.Errorf("%s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	wait, err := s.WaitlistService.EstimateWait(r.Context(), partySize)
+	if err != nil {
+		s.logger.Errorf("error estimating wait: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, resBody{PartySize: partySize, Wait: wait.String()})
+}
+
+// HandleGetPartySuggestion returns the party to seat next on the dining table of the dining_table_id query parameter.
+func (s *Server) HandleGetPartySuggestion(w http.ResponseWriter, r *http.Request) {
+	diningTableID, err := id.Parse(r.URL.Query().Get("dining_table_id"))
+	if err != nil {
+		err := fmt.Errorf("invalid dining_table_id: %q", r.URL.Query().Get("dining_table_id"))
+		s.logger.Errorf("%s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	entry, err := s.WaitlistService.SuggestNextParty(r.Context(), diningTableID)
+	if err != nil {
+		s.logger.Errorf("error suggesting party: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newWaitlistEntryResponse(entry))
+}
+
+func (s *Server) HandleGetWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	entryID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing waitlist entry id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	entry, err := s.WaitlistService.FindWaitlistEntry(r.Context(), entryID)
+	if err != nil {
+		s.logger.Errorf("error finding waitlist entry: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newWaitlistEntryResponse(entry))
+}
+
+// HandleSeatParty opens a table on a dining table for a waiting party, the response links it.
+func (s *Server) HandleSeatParty(w http.ResponseWriter, r *http.Request) {
+	type reqBody struct {
+		DiningTableID id.ID `json:"dining_table_id"`
+	}
+
+	entryID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing waitlist entry id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var req reqBody
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.logger.Errorf("error decoding request: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	entry, err := s.WaitlistService.SeatParty(r.Context(), entryID, req.DiningTableID)
+	if err != nil {
+		s.logger.Errorf("error seating party: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newWaitlistEntryResponse(entry))
+}
+
+func (s *Server) HandleLeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	entryID, err := parsePathID(r, "id")
+	if err != nil {
+		s.logger.Errorf("error parsing waitlist entry id: %s\n", err)
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	entry, err := s.WaitlistService.LeaveWaitlist(r.Context(), entryID)
+	if err != nil {
+		s.logger.Errorf("error leaving waitlist: %s\n", err)
+		writeError(w, domainErrorToHTTPStatus(err), err)
+		return
+	}
+
+	writeJSONBody(w, http.StatusOK, newWaitlistEntryResponse(entry))
+}
@@ -0,0 +1,210 @@
+package http_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type waitlistEntryResponse struct {
+	ID         id.ID
+	Name       string
+	Contact    string
+	PartySize  int
+	QuotedWait string
+	TableID    id.ID
+	Status     domain.WaitlistStatus
+}
+
+func TestJoinWaitlistHandler(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	MustPresaveDiningTable(t, repos, 4)
+
+	tt := []struct {
+		testName string
+		body     string
+		status   int
+	}{
+		{testName: "valid party", body: `{"name":"Smith","contact":"555-0100","party_size":4}`, status: http.StatusCreated},
+		{testName: "without contact", body: `{"name":"Doe","party_size":2}`, status: http.StatusCreated},
+		{testName: "party too large", body: `{"name":"Roe","party_size":6}`, status: http.StatusForbidden},
+		{testName: "without name", body: `{"party_size":2}`, status: http.StatusForbidden},
+		{testName: "malformed body", body: `{"name":`, status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/waitlist", strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+
+			s.HandleJoinWaitlist(w, r)
+
+			require.Equal(t, tc.status, w.Result().StatusCode)
+		})
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/waitlist", nil)
+	w := httptest.NewRecorder()
+
+	s.HandleGetWaitlist(w, r)
+
+	waitlist, statusCode := MustParseReponse[[]waitlistEntryResponse](t, w)
+	require.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, waitlist, 2)
+	assert.Equal(t, "Smith", waitlist[0].Name)
+	assert.Equal(t, "0s", waitlist[0].QuotedWait)
+	assert.Equal(t, "1h0m0s", waitlist[1].QuotedWait, "party not quoted a turn behind the party ahead")
+	assert.Equal(t, domain.WaitlistStatusWaiting, waitlist[1].Status)
+}
+
+func TestGetWaitEstimateHandler(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	ctx := context.Background()
+	diningTable := MustPresaveDiningTable(t, repos, 4)
+
+	_, err := s.TableService.OpenTable(ctx, diningTable.ID, 2)
+	require.NoError(t, err, "Initial setup failed")
+
+	type estimateResponse struct {
+		PartySize int
+		Wait      string
+	}
+
+	getEstimate := func(query url.Values) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/waitlist/estimate?"+query.Encode(), nil)
+		w := httptest.NewRecorder()
+
+		s.HandleGetWaitEstimate(w, r)
+
+		return w
+	}
+
+	estimate, statusCode := MustParseReponse[estimateResponse](t, getEstimate(url.Values{"party_size": {"2"}}))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 2, estimate.PartySize)
+	assert.NotEqual(t, "0s", estimate.Wait, "occupied dining table available right away")
+
+	tt := []struct {
+		testName string
+		query    url.Values
+		status   int
+	}{
+		{testName: "invalid party size", query: url.Values{"party_size": {"many"}}, status: http.StatusBadRequest},
+		{testName: "empty party", query: url.Values{"party_size": {"0"}}, status: http.StatusForbidden},
+		{testName: "party too large", query: url.Values{"party_size": {"5"}}, status: http.StatusForbidden},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			require.Equal(t, tc.status, getEstimate(tc.query).Result().StatusCode)
+		})
+	}
+}
+
+func TestWaitlistLifecycleHandlers(t *testing.T) {
+	repos := MustNewRepositories(t)
+	s := MustNewServer(t, repos)
+	ctx := context.Background()
+	small := MustPresaveDiningTable(t, repos, 2)
+	large := MustPresaveDiningTable(t, repos, 4)
+
+	party, err := s.WaitlistService.JoinWaitlist(ctx, "Smith", "555-0100", 3)
+	require.NoError(t, err, "Initial setup failed")
+	left, err := s.WaitlistService.JoinWaitlist(ctx, "Doe", "", 2)
+	require.NoError(t, err, "Initial setup failed")
+
+	getSuggestion := func(diningTableID string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/waitlist/suggestion?"+url.Values{"dining_table_id": {diningTableID}}.Encode(), nil)
+		w := httptest.NewRecorder()
+
+		s.HandleGetPartySuggestion(w, r)
+
+		return w
+	}
+
+	suggested, statusCode := MustParseReponse[waitlistEntryResponse](t, getSuggestion(small.ID.String()))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, left.ID, suggested.ID, "party too large for the dining table suggested")
+
+	suggested, statusCode = MustParseReponse[waitlistEntryResponse](t, getSuggestion(large.ID.String()))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, party.ID, suggested.ID)
+
+	require.Equal(t, http.StatusNotFound, getSuggestion(id.New().String()).Result().StatusCode)
+	require.Equal(t, http.StatusBadRequest, getSuggestion("invalid").Result().StatusCode)
+
+	seat := func(entryID string, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/waitlist/"+entryID+"/seat", strings.NewReader(body))
+		r.SetPathValue("id", entryID)
+		w := httptest.NewRecorder()
+
+		s.HandleSeatParty(w, r)
+
+		return w
+	}
+
+	leave := func(entryID string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/waitlist/"+entryID+"/leave", nil)
+		r.SetPathValue("id", entryID)
+		w := httptest.NewRecorder()
+
+		s.HandleLeaveWaitlist(w, r)
+
+		return w
+	}
+
+	require.Equal(t, http.StatusForbidden, seat(party.ID.String(), fmt.Sprintf(`{"dining_table_id":%q}`, small.ID)).Result().StatusCode, "party seated on a too small dining table")
+
+	seated, statusCode := MustParseReponse[waitlistEntryResponse](t, seat(party.ID.String(), fmt.Sprintf(`{"dining_table_id":%q}`, large.ID)))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, domain.WaitlistStatusSeated, seated.Status)
+
+	table, err := s.TableService.FindTable(ctx, seated.TableID)
+	require.NoError(t, err, "seated table not opened")
+	assert.Equal(t, large.ID, table.DiningTableID)
+	assert.Equal(t, 3, table.GuestCount)
+
+	gone, statusCode := MustParseReponse[waitlistEntryResponse](t, leave(left.ID.String()))
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, domain.WaitlistStatusLeft, gone.Status)
+
+	r := httptest.NewRequest(http.MethodGet, "/waitlist/"+party.ID.String(), nil)
+	r.SetPathValue("id", party.ID.String())
+	w := httptest.NewRecorder()
+
+	s.HandleGetWaitlistEntry(w, r)
+
+	saved, statusCode := MustParseReponse[waitlistEntryResponse](t, w)
+	require.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, seated, saved)
+
+	tt := []struct {
+		testName string
+		response *httptest.ResponseRecorder
+		status   int
+	}{
+		{testName: "seat twice", response: seat(party.ID.String(), fmt.Sprintf(`{"dining_table_id":%q}`, small.ID)), status: http.StatusForbidden},
+		{testName: "seat after leaving", response: seat(left.ID.String(), fmt.Sprintf(`{"dining_table_id":%q}`, small.ID)), status: http.StatusForbidden},
+		{testName: "seat malformed body", response: seat(party.ID.String(), `{"dining_table_id":`), status: http.StatusBadRequest},
+		{testName: "leave twice", response: leave(left.ID.String()), status: http.StatusForbidden},
+		{testName: "unknown entry", response: leave(id.New().String()), status: http.StatusNotFound},
+		{testName: "invalid entry id", response: leave("invalid"), status: http.StatusBadRequest},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.testName, func(t *testing.T) {
+			require.Equal(t, tc.status, tc.response.Result().StatusCode)
+		})
+	}
+}
@@ -28,3 +28,12 @@ func (id ID) IsNil() bool {
 func (id ID) String() string {
 	return id.UUID.String()
 }
+
+func Parse(s string) (ID, error) {
+	u, err := uuid.Parse(s)
+	if err != nil {
+		return NilID(), err
+	}
+
+	return ID{u}, nil
+}
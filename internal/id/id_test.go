@@ -25,3 +25,19 @@ func TestString(t *testing.T) {
 
 	assert.NotEqual(t, id.String(), "")
 }
+
+func TestParse(t *testing.T) {
+	want := id.New()
+
+	got, err := id.Parse(want.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestParseInvalid(t *testing.T) {
+	got, err := id.Parse("not an id")
+
+	assert.Error(t, err)
+	assert.True(t, got.IsNil())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"order_manager/internal/domain"
 	"order_manager/internal/id"
+	"slices"
 	"sync"
 )
 
@@ -30,7 +31,59 @@ func (b *Bill) Save(ctx context.Context, bill domain.Bill) error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	b.bills[bill.ID] = bill
+	if stored, ok := b.bills[bill.ID]; ok && stored.Version != bill.Version-1 {
+		return domain.Errorf(domain.ESTALE, "bill %s was modified concurrently, stored version is %d, got %d", bill.ID, stored.Version, bill.Version)
+	}
+
+	if err := b.checkTable(bill); err != nil {
+		return err
+	}
+
+	b.bills[bill.ID] = copyBill(bill)
+	return nil
+}
+
+func (b *Bill) SaveAll(ctx context.Context, bills []domain.Bill) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	for _, bill := range bills {
+		if !bill.IsValid() {
+			return domain.Errorf(domain.EINVALID, "bill is invalid: %v", bill)
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, bill := range bills {
+		if stored, ok := b.bills[bill.ID]; ok && stored.Version != bill.Version-1 {
+			return domain.Errorf(domain.ESTALE, "bill %s was modified concurrently, stored version is %d, got %d", bill.ID, stored.Version, bill.Version)
+		}
+		if err := b.checkTable(bill); err != nil {
+			return err
+		}
+	}
+
+	for _, bill := range bills {
+		b.bills[bill.ID] = copyBill(bill)
+	}
+	return nil
+}
+
+// checkTable rejects a bill that is not a sub-bill when another one already charges its table.
+// It must be called with the lock held.
+func (b *Bill) checkTable(bill domain.Bill) error {
+	if bill.ParentID != id.NilID() {
+		return nil
+	}
+
+	for _, stored := range b.bills {
+		if stored.ID != bill.ID && stored.TableID == bill.TableID && stored.ParentID == id.NilID() {
+			return domain.Errorf(domain.ECONFLICT, "a bill was already generated for table %s", bill.TableID)
+		}
+	}
 	return nil
 }
 
@@ -46,5 +99,37 @@ func (b *Bill) FindByID(ctx context.Context, id id.ID) (domain.Bill, error) {
 	if !ok {
 		return domain.Bill{}, domain.Errorf(domain.ENOTFOUND, "bill with id %s not found", id)
 	}
-	return bill, nil
+	return copyBill(bill), nil
+}
+
+func (b *Bill) FindByTableID(ctx context.Context, tableID id.ID) ([]domain.Bill, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bills := make([]domain.Bill, 0)
+	for _, bill := range b.bills {
+		if bill.TableID == tableID {
+			bills = append(bills, copyBill(bill))
+		}
+	}
+	return bills, nil
+}
+
+// copyBill returns a copy of the bill sharing none of its slices, so that the changes a caller makes to a bill
+// before saving it never reach the stored one, even when the save fails.
+func copyBill(bill domain.Bill) domain.Bill {
+	bill.Items = slices.Clone(bill.Items)
+	for i, item := range bill.Items {
+		bill.Items[i].MenuItem = copyMenuItem(item.MenuItem)
+	}
+
+	bill.Taxes = slices.Clone(bill.Taxes)
+	bill.Discounts = slices.Clone(bill.Discounts)
+	bill.Payments = slices.Clone(bill.Payments)
+
+	return bill
 }
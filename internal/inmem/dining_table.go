@@ -0,0 +1,65 @@
+package inmem
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"sync"
+)
+
+type DiningTable struct {
+	tables map[id.ID]domain.DiningTable
+	mu     sync.Mutex
+}
+
+func NewDiningTable() *DiningTable {
+	return &DiningTable{
+		tables: make(map[id.ID]domain.DiningTable),
+	}
+}
+
+func (t *DiningTable) Save(ctx context.Context, table domain.DiningTable) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if !table.IsValid() {
+		return domain.Errorf(domain.EINVALID, "dining table is invalid: %v", table)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tables[table.ID] = table
+	return nil
+}
+
+func (t *DiningTable) FindByID(ctx context.Context, id id.ID) (domain.DiningTable, error) {
+	if ctx.Err() != nil {
+		return domain.DiningTable{}, ctx.Err()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	table, ok := t.tables[id]
+	if !ok {
+		return domain.DiningTable{}, domain.Errorf(domain.ENOTFOUND, "dining table with id %s not found", id)
+	}
+	return table, nil
+}
+
+func (t *DiningTable) FindAll(ctx context.Context) ([]domain.DiningTable, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tables := make([]domain.DiningTable, 0, len(t.tables))
+	for _, table := range t.tables {
+		tables = append(tables, table)
+	}
+	return tables, nil
+}
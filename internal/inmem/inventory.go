@@ -0,0 +1,228 @@
+package inmem
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"slices"
+	"sync"
+	"time"
+)
+
+type Inventory struct {
+	ingredients map[id.ID]domain.Ingredient
+	recipes     map[id.ID]domain.Recipe
+	movements   []domain.StockMovement
+	mu          sync.Mutex
+}
+
+func NewInventory() *Inventory {
+	return &Inventory{
+		ingredients: make(map[id.ID]domain.Ingredient),
+		recipes:     make(map[id.ID]domain.Recipe),
+		movements:   make([]domain.StockMovement, 0),
+	}
+}
+
+func (i *Inventory) SaveIngredient(ctx context.Context, ingredient domain.Ingredient) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if !ingredient.IsValid() {
+		return domain.Errorf(domain.EINVALID, "ingredient is invalid: %v", ingredient)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.ingredients[ingredient.ID] = ingredient
+	return nil
+}
+
+func (i *Inventory) FindIngredient(ctx context.Context, id id.ID) (domain.Ingredient, error) {
+	if ctx.Err() != nil {
+		return domain.Ingredient{}, ctx.Err()
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	ingredient, ok := i.ingredients[id]
+	if !ok {
+		return domain.Ingredient{}, domain.Errorf(domain.ENOTFOUND, "ingredient with id %s not found", id)
+	}
+	return ingredient, nil
+}
+
+func (i *Inventory) FindIngredients(ctx context.Context, ids []id.ID) ([]domain.Ingredient, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	ingredients := make([]domain.Ingredient, 0, len(ids))
+	for _, id := range ids {
+		ingredient, ok := i.ingredients[id]
+		if !ok {
+			return nil, domain.Errorf(domain.ENOTFOUND, "ingredient with id %s not found", id)
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+	return ingredients, nil
+}
+
+func (i *Inventory) FindAllIngredients(ctx context.Context) ([]domain.Ingredient, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	ingredients := make([]domain.Ingredient, 0, len(i.ingredients))
+	for _, ingredient := range i.ingredients {
+		ingredients = append(ingredients, ingredient)
+	}
+	return ingredients, nil
+}
+
+func (i *Inventory) AdjustStock(ctx context.Context, movements []domain.StockMovement) ([]domain.Ingredient, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	for _, movement := range movements {
+		if !movement.IsValid() {
+			return nil, domain.Errorf(domain.EINVALID, "stock movement is invalid: %v", movement)
+		}
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, movement := range movements {
+		if _, ok := i.ingredients[movement.IngredientID]; !ok {
+			return nil, domain.Errorf(domain.ENOTFOUND, "ingredient with id %s not found", movement.IngredientID)
+		}
+	}
+
+	adjusted := make([]id.ID, 0, len(movements))
+	for _, movement := range movements {
+		ingredient := i.ingredients[movement.IngredientID]
+		ingredient.Stock += movement.Delta
+		i.ingredients[movement.IngredientID] = ingredient
+		i.movements = append(i.movements, movement)
+
+		if !slices.Contains(adjusted, movement.IngredientID) {
+			adjusted = append(adjusted, movement.IngredientID)
+		}
+	}
+
+	ingredients := make([]domain.Ingredient, 0, len(adjusted))
+	for _, ingredientID := range adjusted {
+		ingredients = append(ingredients, i.ingredients[ingredientID])
+	}
+	return ingredients, nil
+}
+
+func (i *Inventory) SetStock(ctx context.Context, ingredientID id.ID, stock int, at time.Time) (domain.Ingredient, error) {
+	if ctx.Err() != nil {
+		return domain.Ingredient{}, ctx.Err()
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	ingredient, ok := i.ingredients[ingredientID]
+	if !ok {
+		return domain.Ingredient{}, domain.Errorf(domain.ENOTFOUND, "ingredient with id %s not found", ingredientID)
+	}
+
+	movement := domain.StockMovement{IngredientID: ingredientID, Kind: domain.StockMovementCount, Delta: stock - ingredient.Stock, At: at}
+	if !movement.IsValid() {
+		return domain.Ingredient{}, domain.Errorf(domain.EINVALID, "stock movement is invalid: %v", movement)
+	}
+
+	ingredient.Stock = stock
+	i.ingredients[ingredientID] = ingredient
+	i.movements = append(i.movements, movement)
+	return ingredient, nil
+}
+
+func (i *Inventory) FindStockMovements(ctx context.Context, ingredientID id.ID) ([]domain.StockMovement, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	movements := make([]domain.StockMovement, 0)
+	for _, movement := range i.movements {
+		if movement.IngredientID == ingredientID {
+			movements = append(movements, movement)
+		}
+	}
+
+	slices.SortStableFunc(movements, func(a, b domain.StockMovement) int { return a.At.Compare(b.At) })
+	return movements, nil
+}
+
+func (i *Inventory) SaveRecipe(ctx context.Context, recipe domain.Recipe) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if !recipe.IsValid() {
+		return domain.Errorf(domain.EINVALID, "recipe is invalid: %v", recipe)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if len(recipe.Lines) == 0 {
+		delete(i.recipes, recipe.MenuItemID)
+		return nil
+	}
+
+	i.recipes[recipe.MenuItemID] = recipe
+	return nil
+}
+
+func (i *Inventory) FindRecipes(ctx context.Context, menuItemIDs []id.ID) ([]domain.Recipe, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	recipes := make([]domain.Recipe, 0, len(menuItemIDs))
+	for _, menuItemID := range menuItemIDs {
+		if recipe, ok := i.recipes[menuItemID]; ok {
+			recipes = append(recipes, recipe)
+		}
+	}
+	return recipes, nil
+}
+
+func (i *Inventory) FindRecipesByIngredient(ctx context.Context, ingredientID id.ID) ([]domain.Recipe, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	recipes := make([]domain.Recipe, 0)
+	for _, recipe := range i.recipes {
+		uses := slices.ContainsFunc(recipe.Lines, func(line domain.RecipeLine) bool { return line.IngredientID == ingredientID })
+		if uses {
+			recipes = append(recipes, recipe)
+		}
+	}
+	return recipes, nil
+}
@@ -1,9 +1,12 @@
 package inmem
 
 import (
+	"cmp"
 	"context"
 	"order_manager/internal/domain"
 	"order_manager/internal/id"
+	"slices"
+	"strings"
 	"sync"
 )
 
@@ -65,7 +68,9 @@ func (m *Menu) FindItems(ctx context.Context, ids []id.ID) ([]domain.MenuItem, e
 		if !ok {
 			return nil, domain.Errorf(domain.ENOTFOUND, "menu item with id %s not found", id)
 		}
-		items = append(items, item)
+		if !slices.ContainsFunc(items, func(i domain.MenuItem) bool { return i.ID == id }) {
+			items = append(items, item)
+		}
 	}
 	return items, nil
 }
@@ -85,6 +90,90 @@ func (m *Menu) FindAllItems(ctx context.Context) ([]domain.MenuItem, error) {
 	return items, nil
 }
 
+func (m *Menu) TakePortions(ctx context.Context, portions map[id.ID]int) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Every item is checked before any portion is taken, so that nothing is taken on failure.
+	for itemID, count := range portions {
+		item, ok := m.items[itemID]
+		if !ok {
+			return domain.Errorf(domain.ENOTFOUND, "menu item with id %s not found", itemID)
+		}
+
+		if item.Unavailable {
+			return domain.Errorf(domain.ECONFLICT, "%s is unavailable", item.Name)
+		}
+
+		if item.Portions != nil && *item.Portions < count {
+			return domain.Errorf(domain.ECONFLICT, "%s has only %d portions left", item.Name, *item.Portions)
+		}
+	}
+
+	for itemID, count := range portions {
+		m.addPortions(itemID, -count)
+	}
+	return nil
+}
+
+func (m *Menu) ReturnPortions(ctx context.Context, portions map[id.ID]int) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for itemID := range portions {
+		if _, ok := m.items[itemID]; !ok {
+			return domain.Errorf(domain.ENOTFOUND, "menu item with id %s not found", itemID)
+		}
+	}
+
+	for itemID, count := range portions {
+		m.addPortions(itemID, count)
+	}
+	return nil
+}
+
+func (m *Menu) SetAvailability(ctx context.Context, itemID id.ID, unavailable bool, portions *int) (domain.MenuItem, error) {
+	if ctx.Err() != nil {
+		return domain.MenuItem{}, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[itemID]
+	if !ok {
+		return domain.MenuItem{}, domain.Errorf(domain.ENOTFOUND, "menu item with id %s not found", itemID)
+	}
+
+	item.Unavailable = unavailable
+	if !unavailable {
+		item.Portions = portions
+	}
+	m.items[itemID] = item
+	return item, nil
+}
+
+// addPortions adds to the portions left of an item when they are counted, the lock being held.
+func (m *Menu) addPortions(itemID id.ID, count int) {
+	item := m.items[itemID]
+	if item.Portions == nil {
+		return
+	}
+
+	// The pointer is shared with the items returned before, a new one is stored.
+	left := *item.Portions + count
+	item.Portions = &left
+	m.items[itemID] = item
+}
+
 func (m *Menu) SaveCategory(ctx context.Context, category domain.MenuCategory) error {
 	if ctx.Err() != nil {
 		return ctx.Err()
@@ -115,3 +204,21 @@ func (m *Menu) FindCategory(ctx context.Context, id id.ID) (domain.MenuCategory,
 	}
 	return category, nil
 }
+
+func (m *Menu) FindAllCategories(ctx context.Context) ([]domain.MenuCategory, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	categories := make([]domain.MenuCategory, 0, len(m.categories))
+	for _, category := range m.categories {
+		categories = append(categories, category)
+	}
+	slices.SortFunc(categories, func(a, b domain.MenuCategory) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+	return categories, nil
+}
@@ -0,0 +1,65 @@
+package inmem
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"sync"
+)
+
+type Promotion struct {
+	promotions map[id.ID]domain.Promotion
+	mu         sync.Mutex
+}
+
+func NewPromotion() *Promotion {
+	return &Promotion{
+		promotions: make(map[id.ID]domain.Promotion),
+	}
+}
+
+func (p *Promotion) Save(ctx context.Context, promotion domain.Promotion) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if !promotion.IsValid() {
+		return domain.Errorf(domain.EINVALID, "promotion is invalid: %v", promotion)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.promotions[promotion.ID] = promotion
+	return nil
+}
+
+func (p *Promotion) FindByID(ctx context.Context, id id.ID) (domain.Promotion, error) {
+	if ctx.Err() != nil {
+		return domain.Promotion{}, ctx.Err()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	promotion, ok := p.promotions[id]
+	if !ok {
+		return domain.Promotion{}, domain.Errorf(domain.ENOTFOUND, "promotion with id %s not found", id)
+	}
+	return promotion, nil
+}
+
+func (p *Promotion) FindAll(ctx context.Context) ([]domain.Promotion, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	promotions := make([]domain.Promotion, 0, len(p.promotions))
+	for _, promotion := range p.promotions {
+		promotions = append(promotions, promotion)
+	}
+	return promotions, nil
+}
@@ -0,0 +1,76 @@
+package inmem
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"slices"
+	"sync"
+	"time"
+)
+
+type Reservation struct {
+	reservations map[id.ID]domain.Reservation
+	mu           sync.Mutex
+}
+
+func NewReservation() *Reservation {
+	return &Reservation{reservations: make(map[id.ID]domain.Reservation)}
+}
+
+func (r *Reservation) Save(ctx context.Context, reservation domain.Reservation) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if !reservation.IsValid() {
+		return domain.Errorf(domain.EINVALID, "reservation is invalid: %v", reservation)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, other := range r.reservations {
+		if reservation.Overlaps(other) {
+			return domain.Errorf(domain.ECONFLICT, "dining table %s is reserved from %s to %s", other.DiningTableID, other.StartsAt, other.EndsAt())
+		}
+	}
+
+	r.reservations[reservation.ID] = reservation
+	return nil
+}
+
+func (r *Reservation) FindByID(ctx context.Context, id id.ID) (domain.Reservation, error) {
+	if ctx.Err() != nil {
+		return domain.Reservation{}, ctx.Err()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservation, ok := r.reservations[id]
+	if !ok {
+		return domain.Reservation{}, domain.Errorf(domain.ENOTFOUND, "reservation with id %s not found", id)
+	}
+	return reservation, nil
+}
+
+func (r *Reservation) FindBetween(ctx context.Context, from time.Time, to time.Time) ([]domain.Reservation, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reservations := make([]domain.Reservation, 0)
+	for _, reservation := range r.reservations {
+		if reservation.StartsAt.Before(to) && from.Before(reservation.EndsAt()) {
+			reservations = append(reservations, reservation)
+		}
+	}
+
+	slices.SortFunc(reservations, func(a, b domain.Reservation) int { return a.StartsAt.Compare(b.StartsAt) })
+
+	return reservations, nil
+}
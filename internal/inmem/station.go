@@ -0,0 +1,63 @@
+package inmem
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"sync"
+)
+
+type Station struct {
+	stations map[id.ID]domain.Station
+	mu       sync.Mutex
+}
+
+func NewStation() *Station {
+	return &Station{stations: make(map[id.ID]domain.Station)}
+}
+
+func (s *Station) Save(ctx context.Context, station domain.Station) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if !station.IsValid() {
+		return domain.Errorf(domain.EINVALID, "station is invalid: %v", station)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stations[station.ID] = station
+	return nil
+}
+
+func (s *Station) FindByID(ctx context.Context, id id.ID) (domain.Station, error) {
+	if ctx.Err() != nil {
+		return domain.Station{}, ctx.Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	station, ok := s.stations[id]
+	if !ok {
+		return domain.Station{}, domain.Errorf(domain.ENOTFOUND, "station with id %s not found", id)
+	}
+	return station, nil
+}
+
+func (s *Station) FindAll(ctx context.Context) ([]domain.Station, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stations := make([]domain.Station, 0, len(s.stations))
+	for _, station := range s.stations {
+		stations = append(stations, station)
+	}
+	return stations, nil
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"order_manager/internal/domain"
 	"order_manager/internal/id"
+	"slices"
 	"sync"
+	"time"
 )
 
 type Table struct {
@@ -30,7 +32,40 @@ func (t *Table) Save(ctx context.Context, table domain.Table) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	t.tables[table.ID] = table
+	if stored, ok := t.tables[table.ID]; ok && stored.Version != table.Version-1 {
+		return domain.Errorf(domain.ESTALE, "table %s was modified concurrently, stored version is %d, got %d", table.ID, stored.Version, table.Version)
+	}
+
+	if table.Status == domain.TableStatusOpened && table.DiningTableID != id.NilID() {
+		for _, other := range t.tables {
+			if other.ID != table.ID && other.Status == domain.TableStatusOpened && other.DiningTableID == table.DiningTableID {
+				return domain.Errorf(domain.ECONFLICT, "dining table %s is already occupied", table.DiningTableID)
+			}
+		}
+	}
+
+	t.tables[table.ID] = copyTable(table)
+	return nil
+}
+
+func (t *Table) Delete(ctx context.Context, table domain.Table) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stored, ok := t.tables[table.ID]
+	if !ok {
+		return domain.Errorf(domain.ENOTFOUND, "table with id %s not found", table.ID)
+	}
+
+	if stored.Version != table.Version {
+		return domain.Errorf(domain.ESTALE, "table %s was modified concurrently, stored version is %d, got %d", table.ID, stored.Version, table.Version)
+	}
+
+	delete(t.tables, table.ID)
 	return nil
 }
 
@@ -46,7 +81,7 @@ func (t *Table) FindByID(ctx context.Context, id id.ID) (domain.Table, error) {
 	if !ok {
 		return domain.Table{}, domain.Errorf(domain.ENOTFOUND, "table with id %s not found", id)
 	}
-	return table, nil
+	return copyTable(table), nil
 }
 
 func (t *Table) FindByPreparationID(ctx context.Context, preparationID id.ID) (domain.Table, error) {
@@ -61,7 +96,7 @@ func (t *Table) FindByPreparationID(ctx context.Context, preparationID id.ID) (d
 		for _, order := range table.Orders {
 			for _, preparation := range order.Preparations {
 				if preparation.ID == preparationID {
-					return table, nil
+					return copyTable(table), nil
 				}
 			}
 		}
@@ -70,6 +105,25 @@ func (t *Table) FindByPreparationID(ctx context.Context, preparationID id.ID) (d
 	return domain.Table{}, domain.Errorf(domain.ENOTFOUND, "table with preparation id %s not found", preparationID)
 }
 
+func (t *Table) FindByOrderID(ctx context.Context, orderID id.ID) (domain.Table, error) {
+	if ctx.Err() != nil {
+		return domain.Table{}, ctx.Err()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, table := range t.tables {
+		for _, order := range table.Orders {
+			if order.ID == orderID {
+				return copyTable(table), nil
+			}
+		}
+	}
+
+	return domain.Table{}, domain.Errorf(domain.ENOTFOUND, "table with order id %s not found", orderID)
+}
+
 func (t *Table) FindByStatus(ctx context.Context, status domain.TableStatus) ([]domain.Table, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
@@ -81,8 +135,81 @@ func (t *Table) FindByStatus(ctx context.Context, status domain.TableStatus) ([]
 	tables := make([]domain.Table, 0)
 	for _, table := range t.tables {
 		if table.Status == status {
-			tables = append(tables, table)
+			tables = append(tables, copyTable(table))
 		}
 	}
 	return tables, nil
 }
+
+func (t *Table) FindOrderedBetween(ctx context.Context, from time.Time, to time.Time) ([]domain.Table, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ordered := func(prep domain.Preparation) bool {
+		return len(prep.History) > 0 && !prep.History[0].At.Before(from) && prep.History[0].At.Before(to)
+	}
+
+	tables := make([]domain.Table, 0)
+	for _, table := range t.tables {
+		for _, order := range table.Orders {
+			if slices.ContainsFunc(order.Preparations, ordered) {
+				tables = append(tables, copyTable(table))
+				break
+			}
+		}
+	}
+	return tables, nil
+}
+
+func (t *Table) FindClosedBetween(ctx context.Context, from time.Time, to time.Time) ([]domain.Table, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tables := make([]domain.Table, 0)
+	for _, table := range t.tables {
+		if table.Status == domain.TableStatusClosed && !table.ClosedAt.IsZero() && !table.ClosedAt.Before(from) && table.ClosedAt.Before(to) {
+			tables = append(tables, copyTable(table))
+		}
+	}
+	return tables, nil
+}
+
+// copyTable returns a copy of the table sharing none of its slices, so that the changes a caller makes to a table
+// before saving it never reach the stored one, even when the save fails.
+func copyTable(table domain.Table) domain.Table {
+	table.Orders = slices.Clone(table.Orders)
+	for i, order := range table.Orders {
+		order.Preparations = slices.Clone(order.Preparations)
+		for j, preparation := range order.Preparations {
+			preparation.MenuItem = copyMenuItem(preparation.MenuItem)
+			preparation.Modifiers = slices.Clone(preparation.Modifiers)
+			preparation.History = slices.Clone(preparation.History)
+			order.Preparations[j] = preparation
+		}
+		table.Orders[i] = order
+	}
+
+	return table
+}
+
+func copyMenuItem(item domain.MenuItem) domain.MenuItem {
+	item.ModifierGroups = slices.Clone(item.ModifierGroups)
+	for i, group := range item.ModifierGroups {
+		item.ModifierGroups[i].Options = slices.Clone(group.Options)
+	}
+
+	if item.Portions != nil {
+		portions := *item.Portions
+		item.Portions = &portions
+	}
+
+	return item
+}
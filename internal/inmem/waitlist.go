@@ -0,0 +1,69 @@
+package inmem
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"slices"
+	"sync"
+)
+
+type Waitlist struct {
+	entries map[id.ID]domain.WaitlistEntry
+	mu      sync.Mutex
+}
+
+func NewWaitlist() *Waitlist {
+	return &Waitlist{entries: make(map[id.ID]domain.WaitlistEntry)}
+}
+
+func (w *Waitlist) Save(ctx context.Context, entry domain.WaitlistEntry) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if !entry.IsValid() {
+		return domain.Errorf(domain.EINVALID, "waitlist entry is invalid: %v", entry)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.entries[entry.ID] = entry
+	return nil
+}
+
+func (w *Waitlist) FindByID(ctx context.Context, id id.ID) (domain.WaitlistEntry, error) {
+	if ctx.Err() != nil {
+		return domain.WaitlistEntry{}, ctx.Err()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entry, ok := w.entries[id]
+	if !ok {
+		return domain.WaitlistEntry{}, domain.Errorf(domain.ENOTFOUND, "waitlist entry with id %s not found", id)
+	}
+	return entry, nil
+}
+
+func (w *Waitlist) FindByStatus(ctx context.Context, status domain.WaitlistStatus) ([]domain.WaitlistEntry, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entries := make([]domain.WaitlistEntry, 0)
+	for _, entry := range w.entries {
+		if entry.Status == status {
+			entries = append(entries, entry)
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b domain.WaitlistEntry) int { return a.JoinedAt.Compare(b.JoinedAt) })
+
+	return entries, nil
+}
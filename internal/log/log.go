@@ -3,6 +3,7 @@ package log
 import (
 	"fmt"
 	"io"
+	"strings"
 )
 
 type Level int
@@ -47,3 +48,18 @@ func (l *Logger) Errorf(format string, args ...interface{}) {
 		l.errW.Write([]byte("error: " + fmt.Sprintf(format, args...)))
 	}
 }
+
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return Debug, nil
+	case "info":
+		return Info, nil
+	case "warning", "warn":
+		return Warning, nil
+	case "error":
+		return Error, nil
+	default:
+		return Info, fmt.Errorf("unknown log level %q", s)
+	}
+}
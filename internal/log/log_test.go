@@ -91,3 +91,26 @@ func TestErrorLogger(t *testing.T) {
 	assert.NotContains(t, errBuff.String(), "warning")
 	assert.Contains(t, errBuff.String(), "error")
 }
+
+func TestParseLevel(t *testing.T) {
+	tt := []struct {
+		input string
+		level log.Level
+	}{
+		{input: "debug", level: log.Debug},
+		{input: "INFO", level: log.Info},
+		{input: "warning", level: log.Warning},
+		{input: "warn", level: log.Warning},
+		{input: "error", level: log.Error},
+	}
+
+	for _, tc := range tt {
+		level, err := log.ParseLevel(tc.input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, tc.level, level)
+	}
+
+	_, err := log.ParseLevel("verbose")
+	assert.Error(t, err)
+}
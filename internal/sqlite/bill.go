@@ -7,6 +7,7 @@ import (
 	"order_manager/internal/domain"
 	"order_manager/internal/id"
 	"strings"
+	"time"
 )
 
 type dbBillStatus string
@@ -15,22 +16,113 @@ const (
 	dbBillStatusOpen   dbBillStatus = "pending"
 	dbBillStatusClosed dbBillStatus = "partially paid"
 	dbBillStatusPaid   dbBillStatus = "paid"
+	dbBillStatusSplit  dbBillStatus = "split"
+
+	dbBillStatusPartiallyRefunded dbBillStatus = "partially refunded"
+	dbBillStatusRefunded          dbBillStatus = "refunded"
 )
 
 func (s dbBillStatus) IsValid() bool {
-	return s == dbBillStatusOpen || s == dbBillStatusClosed || s == dbBillStatusPaid
+	return s == dbBillStatusOpen || s == dbBillStatusClosed || s == dbBillStatusPaid || s == dbBillStatusSplit || s == dbBillStatusPartiallyRefunded || s == dbBillStatusRefunded
+}
+
+func toDBBillStatus(status domain.BillStatus) dbBillStatus {
+	switch status {
+	case domain.BillPartiallyPaid:
+		return dbBillStatusClosed
+	case domain.BillStatusPaid:
+		return dbBillStatusPaid
+	case domain.BillStatusSplit:
+		return dbBillStatusSplit
+	case domain.BillStatusPartiallyRefunded:
+		return dbBillStatusPartiallyRefunded
+	case domain.BillStatusRefunded:
+		return dbBillStatusRefunded
+	default:
+		return dbBillStatusOpen
+	}
+}
+
+func toDomainBillStatus(status dbBillStatus) domain.BillStatus {
+	switch status {
+	case dbBillStatusClosed:
+		return domain.BillPartiallyPaid
+	case dbBillStatusPaid:
+		return domain.BillStatusPaid
+	case dbBillStatusSplit:
+		return domain.BillStatusSplit
+	case dbBillStatusPartiallyRefunded:
+		return domain.BillStatusPartiallyRefunded
+	case dbBillStatusRefunded:
+		return domain.BillStatusRefunded
+	default:
+		return domain.BillStatusPending
+	}
 }
 
 type dbBill struct {
-	id      id.ID        `db:"id"`
-	tableID id.ID        `db:"table_id"`
-	total   int          `db:"total"`
-	paid    int          `db:"paid"`
-	status  dbBillStatus `db:"status"`
+	id            id.ID        `db:"id"`
+	tableID       id.ID        `db:"table_id"`
+	parentID      id.ID        `db:"parent_id"`
+	total         int          `db:"total"`
+	serviceCharge int          `db:"service_charge"`
+	serviceRate   float64      `db:"service_charge_rate"`
+	taxExclusive  bool         `db:"tax_exclusive"`
+	status        dbBillStatus `db:"status"`
+	version       int          `db:"version"`
+}
+
+type dbBillTax struct {
+	billID id.ID   `db:"bill_id"`
+	rate   float64 `db:"rate"`
+	net    int     `db:"net"`
+	tax    int     `db:"tax"`
+	gross  int     `db:"gross"`
+}
+
+func (t dbBillTax) IsValid() bool {
+	return t.billID != id.NilID() && t.rate >= 0 && t.net >= 0 && t.tax >= 0 && t.gross == t.net+t.tax
+}
+
+type dbBillDiscount struct {
+	billID        id.ID  `db:"bill_id"`
+	position      int    `db:"position"`
+	promotionID   id.ID  `db:"promotion_id"`
+	preparationID id.ID  `db:"preparation_id"`
+	name          string `db:"name"`
+	amount        int    `db:"amount"`
+	reason        string `db:"reason"`
+}
+
+func (d dbBillDiscount) IsValid() bool {
+	return d.billID != id.NilID() && d.position >= 0 && d.name != "" && d.amount > 0 && (d.promotionID != id.NilID() || d.reason != "")
+}
+
+type dbPayment struct {
+	id            id.ID  `db:"id"`
+	billID        id.ID  `db:"bill_id"`
+	tender        string `db:"tender"`
+	amount        int    `db:"amount"`
+	tip           int    `db:"tip"`
+	tendered      int    `db:"tendered"`
+	change        int    `db:"change_given"`
+	refundOf      id.ID  `db:"refund_of"`
+	preparationID id.ID  `db:"preparation_id"`
+	reason        string `db:"reason"`
+	paidAt        string `db:"paid_at"`
+}
+
+func (p dbPayment) IsValid() bool {
+	isValid := p.id != id.NilID() && p.billID != id.NilID() && p.tender != "" && p.tendered >= 0 && p.change >= 0 && p.paidAt != ""
+	if p.refundOf != id.NilID() {
+		return isValid && p.amount <= 0 && p.tip <= 0 && p.reason != ""
+	}
+
+	return isValid && p.amount > 0 && p.tip >= 0
 }
 
 func (b dbBill) IsValid() bool {
-	return b.id != id.NilID() && b.tableID != id.NilID() && b.total >= 0 && b.paid >= 0 && b.status.IsValid()
+	return b.id != id.NilID() && b.tableID != id.NilID() && b.total >= 0 && b.serviceCharge >= 0 && b.serviceRate >= 0 && b.status.IsValid() && b.version >= 0
 }
 
 type Bill struct {
@@ -52,29 +144,189 @@ func (b *Bill) Save(ctx context.Context, bill domain.Bill) error {
 	}
 	defer tx.Rollback()
 
-	_, err = tx.ExecContext(ctx, `
-		INSERT INTO bills (id, table_id, total, paid, status)
-		VALUES (?, ?, ?, ?, ?)
-	`, bill.ID, bill.TableID, bill.TotalAmount, bill.Paid, dbBillStatus(bill.Status))
+	if err := b.saveBill(ctx, tx, bill); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (b *Bill) SaveAll(ctx context.Context, bills []domain.Bill) error {
+	if len(bills) == 0 {
+		return nil
+	}
+
+	for _, bill := range bills {
+		if !bill.IsValid() {
+			return domain.Errorf(domain.EINVALID, "bill is invalid: %v", bill)
+		}
+	}
+
+	tx, err := b.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, bill := range bills {
+		if err := b.saveBill(ctx, tx, bill); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (b *Bill) saveBill(ctx context.Context, tx *sql.Tx, bill domain.Bill) error {
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO bills (id, table_id, parent_id, total, service_charge, service_charge_rate, tax_exclusive, status, version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET total = excluded.total, service_charge = excluded.service_charge, status = excluded.status, version = excluded.version
+			WHERE bills.version = excluded.version - 1
+	`, bill.ID, bill.TableID, nullableID(bill.ParentID), bill.TotalAmount, bill.ServiceCharge, bill.ServiceChargeRate, bill.TaxExclusive, toDBBillStatus(bill.Status), bill.Version)
+	if isUniqueViolation(err) {
+		// Only one bill charges a table, a concurrent generation got it first.
+		return domain.Errorf(domain.ECONFLICT, "a bill was already generated for table %s", bill.TableID)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to insert bill: %w", err)
 	}
 
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to insert bill: %w", err)
+	}
+
+	if affected == 0 {
+		return domain.Errorf(domain.ESTALE, "bill %s was modified concurrently, version %d is outdated", bill.ID, bill.Version)
+	}
+
+	if err := b.saveTaxes(ctx, tx, bill); err != nil {
+		return err
+	}
+
+	if err := b.insertDiscounts(ctx, tx, bill); err != nil {
+		return err
+	}
+
+	if err := b.insertPayments(ctx, tx, bill); err != nil {
+		return err
+	}
+
+	if len(bill.Items) == 0 {
+		return nil
+	}
+
+	// The items of a bill never change once it is generated, only their discount does.
 	query := fmt.Sprintf(`
-		INSERT INTO bill_menu_items (bill_id, menu_item_id)
+		INSERT INTO bill_items (bill_id, preparation_id, menu_item_id, menu_item_name, menu_item_price, menu_item_tax_category, seat, amount, tax_rate, discount, position)
 		VALUES %s
-	`, strings.Repeat(", (?, ?)", len(bill.Items))[2:])
-	args := make([]interface{}, 0, len(bill.Items)*2)
-	for _, item := range bill.Items {
-		args = append(args, bill.ID, item.ID)
+			ON CONFLICT (bill_id, preparation_id) DO UPDATE SET discount = excluded.discount
+	`, strings.Repeat(", (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", len(bill.Items))[2:])
+	args := make([]interface{}, 0, len(bill.Items)*11)
+	for position, item := range bill.Items {
+		args = append(args, bill.ID, item.PreparationID, item.MenuItem.ID, item.MenuItem.Name, item.MenuItem.Price, item.MenuItem.TaxCategory, item.Seat, item.Amount, item.TaxRate, item.Discount, position)
 	}
 
 	_, err = tx.ExecContext(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to insert bill menu items: %w", err)
+		return fmt.Errorf("failed to insert bill items: %w", err)
 	}
 
-	return tx.Commit()
+	return nil
+}
+
+// saveTaxes replaces the tax breakdown of the bill, which changes when a discount is granted on the bill.
+func (b *Bill) saveTaxes(ctx context.Context, tx *sql.Tx, bill domain.Bill) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM bill_taxes WHERE bill_id = ?`, bill.ID); err != nil {
+		return fmt.Errorf("failed to delete bill taxes: %w", err)
+	}
+
+	if len(bill.Taxes) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(bill.Taxes)*5)
+	for _, line := range bill.Taxes {
+		dbTax := dbBillTax{billID: bill.ID, rate: line.Rate, net: line.Net, tax: line.Tax, gross: line.Gross}
+		if !dbTax.IsValid() {
+			return domain.Errorf(domain.EINVALID, "bill tax is invalid: %v", dbTax)
+		}
+		args = append(args, dbTax.billID, dbTax.rate, dbTax.net, dbTax.tax, dbTax.gross)
+	}
+
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO bill_taxes (bill_id, rate, net, tax, gross)
+		VALUES %s
+	`, strings.Repeat(", (?, ?, ?, ?, ?)", len(bill.Taxes))[2:]), args...)
+	if err != nil {
+		return fmt.Errorf("failed to insert bill taxes: %w", err)
+	}
+
+	return nil
+}
+
+// insertDiscounts inserts the discount lines of the bill, the lines already saved never change.
+func (b *Bill) insertDiscounts(ctx context.Context, tx *sql.Tx, bill domain.Bill) error {
+	if len(bill.Discounts) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(bill.Discounts)*7)
+	for position, line := range bill.Discounts {
+		dbDiscount := dbBillDiscount{
+			billID:        bill.ID,
+			position:      position,
+			promotionID:   line.PromotionID,
+			preparationID: line.PreparationID,
+			name:          line.Name,
+			amount:        line.Amount,
+			reason:        line.Reason,
+		}
+		if !dbDiscount.IsValid() {
+			return domain.Errorf(domain.EINVALID, "bill discount is invalid: %v", dbDiscount)
+		}
+		args = append(args, dbDiscount.billID, dbDiscount.position, nullableID(dbDiscount.promotionID), nullableID(dbDiscount.preparationID), dbDiscount.name, dbDiscount.amount, dbDiscount.reason)
+	}
+
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO bill_discounts (bill_id, position, promotion_id, preparation_id, name, amount, reason)
+		VALUES %s
+			ON CONFLICT DO NOTHING
+	`, strings.Repeat(", (?, ?, ?, ?, ?, ?, ?)", len(bill.Discounts))[2:]), args...)
+	if err != nil {
+		return fmt.Errorf("failed to insert bill discounts: %w", err)
+	}
+
+	return nil
+}
+
+// insertPayments inserts the payments of the bill, the ledger is append only so the payments already saved never change.
+func (b *Bill) insertPayments(ctx context.Context, tx *sql.Tx, bill domain.Bill) error {
+	if len(bill.Payments) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(bill.Payments)*11)
+	for _, payment := range bill.Payments {
+		dbPayment := toDBPayment(bill.ID, payment)
+		if !dbPayment.IsValid() {
+			return domain.Errorf(domain.EINVALID, "payment is invalid: %v", dbPayment)
+		}
+		args = append(args, dbPayment.id, dbPayment.billID, dbPayment.tender, dbPayment.amount, dbPayment.tip, dbPayment.tendered, dbPayment.change,
+			nullableID(dbPayment.refundOf), nullableID(dbPayment.preparationID), dbPayment.reason, dbPayment.paidAt)
+	}
+
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO payments (id, bill_id, tender, amount, tip, tendered, change_given, refund_of, preparation_id, reason, paid_at)
+		VALUES %s
+			ON CONFLICT (id) DO NOTHING
+	`, strings.Repeat(", (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", len(bill.Payments))[2:]), args...)
+	if err != nil {
+		return fmt.Errorf("failed to insert payments: %w", err)
+	}
+
+	return nil
 }
 
 func (b *Bill) FindByID(ctx context.Context, id id.ID) (domain.Bill, error) {
@@ -86,10 +338,10 @@ func (b *Bill) FindByID(ctx context.Context, id id.ID) (domain.Bill, error) {
 
 	var dbBill dbBill
 	err = tx.QueryRowContext(ctx, `
-		SELECT id, table_id, total, paid, status
+		SELECT id, table_id, parent_id, total, service_charge, service_charge_rate, tax_exclusive, status, version
 		FROM bills
 		WHERE id = ?
-	`, id).Scan(&dbBill.id, &dbBill.tableID, &dbBill.total, &dbBill.paid, &dbBill.status)
+	`, id).Scan(&dbBill.id, &dbBill.tableID, &dbBill.parentID, &dbBill.total, &dbBill.serviceCharge, &dbBill.serviceRate, &dbBill.taxExclusive, &dbBill.status, &dbBill.version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Bill{}, domain.Errorf(domain.ENOTFOUND, "bill with id %s not found", id)
@@ -97,43 +349,27 @@ func (b *Bill) FindByID(ctx context.Context, id id.ID) (domain.Bill, error) {
 		return domain.Bill{}, fmt.Errorf("failed to find bill: %w", err)
 	}
 
-	rows, err := tx.QueryContext(ctx, `
-		SELECT id, name, price
-		FROM menu_items
-		WHERE id IN (
-			SELECT menu_item_id
-			FROM preparations
-			WHERE order_id IN (
-				SELECT id
-				FROM orders
-				WHERE table_id = ?
-			)
-		)
-	`, dbBill.tableID)
-	if err != nil {
-		return domain.Bill{}, fmt.Errorf("failed to query menu items: %w", err)
+	items, err := b.findItems(ctx, tx, dbBill.id)
+	if err != nil {
+		return domain.Bill{}, err
 	}
-	defer rows.Close()
 
-	var items []domain.MenuItem
-	for rows.Next() {
-		var item domain.MenuItem
-		if err = rows.Scan(&item.ID, &item.Name, &item.Price); err != nil {
-			return domain.Bill{}, fmt.Errorf("failed to scan menu item: %w", err)
-		}
-		items = append(items, item)
+	taxes, err := b.findTaxes(ctx, tx, dbBill.id)
+	if err != nil {
+		return domain.Bill{}, err
 	}
 
-	bill := domain.Bill{
-		ID:          dbBill.id,
-		TableID:     dbBill.tableID,
-		Items:       items,
-		Status:      domain.BillStatus(dbBill.status),
-		TotalAmount: dbBill.total,
-		Paid:        dbBill.paid,
+	discounts, err := b.findDiscounts(ctx, tx, dbBill.id)
+	if err != nil {
+		return domain.Bill{}, err
 	}
 
-	return bill, tx.Commit()
+	payments, err := b.findPayments(ctx, tx, dbBill.id)
+	if err != nil {
+		return domain.Bill{}, err
+	}
+
+	return toDomainBill(dbBill, items, taxes, discounts, payments), tx.Commit()
 }
 
 func (b *Bill) FindByTableID(ctx context.Context, id id.ID) ([]domain.Bill, error) {
@@ -144,9 +380,10 @@ func (b *Bill) FindByTableID(ctx context.Context, id id.ID) ([]domain.Bill, erro
 	defer tx.Rollback()
 
 	rows, err := tx.QueryContext(ctx, `
-	SELECT id, table_id, total, paid, status
+	SELECT id, table_id, parent_id, total, service_charge, service_charge_rate, tax_exclusive, status, version
 	FROM bills
 	WHERE table_id = ?
+	ORDER BY rowid
 	`, id)
 	if err != nil {
 		return []domain.Bill{}, fmt.Errorf("failed to query bills: %w", err)
@@ -154,46 +391,196 @@ func (b *Bill) FindByTableID(ctx context.Context, id id.ID) ([]domain.Bill, erro
 
 	defer rows.Close()
 
-	var bills []domain.Bill
+	var dbBills []dbBill
 	for rows.Next() {
 		var dbBill dbBill
-		err = rows.Scan(&dbBill.id, &dbBill.tableID, &dbBill.total, &dbBill.paid, &dbBill.status)
+		err = rows.Scan(&dbBill.id, &dbBill.tableID, &dbBill.parentID, &dbBill.total, &dbBill.serviceCharge, &dbBill.serviceRate, &dbBill.taxExclusive, &dbBill.status, &dbBill.version)
 		if err != nil {
 			return []domain.Bill{}, fmt.Errorf("failed to find bill: %w", err)
 		}
+		dbBills = append(dbBills, dbBill)
+	}
 
-		rows, err = tx.QueryContext(ctx, `
-			SELECT id, name, price
-			FROM menu_items
-			WHERE id IN (
-				SELECT menu_item_id
-				FROM bill_menu_items
-				WHERE bill_id = ?
-			)
-			`, dbBill.id)
+	bills := make([]domain.Bill, 0, len(dbBills))
+	for _, dbBill := range dbBills {
+		items, err := b.findItems(ctx, tx, dbBill.id)
 		if err != nil {
-			return []domain.Bill{}, fmt.Errorf("failed to query menu items: %w", err)
+			return []domain.Bill{}, err
 		}
-		defer rows.Close()
 
-		var items []domain.MenuItem
-		for rows.Next() {
-			var item domain.MenuItem
-			if err = rows.Scan(&item.ID, &item.Name, &item.Price); err != nil {
-				return []domain.Bill{}, fmt.Errorf("failed to scan menu item: %w", err)
-			}
-			items = append(items, item)
+		taxes, err := b.findTaxes(ctx, tx, dbBill.id)
+		if err != nil {
+			return []domain.Bill{}, err
 		}
 
-		bills = append(bills, domain.Bill{
-			ID:          dbBill.id,
-			TableID:     dbBill.tableID,
-			Status:      domain.BillStatus(dbBill.status),
-			TotalAmount: dbBill.total,
-			Paid:        dbBill.paid,
-			Items:       items,
-		})
+		discounts, err := b.findDiscounts(ctx, tx, dbBill.id)
+		if err != nil {
+			return []domain.Bill{}, err
+		}
+
+		payments, err := b.findPayments(ctx, tx, dbBill.id)
+		if err != nil {
+			return []domain.Bill{}, err
+		}
+
+		bills = append(bills, toDomainBill(dbBill, items, taxes, discounts, payments))
 	}
 
 	return bills, tx.Commit()
 }
+
+func (b *Bill) findItems(ctx context.Context, tx *sql.Tx, billID id.ID) ([]domain.BillItem, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT preparation_id, seat, amount, tax_rate, discount, menu_item_id, menu_item_name, menu_item_price, menu_item_tax_category
+		FROM bill_items
+		WHERE bill_id = ?
+		ORDER BY position
+	`, billID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bill items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]domain.BillItem, 0)
+	for rows.Next() {
+		var item domain.BillItem
+		if err = rows.Scan(&item.PreparationID, &item.Seat, &item.Amount, &item.TaxRate, &item.Discount, &item.MenuItem.ID, &item.MenuItem.Name, &item.MenuItem.Price, &item.MenuItem.TaxCategory); err != nil {
+			return nil, fmt.Errorf("failed to scan bill item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+func (b *Bill) findTaxes(ctx context.Context, tx *sql.Tx, billID id.ID) ([]domain.TaxLine, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT rate, net, tax, gross
+		FROM bill_taxes
+		WHERE bill_id = ?
+		ORDER BY rate
+	`, billID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bill taxes: %w", err)
+	}
+	defer rows.Close()
+
+	taxes := make([]domain.TaxLine, 0)
+	for rows.Next() {
+		var line domain.TaxLine
+		if err = rows.Scan(&line.Rate, &line.Net, &line.Tax, &line.Gross); err != nil {
+			return nil, fmt.Errorf("failed to scan bill tax: %w", err)
+		}
+		taxes = append(taxes, line)
+	}
+
+	return taxes, rows.Err()
+}
+
+func (b *Bill) findDiscounts(ctx context.Context, tx *sql.Tx, billID id.ID) ([]domain.Discount, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT promotion_id, preparation_id, name, amount, reason
+		FROM bill_discounts
+		WHERE bill_id = ?
+		ORDER BY position
+	`, billID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bill discounts: %w", err)
+	}
+	defer rows.Close()
+
+	discounts := make([]domain.Discount, 0)
+	for rows.Next() {
+		var line domain.Discount
+		if err = rows.Scan(&line.PromotionID, &line.PreparationID, &line.Name, &line.Amount, &line.Reason); err != nil {
+			return nil, fmt.Errorf("failed to scan bill discount: %w", err)
+		}
+		discounts = append(discounts, line)
+	}
+
+	return discounts, rows.Err()
+}
+
+func (b *Bill) findPayments(ctx context.Context, tx *sql.Tx, billID id.ID) ([]domain.Payment, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, bill_id, tender, amount, tip, tendered, change_given, refund_of, preparation_id, reason, paid_at
+		FROM payments
+		WHERE bill_id = ?
+		ORDER BY rowid
+	`, billID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payments: %w", err)
+	}
+	defer rows.Close()
+
+	payments := make([]domain.Payment, 0)
+	for rows.Next() {
+		var dbPayment dbPayment
+		if err = rows.Scan(&dbPayment.id, &dbPayment.billID, &dbPayment.tender, &dbPayment.amount, &dbPayment.tip, &dbPayment.tendered, &dbPayment.change,
+			&dbPayment.refundOf, &dbPayment.preparationID, &dbPayment.reason, &dbPayment.paidAt); err != nil {
+			return nil, fmt.Errorf("failed to scan payment: %w", err)
+		}
+
+		payment, err := toDomainPayment(dbPayment)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+func toDomainBill(dbBill dbBill, items []domain.BillItem, taxes []domain.TaxLine, discounts []domain.Discount, payments []domain.Payment) domain.Bill {
+	return domain.Bill{
+		ID:                dbBill.id,
+		TableID:           dbBill.tableID,
+		ParentID:          dbBill.parentID,
+		Items:             items,
+		Status:            toDomainBillStatus(dbBill.status),
+		TotalAmount:       dbBill.total,
+		ServiceCharge:     dbBill.serviceCharge,
+		ServiceChargeRate: dbBill.serviceRate,
+		TaxExclusive:      dbBill.taxExclusive,
+		Taxes:             taxes,
+		Discounts:         discounts,
+		Payments:          payments,
+		Version:           dbBill.version,
+	}
+}
+
+func toDBPayment(billID id.ID, payment domain.Payment) dbPayment {
+	return dbPayment{
+		id:            payment.ID,
+		billID:        billID,
+		tender:        string(payment.Tender),
+		amount:        payment.Amount,
+		tip:           payment.Tip,
+		tendered:      payment.Tendered,
+		change:        payment.Change,
+		refundOf:      payment.RefundOf,
+		preparationID: payment.PreparationID,
+		reason:        payment.Reason,
+		paidAt:        payment.PaidAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+func toDomainPayment(payment dbPayment) (domain.Payment, error) {
+	paidAt, err := time.Parse(time.RFC3339Nano, payment.paidAt)
+	if err != nil {
+		return domain.Payment{}, fmt.Errorf("failed to parse payment time: %w", err)
+	}
+
+	return domain.Payment{
+		ID:            payment.id,
+		Tender:        domain.TenderType(payment.tender),
+		Amount:        payment.amount,
+		Tip:           payment.tip,
+		Tendered:      payment.tendered,
+		Change:        payment.change,
+		RefundOf:      payment.refundOf,
+		PreparationID: payment.preparationID,
+		Reason:        payment.reason,
+		PaidAt:        paidAt,
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 	"order_manager/internal/id"
 	"order_manager/internal/sqlite"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,7 +15,10 @@ import (
 func MustPresaveTableFromBill(t *testing.T, db *sqlite.DB, bill domain.Bill) {
 	t.Helper()
 
-	items := bill.Items
+	items := make([]domain.MenuItem, 0, len(bill.Items))
+	for _, item := range bill.Items {
+		items = append(items, item.MenuItem)
+	}
 	itemRepo := sqlite.NewMenu(db)
 
 	err := itemRepo.SaveItems(context.Background(), items)
@@ -31,8 +35,9 @@ func MustPresaveTableFromBill(t *testing.T, db *sqlite.DB, bill domain.Bill) {
 
 	for _, item := range bill.Items {
 		table.Orders[0].Preparations = append(table.Orders[0].Preparations, domain.Preparation{
-			ID:       id.New(),
-			MenuItem: item,
+			ID:       item.PreparationID,
+			MenuItem: item.MenuItem,
+			Seat:     item.Seat,
 			Status:   domain.PreparationStatusServed,
 		})
 	}
@@ -43,23 +48,32 @@ func MustPresaveTableFromBill(t *testing.T, db *sqlite.DB, bill domain.Bill) {
 
 func GenerateDummyBill() domain.Bill {
 	return domain.Bill{
-		ID:          id.New(),
-		TableID:     id.New(),
-		TotalAmount: 300,
-		Paid:        0,
-		Status:      domain.BillStatusPending,
-		Items: []domain.MenuItem{
+		ID:            id.New(),
+		TableID:       id.New(),
+		TotalAmount:   330,
+		ServiceCharge: 30,
+		Status:        domain.BillStatusPending,
+		Items: []domain.BillItem{
 			{
-				ID:    id.New(),
-				Name:  "item1",
-				Price: 100,
+				PreparationID: id.New(),
+				MenuItem:      domain.MenuItem{ID: id.New(), Name: "item1", Price: 100, TaxCategory: "food"},
+				Amount:        100,
+				TaxRate:       10,
 			},
 			{
-				ID:    id.New(),
-				Name:  "item2",
-				Price: 200,
+				PreparationID: id.New(),
+				MenuItem:      domain.MenuItem{ID: id.New(), Name: "item2", Price: 200, TaxCategory: "alcohol"},
+				Seat:          1,
+				Amount:        200,
+				TaxRate:       20,
 			},
 		},
+		Taxes: []domain.TaxLine{
+			{Rate: 10, Net: 91, Tax: 9, Gross: 100},
+			{Rate: 20, Net: 167, Tax: 33, Gross: 200},
+		},
+		Discounts: make([]domain.Discount, 0),
+		Payments:  make([]domain.Payment, 0),
 	}
 }
 
@@ -97,6 +111,248 @@ func TestSaveAndRetrieveBillByTableID(t *testing.T) {
 	assert.Equal(t, []domain.Bill{bill}, gotBills)
 }
 
+func TestMenuChangesDoNotAlterTablesAndBills(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	ctx := context.Background()
+	bill := GenerateDummyBill()
+	billRepo := sqlite.NewBill(db)
+	tableRepo := sqlite.NewTable(db)
+	MustPresaveTableFromBill(t, db, bill)
+
+	err := billRepo.Save(ctx, bill)
+	require.NoErrorf(t, err, "failed to save bill: %v", err)
+
+	table, err := tableRepo.FindByID(ctx, bill.TableID)
+	require.NoErrorf(t, err, "failed to retrieve table: %v", err)
+
+	changed := bill.Items[0].MenuItem
+	changed.Name = "renamed"
+	changed.Price = 150
+	changed.TaxCategory = "drinks"
+	err = sqlite.NewMenu(db).SaveItem(ctx, changed)
+	require.NoErrorf(t, err, "failed to save item: %v", err)
+
+	gotBill, err := billRepo.FindByID(ctx, bill.ID)
+	require.NoErrorf(t, err, "failed to retrieve bill: %v", err)
+	assert.Equal(t, bill, gotBill)
+
+	gotTable, err := tableRepo.FindByID(ctx, bill.TableID)
+	require.NoErrorf(t, err, "failed to retrieve table: %v", err)
+	assert.Equal(t, table, gotTable)
+	assert.Equal(t, "item1", gotTable.Orders[0].Preparations[0].MenuItem.Name)
+	assert.Equal(t, 100, gotTable.Orders[0].Preparations[0].MenuItem.Price)
+}
+
+func TestSaveBillTwiceUpdatesPayment(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	bill := GenerateDummyBill()
+	billRepo := sqlite.NewBill(db)
+	MustPresaveTableFromBill(t, db, bill)
+
+	err := billRepo.Save(context.Background(), bill)
+	require.NoErrorf(t, err, "failed to save bill: %v", err)
+
+	bill.Payments = append(bill.Payments,
+		domain.Payment{ID: id.New(), Tender: domain.TenderCash, Amount: 60, Tendered: 100, Change: 40, PaidAt: time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)},
+		domain.Payment{ID: id.New(), Tender: domain.TenderCard, Amount: 40, Tip: 15, PaidAt: time.Date(2026, 10, 17, 20, 5, 0, 0, time.UTC)},
+	)
+	bill.Status = domain.BillPartiallyPaid
+	bill.Version++
+	err = billRepo.Save(context.Background(), bill)
+	require.NoErrorf(t, err, "failed to save bill: %v", err)
+
+	gotBill, err := billRepo.FindByID(context.Background(), bill.ID)
+	require.NoErrorf(t, err, "failed to retrieve bill: %v", err)
+
+	assert.Equal(t, bill, gotBill)
+}
+
+func TestSaveBillWithRefund(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	bill := GenerateDummyBill()
+	billRepo := sqlite.NewBill(db)
+	MustPresaveTableFromBill(t, db, bill)
+
+	payment := domain.Payment{ID: id.New(), Tender: domain.TenderCash, Amount: 330, Tip: 20, Tendered: 350, PaidAt: time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)}
+	bill.Payments = append(bill.Payments, payment)
+	bill.Status = domain.BillStatusPaid
+	err := billRepo.Save(context.Background(), bill)
+	require.NoErrorf(t, err, "failed to save bill: %v", err)
+
+	bill.Payments = append(bill.Payments, domain.Payment{
+		ID:            id.New(),
+		Tender:        domain.TenderCash,
+		Amount:        -110,
+		RefundOf:      payment.ID,
+		PreparationID: bill.Items[0].PreparationID,
+		Reason:        "cold dish",
+		PaidAt:        time.Date(2026, 10, 17, 20, 30, 0, 0, time.UTC),
+	})
+	bill.Status = domain.BillStatusPartiallyRefunded
+	bill.Version++
+	err = billRepo.Save(context.Background(), bill)
+	require.NoErrorf(t, err, "failed to save bill: %v", err)
+
+	gotBill, err := billRepo.FindByID(context.Background(), bill.ID)
+	require.NoErrorf(t, err, "failed to retrieve bill: %v", err)
+
+	assert.Equal(t, bill, gotBill)
+}
+
+func TestSaveBillTwiceUpdatesDiscounts(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	bill := GenerateDummyBill()
+	billRepo := sqlite.NewBill(db)
+	MustPresaveTableFromBill(t, db, bill)
+
+	err := billRepo.Save(context.Background(), bill)
+	require.NoErrorf(t, err, "failed to save bill: %v", err)
+
+	bill.Items[0].Discount = 50
+	bill.Items[1].Discount = 100
+	bill.Discounts = []domain.Discount{{Name: "Manual discount", Amount: 150, Reason: "birthday"}}
+	bill.Taxes = []domain.TaxLine{
+		{Rate: 10, Net: 45, Tax: 5, Gross: 50},
+		{Rate: 20, Net: 83, Tax: 17, Gross: 100},
+	}
+	bill.ServiceCharge = 15
+	bill.TotalAmount = 165
+	bill.Version++
+	err = billRepo.Save(context.Background(), bill)
+	require.NoErrorf(t, err, "failed to save bill: %v", err)
+
+	gotBill, err := billRepo.FindByID(context.Background(), bill.ID)
+	require.NoErrorf(t, err, "failed to retrieve bill: %v", err)
+
+	assert.Equal(t, bill, gotBill)
+}
+
+func TestSaveStaleBill(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	bill := GenerateDummyBill()
+	billRepo := sqlite.NewBill(db)
+	MustPresaveTableFromBill(t, db, bill)
+
+	err := billRepo.Save(context.Background(), bill)
+	require.NoErrorf(t, err, "failed to save bill: %v", err)
+
+	first, second := bill, bill
+	first.Version++
+	first.Payments = []domain.Payment{{ID: id.New(), Tender: domain.TenderCard, Amount: 100, PaidAt: time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)}}
+	first.Status = domain.BillPartiallyPaid
+	second.Version++
+	second.Payments = []domain.Payment{{ID: id.New(), Tender: domain.TenderCard, Amount: 50, PaidAt: time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)}}
+	second.Status = domain.BillPartiallyPaid
+
+	err = billRepo.Save(context.Background(), first)
+	require.NoErrorf(t, err, "failed to save bill: %v", err)
+
+	err = billRepo.Save(context.Background(), second)
+	assert.Equal(t, domain.ESTALE, domain.ErrorCode(err))
+
+	gotBill, err := billRepo.FindByID(context.Background(), bill.ID)
+	require.NoErrorf(t, err, "failed to retrieve bill: %v", err)
+	assert.Equal(t, first, gotBill)
+}
+
+func TestSaveSecondBillOfTable(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	bill := GenerateDummyBill()
+	billRepo := sqlite.NewBill(db)
+	MustPresaveTableFromBill(t, db, bill)
+
+	err := billRepo.Save(context.Background(), bill)
+	require.NoErrorf(t, err, "failed to save bill: %v", err)
+
+	second := bill
+	second.ID = id.New()
+
+	err = billRepo.Save(context.Background(), second)
+	assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err))
+
+	gotBills, err := billRepo.FindByTableID(context.Background(), bill.TableID)
+	require.NoErrorf(t, err, "failed to retrieve bills: %v", err)
+	assert.Equal(t, []domain.Bill{bill}, gotBills)
+}
+
+func TestSaveAllSplitBills(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	bill := GenerateDummyBill()
+	billRepo := sqlite.NewBill(db)
+	MustPresaveTableFromBill(t, db, bill)
+
+	err := billRepo.Save(context.Background(), bill)
+	require.NoErrorf(t, err, "failed to save bill: %v", err)
+
+	bill.Status = domain.BillStatusSplit
+	bill.Version++
+	parts := make([]domain.Bill, 0, len(bill.Items))
+	for i, item := range bill.Items {
+		parts = append(parts, domain.Bill{
+			ID:          id.New(),
+			TableID:     bill.TableID,
+			ParentID:    bill.ID,
+			Items:       []domain.BillItem{item},
+			Taxes:       []domain.TaxLine{bill.Taxes[i]},
+			Discounts:   make([]domain.Discount, 0),
+			Payments:    make([]domain.Payment, 0),
+			Status:      domain.BillStatusPending,
+			TotalAmount: item.Amount,
+			Version:     1,
+		})
+	}
+
+	err = billRepo.SaveAll(context.Background(), append([]domain.Bill{bill}, parts...))
+	require.NoErrorf(t, err, "failed to save bills: %v", err)
+
+	gotBills, err := billRepo.FindByTableID(context.Background(), bill.TableID)
+	require.NoErrorf(t, err, "failed to retrieve bills: %v", err)
+	assert.Equal(t, append([]domain.Bill{bill}, parts...), gotBills)
+}
+
+func TestSaveAllStaleBillsSavesNone(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	bill := GenerateDummyBill()
+	billRepo := sqlite.NewBill(db)
+	MustPresaveTableFromBill(t, db, bill)
+
+	err := billRepo.Save(context.Background(), bill)
+	require.NoErrorf(t, err, "failed to save bill: %v", err)
+
+	part := domain.Bill{
+		ID:          id.New(),
+		TableID:     bill.TableID,
+		ParentID:    bill.ID,
+		Items:       make([]domain.BillItem, 0),
+		Status:      domain.BillStatusPending,
+		TotalAmount: bill.TotalAmount,
+		Version:     1,
+	}
+	bill.Status = domain.BillStatusSplit
+
+	err = billRepo.SaveAll(context.Background(), []domain.Bill{part, bill})
+	assert.Equal(t, domain.ESTALE, domain.ErrorCode(err))
+
+	_, err = billRepo.FindByID(context.Background(), part.ID)
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+}
+
 func TestNotFoundBillByID(t *testing.T) {
 	db := MustOpenDB(t)
 	defer MustCloseDB(t, db)
@@ -0,0 +1,94 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+)
+
+type dbDiningTable struct {
+	id       id.ID  `db:"id"`
+	name     string `db:"name"`
+	capacity int    `db:"capacity"`
+	zone     string `db:"zone"`
+}
+
+func (t dbDiningTable) IsValid() bool {
+	return t.id != id.NilID() && t.name != "" && t.capacity > 0
+}
+
+type DiningTable struct {
+	*DB
+}
+
+func NewDiningTable(db *DB) *DiningTable {
+	return &DiningTable{DB: db}
+}
+
+func (t *DiningTable) Save(ctx context.Context, table domain.DiningTable) error {
+	if !table.IsValid() {
+		return domain.Errorf(domain.EINVALID, "dining table is invalid: %v", table)
+	}
+
+	_, err := t.ExecContext(ctx, `
+		INSERT INTO dining_tables (id, name, capacity, zone)
+		VALUES (?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET name = excluded.name, capacity = excluded.capacity, zone = excluded.zone
+		`, table.ID, table.Name, table.Capacity, table.Zone)
+	if err != nil {
+		return fmt.Errorf("failed to insert dining table: %w", err)
+	}
+
+	return nil
+}
+
+func (t *DiningTable) FindByID(ctx context.Context, id id.ID) (domain.DiningTable, error) {
+	var table dbDiningTable
+	err := t.QueryRowContext(ctx, `
+		SELECT id, name, capacity, zone
+		FROM dining_tables
+		WHERE id = ?
+		`, id).Scan(&table.id, &table.name, &table.capacity, &table.zone)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.DiningTable{}, domain.Errorf(domain.ENOTFOUND, "dining table with id %s not found", id)
+		}
+		return domain.DiningTable{}, fmt.Errorf("failed to find dining table: %w", err)
+	}
+
+	return toDomainDiningTable(table), nil
+}
+
+func (t *DiningTable) FindAll(ctx context.Context) ([]domain.DiningTable, error) {
+	rows, err := t.QueryContext(ctx, `
+		SELECT id, name, capacity, zone
+		FROM dining_tables
+		ORDER BY name
+		`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dining tables: %w", err)
+	}
+	defer rows.Close()
+
+	tables := make([]domain.DiningTable, 0)
+	for rows.Next() {
+		var table dbDiningTable
+		if err := rows.Scan(&table.id, &table.name, &table.capacity, &table.zone); err != nil {
+			return nil, fmt.Errorf("failed to scan dining table: %w", err)
+		}
+		tables = append(tables, toDomainDiningTable(table))
+	}
+
+	return tables, nil
+}
+
+func toDomainDiningTable(table dbDiningTable) domain.DiningTable {
+	return domain.DiningTable{
+		ID:       table.id,
+		Name:     table.name,
+		Capacity: table.capacity,
+		Zone:     table.zone,
+	}
+}
@@ -0,0 +1,88 @@
+package sqlite_test
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"order_manager/internal/sqlite"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func GenerateDummyDiningTable(name string) domain.DiningTable {
+	return domain.DiningTable{
+		ID:       id.New(),
+		Name:     name,
+		Capacity: 4,
+		Zone:     "terrace",
+	}
+}
+
+func TestSaveAndRetrieveDiningTable(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	diningTable := GenerateDummyDiningTable("T1")
+	diningTableRepo := sqlite.NewDiningTable(db)
+
+	err := diningTableRepo.Save(context.Background(), diningTable)
+	require.NoErrorf(t, err, "failed to save dining table: %v", err)
+
+	gotTable, err := diningTableRepo.FindByID(context.Background(), diningTable.ID)
+	require.NoErrorf(t, err, "failed to retrieve dining table: %v", err)
+	assert.Equal(t, diningTable, gotTable)
+
+	gotTables, err := diningTableRepo.FindAll(context.Background())
+	require.NoErrorf(t, err, "failed to retrieve dining tables: %v", err)
+	assert.Equal(t, []domain.DiningTable{diningTable}, gotTables)
+}
+
+func TestNotFoundDiningTable(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	diningTableRepo := sqlite.NewDiningTable(db)
+
+	_, err := diningTableRepo.FindByID(context.Background(), id.New())
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+}
+
+func TestSaveDiningTableWithDuplicatedName(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	diningTableRepo := sqlite.NewDiningTable(db)
+
+	err := diningTableRepo.Save(context.Background(), GenerateDummyDiningTable("T1"))
+	require.NoErrorf(t, err, "failed to save dining table: %v", err)
+
+	err = diningTableRepo.Save(context.Background(), GenerateDummyDiningTable("T1"))
+	assert.Error(t, err)
+}
+
+func TestSaveTableOnOccupiedDiningTable(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	diningTable := GenerateDummyDiningTable("T1")
+	err := sqlite.NewDiningTable(db).Save(context.Background(), diningTable)
+	require.NoErrorf(t, err, "failed to save dining table: %v", err)
+
+	tableRepo := sqlite.NewTable(db)
+	table := domain.Table{ID: id.New(), DiningTableID: diningTable.ID, GuestCount: 2, Status: domain.TableStatusOpened, Orders: []domain.Order{}}
+	err = tableRepo.Save(context.Background(), table)
+	require.NoErrorf(t, err, "failed to save table: %v", err)
+
+	gotTable, err := tableRepo.FindByID(context.Background(), table.ID)
+	require.NoErrorf(t, err, "failed to retrieve table: %v", err)
+	assert.Equal(t, table, gotTable)
+
+	other := domain.Table{ID: id.New(), DiningTableID: diningTable.ID, GuestCount: 2, Status: domain.TableStatusOpened, Orders: []domain.Order{}}
+	err = tableRepo.Save(context.Background(), other)
+	assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "invalid error code")
+
+	_, err = tableRepo.FindByID(context.Background(), other.ID)
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "rejected table saved")
+}
@@ -0,0 +1,497 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"slices"
+	"strings"
+	"time"
+)
+
+type dbIngredient struct {
+	id                id.ID         `db:"id"`
+	name              string        `db:"name"`
+	unit              string        `db:"unit"`
+	stock             int           `db:"stock"`
+	lowStockThreshold sql.NullInt64 `db:"low_stock_threshold"`
+}
+
+func (i dbIngredient) IsValid() bool {
+	return i.id != id.NilID() && i.name != "" && i.unit != "" && (!i.lowStockThreshold.Valid || i.lowStockThreshold.Int64 >= 0)
+}
+
+type dbRecipeLine struct {
+	menuItemID   id.ID `db:"menu_item_id"`
+	ingredientID id.ID `db:"ingredient_id"`
+	quantity     int   `db:"quantity"`
+}
+
+func (l dbRecipeLine) IsValid() bool {
+	return l.menuItemID != id.NilID() && l.ingredientID != id.NilID() && l.quantity > 0
+}
+
+type dbStockMovementKind string
+
+const (
+	dbStockMovementKindDelivery    dbStockMovementKind = "delivery"
+	dbStockMovementKindCount       dbStockMovementKind = "count"
+	dbStockMovementKindAdjustment  dbStockMovementKind = "adjustment"
+	dbStockMovementKindConsumption dbStockMovementKind = "consumption"
+	dbStockMovementKindRestock     dbStockMovementKind = "restock"
+)
+
+func (k dbStockMovementKind) IsValid() bool {
+	return k == dbStockMovementKindDelivery ||
+		k == dbStockMovementKindCount ||
+		k == dbStockMovementKindAdjustment ||
+		k == dbStockMovementKindConsumption ||
+		k == dbStockMovementKindRestock
+}
+
+type dbStockMovement struct {
+	ingredientID  id.ID               `db:"ingredient_id"`
+	kind          dbStockMovementKind `db:"kind"`
+	delta         int                 `db:"delta"`
+	reason        string              `db:"reason"`
+	preparationID id.ID               `db:"preparation_id"`
+	at            string              `db:"at"`
+}
+
+func (m dbStockMovement) IsValid() bool {
+	return m.ingredientID != id.NilID() && m.kind.IsValid() && m.at != ""
+}
+
+type Inventory struct {
+	*DB
+}
+
+func NewInventory(db *DB) *Inventory {
+	return &Inventory{DB: db}
+}
+
+func (i *Inventory) SaveIngredient(ctx context.Context, ingredient domain.Ingredient) error {
+	if !ingredient.IsValid() {
+		return domain.Errorf(domain.EINVALID, "ingredient is invalid: %v", ingredient)
+	}
+
+	dbIngredient := toDBIngredient(ingredient)
+	if !dbIngredient.IsValid() {
+		return domain.Errorf(domain.EINVALID, "ingredient is invalid: %v", dbIngredient)
+	}
+
+	_, err := i.ExecContext(ctx, `
+		INSERT INTO ingredients (id, name, unit, stock, low_stock_threshold)
+		VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET
+				name = excluded.name, unit = excluded.unit, stock = excluded.stock, low_stock_threshold = excluded.low_stock_threshold
+	`, dbIngredient.id, dbIngredient.name, dbIngredient.unit, dbIngredient.stock, dbIngredient.lowStockThreshold)
+	if err != nil {
+		return fmt.Errorf("failed to insert ingredient: %w", err)
+	}
+
+	return nil
+}
+
+func (i *Inventory) FindIngredient(ctx context.Context, id id.ID) (domain.Ingredient, error) {
+	return i.findIngredient(ctx, i, id)
+}
+
+func (i *Inventory) FindIngredients(ctx context.Context, ids []id.ID) ([]domain.Ingredient, error) {
+	ingredients := make([]domain.Ingredient, 0, len(ids))
+	if len(ids) == 0 {
+		return ingredients, nil
+	}
+
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(`
+		SELECT id, name, unit, stock, low_stock_threshold
+		FROM ingredients
+		WHERE id IN (%s)
+	`, strings.Repeat(", ?", len(ids))[2:]), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ingredients: %w", err)
+	}
+
+	ingredients, err = scanIngredients(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ingredients) != len(ids) {
+		return nil, domain.Errorf(domain.ENOTFOUND, "failed to find all ingredients")
+	}
+
+	return ingredients, nil
+}
+
+func (i *Inventory) FindAllIngredients(ctx context.Context) ([]domain.Ingredient, error) {
+	rows, err := i.QueryContext(ctx, `
+		SELECT id, name, unit, stock, low_stock_threshold
+		FROM ingredients
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ingredients: %w", err)
+	}
+
+	return scanIngredients(rows)
+}
+
+func (i *Inventory) AdjustStock(ctx context.Context, movements []domain.StockMovement) ([]domain.Ingredient, error) {
+	dbMovements := make([]dbStockMovement, 0, len(movements))
+	for _, movement := range movements {
+		if !movement.IsValid() {
+			return nil, domain.Errorf(domain.EINVALID, "stock movement is invalid: %v", movement)
+		}
+
+		dbMovement := toDBStockMovement(movement)
+		if !dbMovement.IsValid() {
+			return nil, domain.Errorf(domain.EINVALID, "stock movement is invalid: %v", dbMovement)
+		}
+		dbMovements = append(dbMovements, dbMovement)
+	}
+
+	tx, err := i.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	adjusted := make([]id.ID, 0, len(dbMovements))
+	for _, movement := range dbMovements {
+		res, err := tx.ExecContext(ctx, `UPDATE ingredients SET stock = stock + ? WHERE id = ?`, movement.delta, movement.ingredientID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to adjust stock: %w", err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to adjust stock: %w", err)
+		}
+
+		if affected == 0 {
+			return nil, domain.Errorf(domain.ENOTFOUND, "ingredient with id %s not found", movement.ingredientID)
+		}
+
+		if err := insertStockMovement(ctx, tx, movement); err != nil {
+			return nil, err
+		}
+
+		if !slices.Contains(adjusted, movement.ingredientID) {
+			adjusted = append(adjusted, movement.ingredientID)
+		}
+	}
+
+	ingredients := make([]domain.Ingredient, 0, len(adjusted))
+	for _, ingredientID := range adjusted {
+		ingredient, err := i.findIngredient(ctx, tx, ingredientID)
+		if err != nil {
+			return nil, err
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+
+	return ingredients, tx.Commit()
+}
+
+func (i *Inventory) SetStock(ctx context.Context, ingredientID id.ID, stock int, at time.Time) (domain.Ingredient, error) {
+	tx, err := i.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Ingredient{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	counted, err := i.findIngredient(ctx, tx, ingredientID)
+	if err != nil {
+		return domain.Ingredient{}, err
+	}
+
+	movement := domain.StockMovement{IngredientID: ingredientID, Kind: domain.StockMovementCount, Delta: stock - counted.Stock, At: at}
+	if !movement.IsValid() {
+		return domain.Ingredient{}, domain.Errorf(domain.EINVALID, "stock movement is invalid: %v", movement)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE ingredients SET stock = ? WHERE id = ?`, stock, ingredientID); err != nil {
+		return domain.Ingredient{}, fmt.Errorf("failed to set stock: %w", err)
+	}
+
+	if err := insertStockMovement(ctx, tx, toDBStockMovement(movement)); err != nil {
+		return domain.Ingredient{}, err
+	}
+
+	ingredient, err := i.findIngredient(ctx, tx, ingredientID)
+	if err != nil {
+		return domain.Ingredient{}, err
+	}
+
+	return ingredient, tx.Commit()
+}
+
+func (i *Inventory) FindStockMovements(ctx context.Context, ingredientID id.ID) ([]domain.StockMovement, error) {
+	rows, err := i.QueryContext(ctx, `
+		SELECT ingredient_id, kind, delta, reason, preparation_id, at
+		FROM stock_movements
+		WHERE ingredient_id = ?
+		ORDER BY at, rowid
+	`, ingredientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock movements: %w", err)
+	}
+	defer rows.Close()
+
+	movements := make([]domain.StockMovement, 0)
+	for rows.Next() {
+		var movement dbStockMovement
+		err := rows.Scan(&movement.ingredientID, &movement.kind, &movement.delta, &movement.reason, &movement.preparationID, &movement.at)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock movement: %w", err)
+		}
+
+		if !movement.IsValid() {
+			return nil, domain.Errorf(domain.EINVALID, "stock movement is invalid: %v", movement)
+		}
+
+		domainMovement, err := toDomainStockMovement(movement)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements, domainMovement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query stock movements: %w", err)
+	}
+
+	return movements, nil
+}
+
+func (i *Inventory) SaveRecipe(ctx context.Context, recipe domain.Recipe) error {
+	if !recipe.IsValid() {
+		return domain.Errorf(domain.EINVALID, "recipe is invalid: %v", recipe)
+	}
+
+	tx, err := i.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recipe_lines WHERE menu_item_id = ?`, recipe.MenuItemID); err != nil {
+		return fmt.Errorf("failed to delete recipe lines: %w", err)
+	}
+
+	if len(recipe.Lines) > 0 {
+		args := make([]interface{}, 0, len(recipe.Lines)*3)
+		for _, line := range recipe.Lines {
+			dbLine := dbRecipeLine{menuItemID: recipe.MenuItemID, ingredientID: line.IngredientID, quantity: line.Quantity}
+			if !dbLine.IsValid() {
+				return domain.Errorf(domain.EINVALID, "recipe line is invalid: %v", dbLine)
+			}
+			args = append(args, dbLine.menuItemID, dbLine.ingredientID, dbLine.quantity)
+		}
+
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+			INSERT INTO recipe_lines (menu_item_id, ingredient_id, quantity)
+			VALUES %s
+		`, strings.Repeat(", (?, ?, ?)", len(recipe.Lines))[2:]), args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert recipe lines: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (i *Inventory) FindRecipes(ctx context.Context, menuItemIDs []id.ID) ([]domain.Recipe, error) {
+	if len(menuItemIDs) == 0 {
+		return make([]domain.Recipe, 0), nil
+	}
+
+	args := make([]interface{}, 0, len(menuItemIDs))
+	for _, id := range menuItemIDs {
+		args = append(args, id)
+	}
+
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(`
+		SELECT menu_item_id, ingredient_id, quantity
+		FROM recipe_lines
+		WHERE menu_item_id IN (%s)
+		ORDER BY rowid
+	`, strings.Repeat(", ?", len(menuItemIDs))[2:]), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recipe lines: %w", err)
+	}
+
+	return scanRecipes(rows)
+}
+
+func (i *Inventory) FindRecipesByIngredient(ctx context.Context, ingredientID id.ID) ([]domain.Recipe, error) {
+	rows, err := i.QueryContext(ctx, `
+		SELECT menu_item_id, ingredient_id, quantity
+		FROM recipe_lines
+		WHERE menu_item_id IN (
+			SELECT menu_item_id
+			FROM recipe_lines
+			WHERE ingredient_id = ?
+		)
+		ORDER BY rowid
+	`, ingredientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recipe lines: %w", err)
+	}
+
+	return scanRecipes(rows)
+}
+
+func (i *Inventory) findIngredient(ctx context.Context, q queryer, ingredientID id.ID) (domain.Ingredient, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT id, name, unit, stock, low_stock_threshold
+		FROM ingredients
+		WHERE id = ?
+	`, ingredientID)
+	if err != nil {
+		return domain.Ingredient{}, fmt.Errorf("failed to find ingredient: %w", err)
+	}
+
+	ingredients, err := scanIngredients(rows)
+	if err != nil {
+		return domain.Ingredient{}, err
+	}
+
+	if len(ingredients) == 0 {
+		return domain.Ingredient{}, domain.Errorf(domain.ENOTFOUND, "ingredient with id %s not found", ingredientID)
+	}
+
+	return ingredients[0], nil
+}
+
+// scanIngredients reads the ingredients of the rows, which it closes.
+func scanIngredients(rows *sql.Rows) ([]domain.Ingredient, error) {
+	defer rows.Close()
+
+	ingredients := make([]domain.Ingredient, 0)
+	for rows.Next() {
+		var ingredient dbIngredient
+		err := rows.Scan(&ingredient.id, &ingredient.name, &ingredient.unit, &ingredient.stock, &ingredient.lowStockThreshold)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan ingredient: %w", err)
+		}
+		ingredients = append(ingredients, toDomainIngredient(ingredient))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query ingredients: %w", err)
+	}
+
+	return ingredients, nil
+}
+
+// scanRecipes reads the recipe lines of the rows, which it closes, and groups them per menu item.
+func scanRecipes(rows *sql.Rows) ([]domain.Recipe, error) {
+	defer rows.Close()
+
+	recipes := make([]domain.Recipe, 0)
+	for rows.Next() {
+		var line dbRecipeLine
+		if err := rows.Scan(&line.menuItemID, &line.ingredientID, &line.quantity); err != nil {
+			return nil, fmt.Errorf("failed to scan recipe line: %w", err)
+		}
+
+		k := len(recipes) - 1
+		for ; k >= 0; k-- {
+			if recipes[k].MenuItemID == line.menuItemID {
+				break
+			}
+		}
+
+		if k < 0 {
+			recipes = append(recipes, domain.Recipe{MenuItemID: line.menuItemID, Lines: make([]domain.RecipeLine, 0)})
+			k = len(recipes) - 1
+		}
+
+		recipes[k].Lines = append(recipes[k].Lines, domain.RecipeLine{IngredientID: line.ingredientID, Quantity: line.quantity})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query recipe lines: %w", err)
+	}
+
+	return recipes, nil
+}
+
+func toDBIngredient(ingredient domain.Ingredient) dbIngredient {
+	dbIngredient := dbIngredient{
+		id:    ingredient.ID,
+		name:  ingredient.Name,
+		unit:  string(ingredient.Unit),
+		stock: ingredient.Stock,
+	}
+
+	if ingredient.LowStockThreshold != nil {
+		dbIngredient.lowStockThreshold = sql.NullInt64{Int64: int64(*ingredient.LowStockThreshold), Valid: true}
+	}
+
+	return dbIngredient
+}
+
+func toDomainIngredient(ingredient dbIngredient) domain.Ingredient {
+	var threshold *int
+	if ingredient.lowStockThreshold.Valid {
+		n := int(ingredient.lowStockThreshold.Int64)
+		threshold = &n
+	}
+
+	return domain.Ingredient{
+		ID:                ingredient.id,
+		Name:              ingredient.name,
+		Unit:              domain.Unit(ingredient.unit),
+		Stock:             ingredient.stock,
+		LowStockThreshold: threshold,
+	}
+}
+
+func insertStockMovement(ctx context.Context, tx *sql.Tx, movement dbStockMovement) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO stock_movements (ingredient_id, kind, delta, reason, preparation_id, at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, movement.ingredientID, movement.kind, movement.delta, movement.reason, nullableID(movement.preparationID), movement.at)
+	if err != nil {
+		return fmt.Errorf("failed to insert stock movement: %w", err)
+	}
+
+	return nil
+}
+
+func toDBStockMovement(movement domain.StockMovement) dbStockMovement {
+	return dbStockMovement{
+		ingredientID:  movement.IngredientID,
+		kind:          dbStockMovementKind(movement.Kind),
+		delta:         movement.Delta,
+		reason:        movement.Reason,
+		preparationID: movement.PreparationID,
+		at:            movement.At.UTC().Format(dbTimeLayout),
+	}
+}
+
+func toDomainStockMovement(movement dbStockMovement) (domain.StockMovement, error) {
+	at, err := time.Parse(dbTimeLayout, movement.at)
+	if err != nil {
+		return domain.StockMovement{}, fmt.Errorf("failed to parse stock movement time: %w", err)
+	}
+
+	return domain.StockMovement{
+		IngredientID:  movement.ingredientID,
+		Kind:          domain.StockMovementKind(movement.kind),
+		Delta:         movement.delta,
+		Reason:        movement.reason,
+		PreparationID: movement.preparationID,
+		At:            at,
+	}, nil
+}
@@ -0,0 +1,156 @@
+package sqlite_test
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"order_manager/internal/sqlite"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func GenerateDummyIngredient(name string) domain.Ingredient {
+	threshold := 100
+	return domain.Ingredient{
+		ID:                id.New(),
+		Name:              name,
+		Unit:              domain.UnitGram,
+		Stock:             1000,
+		LowStockThreshold: &threshold,
+	}
+}
+
+func TestSaveAndRetrieveIngredient(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	inventoryRepo := sqlite.NewInventory(db)
+	flour := GenerateDummyIngredient("flour")
+	eggs := domain.Ingredient{ID: id.New(), Name: "eggs", Unit: domain.UnitPiece, Stock: 60}
+
+	for _, ingredient := range []domain.Ingredient{flour, eggs} {
+		err := inventoryRepo.SaveIngredient(context.Background(), ingredient)
+		require.NoErrorf(t, err, "failed to save ingredient: %v", err)
+	}
+
+	gotIngredient, err := inventoryRepo.FindIngredient(context.Background(), flour.ID)
+	require.NoErrorf(t, err, "failed to retrieve ingredient: %v", err)
+	assert.Equal(t, flour, gotIngredient)
+
+	gotIngredients, err := inventoryRepo.FindAllIngredients(context.Background())
+	require.NoErrorf(t, err, "failed to retrieve ingredients: %v", err)
+	assert.Equal(t, []domain.Ingredient{eggs, flour}, gotIngredients)
+
+	_, err = inventoryRepo.FindIngredients(context.Background(), []id.ID{flour.ID, id.New()})
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+
+	_, err = inventoryRepo.FindIngredient(context.Background(), id.New())
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+}
+
+func TestAdjustAndSetStock(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	ctx := context.Background()
+	inventoryRepo := sqlite.NewInventory(db)
+	flour := GenerateDummyIngredient("flour")
+	sugar := GenerateDummyIngredient("sugar")
+	for _, ingredient := range []domain.Ingredient{flour, sugar} {
+		require.NoError(t, inventoryRepo.SaveIngredient(ctx, ingredient))
+	}
+
+	stock := func(ingredientID id.ID) int {
+		t.Helper()
+		ingredient, err := inventoryRepo.FindIngredient(ctx, ingredientID)
+		require.NoErrorf(t, err, "failed to retrieve ingredient: %v", err)
+		return ingredient.Stock
+	}
+
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	prepID := id.New()
+	movements := []domain.StockMovement{
+		{IngredientID: flour.ID, Kind: domain.StockMovementConsumption, Delta: -1000, PreparationID: prepID, At: now},
+		{IngredientID: sugar.ID, Kind: domain.StockMovementDelivery, Delta: 50, At: now},
+		{IngredientID: flour.ID, Kind: domain.StockMovementAdjustment, Delta: -200, Reason: "spilled", At: now},
+	}
+	adjusted, err := inventoryRepo.AdjustStock(ctx, movements)
+	require.NoErrorf(t, err, "failed to adjust stock: %v", err)
+	require.Len(t, adjusted, 2)
+	assert.Equal(t, flour.ID, adjusted[0].ID, "ingredients not in the order of their movements")
+	assert.Equal(t, -200, stock(flour.ID), "the stock goes negative")
+	assert.Equal(t, 1050, stock(sugar.ID))
+
+	// Nothing is adjusted when an ingredient is missing.
+	_, err = inventoryRepo.AdjustStock(ctx, []domain.StockMovement{
+		{IngredientID: sugar.ID, Kind: domain.StockMovementDelivery, Delta: 50, At: now},
+		{IngredientID: id.New(), Kind: domain.StockMovementDelivery, Delta: 10, At: now},
+	})
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+	assert.Equal(t, 1050, stock(sugar.ID))
+
+	_, err = inventoryRepo.AdjustStock(ctx, []domain.StockMovement{{IngredientID: sugar.ID, Kind: domain.StockMovementAdjustment, Delta: 50, At: now}})
+	assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "adjustment without reason recorded")
+
+	counted, err := inventoryRepo.SetStock(ctx, flour.ID, 300, now.Add(time.Hour))
+	require.NoErrorf(t, err, "failed to set stock: %v", err)
+	assert.Equal(t, 300, counted.Stock)
+	assert.Equal(t, 300, stock(flour.ID))
+
+	_, err = inventoryRepo.SetStock(ctx, id.New(), 300, now)
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+
+	gotMovements, err := inventoryRepo.FindStockMovements(ctx, flour.ID)
+	require.NoErrorf(t, err, "failed to retrieve stock movements: %v", err)
+	counting := domain.StockMovement{IngredientID: flour.ID, Kind: domain.StockMovementCount, Delta: 500, At: now.Add(time.Hour)}
+	assert.Equal(t, []domain.StockMovement{movements[0], movements[2], counting}, gotMovements)
+
+	gotMovements, err = inventoryRepo.FindStockMovements(ctx, sugar.ID)
+	require.NoErrorf(t, err, "failed to retrieve stock movements: %v", err)
+	assert.Equal(t, []domain.StockMovement{movements[1]}, gotMovements, "movements of a failed adjustment recorded")
+}
+
+func TestSaveAndRetrieveRecipes(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	ctx := context.Background()
+	menuRepo := sqlite.NewMenu(db)
+	inventoryRepo := sqlite.NewInventory(db)
+
+	pancakes := GenerateDummyItem()
+	cake := GenerateDummyItem()
+	require.NoError(t, menuRepo.SaveItems(ctx, []domain.MenuItem{pancakes, cake}))
+
+	flour := GenerateDummyIngredient("flour")
+	eggs := GenerateDummyIngredient("eggs")
+	sugar := GenerateDummyIngredient("sugar")
+	for _, ingredient := range []domain.Ingredient{flour, eggs, sugar} {
+		require.NoError(t, inventoryRepo.SaveIngredient(ctx, ingredient))
+	}
+
+	pancakesRecipe := domain.Recipe{MenuItemID: pancakes.ID, Lines: []domain.RecipeLine{{IngredientID: flour.ID, Quantity: 120}, {IngredientID: eggs.ID, Quantity: 2}}}
+	cakeRecipe := domain.Recipe{MenuItemID: cake.ID, Lines: []domain.RecipeLine{{IngredientID: sugar.ID, Quantity: 200}, {IngredientID: flour.ID, Quantity: 250}}}
+	for _, recipe := range []domain.Recipe{pancakesRecipe, cakeRecipe} {
+		err := inventoryRepo.SaveRecipe(ctx, recipe)
+		require.NoErrorf(t, err, "failed to save recipe: %v", err)
+	}
+
+	gotRecipes, err := inventoryRepo.FindRecipes(ctx, []id.ID{pancakes.ID, cake.ID, id.New()})
+	require.NoErrorf(t, err, "failed to retrieve recipes: %v", err)
+	assert.Equal(t, []domain.Recipe{pancakesRecipe, cakeRecipe}, gotRecipes)
+
+	gotRecipes, err = inventoryRepo.FindRecipesByIngredient(ctx, sugar.ID)
+	require.NoErrorf(t, err, "failed to retrieve recipes: %v", err)
+	assert.Equal(t, []domain.Recipe{cakeRecipe}, gotRecipes)
+
+	err = inventoryRepo.SaveRecipe(ctx, domain.Recipe{MenuItemID: cake.ID, Lines: make([]domain.RecipeLine, 0)})
+	require.NoErrorf(t, err, "failed to remove recipe: %v", err)
+
+	gotRecipes, err = inventoryRepo.FindRecipesByIngredient(ctx, flour.ID)
+	require.NoErrorf(t, err, "failed to retrieve recipes: %v", err)
+	assert.Equal(t, []domain.Recipe{pancakesRecipe}, gotRecipes)
+}
@@ -6,17 +6,50 @@ import (
 	"fmt"
 	"order_manager/internal/domain"
 	"order_manager/internal/id"
+	"slices"
 	"strings"
 )
 
 type dbMenuItem struct {
-	id    id.ID  `db:"id"`
-	name  string `db:"name"`
-	price int    `db:"price"`
+	id          id.ID         `db:"id"`
+	name        string        `db:"name"`
+	price       int           `db:"price"`
+	taxCategory string        `db:"tax_category"`
+	unavailable bool          `db:"unavailable"`
+	portions    sql.NullInt64 `db:"portions"`
 }
 
 func (i dbMenuItem) IsValid() bool {
-	return i.id != id.NilID() && i.name != "" && i.price >= 0
+	return i.id != id.NilID() && i.name != "" && i.price >= 0 && (!i.portions.Valid || i.portions.Int64 >= 0)
+}
+
+type dbModifierGroup struct {
+	id            id.ID  `db:"id"`
+	menuItemID    id.ID  `db:"menu_item_id"`
+	name          string `db:"name"`
+	minSelections int    `db:"min_selections"`
+	maxSelections int    `db:"max_selections"`
+	position      int    `db:"position"`
+}
+
+func (g dbModifierGroup) IsValid() bool {
+	return g.id != id.NilID() && g.menuItemID != id.NilID() && g.name != "" && g.minSelections >= 0 && g.maxSelections >= 1 && g.maxSelections >= g.minSelections
+}
+
+type dbModifierOption struct {
+	id         id.ID  `db:"id"`
+	groupID    id.ID  `db:"group_id"`
+	name       string `db:"name"`
+	priceDelta int    `db:"price_delta"`
+	position   int    `db:"position"`
+}
+
+func (o dbModifierOption) IsValid() bool {
+	return o.id != id.NilID() && o.groupID != id.NilID() && o.name != ""
+}
+
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
 }
 
 type dbMenuItemCategory struct {
@@ -50,15 +83,29 @@ func (m *Menu) SaveItem(ctx context.Context, item domain.MenuItem) error {
 		return domain.Errorf(domain.EINVALID, "menu item is invalid: %v", item)
 	}
 
-	_, err := m.ExecContext(ctx, `
-		INSERT INTO menu_items (id, name, price)
-		VALUES (?, ?, ?)
-	`, item.ID, item.Name, item.Price)
+	tx, err := m.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	dbItem := toDBMenuItem(item)
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO menu_items (id, name, price, tax_category, unavailable, portions)
+		VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET
+				name = excluded.name, price = excluded.price, tax_category = excluded.tax_category,
+				unavailable = excluded.unavailable, portions = excluded.portions
+	`, dbItem.id, dbItem.name, dbItem.price, dbItem.taxCategory, dbItem.unavailable, dbItem.portions)
 	if err != nil {
 		return fmt.Errorf("failed to insert item: %w", err)
 	}
 
-	return nil
+	if err := m.saveModifierGroups(ctx, tx, item); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (m *Menu) SaveItems(ctx context.Context, items []domain.MenuItem) error {
@@ -80,26 +127,28 @@ func (m *Menu) SaveItems(ctx context.Context, items []domain.MenuItem) error {
 
 	dbItems := make([]dbMenuItem, 0, len(items))
 	for _, item := range items {
-		dbItems = append(dbItems, dbMenuItem{
-			id:    item.ID,
-			name:  item.Name,
-			price: item.Price,
-		})
+		dbItems = append(dbItems, toDBMenuItem(item))
 	}
 
 	if err := m.insertItems(ctx, tx, dbItems); err != nil {
 		return err
 	}
 
+	for _, item := range items {
+		if err := m.saveModifierGroups(ctx, tx, item); err != nil {
+			return err
+		}
+	}
+
 	return tx.Commit()
 }
 
 func (m *Menu) FindItem(ctx context.Context, id id.ID) (domain.MenuItem, error) {
 	var item dbMenuItem
 	err := m.QueryRowContext(ctx, `
-		SELECT id, name, price
+		SELECT id, name, price, tax_category, unavailable, portions
 		FROM menu_items WHERE id = ?
-		`, id).Scan(&item.id, &item.name, &item.price)
+		`, id).Scan(&item.id, &item.name, &item.price, &item.taxCategory, &item.unavailable, &item.portions)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return domain.MenuItem{}, domain.Errorf(domain.ENOTFOUND, "failed to find item with id %s", id)
@@ -107,11 +156,12 @@ func (m *Menu) FindItem(ctx context.Context, id id.ID) (domain.MenuItem, error)
 		return domain.MenuItem{}, fmt.Errorf("failed to find item: %w", err)
 	}
 
-	return domain.MenuItem{
-		ID:    item.id,
-		Name:  item.name,
-		Price: item.price,
-	}, nil
+	items := []domain.MenuItem{toDomainMenuItem(item)}
+	if err := m.withModifierGroups(ctx, m, items); err != nil {
+		return domain.MenuItem{}, err
+	}
+
+	return items[0], nil
 }
 
 func (m *Menu) FindItems(ctx context.Context, ids []id.ID) ([]domain.MenuItem, error) {
@@ -120,8 +170,16 @@ func (m *Menu) FindItems(ctx context.Context, ids []id.ID) ([]domain.MenuItem, e
 		return items, nil
 	}
 
+	distinct := make([]id.ID, 0, len(ids))
+	for _, id := range ids {
+		if !slices.Contains(distinct, id) {
+			distinct = append(distinct, id)
+		}
+	}
+	ids = distinct
+
 	query := fmt.Sprintf(`
-		SELECT id, name, price 
+		SELECT id, name, price, tax_category, unavailable, portions
 		FROM menu_items
 		WHERE id IN (%s)
 		`, strings.Repeat(", ?", len(ids))[2:])
@@ -138,27 +196,27 @@ func (m *Menu) FindItems(ctx context.Context, ids []id.ID) ([]domain.MenuItem, e
 
 	for rows.Next() {
 		var item dbMenuItem
-		if err := rows.Scan(&item.id, &item.name, &item.price); err != nil {
+		if err := rows.Scan(&item.id, &item.name, &item.price, &item.taxCategory, &item.unavailable, &item.portions); err != nil {
 			return nil, fmt.Errorf("failed to scan item: %w", err)
 		}
 
-		items = append(items, domain.MenuItem{
-			ID:    item.id,
-			Name:  item.name,
-			Price: item.price,
-		})
+		items = append(items, toDomainMenuItem(item))
 	}
 
 	if len(items) != len(ids) {
 		return nil, domain.Errorf(domain.ENOTFOUND, "failed to find all items")
 	}
 
+	if err := m.withModifierGroups(ctx, m, items); err != nil {
+		return nil, err
+	}
+
 	return items, nil
 }
 
 func (m *Menu) FindAllItems(ctx context.Context) ([]domain.MenuItem, error) {
 	rows, err := m.QueryContext(ctx, `
-		SELECT id, name, price
+		SELECT id, name, price, tax_category, unavailable, portions
 		FROM menu_items
 	`)
 	if err != nil {
@@ -168,20 +226,152 @@ func (m *Menu) FindAllItems(ctx context.Context) ([]domain.MenuItem, error) {
 	items := make([]domain.MenuItem, 0)
 	for rows.Next() {
 		var item dbMenuItem
-		if err := rows.Scan(&item.id, &item.name, &item.price); err != nil {
+		if err := rows.Scan(&item.id, &item.name, &item.price, &item.taxCategory, &item.unavailable, &item.portions); err != nil {
 			return nil, fmt.Errorf("failed to scan item: %w", err)
 		}
 
-		items = append(items, domain.MenuItem{
-			ID:    item.id,
-			Name:  item.name,
-			Price: item.price,
-		})
+		items = append(items, toDomainMenuItem(item))
+	}
+
+	if err := m.withModifierGroups(ctx, m, items); err != nil {
+		return nil, err
 	}
 
 	return items, nil
 }
 
+func (m *Menu) TakePortions(ctx context.Context, portions map[id.ID]int) error {
+	tx, err := m.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for itemID, count := range portions {
+		// The portions are only taken of an available item having enough of them left, in one statement.
+		result, err := tx.ExecContext(ctx, `
+			UPDATE menu_items
+			SET portions = portions - ?
+			WHERE id = ? AND unavailable = 0 AND (portions IS NULL OR portions >= ?)
+		`, count, itemID, count)
+		if err != nil {
+			return fmt.Errorf("failed to take portions: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to take portions: %w", err)
+		}
+
+		if affected == 0 {
+			var item dbMenuItem
+			err := tx.QueryRowContext(ctx, `
+				SELECT name, unavailable, portions
+				FROM menu_items WHERE id = ?
+			`, itemID).Scan(&item.name, &item.unavailable, &item.portions)
+			if err != nil {
+				if err == sql.ErrNoRows {
+					return domain.Errorf(domain.ENOTFOUND, "failed to find item with id %s", itemID)
+				}
+				return fmt.Errorf("failed to find item: %w", err)
+			}
+
+			if item.unavailable {
+				return domain.Errorf(domain.ECONFLICT, "%s is unavailable", item.name)
+			}
+			return domain.Errorf(domain.ECONFLICT, "%s has only %d portions left", item.name, item.portions.Int64)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (m *Menu) ReturnPortions(ctx context.Context, portions map[id.ID]int) error {
+	tx, err := m.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for itemID, count := range portions {
+		result, err := tx.ExecContext(ctx, `
+			UPDATE menu_items
+			SET portions = portions + ?
+			WHERE id = ?
+		`, count, itemID)
+		if err != nil {
+			return fmt.Errorf("failed to return portions: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to return portions: %w", err)
+		}
+
+		if affected == 0 {
+			return domain.Errorf(domain.ENOTFOUND, "failed to find item with id %s", itemID)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (m *Menu) SetAvailability(ctx context.Context, itemID id.ID, unavailable bool, portions *int) (domain.MenuItem, error) {
+	tx, err := m.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.MenuItem{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Only the availability is written, the portions taken concurrently are kept.
+	var result sql.Result
+	if unavailable {
+		result, err = tx.ExecContext(ctx, `
+			UPDATE menu_items
+			SET unavailable = 1
+			WHERE id = ?
+		`, itemID)
+	} else {
+		var left sql.NullInt64
+		if portions != nil {
+			left = sql.NullInt64{Int64: int64(*portions), Valid: true}
+		}
+		result, err = tx.ExecContext(ctx, `
+			UPDATE menu_items
+			SET unavailable = 0, portions = ?
+			WHERE id = ?
+		`, left, itemID)
+	}
+	if err != nil {
+		return domain.MenuItem{}, fmt.Errorf("failed to set availability: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return domain.MenuItem{}, fmt.Errorf("failed to set availability: %w", err)
+	}
+
+	if affected == 0 {
+		return domain.MenuItem{}, domain.Errorf(domain.ENOTFOUND, "failed to find item with id %s", itemID)
+	}
+
+	var item dbMenuItem
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, name, price, tax_category, unavailable, portions
+		FROM menu_items WHERE id = ?
+		`, itemID).Scan(&item.id, &item.name, &item.price, &item.taxCategory, &item.unavailable, &item.portions)
+	if err != nil {
+		return domain.MenuItem{}, fmt.Errorf("failed to find item: %w", err)
+	}
+
+	items := []domain.MenuItem{toDomainMenuItem(item)}
+	if err := m.withModifierGroups(ctx, tx, items); err != nil {
+		return domain.MenuItem{}, err
+	}
+
+	return items[0], tx.Commit()
+}
+
 func (m *Menu) SaveCategory(ctx context.Context, category domain.MenuCategory) error {
 	if !category.IsValid() {
 		return domain.Errorf(domain.EINVALID, "menu category is invalid: %v", category)
@@ -196,11 +386,20 @@ func (m *Menu) SaveCategory(ctx context.Context, category domain.MenuCategory) e
 	_, err = tx.ExecContext(ctx, `
 		INSERT INTO menu_categories (id, name)
 		VALUES (?, ?)
+			ON CONFLICT (id) DO UPDATE SET name = excluded.name
 		`, category.ID, category.Name)
 	if err != nil {
 		return fmt.Errorf("failed to insert category: %w", err)
 	}
 
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM menu_item_categories
+		WHERE category_id = ?
+		`, category.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete menu item categories: %w", err)
+	}
+
 	if len(category.MenuItems) == 0 {
 		return tx.Commit()
 	}
@@ -242,8 +441,64 @@ func (m *Menu) FindCategory(ctx context.Context, id id.ID) (domain.MenuCategory,
 		return domain.MenuCategory{}, fmt.Errorf("failed to find category: %w", err)
 	}
 
+	items, err := m.findCategoryItems(ctx, tx, category.id)
+	if err != nil {
+		return domain.MenuCategory{}, err
+	}
+
+	return domain.MenuCategory{
+		ID:        category.id,
+		Name:      category.name,
+		MenuItems: items,
+	}, tx.Commit()
+}
+
+func (m *Menu) FindAllCategories(ctx context.Context) ([]domain.MenuCategory, error) {
+	tx, err := m.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	rows, err := tx.QueryContext(ctx, `
-		SELECT id, name, price
+		SELECT id, name
+		FROM menu_categories
+		ORDER BY name, id
+		`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query categories: %w", err)
+	}
+	defer rows.Close()
+
+	var dbCategories []dbMenuItemCategory
+	for rows.Next() {
+		var category dbMenuItemCategory
+		if err := rows.Scan(&category.id, &category.name); err != nil {
+			return nil, fmt.Errorf("failed to scan category: %w", err)
+		}
+		dbCategories = append(dbCategories, category)
+	}
+
+	categories := make([]domain.MenuCategory, 0, len(dbCategories))
+	for _, category := range dbCategories {
+		items, err := m.findCategoryItems(ctx, tx, category.id)
+		if err != nil {
+			return nil, err
+		}
+
+		categories = append(categories, domain.MenuCategory{
+			ID:        category.id,
+			Name:      category.name,
+			MenuItems: items,
+		})
+	}
+
+	return categories, tx.Commit()
+}
+
+func (m *Menu) findCategoryItems(ctx context.Context, tx *sql.Tx, categoryID id.ID) ([]domain.MenuItem, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, name, price, tax_category, unavailable, portions
 		FROM menu_items 
 		WHERE id 
 		IN (
@@ -251,31 +506,27 @@ func (m *Menu) FindCategory(ctx context.Context, id id.ID) (domain.MenuCategory,
 			FROM menu_item_categories 
 			WHERE category_id = ?
 		)
-	`, id)
+	`, categoryID)
 	if err != nil {
-		return domain.MenuCategory{}, fmt.Errorf("failed to query menu item categories: %w", err)
+		return nil, fmt.Errorf("failed to query menu item categories: %w", err)
 	}
 	defer rows.Close()
 
 	items := make([]domain.MenuItem, 0)
 	for rows.Next() {
 		var item dbMenuItem
-		if err := rows.Scan(&item.id, &item.name, &item.price); err != nil {
-			return domain.MenuCategory{}, fmt.Errorf("failed to scan menu item: %w", err)
+		if err := rows.Scan(&item.id, &item.name, &item.price, &item.taxCategory, &item.unavailable, &item.portions); err != nil {
+			return nil, fmt.Errorf("failed to scan menu item: %w", err)
 		}
 
-		items = append(items, domain.MenuItem{
-			ID:    item.id,
-			Name:  item.name,
-			Price: item.price,
-		})
+		items = append(items, toDomainMenuItem(item))
 	}
 
-	return domain.MenuCategory{
-		ID:        category.id,
-		Name:      category.name,
-		MenuItems: items,
-	}, tx.Commit()
+	if err := m.withModifierGroups(ctx, tx, items); err != nil {
+		return nil, err
+	}
+
+	return items, nil
 }
 
 func (m *Menu) insertItems(context context.Context, tx *sql.Tx, items []dbMenuItem) error {
@@ -290,12 +541,12 @@ func (m *Menu) insertItems(context context.Context, tx *sql.Tx, items []dbMenuIt
 	}
 
 	itemQuery := fmt.Sprintf(`
-		INSERT INTO menu_items (id, name, price) 
+		INSERT INTO menu_items (id, name, price, tax_category, unavailable, portions)
 		VALUES %s
-		`, strings.Repeat(", (?, ?, ?)", len(items))[2:])
-	args := make([]interface{}, 0, len(items)*3)
+		`, strings.Repeat(", (?, ?, ?, ?, ?, ?)", len(items))[2:])
+	args := make([]interface{}, 0, len(items)*6)
 	for _, i := range items {
-		args = append(args, i.id, i.name, i.price)
+		args = append(args, i.id, i.name, i.price, i.taxCategory, i.unavailable, i.portions)
 	}
 
 	_, err := tx.ExecContext(context, itemQuery, args...)
@@ -305,3 +556,154 @@ func (m *Menu) insertItems(context context.Context, tx *sql.Tx, items []dbMenuIt
 
 	return nil
 }
+
+func toDBMenuItem(item domain.MenuItem) dbMenuItem {
+	dbItem := dbMenuItem{
+		id:          item.ID,
+		name:        item.Name,
+		price:       item.Price,
+		taxCategory: item.TaxCategory,
+		unavailable: item.Unavailable,
+	}
+
+	if item.Portions != nil {
+		dbItem.portions = sql.NullInt64{Int64: int64(*item.Portions), Valid: true}
+	}
+
+	return dbItem
+}
+
+func toDomainMenuItem(item dbMenuItem) domain.MenuItem {
+	var portions *int
+	if item.portions.Valid {
+		n := int(item.portions.Int64)
+		portions = &n
+	}
+
+	return domain.MenuItem{
+		ID:          item.id,
+		Name:        item.name,
+		Price:       item.price,
+		TaxCategory: item.taxCategory,
+		Unavailable: item.unavailable,
+		Portions:    portions,
+	}
+}
+
+// saveModifierGroups replaces the modifier groups and options of the item.
+func (m *Menu) saveModifierGroups(ctx context.Context, tx *sql.Tx, item domain.MenuItem) error {
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM modifier_groups
+		WHERE menu_item_id = ?
+		`, item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete modifier groups: %w", err)
+	}
+
+	for groupPosition, group := range item.ModifierGroups {
+		dbGroup := dbModifierGroup{
+			id:            group.ID,
+			menuItemID:    item.ID,
+			name:          group.Name,
+			minSelections: group.MinSelections,
+			maxSelections: group.MaxSelections,
+			position:      groupPosition,
+		}
+		if !dbGroup.IsValid() {
+			return domain.Errorf(domain.EINVALID, "modifier group is invalid: %v", dbGroup)
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO modifier_groups (id, menu_item_id, name, min_selections, max_selections, position)
+			VALUES (?, ?, ?, ?, ?, ?)
+			`, dbGroup.id, dbGroup.menuItemID, dbGroup.name, dbGroup.minSelections, dbGroup.maxSelections, dbGroup.position)
+		if err != nil {
+			return fmt.Errorf("failed to insert modifier group: %w", err)
+		}
+
+		for optionPosition, option := range group.Options {
+			dbOption := dbModifierOption{
+				id:         option.ID,
+				groupID:    group.ID,
+				name:       option.Name,
+				priceDelta: option.PriceDelta,
+				position:   optionPosition,
+			}
+			if !dbOption.IsValid() {
+				return domain.Errorf(domain.EINVALID, "modifier option is invalid: %v", dbOption)
+			}
+
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO modifier_options (id, group_id, name, price_delta, position)
+				VALUES (?, ?, ?, ?, ?)
+				`, dbOption.id, dbOption.groupID, dbOption.name, dbOption.priceDelta, dbOption.position)
+			if err != nil {
+				return fmt.Errorf("failed to insert modifier option: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// withModifierGroups loads the modifier groups of the items in place.
+// Items without modifier groups keep a nil slice.
+func (m *Menu) withModifierGroups(ctx context.Context, q queryer, items []domain.MenuItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT g.id, g.menu_item_id, g.name, g.min_selections, g.max_selections, o.id, o.name, o.price_delta
+		FROM modifier_groups g
+		JOIN modifier_options o ON o.group_id = g.id
+		WHERE g.menu_item_id IN (%s)
+		ORDER BY g.menu_item_id, g.position, o.position
+		`, strings.Repeat(", ?", len(items))[2:])
+	args := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		args = append(args, item.ID)
+	}
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query modifier groups: %w", err)
+	}
+	defer rows.Close()
+
+	groups := make(map[id.ID][]domain.ModifierGroup)
+	for rows.Next() {
+		var group dbModifierGroup
+		var option dbModifierOption
+		if err := rows.Scan(&group.id, &group.menuItemID, &group.name, &group.minSelections, &group.maxSelections, &option.id, &option.name, &option.priceDelta); err != nil {
+			return fmt.Errorf("failed to scan modifier group: %w", err)
+		}
+
+		itemGroups := groups[group.menuItemID]
+		if len(itemGroups) == 0 || itemGroups[len(itemGroups)-1].ID != group.id {
+			itemGroups = append(itemGroups, domain.ModifierGroup{
+				ID:            group.id,
+				Name:          group.name,
+				MinSelections: group.minSelections,
+				MaxSelections: group.maxSelections,
+			})
+		}
+
+		last := &itemGroups[len(itemGroups)-1]
+		last.Options = append(last.Options, domain.ModifierOption{
+			ID:         option.id,
+			Name:       option.name,
+			PriceDelta: option.priceDelta,
+		})
+		groups[group.menuItemID] = itemGroups
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read modifier groups: %w", err)
+	}
+
+	for i := range items {
+		items[i].ModifierGroups = groups[items[i].ID]
+	}
+
+	return nil
+}
@@ -67,6 +67,23 @@ func TestSaveAndRetrieveItemsByID(t *testing.T) {
 	assert.ElementsMatch(t, []domain.MenuItem{item1, item2, item3}, gotItems)
 }
 
+func TestRetrieveItemsByRepeatedIDs(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	item1 := GenerateDummyItem()
+	item2 := GenerateDummyItem()
+	menuRepo := sqlite.NewMenu(db)
+
+	err := menuRepo.SaveItems(context.Background(), []domain.MenuItem{item1, item2})
+	require.NoErrorf(t, err, "failed to save items: %v", err)
+
+	gotItems, err := menuRepo.FindItems(context.Background(), []id.ID{item1.ID, item2.ID, item1.ID, item1.ID})
+	require.NoErrorf(t, err, "failed to retrieve items: %v", err)
+
+	assert.ElementsMatch(t, []domain.MenuItem{item1, item2}, gotItems)
+}
+
 func TestSaveAndRetrieveItemsByEmptyID(t *testing.T) {
 	db := MustOpenDB(t)
 	defer MustCloseDB(t, db)
@@ -91,6 +108,130 @@ func TestSaveAndRetrieveItemsByEmptyID(t *testing.T) {
 	assert.ElementsMatch(t, []domain.MenuItem{}, gotItems)
 }
 
+func TestSaveAndRetrieveItemWithModifierGroups(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	item := GenerateDummyItem()
+	item.ModifierGroups = []domain.ModifierGroup{
+		{
+			ID: id.New(), Name: "Cooking", MinSelections: 1, MaxSelections: 1,
+			Options: []domain.ModifierOption{{ID: id.New(), Name: "Rare"}, {ID: id.New(), Name: "Medium"}},
+		},
+		{
+			ID: id.New(), Name: "Extras", MinSelections: 0, MaxSelections: 1,
+			Options: []domain.ModifierOption{{ID: id.New(), Name: "Bacon", PriceDelta: 200}},
+		},
+	}
+	menuRepo := sqlite.NewMenu(db)
+
+	err := menuRepo.SaveItem(context.Background(), item)
+	require.NoErrorf(t, err, "failed to save item: %v", err)
+
+	gotItem, err := menuRepo.FindItem(context.Background(), item.ID)
+	require.NoErrorf(t, err, "failed to retrieve item: %v", err)
+	assert.Equal(t, item, gotItem)
+
+	item.ModifierGroups = item.ModifierGroups[1:]
+	err = menuRepo.SaveItem(context.Background(), item)
+	require.NoErrorf(t, err, "failed to update item: %v", err)
+
+	gotItems, err := menuRepo.FindAllItems(context.Background())
+	require.NoErrorf(t, err, "failed to retrieve items: %v", err)
+	assert.Equal(t, []domain.MenuItem{item}, gotItems)
+}
+
+func TestTakeAndReturnPortions(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	ctx := context.Background()
+	menuRepo := sqlite.NewMenu(db)
+
+	portions := 2
+	soup := GenerateDummyItem()
+	soup.Portions = &portions
+	bread := GenerateDummyItem()
+	closed := GenerateDummyItem()
+	closed.Unavailable = true
+	require.NoError(t, menuRepo.SaveItems(ctx, []domain.MenuItem{soup, bread, closed}))
+
+	portionsLeft := func(itemID id.ID) *int {
+		t.Helper()
+		item, err := menuRepo.FindItem(ctx, itemID)
+		require.NoErrorf(t, err, "failed to retrieve item: %v", err)
+		return item.Portions
+	}
+
+	err := menuRepo.TakePortions(ctx, map[id.ID]int{soup.ID: 2, bread.ID: 3})
+	require.NoErrorf(t, err, "failed to take portions: %v", err)
+	assert.Equal(t, 0, *portionsLeft(soup.ID))
+	assert.Nil(t, portionsLeft(bread.ID))
+
+	err = menuRepo.TakePortions(ctx, map[id.ID]int{soup.ID: 1})
+	assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err))
+
+	err = menuRepo.TakePortions(ctx, map[id.ID]int{closed.ID: 1})
+	assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err))
+
+	err = menuRepo.TakePortions(ctx, map[id.ID]int{id.New(): 1})
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+
+	err = menuRepo.ReturnPortions(ctx, map[id.ID]int{soup.ID: 1})
+	require.NoErrorf(t, err, "failed to return portions: %v", err)
+	assert.Equal(t, 1, *portionsLeft(soup.ID))
+
+	// Nothing is taken when one of the items falls short.
+	err = menuRepo.TakePortions(ctx, map[id.ID]int{soup.ID: 1, closed.ID: 1})
+	assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err))
+	assert.Equal(t, 1, *portionsLeft(soup.ID))
+
+	err = menuRepo.ReturnPortions(ctx, map[id.ID]int{id.New(): 1})
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+}
+
+func TestSetAvailability(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	ctx := context.Background()
+	menuRepo := sqlite.NewMenu(db)
+
+	portions := 5
+	soup := GenerateDummyItem()
+	soup.Portions = &portions
+	require.NoError(t, menuRepo.SaveItem(ctx, soup))
+
+	// The item is read before the portions are taken, marking it unavailable must not write them back.
+	stale, err := menuRepo.FindItem(ctx, soup.ID)
+	require.NoError(t, err)
+	require.NoError(t, menuRepo.TakePortions(ctx, map[id.ID]int{soup.ID: 2}))
+
+	item, err := menuRepo.SetAvailability(ctx, stale.ID, true, nil)
+	require.NoErrorf(t, err, "failed to set availability: %v", err)
+	assert.True(t, item.Unavailable)
+	require.NotNil(t, item.Portions)
+	assert.Equal(t, 3, *item.Portions, "portions taken concurrently given back")
+
+	restocked := 4
+	item, err = menuRepo.SetAvailability(ctx, soup.ID, false, &restocked)
+	require.NoErrorf(t, err, "failed to set availability: %v", err)
+	assert.False(t, item.Unavailable)
+	require.NotNil(t, item.Portions)
+	assert.Equal(t, 4, *item.Portions)
+
+	item, err = menuRepo.SetAvailability(ctx, soup.ID, false, nil)
+	require.NoErrorf(t, err, "failed to set availability: %v", err)
+	assert.Nil(t, item.Portions, "portions are still counted")
+
+	gotItem, err := menuRepo.FindItem(ctx, soup.ID)
+	require.NoError(t, err)
+	assert.Equal(t, item, gotItem)
+
+	_, err = menuRepo.SetAvailability(ctx, id.New(), true, nil)
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+}
+
 func TestNotFoundItem(t *testing.T) {
 	db := MustOpenDB(t)
 	defer MustCloseDB(t, db)
@@ -241,3 +382,67 @@ func TestFindCategoryWithContextCancellation(t *testing.T) {
 	_, err := menuRepo.FindCategory(ctx, id.New())
 	assert.Error(t, err)
 }
+
+func TestUpdateCategoryItems(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	item1 := GenerateDummyItem()
+	item2 := GenerateDummyItem()
+	category := GenerateDummyCategory()
+	category.MenuItems = []domain.MenuItem{item1}
+	menuRepo := sqlite.NewMenu(db)
+	err := menuRepo.SaveItems(context.Background(), []domain.MenuItem{item1, item2})
+	require.NoErrorf(t, err, "failed to save items: %v", err)
+
+	err = menuRepo.SaveCategory(context.Background(), category)
+	require.NoErrorf(t, err, "failed to save category: %v", err)
+
+	category.Name = "renamed"
+	category.MenuItems = []domain.MenuItem{item2}
+	err = menuRepo.SaveCategory(context.Background(), category)
+	require.NoErrorf(t, err, "failed to update category: %v", err)
+
+	gotCategory, err := menuRepo.FindCategory(context.Background(), category.ID)
+	require.NoErrorf(t, err, "failed to retrieve category: %v", err)
+
+	assert.Equal(t, category, gotCategory)
+}
+
+func TestFindAllCategories(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	item := GenerateDummyItem()
+	category1 := GenerateDummyCategory()
+	category1.Name = "starters"
+	category1.MenuItems = []domain.MenuItem{item}
+	category2 := GenerateDummyCategory()
+	category2.Name = "mains"
+	menuRepo := sqlite.NewMenu(db)
+	err := menuRepo.SaveItem(context.Background(), item)
+	require.NoErrorf(t, err, "failed to save item: %v", err)
+
+	for _, category := range []domain.MenuCategory{category1, category2} {
+		err = menuRepo.SaveCategory(context.Background(), category)
+		require.NoErrorf(t, err, "failed to save category: %v", err)
+	}
+
+	gotCategories, err := menuRepo.FindAllCategories(context.Background())
+	require.NoErrorf(t, err, "failed to retrieve categories: %v", err)
+
+	assert.Equal(t, []domain.MenuCategory{category2, category1}, gotCategories, "categories not ordered by name")
+}
+
+func TestFindAllCategoriesWithContextCancellation(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	menuRepo := sqlite.NewMenu(db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := menuRepo.FindAllCategories(ctx)
+	assert.Error(t, err)
+}
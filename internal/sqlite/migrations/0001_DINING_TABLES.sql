@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS dining_tables (
+    id BLOB(16) PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    capacity INTEGER NOT NULL CHECK(capacity > 0),
+    zone TEXT NOT NULL DEFAULT ''
+);
+
+ALTER TABLE tables ADD COLUMN dining_table_id BLOB(16) REFERENCES dining_tables(id);
+ALTER TABLE tables ADD COLUMN guest_count INTEGER NOT NULL DEFAULT 0 CHECK(guest_count >= 0);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tables_opened_dining_table_id
+    ON tables (dining_table_id)
+    WHERE status = 'opened';
@@ -0,0 +1,2 @@
+ALTER TABLE tables ADD COLUMN version INTEGER NOT NULL DEFAULT 0 CHECK(version >= 0);
+ALTER TABLE bills ADD COLUMN version INTEGER NOT NULL DEFAULT 0 CHECK(version >= 0);
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS modifier_groups (
+    id BLOB(16) PRIMARY KEY,
+    menu_item_id BLOB(16) NOT NULL,
+    name TEXT NOT NULL,
+    min_selections INTEGER NOT NULL CHECK(min_selections >= 0),
+    max_selections INTEGER NOT NULL CHECK(max_selections >= 1 AND max_selections >= min_selections),
+    position INTEGER NOT NULL,
+    FOREIGN KEY (menu_item_id) REFERENCES menu_items(id)
+);
+
+CREATE TABLE IF NOT EXISTS modifier_options (
+    id BLOB(16) PRIMARY KEY,
+    group_id BLOB(16) NOT NULL,
+    name TEXT NOT NULL,
+    price_delta INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    FOREIGN KEY (group_id) REFERENCES modifier_groups(id) ON DELETE CASCADE
+);
+
+-- Chosen modifiers are copied from the menu so that later menu changes do not alter past orders.
+CREATE TABLE IF NOT EXISTS preparation_modifiers (
+    preparation_id BLOB(16) NOT NULL,
+    option_id BLOB(16) NOT NULL,
+    group_id BLOB(16) NOT NULL,
+    group_name TEXT NOT NULL,
+    option_name TEXT NOT NULL,
+    price_delta INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    PRIMARY KEY (preparation_id, option_id),
+    FOREIGN KEY (preparation_id) REFERENCES preparations(id)
+);
@@ -0,0 +1,49 @@
+ALTER TABLE preparations ADD COLUMN seat INTEGER NOT NULL DEFAULT 0 CHECK(seat >= 0);
+
+-- Bills reference the bill they were split from and accept the 'split' status.
+-- SQLite cannot alter a CHECK constraint, so the table is rebuilt.
+CREATE TABLE bills_new (
+    id BLOB(16) PRIMARY KEY,
+    table_id BLOB(16) NOT NULL,
+    parent_id BLOB(16),
+    total INTEGER NOT NULL CHECK(total >= 0),
+    paid INTEGER NOT NULL CHECK(paid >= 0 AND paid <= total),
+    status TEXT NOT NULL CHECK(status IN ('pending', 'partially paid', 'paid', 'split')),
+    version INTEGER NOT NULL DEFAULT 0 CHECK(version >= 0),
+    FOREIGN KEY (table_id) REFERENCES tables(id),
+    FOREIGN KEY (parent_id) REFERENCES bills(id)
+);
+
+INSERT INTO bills_new (id, table_id, total, paid, status, version)
+SELECT id, table_id, total, paid, status, version
+FROM bills;
+
+DROP TABLE bill_menu_items;
+DROP TABLE bills;
+ALTER TABLE bills_new RENAME TO bills;
+
+-- Bill items are charged per preparation instead of per menu item,
+-- the items of existing bills are rebuilt from the preparations of their table.
+CREATE TABLE bill_items (
+    bill_id BLOB(16) NOT NULL,
+    preparation_id BLOB(16) NOT NULL,
+    seat INTEGER NOT NULL CHECK(seat >= 0),
+    amount INTEGER NOT NULL CHECK(amount >= 0),
+    position INTEGER NOT NULL,
+    PRIMARY KEY (bill_id, preparation_id),
+    FOREIGN KEY (bill_id) REFERENCES bills(id),
+    FOREIGN KEY (preparation_id) REFERENCES preparations(id)
+);
+
+INSERT INTO bill_items (bill_id, preparation_id, seat, amount, position)
+SELECT
+    b.id,
+    p.id,
+    p.seat,
+    m.price + COALESCE((SELECT SUM(pm.price_delta) FROM preparation_modifiers pm WHERE pm.preparation_id = p.id), 0),
+    ROW_NUMBER() OVER (PARTITION BY b.id ORDER BY o.rowid, p.rowid) - 1
+FROM bills b
+JOIN orders o ON o.table_id = b.table_id
+JOIN preparations p ON p.order_id = o.id
+JOIN menu_items m ON m.id = p.menu_item_id
+WHERE p.status != 'aborted';
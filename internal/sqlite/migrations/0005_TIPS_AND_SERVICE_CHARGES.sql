@@ -0,0 +1,3 @@
+-- The service charge is part of the total, the tips are paid on top of it.
+ALTER TABLE bills ADD COLUMN service_charge INTEGER NOT NULL DEFAULT 0 CHECK(service_charge >= 0 AND service_charge <= total);
+ALTER TABLE bills ADD COLUMN tips INTEGER NOT NULL DEFAULT 0 CHECK(tips >= 0);
@@ -0,0 +1,21 @@
+ALTER TABLE menu_items ADD COLUMN tax_category TEXT NOT NULL DEFAULT '';
+
+-- The rate of an item is the one of its tax category when the bill was generated.
+ALTER TABLE bill_items ADD COLUMN tax_rate REAL NOT NULL DEFAULT 0 CHECK(tax_rate >= 0);
+
+ALTER TABLE bills ADD COLUMN tax_exclusive INTEGER NOT NULL DEFAULT 0 CHECK(tax_exclusive IN (0, 1));
+
+CREATE TABLE bill_taxes (
+    bill_id BLOB(16) NOT NULL,
+    rate REAL NOT NULL CHECK(rate >= 0),
+    net INTEGER NOT NULL CHECK(net >= 0),
+    tax INTEGER NOT NULL CHECK(tax >= 0),
+    gross INTEGER NOT NULL CHECK(gross = net + tax),
+    PRIMARY KEY (bill_id, rate),
+    FOREIGN KEY (bill_id) REFERENCES bills(id)
+);
+
+-- The existing bills were not taxed.
+INSERT INTO bill_taxes (bill_id, rate, net, tax, gross)
+SELECT id, 0, total - service_charge, 0, total - service_charge
+FROM bills;
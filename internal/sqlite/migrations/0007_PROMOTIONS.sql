@@ -0,0 +1,53 @@
+CREATE TABLE promotions (
+    id BLOB(16) PRIMARY KEY,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK(kind IN ('percentage', 'fixed_amount', 'fixed_price', 'buy_x_get_y')),
+    scope TEXT NOT NULL CHECK(scope IN ('line', 'bill')),
+    percent REAL NOT NULL DEFAULT 0 CHECK(percent >= 0 AND percent <= 100),
+    amount INTEGER NOT NULL DEFAULT 0 CHECK(amount >= 0),
+    buy_quantity INTEGER NOT NULL DEFAULT 0 CHECK(buy_quantity >= 0),
+    free_quantity INTEGER NOT NULL DEFAULT 0 CHECK(free_quantity >= 0),
+    -- The time window is stored in seconds after midnight, both ends are null when the promotion applies all day.
+    window_start INTEGER CHECK(window_start >= 0 AND window_start < 86400),
+    window_end INTEGER CHECK(window_end >= 0 AND window_end <= 86400),
+    priority INTEGER NOT NULL DEFAULT 0,
+    stackable INTEGER NOT NULL DEFAULT 0 CHECK(stackable IN (0, 1)),
+    active INTEGER NOT NULL DEFAULT 1 CHECK(active IN (0, 1)),
+    CHECK((window_start IS NULL) = (window_end IS NULL))
+);
+
+CREATE TABLE promotion_menu_items (
+    promotion_id BLOB(16) NOT NULL,
+    menu_item_id BLOB(16) NOT NULL,
+    PRIMARY KEY (promotion_id, menu_item_id),
+    FOREIGN KEY (promotion_id) REFERENCES promotions(id),
+    FOREIGN KEY (menu_item_id) REFERENCES menu_items(id)
+);
+
+ALTER TABLE bill_items ADD COLUMN discount INTEGER NOT NULL DEFAULT 0 CHECK(discount >= 0 AND discount <= amount);
+
+-- The rate of the service charge is kept to price a bill again after a manual discount,
+-- the rate of the existing bills is derived from their amounts.
+ALTER TABLE bills ADD COLUMN service_charge_rate REAL NOT NULL DEFAULT 0 CHECK(service_charge_rate >= 0);
+
+UPDATE bills
+SET service_charge_rate = COALESCE(service_charge * 100.0 / NULLIF(
+    total - service_charge - CASE WHEN tax_exclusive THEN (SELECT COALESCE(SUM(t.tax), 0) FROM bill_taxes t WHERE t.bill_id = bills.id) ELSE 0 END,
+    0
+), 0)
+WHERE service_charge > 0;
+
+CREATE TABLE bill_discounts (
+    bill_id BLOB(16) NOT NULL,
+    position INTEGER NOT NULL,
+    promotion_id BLOB(16),
+    preparation_id BLOB(16),
+    name TEXT NOT NULL,
+    amount INTEGER NOT NULL CHECK(amount > 0),
+    reason TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (bill_id, position),
+    FOREIGN KEY (bill_id) REFERENCES bills(id),
+    FOREIGN KEY (promotion_id) REFERENCES promotions(id),
+    FOREIGN KEY (preparation_id) REFERENCES preparations(id),
+    CHECK(promotion_id IS NOT NULL OR reason != '')
+);
@@ -0,0 +1,25 @@
+-- Every payment of a bill is recorded in a ledger, the amount paid and the tips of a bill are derived from it.
+CREATE TABLE payments (
+    id BLOB(16) PRIMARY KEY,
+    bill_id BLOB(16) NOT NULL,
+    tender TEXT NOT NULL CHECK(tender IN ('cash', 'card', 'voucher')),
+    amount INTEGER NOT NULL CHECK(amount > 0),
+    tip INTEGER NOT NULL DEFAULT 0 CHECK(tip >= 0),
+    tendered INTEGER NOT NULL DEFAULT 0 CHECK(tendered >= 0),
+    change_given INTEGER NOT NULL DEFAULT 0 CHECK(change_given >= 0),
+    -- The time of the payment is stored in UTC, in the RFC 3339 format.
+    paid_at TEXT NOT NULL,
+    FOREIGN KEY (bill_id) REFERENCES bills(id),
+    CHECK((tender = 'cash' AND tendered = amount + tip + change_given) OR (tender != 'cash' AND tendered = 0 AND change_given = 0))
+);
+
+CREATE INDEX payments_bill_id ON payments(bill_id);
+
+-- The tender of the existing payments was not recorded, each bill gets a single card payment of what was paid on it.
+INSERT INTO payments (id, bill_id, tender, amount, tip, paid_at)
+SELECT randomblob(16), id, 'card', paid, tips, strftime('%Y-%m-%dT%H:%M:%fZ', 'now')
+FROM bills
+WHERE paid > 0;
+
+ALTER TABLE bills DROP COLUMN paid;
+ALTER TABLE bills DROP COLUMN tips;
@@ -0,0 +1,54 @@
+-- Bills accept the refunded statuses, SQLite cannot alter a CHECK constraint so the table is rebuilt.
+CREATE TABLE bills_new (
+    id BLOB(16) PRIMARY KEY,
+    table_id BLOB(16) NOT NULL,
+    parent_id BLOB(16),
+    total INTEGER NOT NULL CHECK(total >= 0),
+    service_charge INTEGER NOT NULL DEFAULT 0 CHECK(service_charge >= 0 AND service_charge <= total),
+    tax_exclusive INTEGER NOT NULL DEFAULT 0 CHECK(tax_exclusive IN (0, 1)),
+    service_charge_rate REAL NOT NULL DEFAULT 0 CHECK(service_charge_rate >= 0),
+    status TEXT NOT NULL CHECK(status IN ('pending', 'partially paid', 'paid', 'split', 'partially refunded', 'refunded')),
+    version INTEGER NOT NULL DEFAULT 0 CHECK(version >= 0),
+    FOREIGN KEY (table_id) REFERENCES tables(id),
+    FOREIGN KEY (parent_id) REFERENCES bills(id)
+);
+
+INSERT INTO bills_new (id, table_id, parent_id, total, service_charge, tax_exclusive, service_charge_rate, status, version)
+SELECT id, table_id, parent_id, total, service_charge, tax_exclusive, service_charge_rate, status, version
+FROM bills;
+
+DROP TABLE bills;
+ALTER TABLE bills_new RENAME TO bills;
+
+-- Refunds are negative entries of the ledger referencing the payment they give back.
+CREATE TABLE payments_new (
+    id BLOB(16) PRIMARY KEY,
+    bill_id BLOB(16) NOT NULL,
+    tender TEXT NOT NULL CHECK(tender IN ('cash', 'card', 'voucher')),
+    amount INTEGER NOT NULL,
+    tip INTEGER NOT NULL DEFAULT 0,
+    tendered INTEGER NOT NULL DEFAULT 0 CHECK(tendered >= 0),
+    change_given INTEGER NOT NULL DEFAULT 0 CHECK(change_given >= 0),
+    refund_of BLOB(16),
+    preparation_id BLOB(16),
+    reason TEXT NOT NULL DEFAULT '',
+    -- The time of the payment is stored in UTC, in the RFC 3339 format.
+    paid_at TEXT NOT NULL,
+    FOREIGN KEY (bill_id) REFERENCES bills(id),
+    FOREIGN KEY (refund_of) REFERENCES payments(id),
+    FOREIGN KEY (preparation_id) REFERENCES preparations(id),
+    CHECK(
+        (refund_of IS NULL AND amount > 0 AND tip >= 0 AND preparation_id IS NULL AND reason = ''
+            AND ((tender = 'cash' AND tendered = amount + tip + change_given) OR (tender != 'cash' AND tendered = 0 AND change_given = 0)))
+        OR (refund_of IS NOT NULL AND amount <= 0 AND tip <= 0 AND amount + tip < 0 AND tendered = 0 AND change_given = 0 AND reason != '')
+    )
+);
+
+INSERT INTO payments_new (id, bill_id, tender, amount, tip, tendered, change_given, paid_at)
+SELECT id, bill_id, tender, amount, tip, tendered, change_given, paid_at
+FROM payments;
+
+DROP TABLE payments;
+ALTER TABLE payments_new RENAME TO payments;
+
+CREATE INDEX payments_bill_id ON payments(bill_id);
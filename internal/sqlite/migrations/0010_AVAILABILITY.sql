@@ -0,0 +1,2 @@
+ALTER TABLE menu_items ADD COLUMN unavailable INTEGER NOT NULL DEFAULT 0 CHECK(unavailable IN (0, 1));
+ALTER TABLE menu_items ADD COLUMN portions INTEGER CHECK(portions >= 0);
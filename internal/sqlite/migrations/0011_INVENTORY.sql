@@ -0,0 +1,19 @@
+-- The stock can go negative when the kitchen used more than recorded, until the next count.
+CREATE TABLE ingredients (
+    id BLOB(16) PRIMARY KEY,
+    name TEXT NOT NULL,
+    unit TEXT NOT NULL CHECK(unit IN ('g', 'ml', 'piece')),
+    stock INTEGER NOT NULL,
+    low_stock_threshold INTEGER CHECK(low_stock_threshold >= 0)
+);
+
+CREATE TABLE recipe_lines (
+    menu_item_id BLOB(16) NOT NULL,
+    ingredient_id BLOB(16) NOT NULL,
+    quantity INTEGER NOT NULL CHECK(quantity > 0),
+    PRIMARY KEY (menu_item_id, ingredient_id),
+    FOREIGN KEY (menu_item_id) REFERENCES menu_items(id),
+    FOREIGN KEY (ingredient_id) REFERENCES ingredients(id)
+);
+
+CREATE INDEX recipe_lines_ingredient_id ON recipe_lines(ingredient_id);
@@ -0,0 +1,22 @@
+-- The ordered menu item is copied onto preparations and bill items so that later menu changes do not alter past orders and bills.
+ALTER TABLE preparations ADD COLUMN menu_item_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE preparations ADD COLUMN menu_item_price INTEGER NOT NULL DEFAULT 0 CHECK(menu_item_price >= 0);
+ALTER TABLE preparations ADD COLUMN menu_item_tax_category TEXT NOT NULL DEFAULT '';
+
+UPDATE preparations
+SET
+    menu_item_name = (SELECT m.name FROM menu_items m WHERE m.id = preparations.menu_item_id),
+    menu_item_price = (SELECT m.price FROM menu_items m WHERE m.id = preparations.menu_item_id),
+    menu_item_tax_category = (SELECT m.tax_category FROM menu_items m WHERE m.id = preparations.menu_item_id);
+
+ALTER TABLE bill_items ADD COLUMN menu_item_id BLOB(16);
+ALTER TABLE bill_items ADD COLUMN menu_item_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE bill_items ADD COLUMN menu_item_price INTEGER NOT NULL DEFAULT 0 CHECK(menu_item_price >= 0);
+ALTER TABLE bill_items ADD COLUMN menu_item_tax_category TEXT NOT NULL DEFAULT '';
+
+UPDATE bill_items
+SET
+    menu_item_id = (SELECT p.menu_item_id FROM preparations p WHERE p.id = bill_items.preparation_id),
+    menu_item_name = (SELECT p.menu_item_name FROM preparations p WHERE p.id = bill_items.preparation_id),
+    menu_item_price = (SELECT p.menu_item_price FROM preparations p WHERE p.id = bill_items.preparation_id),
+    menu_item_tax_category = (SELECT p.menu_item_tax_category FROM preparations p WHERE p.id = bill_items.preparation_id);
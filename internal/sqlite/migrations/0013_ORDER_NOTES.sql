@@ -0,0 +1 @@
+ALTER TABLE preparations ADD COLUMN note TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,24 @@
+CREATE TABLE stations (
+    id BLOB(16) PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+-- A menu item or a category is routed to one station at most.
+CREATE TABLE station_menu_items (
+    station_id BLOB(16) NOT NULL,
+    menu_item_id BLOB(16) PRIMARY KEY,
+    position INTEGER NOT NULL,
+    FOREIGN KEY (station_id) REFERENCES stations(id),
+    FOREIGN KEY (menu_item_id) REFERENCES menu_items(id)
+);
+
+CREATE TABLE station_categories (
+    station_id BLOB(16) NOT NULL,
+    category_id BLOB(16) PRIMARY KEY,
+    position INTEGER NOT NULL,
+    FOREIGN KEY (station_id) REFERENCES stations(id),
+    FOREIGN KEY (category_id) REFERENCES menu_categories(id)
+);
+
+-- The preparations taken before the stations were set up are routed nowhere.
+ALTER TABLE preparations ADD COLUMN station_id BLOB(16) REFERENCES stations(id);
@@ -0,0 +1,13 @@
+-- The times are stored in UTC with a fixed number of digits so that they compare as text.
+CREATE TABLE preparation_history (
+    preparation_id BLOB(16) NOT NULL,
+    position INTEGER NOT NULL,
+    status TEXT NOT NULL,
+    at TEXT NOT NULL,
+    PRIMARY KEY (preparation_id, position),
+    FOREIGN KEY (preparation_id) REFERENCES preparations(id)
+);
+
+CREATE INDEX preparation_history_at ON preparation_history(at);
+
+-- The preparations taken before the history was recorded have none.
@@ -0,0 +1,25 @@
+-- Preparations accept the held status, SQLite cannot alter a CHECK constraint so the table is rebuilt.
+-- The preparations taken before the courses are all of the first course.
+CREATE TABLE preparations_new (
+    id BLOB(16) PRIMARY KEY,
+    order_id BLOB(16) NOT NULL,
+    menu_item_id BLOB(16) NOT NULL,
+    status TEXT NOT NULL CHECK(status IN ('held', 'pending', 'in progress', 'ready', 'served', 'aborted')),
+    seat INTEGER NOT NULL DEFAULT 0 CHECK(seat >= 0),
+    menu_item_name TEXT NOT NULL DEFAULT '',
+    menu_item_price INTEGER NOT NULL DEFAULT 0 CHECK(menu_item_price >= 0),
+    menu_item_tax_category TEXT NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    station_id BLOB(16),
+    course INTEGER NOT NULL DEFAULT 1 CHECK(course >= 0),
+    FOREIGN KEY (order_id) REFERENCES orders(id),
+    FOREIGN KEY (menu_item_id) REFERENCES menu_items(id),
+    FOREIGN KEY (station_id) REFERENCES stations(id)
+);
+
+INSERT INTO preparations_new (id, order_id, menu_item_id, status, seat, menu_item_name, menu_item_price, menu_item_tax_category, note, station_id)
+SELECT id, order_id, menu_item_id, status, seat, menu_item_name, menu_item_price, menu_item_tax_category, note, station_id
+FROM preparations;
+
+DROP TABLE preparations;
+ALTER TABLE preparations_new RENAME TO preparations;
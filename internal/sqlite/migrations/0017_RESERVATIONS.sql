@@ -0,0 +1,14 @@
+-- The times are stored in UTC with a fixed number of digits so that they compare as text.
+CREATE TABLE reservations (
+    id BLOB(16) PRIMARY KEY,
+    guest_name TEXT NOT NULL,
+    contact TEXT NOT NULL DEFAULT '',
+    party_size INTEGER NOT NULL CHECK(party_size > 0),
+    starts_at TEXT NOT NULL,
+    ends_at TEXT NOT NULL CHECK(ends_at > starts_at),
+    dining_table_id BLOB(16) REFERENCES dining_tables(id),
+    table_id BLOB(16) REFERENCES tables(id),
+    status TEXT NOT NULL CHECK(status IN ('booked', 'confirmed', 'seated', 'no show', 'cancelled'))
+);
+
+CREATE INDEX reservations_starts_at ON reservations(starts_at);
@@ -0,0 +1,18 @@
+-- The tables opened before the times were recorded have none, and are left out of the turn times.
+ALTER TABLE tables ADD COLUMN opened_at TEXT;
+ALTER TABLE tables ADD COLUMN closed_at TEXT;
+
+CREATE INDEX tables_closed_at ON tables(closed_at);
+
+CREATE TABLE waitlist (
+    id BLOB(16) PRIMARY KEY,
+    name TEXT NOT NULL,
+    contact TEXT NOT NULL DEFAULT '',
+    party_size INTEGER NOT NULL CHECK(party_size > 0),
+    joined_at TEXT NOT NULL,
+    quoted_wait INTEGER NOT NULL CHECK(quoted_wait >= 0),
+    table_id BLOB(16) REFERENCES tables(id),
+    status TEXT NOT NULL CHECK(status IN ('waiting', 'seated', 'left'))
+);
+
+CREATE INDEX waitlist_joined_at ON waitlist(joined_at);
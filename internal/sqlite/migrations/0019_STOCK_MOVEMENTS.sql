@@ -0,0 +1,12 @@
+-- A movement is written along with the change of the stock of its ingredient, the stock recorded before has none.
+-- The preparation is not a foreign key, the stock being taken on order before the preparation is saved.
+CREATE TABLE stock_movements (
+    ingredient_id BLOB(16) NOT NULL REFERENCES ingredients(id),
+    kind TEXT NOT NULL CHECK(kind IN ('delivery', 'count', 'adjustment', 'consumption', 'restock')),
+    delta INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    preparation_id BLOB(16),
+    at TEXT NOT NULL
+);
+
+CREATE INDEX stock_movements_ingredient_id ON stock_movements(ingredient_id, at);
@@ -0,0 +1,4 @@
+-- A table is charged by a single bill, its sub-bills only being created by splitting it.
+CREATE UNIQUE INDEX IF NOT EXISTS bills_table_id
+    ON bills (table_id)
+    WHERE parent_id IS NULL;
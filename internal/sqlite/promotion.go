@@ -0,0 +1,247 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"strings"
+	"time"
+)
+
+type dbPromotion struct {
+	id           id.ID         `db:"id"`
+	name         string        `db:"name"`
+	kind         string        `db:"kind"`
+	scope        string        `db:"scope"`
+	percent      float64       `db:"percent"`
+	amount       int           `db:"amount"`
+	buyQuantity  int           `db:"buy_quantity"`
+	freeQuantity int           `db:"free_quantity"`
+	windowStart  sql.NullInt64 `db:"window_start"`
+	windowEnd    sql.NullInt64 `db:"window_end"`
+	priority     int           `db:"priority"`
+	stackable    bool          `db:"stackable"`
+	active       bool          `db:"active"`
+}
+
+func (p dbPromotion) IsValid() bool {
+	return p.id != id.NilID() && p.name != "" && p.percent >= 0 && p.amount >= 0 && p.buyQuantity >= 0 && p.freeQuantity >= 0 && p.windowStart.Valid == p.windowEnd.Valid
+}
+
+type Promotion struct {
+	*DB
+}
+
+func NewPromotion(db *DB) *Promotion {
+	return &Promotion{DB: db}
+}
+
+func (p *Promotion) Save(ctx context.Context, promotion domain.Promotion) error {
+	if !promotion.IsValid() {
+		return domain.Errorf(domain.EINVALID, "promotion is invalid: %v", promotion)
+	}
+
+	dbPromotion := toDBPromotion(promotion)
+	if !dbPromotion.IsValid() {
+		return domain.Errorf(domain.EINVALID, "promotion is invalid: %v", dbPromotion)
+	}
+
+	tx, err := p.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO promotions (id, name, kind, scope, percent, amount, buy_quantity, free_quantity, window_start, window_end, priority, stackable, active)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET
+				name = excluded.name, kind = excluded.kind, scope = excluded.scope, percent = excluded.percent, amount = excluded.amount,
+				buy_quantity = excluded.buy_quantity, free_quantity = excluded.free_quantity, window_start = excluded.window_start,
+				window_end = excluded.window_end, priority = excluded.priority, stackable = excluded.stackable, active = excluded.active
+	`, dbPromotion.id, dbPromotion.name, dbPromotion.kind, dbPromotion.scope, dbPromotion.percent, dbPromotion.amount, dbPromotion.buyQuantity,
+		dbPromotion.freeQuantity, dbPromotion.windowStart, dbPromotion.windowEnd, dbPromotion.priority, dbPromotion.stackable, dbPromotion.active)
+	if err != nil {
+		return fmt.Errorf("failed to insert promotion: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM promotion_menu_items WHERE promotion_id = ?`, promotion.ID); err != nil {
+		return fmt.Errorf("failed to delete promotion menu items: %w", err)
+	}
+
+	if len(promotion.MenuItemIDs) > 0 {
+		args := make([]interface{}, 0, len(promotion.MenuItemIDs)*2)
+		for _, itemID := range promotion.MenuItemIDs {
+			args = append(args, promotion.ID, itemID)
+		}
+
+		_, err = tx.ExecContext(ctx, fmt.Sprintf(`
+			INSERT INTO promotion_menu_items (promotion_id, menu_item_id)
+			VALUES %s
+		`, strings.Repeat(", (?, ?)", len(promotion.MenuItemIDs))[2:]), args...)
+		if err != nil {
+			return fmt.Errorf("failed to insert promotion menu items: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (p *Promotion) FindByID(ctx context.Context, id id.ID) (domain.Promotion, error) {
+	tx, err := p.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Promotion{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, name, kind, scope, percent, amount, buy_quantity, free_quantity, window_start, window_end, priority, stackable, active
+		FROM promotions
+		WHERE id = ?
+	`, id)
+	if err != nil {
+		return domain.Promotion{}, fmt.Errorf("failed to find promotion: %w", err)
+	}
+
+	promotions, err := p.scanPromotions(ctx, tx, rows)
+	if err != nil {
+		return domain.Promotion{}, err
+	}
+
+	if len(promotions) == 0 {
+		return domain.Promotion{}, domain.Errorf(domain.ENOTFOUND, "promotion with id %s not found", id)
+	}
+
+	return promotions[0], tx.Commit()
+}
+
+func (p *Promotion) FindAll(ctx context.Context) ([]domain.Promotion, error) {
+	tx, err := p.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, name, kind, scope, percent, amount, buy_quantity, free_quantity, window_start, window_end, priority, stackable, active
+		FROM promotions
+		ORDER BY priority DESC, id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query promotions: %w", err)
+	}
+
+	promotions, err := p.scanPromotions(ctx, tx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return promotions, tx.Commit()
+}
+
+// scanPromotions reads the promotions of the rows, which it closes, along with their menu items.
+func (p *Promotion) scanPromotions(ctx context.Context, tx *sql.Tx, rows *sql.Rows) ([]domain.Promotion, error) {
+	defer rows.Close()
+
+	var dbPromotions []dbPromotion
+	for rows.Next() {
+		var promotion dbPromotion
+		err := rows.Scan(&promotion.id, &promotion.name, &promotion.kind, &promotion.scope, &promotion.percent, &promotion.amount, &promotion.buyQuantity,
+			&promotion.freeQuantity, &promotion.windowStart, &promotion.windowEnd, &promotion.priority, &promotion.stackable, &promotion.active)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan promotion: %w", err)
+		}
+		dbPromotions = append(dbPromotions, promotion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query promotions: %w", err)
+	}
+	rows.Close()
+
+	promotions := make([]domain.Promotion, 0, len(dbPromotions))
+	for _, dbPromotion := range dbPromotions {
+		itemIDs, err := p.findMenuItemIDs(ctx, tx, dbPromotion.id)
+		if err != nil {
+			return nil, err
+		}
+
+		promotions = append(promotions, toDomainPromotion(dbPromotion, itemIDs))
+	}
+
+	return promotions, nil
+}
+
+func (p *Promotion) findMenuItemIDs(ctx context.Context, tx *sql.Tx, promotionID id.ID) ([]id.ID, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT menu_item_id
+		FROM promotion_menu_items
+		WHERE promotion_id = ?
+		ORDER BY rowid
+	`, promotionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query promotion menu items: %w", err)
+	}
+	defer rows.Close()
+
+	itemIDs := make([]id.ID, 0)
+	for rows.Next() {
+		var itemID id.ID
+		if err := rows.Scan(&itemID); err != nil {
+			return nil, fmt.Errorf("failed to scan promotion menu item: %w", err)
+		}
+		itemIDs = append(itemIDs, itemID)
+	}
+
+	return itemIDs, rows.Err()
+}
+
+func toDBPromotion(promotion domain.Promotion) dbPromotion {
+	dbPromotion := dbPromotion{
+		id:           promotion.ID,
+		name:         promotion.Name,
+		kind:         string(promotion.Kind),
+		scope:        string(promotion.Scope),
+		percent:      promotion.Percent,
+		amount:       promotion.Amount,
+		buyQuantity:  promotion.BuyQuantity,
+		freeQuantity: promotion.FreeQuantity,
+		priority:     promotion.Priority,
+		stackable:    promotion.Stackable,
+		active:       promotion.Active,
+	}
+
+	if promotion.Window != nil {
+		dbPromotion.windowStart = sql.NullInt64{Int64: int64(promotion.Window.Start / time.Second), Valid: true}
+		dbPromotion.windowEnd = sql.NullInt64{Int64: int64(promotion.Window.End / time.Second), Valid: true}
+	}
+
+	return dbPromotion
+}
+
+func toDomainPromotion(promotion dbPromotion, itemIDs []id.ID) domain.Promotion {
+	var window *domain.TimeWindow
+	if promotion.windowStart.Valid && promotion.windowEnd.Valid {
+		window = &domain.TimeWindow{
+			Start: time.Duration(promotion.windowStart.Int64) * time.Second,
+			End:   time.Duration(promotion.windowEnd.Int64) * time.Second,
+		}
+	}
+
+	return domain.Promotion{
+		ID:           promotion.id,
+		Name:         promotion.name,
+		Kind:         domain.PromotionKind(promotion.kind),
+		Scope:        domain.PromotionScope(promotion.scope),
+		Percent:      promotion.percent,
+		Amount:       promotion.amount,
+		BuyQuantity:  promotion.buyQuantity,
+		FreeQuantity: promotion.freeQuantity,
+		MenuItemIDs:  itemIDs,
+		Window:       window,
+		Priority:     promotion.priority,
+		Stackable:    promotion.stackable,
+		Active:       promotion.active,
+	}
+}
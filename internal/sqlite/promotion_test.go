@@ -0,0 +1,102 @@
+package sqlite_test
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"order_manager/internal/sqlite"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func GenerateDummyPromotion(itemIDs ...id.ID) domain.Promotion {
+	if itemIDs == nil {
+		itemIDs = make([]id.ID, 0)
+	}
+
+	return domain.Promotion{
+		ID:          id.New(),
+		Name:        "happy hour",
+		Kind:        domain.PromotionFixedPrice,
+		Scope:       domain.PromotionScopeLine,
+		Amount:      50,
+		MenuItemIDs: itemIDs,
+		Window:      &domain.TimeWindow{Start: 17 * time.Hour, End: 19*time.Hour + 30*time.Minute},
+		Priority:    1,
+		Active:      true,
+	}
+}
+
+func TestSaveAndRetrievePromotion(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	item := domain.MenuItem{ID: id.New(), Name: "beer", Price: 80}
+	err := sqlite.NewMenu(db).SaveItem(context.Background(), item)
+	require.NoErrorf(t, err, "failed to save item: %v", err)
+
+	promotionRepo := sqlite.NewPromotion(db)
+	happyHour := GenerateDummyPromotion(item.ID)
+	billWide := domain.Promotion{
+		ID:          id.New(),
+		Name:        "ten off",
+		Kind:        domain.PromotionPercentage,
+		Scope:       domain.PromotionScopeBill,
+		Percent:     10,
+		MenuItemIDs: make([]id.ID, 0),
+		Stackable:   true,
+		Active:      true,
+	}
+
+	for _, promotion := range []domain.Promotion{billWide, happyHour} {
+		err = promotionRepo.Save(context.Background(), promotion)
+		require.NoErrorf(t, err, "failed to save promotion: %v", err)
+	}
+
+	gotPromotion, err := promotionRepo.FindByID(context.Background(), happyHour.ID)
+	require.NoErrorf(t, err, "failed to retrieve promotion: %v", err)
+	assert.Equal(t, happyHour, gotPromotion)
+
+	gotPromotions, err := promotionRepo.FindAll(context.Background())
+	require.NoErrorf(t, err, "failed to retrieve promotions: %v", err)
+	assert.Equal(t, []domain.Promotion{happyHour, billWide}, gotPromotions)
+}
+
+func TestSavePromotionTwiceUpdatesIt(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	promotionRepo := sqlite.NewPromotion(db)
+	promotion := GenerateDummyPromotion()
+
+	err := promotionRepo.Save(context.Background(), promotion)
+	require.NoErrorf(t, err, "failed to save promotion: %v", err)
+
+	promotion.Active = false
+	promotion.Window = nil
+	err = promotionRepo.Save(context.Background(), promotion)
+	require.NoErrorf(t, err, "failed to save promotion: %v", err)
+
+	gotPromotion, err := promotionRepo.FindByID(context.Background(), promotion.ID)
+	require.NoErrorf(t, err, "failed to retrieve promotion: %v", err)
+	assert.Equal(t, promotion, gotPromotion)
+}
+
+func TestSavePromotionWithUnknownMenuItem(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	err := sqlite.NewPromotion(db).Save(context.Background(), GenerateDummyPromotion(id.New()))
+	assert.Error(t, err)
+}
+
+func TestNotFoundPromotion(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	_, err := sqlite.NewPromotion(db).FindByID(context.Background(), id.New())
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+}
@@ -0,0 +1,191 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"time"
+)
+
+type dbReservationStatus string
+
+const (
+	dbReservationStatusBooked    dbReservationStatus = "booked"
+	dbReservationStatusConfirmed dbReservationStatus = "confirmed"
+	dbReservationStatusSeated    dbReservationStatus = "seated"
+	dbReservationStatusNoShow    dbReservationStatus = "no show"
+	dbReservationStatusCancelled dbReservationStatus = "cancelled"
+)
+
+func (s dbReservationStatus) IsValid() bool {
+	return s == dbReservationStatusBooked ||
+		s == dbReservationStatusConfirmed ||
+		s == dbReservationStatusSeated ||
+		s == dbReservationStatusNoShow ||
+		s == dbReservationStatusCancelled
+}
+
+type dbReservation struct {
+	id            id.ID               `db:"id"`
+	guestName     string              `db:"guest_name"`
+	contact       string              `db:"contact"`
+	partySize     int                 `db:"party_size"`
+	startsAt      string              `db:"starts_at"`
+	endsAt        string              `db:"ends_at"`
+	diningTableID id.ID               `db:"dining_table_id"`
+	tableID       id.ID               `db:"table_id"`
+	status        dbReservationStatus `db:"status"`
+}
+
+// holdsDiningTable reports whether the reservation keeps its dining table from the other reservations.
+func (r dbReservation) holdsDiningTable() bool {
+	return r.diningTableID != id.NilID() &&
+		(r.status == dbReservationStatusBooked || r.status == dbReservationStatusConfirmed || r.status == dbReservationStatusSeated)
+}
+
+func (r dbReservation) IsValid() bool {
+	return r.id != id.NilID() && r.guestName != "" && r.partySize > 0 && r.startsAt < r.endsAt && r.status.IsValid()
+}
+
+type Reservation struct {
+	*DB
+}
+
+func NewReservation(db *DB) *Reservation {
+	return &Reservation{DB: db}
+}
+
+func (r *Reservation) Save(ctx context.Context, reservation domain.Reservation) error {
+	if !reservation.IsValid() {
+		return domain.Errorf(domain.EINVALID, "reservation is invalid: %v", reservation)
+	}
+
+	dbReservation := toDBReservation(reservation)
+	if !dbReservation.IsValid() {
+		return domain.Errorf(domain.EINVALID, "reservation is invalid: %v", dbReservation)
+	}
+
+	// The reservation is only written when no other reservation holds its dining table at an overlapping time,
+	// the check and the write being a single statement so that concurrent bookings cannot both pass it.
+	res, err := r.ExecContext(ctx, `
+		INSERT INTO reservations (id, guest_name, contact, party_size, starts_at, ends_at, dining_table_id, table_id, status)
+		SELECT ?, ?, ?, ?, ?, ?, ?, ?, ?
+		WHERE NOT ? OR NOT EXISTS (
+			SELECT 1
+			FROM reservations
+			WHERE id != ? AND dining_table_id = ? AND status IN (?, ?, ?) AND starts_at < ? AND ends_at > ?
+		)
+			ON CONFLICT (id) DO UPDATE SET guest_name = excluded.guest_name, contact = excluded.contact, party_size = excluded.party_size,
+				starts_at = excluded.starts_at, ends_at = excluded.ends_at, dining_table_id = excluded.dining_table_id,
+				table_id = excluded.table_id, status = excluded.status
+		`, dbReservation.id, dbReservation.guestName, dbReservation.contact, dbReservation.partySize, dbReservation.startsAt, dbReservation.endsAt,
+		nullableID(dbReservation.diningTableID), nullableID(dbReservation.tableID), dbReservation.status,
+		dbReservation.holdsDiningTable(),
+		dbReservation.id, nullableID(dbReservation.diningTableID), dbReservationStatusBooked, dbReservationStatusConfirmed, dbReservationStatusSeated,
+		dbReservation.endsAt, dbReservation.startsAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert reservation: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to insert reservation: %w", err)
+	}
+
+	if affected == 0 {
+		return domain.Errorf(domain.ECONFLICT, "dining table %s is reserved at an overlapping time", dbReservation.diningTableID)
+	}
+
+	return nil
+}
+
+func (r *Reservation) FindByID(ctx context.Context, id id.ID) (domain.Reservation, error) {
+	var dbReservation dbReservation
+	err := r.QueryRowContext(ctx, `
+		SELECT id, guest_name, contact, party_size, starts_at, ends_at, dining_table_id, table_id, status
+		FROM reservations
+		WHERE id = ?
+		`, id).Scan(&dbReservation.id, &dbReservation.guestName, &dbReservation.contact, &dbReservation.partySize, &dbReservation.startsAt,
+		&dbReservation.endsAt, &dbReservation.diningTableID, &dbReservation.tableID, &dbReservation.status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Reservation{}, domain.Errorf(domain.ENOTFOUND, "reservation with id %s not found", id)
+		}
+		return domain.Reservation{}, fmt.Errorf("failed to find reservation: %w", err)
+	}
+
+	return toDomainReservation(dbReservation)
+}
+
+func (r *Reservation) FindBetween(ctx context.Context, from time.Time, to time.Time) ([]domain.Reservation, error) {
+	rows, err := r.QueryContext(ctx, `
+		SELECT id, guest_name, contact, party_size, starts_at, ends_at, dining_table_id, table_id, status
+		FROM reservations
+		WHERE starts_at < ? AND ends_at > ?
+		ORDER BY starts_at
+		`, to.UTC().Format(dbTimeLayout), from.UTC().Format(dbTimeLayout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reservations: %w", err)
+	}
+	defer rows.Close()
+
+	reservations := make([]domain.Reservation, 0)
+	for rows.Next() {
+		var dbReservation dbReservation
+		if err := rows.Scan(&dbReservation.id, &dbReservation.guestName, &dbReservation.contact, &dbReservation.partySize, &dbReservation.startsAt,
+			&dbReservation.endsAt, &dbReservation.diningTableID, &dbReservation.tableID, &dbReservation.status); err != nil {
+			return nil, fmt.Errorf("failed to scan reservation: %w", err)
+		}
+
+		reservation, err := toDomainReservation(dbReservation)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, reservation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query reservations: %w", err)
+	}
+
+	return reservations, nil
+}
+
+func toDBReservation(reservation domain.Reservation) dbReservation {
+	return dbReservation{
+		id:            reservation.ID,
+		guestName:     reservation.GuestName,
+		contact:       reservation.Contact,
+		partySize:     reservation.PartySize,
+		startsAt:      reservation.StartsAt.UTC().Format(dbTimeLayout),
+		endsAt:        reservation.EndsAt().UTC().Format(dbTimeLayout),
+		diningTableID: reservation.DiningTableID,
+		tableID:       reservation.TableID,
+		status:        dbReservationStatus(reservation.Status),
+	}
+}
+
+func toDomainReservation(reservation dbReservation) (domain.Reservation, error) {
+	startsAt, err := time.Parse(dbTimeLayout, reservation.startsAt)
+	if err != nil {
+		return domain.Reservation{}, fmt.Errorf("failed to parse reservation start: %w", err)
+	}
+
+	endsAt, err := time.Parse(dbTimeLayout, reservation.endsAt)
+	if err != nil {
+		return domain.Reservation{}, fmt.Errorf("failed to parse reservation end: %w", err)
+	}
+
+	return domain.Reservation{
+		ID:            reservation.id,
+		GuestName:     reservation.guestName,
+		Contact:       reservation.contact,
+		PartySize:     reservation.partySize,
+		StartsAt:      startsAt,
+		Duration:      endsAt.Sub(startsAt),
+		DiningTableID: reservation.diningTableID,
+		TableID:       reservation.tableID,
+		Status:        domain.ReservationStatus(reservation.status),
+	}, nil
+}
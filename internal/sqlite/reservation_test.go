@@ -0,0 +1,102 @@
+package sqlite_test
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"order_manager/internal/sqlite"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndRetrieveReservation(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	ctx := context.Background()
+	reservationRepo := sqlite.NewReservation(db)
+
+	diningTable := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 4}
+	require.NoError(t, sqlite.NewDiningTable(db).Save(ctx, diningTable), "Initial setup failed")
+	table := GenerateDummyTable(domain.TableStatusOpened)
+	MustPresaveItemsFromTable(t, db, table)
+	require.NoError(t, sqlite.NewTable(db).Save(ctx, table), "Initial setup failed")
+
+	dinner := time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)
+	lunch := domain.Reservation{ID: id.New(), GuestName: "Doe", PartySize: 2, StartsAt: dinner.Add(-8 * time.Hour), Duration: time.Hour, Status: domain.ReservationStatusBooked}
+	seated := domain.Reservation{ID: id.New(), GuestName: "Smith", Contact: "555-0100", PartySize: 4, StartsAt: dinner, Duration: 90 * time.Minute, DiningTableID: diningTable.ID, TableID: table.ID, Status: domain.ReservationStatusSeated}
+	late := domain.Reservation{ID: id.New(), GuestName: "Roe", PartySize: 3, StartsAt: dinner.Add(time.Hour), Duration: 2 * time.Hour, DiningTableID: diningTable.ID, Status: domain.ReservationStatusCancelled}
+	for _, reservation := range []domain.Reservation{late, seated, lunch} {
+		err := reservationRepo.Save(ctx, reservation)
+		require.NoErrorf(t, err, "failed to save reservation: %v", err)
+	}
+
+	gotReservation, err := reservationRepo.FindByID(ctx, seated.ID)
+	require.NoErrorf(t, err, "failed to retrieve reservation: %v", err)
+	assert.Equal(t, seated, gotReservation)
+
+	gotReservations, err := reservationRepo.FindBetween(ctx, dinner, dinner.Add(24*time.Hour))
+	require.NoErrorf(t, err, "failed to retrieve reservations: %v", err)
+	assert.Equal(t, []domain.Reservation{seated, late}, gotReservations)
+
+	// A reservation ending when the range starts is out of it.
+	gotReservations, err = reservationRepo.FindBetween(ctx, lunch.EndsAt(), dinner.Add(30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Reservation{seated}, gotReservations)
+
+	invalid := lunch
+	invalid.ID = id.New()
+	invalid.Status = "pending"
+	err = reservationRepo.Save(ctx, invalid)
+	assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "reservation of an unknown status saved")
+
+	_, err = reservationRepo.FindByID(ctx, id.New())
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+}
+
+func TestSaveOverlappingReservation(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	ctx := context.Background()
+	reservationRepo := sqlite.NewReservation(db)
+
+	diningTable := domain.DiningTable{ID: id.New(), Name: "T1", Capacity: 4}
+	require.NoError(t, sqlite.NewDiningTable(db).Save(ctx, diningTable), "Initial setup failed")
+
+	dinner := time.Date(2026, 10, 17, 20, 0, 0, 0, time.UTC)
+	booked := domain.Reservation{ID: id.New(), GuestName: "Smith", PartySize: 4, StartsAt: dinner, Duration: 2 * time.Hour, DiningTableID: diningTable.ID, Status: domain.ReservationStatusBooked}
+	require.NoError(t, reservationRepo.Save(ctx, booked), "Initial setup failed")
+
+	// Saving the reservation again does not conflict with itself.
+	booked.PartySize = 3
+	require.NoError(t, reservationRepo.Save(ctx, booked), "failed to update reservation")
+
+	overlapping := domain.Reservation{ID: id.New(), GuestName: "Doe", PartySize: 2, StartsAt: dinner.Add(time.Hour), Duration: time.Hour, DiningTableID: diningTable.ID, Status: domain.ReservationStatusBooked}
+	err := reservationRepo.Save(ctx, overlapping)
+	assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "overlapping reservation saved")
+
+	_, err = reservationRepo.FindByID(ctx, overlapping.ID)
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "overlapping reservation saved")
+
+	// Moving an existing reservation onto the held dining table conflicts as well.
+	overlapping.DiningTableID = id.NilID()
+	require.NoError(t, reservationRepo.Save(ctx, overlapping), "Initial setup failed")
+	overlapping.DiningTableID = diningTable.ID
+	err = reservationRepo.Save(ctx, overlapping)
+	assert.Equal(t, domain.ECONFLICT, domain.ErrorCode(err), "reservation moved onto a held dining table")
+
+	saved, err := reservationRepo.FindByID(ctx, overlapping.ID)
+	require.NoError(t, err)
+	assert.Equal(t, id.NilID(), saved.DiningTableID, "reservation moved onto a held dining table")
+
+	// A reservation starting when the other ends, or no longer holding the dining table, does not conflict.
+	next := domain.Reservation{ID: id.New(), GuestName: "Roe", PartySize: 2, StartsAt: booked.EndsAt(), Duration: time.Hour, DiningTableID: diningTable.ID, Status: domain.ReservationStatusConfirmed}
+	require.NoError(t, reservationRepo.Save(ctx, next), "reservation after the other one rejected")
+
+	overlapping.Status = domain.ReservationStatusCancelled
+	require.NoError(t, reservationRepo.Save(ctx, overlapping), "cancelled reservation rejected")
+}
@@ -4,13 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
 	"io/fs"
+	"order_manager/internal/id"
 	"os"
 	"path/filepath"
 	"sort"
+	"time"
 
-	_ "modernc.org/sqlite"
+	driver "modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
 )
 
 type logger interface{}
@@ -71,8 +75,21 @@ func (db *DB) Close() error {
 	return nil
 }
 
+// migrate runs the migrations on a single connection with the foreign keys disabled,
+// so that a migration can rebuild a table other tables refer to. The foreign keys are checked
+// before every migration is committed.
 func (db *DB) migrate() error {
-	if _, err := db.ExecContext(db.ctx, `CREATE TABLE IF NOT EXISTS migrations (filename TEXT PRIMARY KEY)`); err != nil {
+	conn, err := db.Conn(db.ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(db.ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+		return fmt.Errorf("cannot disable foreign keys: %w", err)
+	}
+
+	if _, err := conn.ExecContext(db.ctx, `CREATE TABLE IF NOT EXISTS migrations (filename TEXT PRIMARY KEY)`); err != nil {
 		return fmt.Errorf("cannot create migrations table: %w", err)
 	}
 
@@ -83,16 +100,20 @@ func (db *DB) migrate() error {
 	sort.Strings(names)
 
 	for _, name := range names {
-		if err := db.migrateFile(name); err != nil {
+		if err := db.migrateFile(conn, name); err != nil {
 			return fmt.Errorf("migration error: name=%q err=%w", name, err)
 		}
 	}
 
-	return err
+	if _, err := conn.ExecContext(db.ctx, `PRAGMA foreign_keys = ON`); err != nil {
+		return fmt.Errorf("cannot enable foreign keys: %w", err)
+	}
+
+	return nil
 }
 
-func (db *DB) migrateFile(filename string) error {
-	tx, err := db.BeginTx(db.ctx, nil)
+func (db *DB) migrateFile(conn *sql.Conn, filename string) error {
+	tx, err := conn.BeginTx(db.ctx, nil)
 	if err != nil {
 		return err
 	}
@@ -122,5 +143,47 @@ func (db *DB) migrateFile(filename string) error {
 		return err
 	}
 
+	rows, err := tx.QueryContext(db.ctx, `PRAGMA foreign_key_check`)
+	if err != nil {
+		return err
+	}
+	violated := rows.Next()
+	rows.Close()
+	if violated {
+		return fmt.Errorf("foreign key constraint failed")
+	}
+
 	return tx.Commit()
 }
+
+// dbTimeLayout formats the times of the queried ranges with a fixed width, so that they sort as text.
+const dbTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// nullableTime maps the zero time to NULL so optional times can be stored.
+func nullableTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: t.UTC().Format(dbTimeLayout), Valid: true}
+}
+
+func parseNullableTime(s sql.NullString) (time.Time, error) {
+	if !s.Valid {
+		return time.Time{}, nil
+	}
+	return time.Parse(dbTimeLayout, s.String)
+}
+
+// nullableID maps the nil ID to NULL so optional references can be stored.
+func nullableID(v id.ID) interface{} {
+	if v.IsNil() {
+		return nil
+	}
+	return v
+}
+
+// isUniqueViolation reports whether an error is a violation of a unique constraint or index.
+func isUniqueViolation(err error) bool {
+	var sqliteErr *driver.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
@@ -0,0 +1,183 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"strings"
+)
+
+type dbStation struct {
+	id   id.ID  `db:"id"`
+	name string `db:"name"`
+}
+
+func (s dbStation) IsValid() bool {
+	return s.id != id.NilID() && s.name != ""
+}
+
+type Station struct {
+	*DB
+}
+
+func NewStation(db *DB) *Station {
+	return &Station{DB: db}
+}
+
+func (s *Station) Save(ctx context.Context, station domain.Station) error {
+	if !station.IsValid() {
+		return domain.Errorf(domain.EINVALID, "station is invalid: %v", station)
+	}
+
+	dbStation := dbStation{id: station.ID, name: station.Name}
+	if !dbStation.IsValid() {
+		return domain.Errorf(domain.EINVALID, "station is invalid: %v", dbStation)
+	}
+
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO stations (id, name)
+		VALUES (?, ?)
+			ON CONFLICT (id) DO UPDATE SET name = excluded.name
+	`, dbStation.id, dbStation.name)
+	if err != nil {
+		return fmt.Errorf("failed to insert station: %w", err)
+	}
+
+	if err := s.saveRoutes(ctx, tx, "station_menu_items", "menu_item_id", station.ID, station.MenuItemIDs); err != nil {
+		return err
+	}
+
+	if err := s.saveRoutes(ctx, tx, "station_categories", "category_id", station.ID, station.CategoryIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Station) FindByID(ctx context.Context, id id.ID) (domain.Station, error) {
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.Station{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var dbStation dbStation
+	err = tx.QueryRowContext(ctx, `SELECT id, name FROM stations WHERE id = ?`, id).Scan(&dbStation.id, &dbStation.name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Station{}, domain.Errorf(domain.ENOTFOUND, "station with id %s not found", id)
+		}
+		return domain.Station{}, fmt.Errorf("failed to find station: %w", err)
+	}
+
+	station, err := s.withRoutes(ctx, tx, dbStation)
+	if err != nil {
+		return domain.Station{}, err
+	}
+
+	return station, tx.Commit()
+}
+
+func (s *Station) FindAll(ctx context.Context) ([]domain.Station, error) {
+	tx, err := s.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, name FROM stations ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stations: %w", err)
+	}
+	defer rows.Close()
+
+	dbStations := make([]dbStation, 0)
+	for rows.Next() {
+		var dbStation dbStation
+		if err := rows.Scan(&dbStation.id, &dbStation.name); err != nil {
+			return nil, fmt.Errorf("failed to scan station: %w", err)
+		}
+		dbStations = append(dbStations, dbStation)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query stations: %w", err)
+	}
+
+	stations := make([]domain.Station, 0, len(dbStations))
+	for _, dbStation := range dbStations {
+		station, err := s.withRoutes(ctx, tx, dbStation)
+		if err != nil {
+			return nil, err
+		}
+		stations = append(stations, station)
+	}
+
+	return stations, tx.Commit()
+}
+
+// saveRoutes replaces the IDs routed to the station in the table of the routes.
+func (s *Station) saveRoutes(ctx context.Context, tx *sql.Tx, table string, column string, stationID id.ID, ids []id.ID) error {
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE station_id = ?`, table), stationID); err != nil {
+		return fmt.Errorf("failed to delete station routes: %w", err)
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(ids)*3)
+	for position, routedID := range ids {
+		args = append(args, stationID, routedID, position)
+	}
+
+	_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO %s (station_id, %s, position)
+		VALUES %s
+	`, table, column, strings.Repeat(", (?, ?, ?)", len(ids))[2:]), args...)
+	if err != nil {
+		return fmt.Errorf("failed to insert station routes: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Station) withRoutes(ctx context.Context, q queryer, dbStation dbStation) (domain.Station, error) {
+	menuItemIDs, err := s.findRoutes(ctx, q, `SELECT menu_item_id FROM station_menu_items WHERE station_id = ? ORDER BY position`, dbStation.id)
+	if err != nil {
+		return domain.Station{}, err
+	}
+
+	categoryIDs, err := s.findRoutes(ctx, q, `SELECT category_id FROM station_categories WHERE station_id = ? ORDER BY position`, dbStation.id)
+	if err != nil {
+		return domain.Station{}, err
+	}
+
+	return domain.Station{ID: dbStation.id, Name: dbStation.name, MenuItemIDs: menuItemIDs, CategoryIDs: categoryIDs}, nil
+}
+
+func (s *Station) findRoutes(ctx context.Context, q queryer, query string, stationID id.ID) ([]id.ID, error) {
+	rows, err := q.QueryContext(ctx, query, stationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query station routes: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]id.ID, 0)
+	for rows.Next() {
+		var routedID id.ID
+		if err := rows.Scan(&routedID); err != nil {
+			return nil, fmt.Errorf("failed to scan station route: %w", err)
+		}
+		ids = append(ids, routedID)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,74 @@
+package sqlite_test
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"order_manager/internal/sqlite"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndRetrieveStation(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	ctx := context.Background()
+	menuRepo := sqlite.NewMenu(db)
+	stationRepo := sqlite.NewStation(db)
+
+	first, second := GenerateDummyItem(), GenerateDummyItem()
+	require.NoError(t, menuRepo.SaveItems(ctx, []domain.MenuItem{first, second}), "Initial setup failed")
+	category := GenerateDummyCategory()
+	require.NoError(t, menuRepo.SaveCategory(ctx, category), "Initial setup failed")
+
+	grill := domain.Station{ID: id.New(), Name: "grill", MenuItemIDs: []id.ID{second.ID, first.ID}, CategoryIDs: []id.ID{category.ID}}
+	bar := domain.Station{ID: id.New(), Name: "bar", MenuItemIDs: []id.ID{}, CategoryIDs: []id.ID{}}
+	for _, station := range []domain.Station{grill, bar} {
+		err := stationRepo.Save(ctx, station)
+		require.NoErrorf(t, err, "failed to save station: %v", err)
+	}
+
+	gotStation, err := stationRepo.FindByID(ctx, grill.ID)
+	require.NoErrorf(t, err, "failed to retrieve station: %v", err)
+	assert.Equal(t, grill, gotStation)
+
+	// Saving the station again replaces its routes.
+	grill.MenuItemIDs = []id.ID{first.ID}
+	grill.CategoryIDs = []id.ID{}
+	require.NoError(t, stationRepo.Save(ctx, grill))
+
+	gotStations, err := stationRepo.FindAll(ctx)
+	require.NoErrorf(t, err, "failed to retrieve stations: %v", err)
+	assert.Equal(t, []domain.Station{bar, grill}, gotStations)
+
+	_, err = stationRepo.FindByID(ctx, id.New())
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+}
+
+func TestSaveAndRetrieveTableWithStations(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	ctx := context.Background()
+	station := domain.Station{ID: id.New(), Name: "grill", MenuItemIDs: []id.ID{}, CategoryIDs: []id.ID{}}
+	require.NoError(t, sqlite.NewStation(db).Save(ctx, station), "Initial setup failed")
+
+	table := GenerateDummyTable(domain.TableStatusOpened)
+	routed := table.Orders[0].Preparations[0]
+	routed.ID = id.New()
+	routed.MenuItem = GenerateDummyItem()
+	routed.StationID = station.ID
+	table.Orders[0].Preparations = append(table.Orders[0].Preparations, routed)
+	MustPresaveItemsFromTable(t, db, table)
+
+	tableRepo := sqlite.NewTable(db)
+	err := tableRepo.Save(ctx, table)
+	require.NoErrorf(t, err, "failed to save table: %v", err)
+
+	gotTable, err := tableRepo.FindByID(ctx, table.ID)
+	require.NoErrorf(t, err, "failed to retrieve table: %v", err)
+	assert.Equal(t, table, gotTable)
+}
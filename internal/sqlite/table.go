@@ -7,6 +7,7 @@ import (
 	"order_manager/internal/domain"
 	"order_manager/internal/id"
 	"strings"
+	"time"
 )
 
 type dbTableStatus string
@@ -21,12 +22,17 @@ func (s dbTableStatus) IsValid() bool {
 }
 
 type dbTable struct {
-	id     id.ID         `db:"id"`
-	status dbTableStatus `db:"status"`
+	id            id.ID          `db:"id"`
+	diningTableID id.ID          `db:"dining_table_id"`
+	guestCount    int            `db:"guest_count"`
+	status        dbTableStatus  `db:"status"`
+	openedAt      sql.NullString `db:"opened_at"`
+	closedAt      sql.NullString `db:"closed_at"`
+	version       int            `db:"version"`
 }
 
 func (t dbTable) IsValid() bool {
-	return t.id != id.NilID() && t.status.IsValid()
+	return t.id != id.NilID() && t.guestCount >= 0 && t.status.IsValid() && t.version >= 0
 }
 
 type dbOrderStatus string
@@ -54,6 +60,7 @@ func (o dbOrder) IsValid() bool {
 type dbPreparationStatus string
 
 const (
+	dbPreparationStatusHeld       dbPreparationStatus = "held"
 	dbPreparationStatusPending    dbPreparationStatus = "pending"
 	dbPreparationStatusInProgress dbPreparationStatus = "in progress"
 	dbPreparationStatusReady      dbPreparationStatus = "ready"
@@ -62,22 +69,57 @@ const (
 )
 
 func (s dbPreparationStatus) IsValid() bool {
-	return s == dbPreparationStatusPending ||
+	return s == dbPreparationStatusHeld ||
+		s == dbPreparationStatusPending ||
 		s == dbPreparationStatusInProgress ||
 		s == dbPreparationStatusReady ||
 		s == dbPreparationStatusServed ||
 		s == dbPreparationStatusAborted
 }
 
+// dbPreparation keeps the name, price and tax category of the menu item as they were when ordering,
+// so that later changes to the menu do not rewrite past orders and bills.
 type dbPreparation struct {
-	id         id.ID               `db:"id"`
-	orderID    id.ID               `db:"order_id"`
-	menuItemID id.ID               `db:"menu_item_id"`
-	status     dbPreparationStatus `db:"status"`
+	id                  id.ID               `db:"id"`
+	orderID             id.ID               `db:"order_id"`
+	menuItemID          id.ID               `db:"menu_item_id"`
+	menuItemName        string              `db:"menu_item_name"`
+	menuItemPrice       int                 `db:"menu_item_price"`
+	menuItemTaxCategory string              `db:"menu_item_tax_category"`
+	seat                int                 `db:"seat"`
+	note                string              `db:"note"`
+	course              int                 `db:"course"`
+	stationID           id.ID               `db:"station_id"`
+	status              dbPreparationStatus `db:"status"`
 }
 
 func (p dbPreparation) IsValid() bool {
-	return p.id != id.NilID() && p.orderID != id.NilID() && p.menuItemID != id.NilID() && p.status.IsValid()
+	return p.id != id.NilID() && p.orderID != id.NilID() && p.menuItemID != id.NilID() && p.menuItemName != "" && p.menuItemPrice >= 0 && p.seat >= 0 && p.course >= 0 && p.status.IsValid()
+}
+
+type dbPreparationModifier struct {
+	preparationID id.ID  `db:"preparation_id"`
+	optionID      id.ID  `db:"option_id"`
+	groupID       id.ID  `db:"group_id"`
+	groupName     string `db:"group_name"`
+	optionName    string `db:"option_name"`
+	priceDelta    int    `db:"price_delta"`
+	position      int    `db:"position"`
+}
+
+func (m dbPreparationModifier) IsValid() bool {
+	return m.preparationID != id.NilID() && m.optionID != id.NilID() && m.groupID != id.NilID() && m.groupName != "" && m.optionName != ""
+}
+
+type dbPreparationTransition struct {
+	preparationID id.ID               `db:"preparation_id"`
+	position      int                 `db:"position"`
+	status        dbPreparationStatus `db:"status"`
+	at            string              `db:"at"`
+}
+
+func (t dbPreparationTransition) IsValid() bool {
+	return t.preparationID != id.NilID() && t.position >= 0 && t.status.IsValid() && t.at != ""
 }
 
 type Table struct {
@@ -99,7 +141,7 @@ func (t *Table) Save(ctx context.Context, table domain.Table) error {
 	}
 	defer tx.Rollback()
 
-	dbTable, dbOrders, dbPreparations, err := toDBTable(table)
+	dbTable, dbOrders, dbPreparations, dbModifiers, dbHistory, err := toDBTable(table)
 	if err != nil {
 		return err
 	}
@@ -119,6 +161,43 @@ func (t *Table) Save(ctx context.Context, table domain.Table) error {
 		return fmt.Errorf("failed to insert preparations: %w", err)
 	}
 
+	err = t.insertPreparationModifiers(ctx, tx, dbModifiers)
+	if err != nil {
+		return fmt.Errorf("failed to insert preparation modifiers: %w", err)
+	}
+
+	err = t.insertPreparationHistory(ctx, tx, dbHistory)
+	if err != nil {
+		return fmt.Errorf("failed to insert preparation history: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (t *Table) Delete(ctx context.Context, table domain.Table) error {
+	tx, err := t.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var version int
+	if err := tx.QueryRowContext(ctx, `SELECT version FROM tables WHERE id = ?`, table.ID).Scan(&version); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Errorf(domain.ENOTFOUND, "table with id %s not found", table.ID)
+		}
+		return fmt.Errorf("failed to find table: %w", err)
+	}
+
+	if version != table.Version {
+		return domain.Errorf(domain.ESTALE, "table %s was modified concurrently, stored version is %d, got %d", table.ID, version, table.Version)
+	}
+
+	// The orders of the table are not deleted, a table at the version it was opened with having none.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tables WHERE id = ?`, table.ID); err != nil {
+		return fmt.Errorf("failed to delete table: %w", err)
+	}
+
 	return tx.Commit()
 }
 
@@ -131,10 +210,10 @@ func (t *Table) FindByID(ctx context.Context, id id.ID) (domain.Table, error) {
 
 	var dbTable dbTable
 	if err = tx.QueryRowContext(ctx, `
-		SELECT id, status
+		SELECT id, dining_table_id, guest_count, status, opened_at, closed_at, version
 		FROM tables
 		WHERE id = ?
-		`, id).Scan(&dbTable.id, &dbTable.status); err != nil {
+		`, id).Scan(&dbTable.id, &dbTable.diningTableID, &dbTable.guestCount, &dbTable.status, &dbTable.openedAt, &dbTable.closedAt, &dbTable.version); err != nil {
 		if err == sql.ErrNoRows {
 			return domain.Table{}, domain.Errorf(domain.ENOTFOUND, "table %d not found", id)
 		}
@@ -163,7 +242,7 @@ func (t *Table) FindByID(ctx context.Context, id id.ID) (domain.Table, error) {
 	var dbPreparations []dbPreparation
 	for _, o := range dbOrders {
 		rows, err = tx.QueryContext(ctx, `
-			SELECT id, order_id, menu_item_id, status
+			SELECT id, order_id, menu_item_id, menu_item_name, menu_item_price, menu_item_tax_category, seat, note, course, station_id, status
 			FROM preparations
 			WHERE order_id = ?
 			`, o.id)
@@ -174,27 +253,27 @@ func (t *Table) FindByID(ctx context.Context, id id.ID) (domain.Table, error) {
 
 		for rows.Next() {
 			var dbPreparation dbPreparation
-			if err = rows.Scan(&dbPreparation.id, &dbPreparation.orderID, &dbPreparation.menuItemID, &dbPreparation.status); err != nil {
+			if err = rows.Scan(&dbPreparation.id, &dbPreparation.orderID, &dbPreparation.menuItemID, &dbPreparation.menuItemName, &dbPreparation.menuItemPrice, &dbPreparation.menuItemTaxCategory, &dbPreparation.seat, &dbPreparation.note, &dbPreparation.course, &dbPreparation.stationID, &dbPreparation.status); err != nil {
 				return domain.Table{}, err
 			}
 			dbPreparations = append(dbPreparations, dbPreparation)
 		}
 	}
 
-	var dbItems []dbMenuItem
-	for _, p := range dbPreparations {
-		var dbItem dbMenuItem
-		if err = tx.QueryRowContext(ctx, `
-			SELECT id, name, price
-			FROM menu_items
-			WHERE id = ?
-			`, p.menuItemID).Scan(&dbItem.id, &dbItem.name, &dbItem.price); err != nil {
-			return domain.Table{}, err
-		}
-		dbItems = append(dbItems, dbItem)
+	dbModifiers, err := t.findPreparationModifiers(ctx, tx, dbTable.id)
+	if err != nil {
+		return domain.Table{}, err
+	}
+
+	dbHistory, err := t.findPreparationHistory(ctx, tx, dbTable.id)
+	if err != nil {
+		return domain.Table{}, err
 	}
 
-	table := toDomainTable(dbTable, dbOrders, dbPreparations, dbItems)
+	table, err := toDomainTable(dbTable, dbOrders, dbPreparations, dbModifiers, dbHistory)
+	if err != nil {
+		return domain.Table{}, err
+	}
 
 	return table, tx.Commit()
 }
@@ -220,6 +299,23 @@ func (t *Table) FindByPreparationID(ctx context.Context, preparationID id.ID) (d
 	return t.FindByID(ctx, tableID)
 }
 
+func (t *Table) FindByOrderID(ctx context.Context, orderID id.ID) (domain.Table, error) {
+	var tableID id.ID
+	err := t.QueryRowContext(ctx, `
+		SELECT table_id
+		FROM orders
+		WHERE id = ?
+		`, orderID).Scan(&tableID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Table{}, domain.Errorf(domain.ENOTFOUND, "table with order id %s not found", orderID)
+		}
+		return domain.Table{}, fmt.Errorf("failed to find table: %w", err)
+	}
+
+	return t.FindByID(ctx, tableID)
+}
+
 func (t *Table) FindByStatus(ctx context.Context, status domain.TableStatus) ([]domain.Table, error) {
 	tx, err := t.BeginTx(ctx, nil)
 	if err != nil {
@@ -227,7 +323,7 @@ func (t *Table) FindByStatus(ctx context.Context, status domain.TableStatus) ([]
 	}
 
 	rows, err := tx.QueryContext(ctx, `
-		SELECT id, status
+		SELECT id, dining_table_id, guest_count, status, opened_at, closed_at, version
 		FROM tables
 		WHERE status = ?
 		`, dbTableStatus(status))
@@ -239,7 +335,7 @@ func (t *Table) FindByStatus(ctx context.Context, status domain.TableStatus) ([]
 	tables := make([]domain.Table, 0)
 	for rows.Next() {
 		var dbTable dbTable
-		if err = rows.Scan(&dbTable.id, &dbTable.status); err != nil {
+		if err = rows.Scan(&dbTable.id, &dbTable.diningTableID, &dbTable.guestCount, &dbTable.status, &dbTable.openedAt, &dbTable.closedAt, &dbTable.version); err != nil {
 			return nil, fmt.Errorf("failed to scan table: %w", err)
 		}
 
@@ -265,7 +361,7 @@ func (t *Table) FindByStatus(ctx context.Context, status domain.TableStatus) ([]
 		var dbPreparations []dbPreparation
 		for _, o := range dbOrders {
 			rows, err = tx.QueryContext(ctx, `
-				SELECT id, order_id, menu_item_id, status
+				SELECT id, order_id, menu_item_id, menu_item_name, menu_item_price, menu_item_tax_category, seat, note, course, station_id, status
 				FROM preparations
 				WHERE order_id = ?
 				`, o.id)
@@ -276,47 +372,114 @@ func (t *Table) FindByStatus(ctx context.Context, status domain.TableStatus) ([]
 
 			for rows.Next() {
 				var dbPreparation dbPreparation
-				if err = rows.Scan(&dbPreparation.id, &dbPreparation.orderID, &dbPreparation.menuItemID, &dbPreparation.status); err != nil {
+				if err = rows.Scan(&dbPreparation.id, &dbPreparation.orderID, &dbPreparation.menuItemID, &dbPreparation.menuItemName, &dbPreparation.menuItemPrice, &dbPreparation.menuItemTaxCategory, &dbPreparation.seat, &dbPreparation.note, &dbPreparation.course, &dbPreparation.stationID, &dbPreparation.status); err != nil {
 					return nil, fmt.Errorf("failed to scan preparation: %w", err)
 				}
 				dbPreparations = append(dbPreparations, dbPreparation)
 			}
 		}
 
-		var dbItems []dbMenuItem
-		for _, p := range dbPreparations {
-			var dbItem dbMenuItem
-			if err = tx.QueryRowContext(ctx, `
-				SELECT id, name, price
-				FROM menu_items
-				WHERE id = ?
-				`, p.menuItemID).Scan(&dbItem.id, &dbItem.name, &dbItem.price); err != nil {
-				return nil, fmt.Errorf("failed to scan menu item: %w", err)
-			}
-			dbItems = append(dbItems, dbItem)
+		dbModifiers, err := t.findPreparationModifiers(ctx, tx, dbTable.id)
+		if err != nil {
+			return nil, err
+		}
+
+		dbHistory, err := t.findPreparationHistory(ctx, tx, dbTable.id)
+		if err != nil {
+			return nil, err
 		}
 
-		table := toDomainTable(dbTable, dbOrders, dbPreparations, dbItems)
+		table, err := toDomainTable(dbTable, dbOrders, dbPreparations, dbModifiers, dbHistory)
+		if err != nil {
+			return nil, err
+		}
 		tables = append(tables, table)
 	}
 
 	return tables, tx.Commit()
 }
 
+// FindOrderedBetween finds the tables having a preparation whose first transition, the order, is in the time range.
+func (t *Table) FindOrderedBetween(ctx context.Context, from time.Time, to time.Time) ([]domain.Table, error) {
+	return t.findTables(ctx, `
+		SELECT DISTINCT o.table_id
+		FROM preparation_history h
+		JOIN preparations p ON p.id = h.preparation_id
+		JOIN orders o ON o.id = p.order_id
+		WHERE h.position = 0 AND h.at >= ? AND h.at < ?
+		`, from.UTC().Format(dbTimeLayout), to.UTC().Format(dbTimeLayout))
+}
+
+func (t *Table) FindClosedBetween(ctx context.Context, from time.Time, to time.Time) ([]domain.Table, error) {
+	return t.findTables(ctx, `
+		SELECT id
+		FROM tables
+		WHERE status = ? AND closed_at >= ? AND closed_at < ?
+		`, dbTableStatusClosed, from.UTC().Format(dbTimeLayout), to.UTC().Format(dbTimeLayout))
+}
+
+// findTables finds the tables of the IDs selected by the query.
+func (t *Table) findTables(ctx context.Context, query string, args ...any) ([]domain.Table, error) {
+	rows, err := t.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+	defer rows.Close()
+
+	tableIDs := make([]id.ID, 0)
+	for rows.Next() {
+		var tableID id.ID
+		if err := rows.Scan(&tableID); err != nil {
+			return nil, fmt.Errorf("failed to scan table id: %w", err)
+		}
+		tableIDs = append(tableIDs, tableID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+	rows.Close()
+
+	tables := make([]domain.Table, 0, len(tableIDs))
+	for _, tableID := range tableIDs {
+		table, err := t.FindByID(ctx, tableID)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
 func (t *Table) insertTable(ctx context.Context, tx *sql.Tx, table dbTable) error {
 	if !table.IsValid() {
 		return domain.Errorf(domain.EINVALID, "table is invalid: %v", table)
 	}
 
-	_, err := tx.ExecContext(ctx, `
-		INSERT INTO tables (id, status)
-		VALUES (?, ?)
-			ON CONFLICT (id) DO UPDATE SET status = excluded.status
-		`, table.id, table.status)
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO tables (id, dining_table_id, guest_count, status, opened_at, closed_at, version)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET guest_count = excluded.guest_count, status = excluded.status,
+				opened_at = excluded.opened_at, closed_at = excluded.closed_at, version = excluded.version
+			WHERE tables.version = excluded.version - 1
+		`, table.id, nullableID(table.diningTableID), table.guestCount, table.status, table.openedAt, table.closedAt, table.version)
+	if isUniqueViolation(err) {
+		// Only one table can be opened on a dining table, a concurrent opening got it first.
+		return domain.Errorf(domain.ECONFLICT, "dining table %s is already occupied", table.diningTableID)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to insert table: %w", err)
 	}
 
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to insert table: %w", err)
+	}
+
+	if affected == 0 {
+		return domain.Errorf(domain.ESTALE, "table %s was modified concurrently, version %d is outdated", table.id, table.version)
+	}
+
 	return nil
 }
 
@@ -361,13 +524,13 @@ func (t *Table) insertPreparations(ctx context.Context, tx *sql.Tx, preparations
 	}
 
 	preparationQuery := fmt.Sprintf(`
-		INSERT INTO preparations (id, order_id, menu_item_id, status)
+		INSERT INTO preparations (id, order_id, menu_item_id, menu_item_name, menu_item_price, menu_item_tax_category, seat, note, course, station_id, status)
 		VALUES %s
 			ON CONFLICT (id) DO UPDATE SET status = excluded.status
-		`, strings.Repeat(", (?, ?, ?, ?)", len(preparations))[2:])
-	args := make([]interface{}, 0, len(preparations)*4)
+		`, strings.Repeat(", (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", len(preparations))[2:])
+	args := make([]interface{}, 0, len(preparations)*11)
 	for _, p := range preparations {
-		args = append(args, p.id, p.orderID, p.menuItemID, p.status)
+		args = append(args, p.id, p.orderID, p.menuItemID, p.menuItemName, p.menuItemPrice, p.menuItemTaxCategory, p.seat, p.note, p.course, nullableID(p.stationID), p.status)
 	}
 
 	_, err := tx.ExecContext(ctx, preparationQuery, args...)
@@ -378,14 +541,139 @@ func (t *Table) insertPreparations(ctx context.Context, tx *sql.Tx, preparations
 	return nil
 }
 
-func toDBTable(table domain.Table) (dbTable, []dbOrder, []dbPreparation, error) {
+func (t *Table) insertPreparationModifiers(ctx context.Context, tx *sql.Tx, modifiers []dbPreparationModifier) error {
+	if len(modifiers) == 0 {
+		return nil
+	}
+
+	for _, m := range modifiers {
+		if !m.IsValid() {
+			return domain.Errorf(domain.EINVALID, "preparation modifier is invalid: %v", m)
+		}
+	}
+
+	// Modifiers are chosen when ordering and never change afterwards.
+	modifierQuery := fmt.Sprintf(`
+		INSERT INTO preparation_modifiers (preparation_id, option_id, group_id, group_name, option_name, price_delta, position)
+		VALUES %s
+			ON CONFLICT DO NOTHING
+		`, strings.Repeat(", (?, ?, ?, ?, ?, ?, ?)", len(modifiers))[2:])
+	args := make([]interface{}, 0, len(modifiers)*7)
+	for _, m := range modifiers {
+		args = append(args, m.preparationID, m.optionID, m.groupID, m.groupName, m.optionName, m.priceDelta, m.position)
+	}
+
+	_, err := tx.ExecContext(ctx, modifierQuery, args...)
+	if err != nil {
+		return fmt.Errorf("failed to insert preparation modifiers: %w", err)
+	}
+
+	return nil
+}
+
+func (t *Table) findPreparationModifiers(ctx context.Context, tx *sql.Tx, tableID id.ID) ([]dbPreparationModifier, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT preparation_id, option_id, group_id, group_name, option_name, price_delta, position
+		FROM preparation_modifiers
+		WHERE preparation_id IN (
+			SELECT p.id
+			FROM preparations p
+			JOIN orders o ON o.id = p.order_id
+			WHERE o.table_id = ?
+		)
+		ORDER BY position
+		`, tableID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query preparation modifiers: %w", err)
+	}
+	defer rows.Close()
+
+	var dbModifiers []dbPreparationModifier
+	for rows.Next() {
+		var m dbPreparationModifier
+		if err := rows.Scan(&m.preparationID, &m.optionID, &m.groupID, &m.groupName, &m.optionName, &m.priceDelta, &m.position); err != nil {
+			return nil, fmt.Errorf("failed to scan preparation modifier: %w", err)
+		}
+		dbModifiers = append(dbModifiers, m)
+	}
+
+	return dbModifiers, rows.Err()
+}
+
+func (t *Table) insertPreparationHistory(ctx context.Context, tx *sql.Tx, history []dbPreparationTransition) error {
+	if len(history) == 0 {
+		return nil
+	}
+
+	for _, h := range history {
+		if !h.IsValid() {
+			return domain.Errorf(domain.EINVALID, "preparation transition is invalid: %v", h)
+		}
+	}
+
+	// The history is only ever appended to, the transitions already saved are kept as they are.
+	historyQuery := fmt.Sprintf(`
+		INSERT INTO preparation_history (preparation_id, position, status, at)
+		VALUES %s
+			ON CONFLICT DO NOTHING
+		`, strings.Repeat(", (?, ?, ?, ?)", len(history))[2:])
+	args := make([]interface{}, 0, len(history)*4)
+	for _, h := range history {
+		args = append(args, h.preparationID, h.position, h.status, h.at)
+	}
+
+	_, err := tx.ExecContext(ctx, historyQuery, args...)
+	if err != nil {
+		return fmt.Errorf("failed to insert preparation history: %w", err)
+	}
+
+	return nil
+}
+
+func (t *Table) findPreparationHistory(ctx context.Context, tx *sql.Tx, tableID id.ID) ([]dbPreparationTransition, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT preparation_id, position, status, at
+		FROM preparation_history
+		WHERE preparation_id IN (
+			SELECT p.id
+			FROM preparations p
+			JOIN orders o ON o.id = p.order_id
+			WHERE o.table_id = ?
+		)
+		ORDER BY position
+		`, tableID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query preparation history: %w", err)
+	}
+	defer rows.Close()
+
+	var dbHistory []dbPreparationTransition
+	for rows.Next() {
+		var h dbPreparationTransition
+		if err := rows.Scan(&h.preparationID, &h.position, &h.status, &h.at); err != nil {
+			return nil, fmt.Errorf("failed to scan preparation transition: %w", err)
+		}
+		dbHistory = append(dbHistory, h)
+	}
+
+	return dbHistory, rows.Err()
+}
+
+func toDBTable(table domain.Table) (dbTable, []dbOrder, []dbPreparation, []dbPreparationModifier, []dbPreparationTransition, error) {
 	dbTable := dbTable{
-		id:     table.ID,
-		status: dbTableStatus(table.Status),
+		id:            table.ID,
+		diningTableID: table.DiningTableID,
+		guestCount:    table.GuestCount,
+		status:        dbTableStatus(table.Status),
+		openedAt:      nullableTime(table.OpenedAt),
+		closedAt:      nullableTime(table.ClosedAt),
+		version:       table.Version,
 	}
 
 	dbOrders := make([]dbOrder, 0, len(table.Orders))
 	dbPreparations := make([]dbPreparation, 0, len(table.Orders))
+	dbModifiers := make([]dbPreparationModifier, 0)
+	dbHistory := make([]dbPreparationTransition, 0)
 	for _, o := range table.Orders {
 		dbOrder := dbOrder{
 			id:      o.ID,
@@ -396,24 +684,67 @@ func toDBTable(table domain.Table) (dbTable, []dbOrder, []dbPreparation, error)
 
 		for _, p := range o.Preparations {
 			dbPreparation := dbPreparation{
-				id:         p.ID,
-				orderID:    o.ID,
-				menuItemID: p.MenuItem.ID,
-				status:     dbPreparationStatus(p.Status),
+				id:                  p.ID,
+				orderID:             o.ID,
+				menuItemID:          p.MenuItem.ID,
+				menuItemName:        p.MenuItem.Name,
+				menuItemPrice:       p.MenuItem.Price,
+				menuItemTaxCategory: p.MenuItem.TaxCategory,
+				seat:                p.Seat,
+				note:                p.Note,
+				course:              p.Course,
+				stationID:           p.StationID,
+				status:              dbPreparationStatus(p.Status),
 			}
 			dbPreparations = append(dbPreparations, dbPreparation)
+
+			for position, m := range p.Modifiers {
+				dbModifiers = append(dbModifiers, dbPreparationModifier{
+					preparationID: p.ID,
+					optionID:      m.OptionID,
+					groupID:       m.GroupID,
+					groupName:     m.GroupName,
+					optionName:    m.OptionName,
+					priceDelta:    m.PriceDelta,
+					position:      position,
+				})
+			}
+
+			for position, h := range p.History {
+				dbHistory = append(dbHistory, dbPreparationTransition{
+					preparationID: p.ID,
+					position:      position,
+					status:        dbPreparationStatus(h.Status),
+					at:            h.At.UTC().Format(dbTimeLayout),
+				})
+			}
 		}
 	}
 
-	return dbTable, dbOrders, dbPreparations, nil
+	return dbTable, dbOrders, dbPreparations, dbModifiers, dbHistory, nil
 }
 
-func toDomainTable(dbTable dbTable, dbOrders []dbOrder, dbPreparations []dbPreparation, dbItems []dbMenuItem) domain.Table {
+func toDomainTable(dbTable dbTable, dbOrders []dbOrder, dbPreparations []dbPreparation, dbModifiers []dbPreparationModifier, dbHistory []dbPreparationTransition) (domain.Table, error) {
 	table := domain.Table{
-		ID:     dbTable.id,
-		Status: domain.TableStatus(dbTable.status),
-		Orders: make([]domain.Order, 0, len(dbOrders)),
+		ID:            dbTable.id,
+		DiningTableID: dbTable.diningTableID,
+		GuestCount:    dbTable.guestCount,
+		Status:        domain.TableStatus(dbTable.status),
+		Version:       dbTable.version,
+		Orders:        make([]domain.Order, 0, len(dbOrders)),
+	}
+
+	openedAt, err := parseNullableTime(dbTable.openedAt)
+	if err != nil {
+		return domain.Table{}, fmt.Errorf("failed to parse table opening: %w", err)
 	}
+	table.OpenedAt = openedAt
+
+	closedAt, err := parseNullableTime(dbTable.closedAt)
+	if err != nil {
+		return domain.Table{}, fmt.Errorf("failed to parse table closing: %w", err)
+	}
+	table.ClosedAt = closedAt
 
 	for _, o := range dbOrders {
 		order := domain.Order{
@@ -426,26 +757,48 @@ func toDomainTable(dbTable dbTable, dbOrders []dbOrder, dbPreparations []dbPrepa
 				continue
 			}
 
-			var item domain.MenuItem
-			for _, i := range dbItems {
-				if i.id == p.menuItemID {
-					item = domain.MenuItem{
-						ID:    i.id,
-						Name:  i.name,
-						Price: i.price,
-					}
-					break
+			preparation := domain.Preparation{
+				ID: p.id,
+				MenuItem: domain.MenuItem{
+					ID:          p.menuItemID,
+					Name:        p.menuItemName,
+					Price:       p.menuItemPrice,
+					TaxCategory: p.menuItemTaxCategory,
+				},
+				Seat:      p.seat,
+				Note:      p.note,
+				Course:    p.course,
+				StationID: p.stationID,
+				Status:    domain.PreparationStatus(p.status),
+			}
+			for _, m := range dbModifiers {
+				if m.preparationID != p.id {
+					continue
 				}
+
+				preparation.Modifiers = append(preparation.Modifiers, domain.Modifier{
+					GroupID:    m.groupID,
+					GroupName:  m.groupName,
+					OptionID:   m.optionID,
+					OptionName: m.optionName,
+					PriceDelta: m.priceDelta,
+				})
 			}
-			preparation := domain.Preparation{
-				ID:       p.id,
-				MenuItem: item,
-				Status:   domain.PreparationStatus(p.status),
+			for _, h := range dbHistory {
+				if h.preparationID != p.id {
+					continue
+				}
+
+				at, err := time.Parse(dbTimeLayout, h.at)
+				if err != nil {
+					return domain.Table{}, fmt.Errorf("failed to parse preparation transition time: %w", err)
+				}
+				preparation.History = append(preparation.History, domain.PreparationTransition{Status: domain.PreparationStatus(h.status), At: at})
 			}
 			order.Preparations = append(order.Preparations, preparation)
 		}
 		table.Orders = append(table.Orders, order)
 	}
 
-	return table
+	return table, nil
 }
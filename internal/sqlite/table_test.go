@@ -6,6 +6,7 @@ import (
 	"order_manager/internal/id"
 	"order_manager/internal/sqlite"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -28,7 +29,7 @@ func MustPresaveItemsFromTable(t *testing.T, db *sqlite.DB, table domain.Table)
 
 func GenerateDummyTable(status domain.TableStatus) domain.Table {
 	menuItem := domain.MenuItem{ID: id.New(), Name: "item", Price: 100}
-	preparation := domain.Preparation{ID: id.New(), MenuItem: menuItem, Status: domain.PreparationStatusServed}
+	preparation := domain.Preparation{ID: id.New(), MenuItem: menuItem, Seat: 1, Note: "no ice", Status: domain.PreparationStatusServed}
 	order := domain.Order{ID: id.New(), Status: domain.OrderStatusDone, Preparations: []domain.Preparation{preparation}}
 	table := domain.Table{
 		ID:     id.New(),
@@ -56,6 +57,30 @@ func TestSaveAndRetrieveTableByID(t *testing.T) {
 	assert.Equal(t, table, gotTable)
 }
 
+func TestSaveAndRetrieveTableWithModifiers(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	table := GenerateDummyTable(domain.TableStatusOpened)
+	table.Orders[0].Preparations[0].Modifiers = []domain.Modifier{
+		{GroupID: id.New(), GroupName: "Extras", OptionID: id.New(), OptionName: "Bacon", PriceDelta: 200},
+		{GroupID: id.New(), GroupName: "Extras", OptionID: id.New(), OptionName: "No onions"},
+	}
+	tableRepo := sqlite.NewTable(db)
+	MustPresaveItemsFromTable(t, db, table)
+
+	err := tableRepo.Save(context.Background(), table)
+	require.NoErrorf(t, err, "failed to save table: %v", err)
+
+	gotTable, err := tableRepo.FindByID(context.Background(), table.ID)
+	require.NoErrorf(t, err, "failed to retrieve table: %v", err)
+	assert.Equal(t, table, gotTable)
+
+	gotTables, err := tableRepo.FindByStatus(context.Background(), domain.TableStatusOpened)
+	require.NoErrorf(t, err, "failed to retrieve tables: %v", err)
+	assert.Equal(t, []domain.Table{table}, gotTables)
+}
+
 func TestSaveAndRetrieveTableByStatus(t *testing.T) {
 	db := MustOpenDB(t)
 	defer MustCloseDB(t, db)
@@ -132,3 +157,180 @@ func TestFindTableByStatusWithContextCancellation(t *testing.T) {
 	_, err = tableRepo.FindByStatus(ctx, domain.TableStatusOpened)
 	assert.Error(t, err)
 }
+
+func TestRetrieveTableByOrderID(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	table := GenerateDummyTable(domain.TableStatusOpened)
+	tableRepo := sqlite.NewTable(db)
+	MustPresaveItemsFromTable(t, db, table)
+
+	err := tableRepo.Save(context.Background(), table)
+	require.NoErrorf(t, err, "failed to save table: %v", err)
+
+	gotTable, err := tableRepo.FindByOrderID(context.Background(), table.Orders[0].ID)
+	require.NoErrorf(t, err, "failed to retrieve table: %v", err)
+	assert.Equal(t, table, gotTable)
+
+	_, err = tableRepo.FindByOrderID(context.Background(), id.New())
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+}
+
+func TestSaveStaleTable(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	table := GenerateDummyTable(domain.TableStatusOpened)
+	tableRepo := sqlite.NewTable(db)
+	MustPresaveItemsFromTable(t, db, table)
+
+	err := tableRepo.Save(context.Background(), table)
+	require.NoErrorf(t, err, "failed to save table: %v", err)
+
+	updated := table
+	updated.Version++
+	updated.Status = domain.TableStatusClosed
+	err = tableRepo.Save(context.Background(), updated)
+	require.NoErrorf(t, err, "failed to save table: %v", err)
+
+	// Saving the same version again, or an older one, is a stale write.
+	err = tableRepo.Save(context.Background(), updated)
+	assert.Equal(t, domain.ESTALE, domain.ErrorCode(err))
+	err = tableRepo.Save(context.Background(), table)
+	assert.Equal(t, domain.ESTALE, domain.ErrorCode(err))
+
+	gotTable, err := tableRepo.FindByID(context.Background(), table.ID)
+	require.NoErrorf(t, err, "failed to retrieve table: %v", err)
+	assert.Equal(t, updated, gotTable)
+}
+
+func TestDeleteTable(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	ctx := context.Background()
+	tableRepo := sqlite.NewTable(db)
+	table := domain.Table{ID: id.New(), GuestCount: 2, Status: domain.TableStatusOpened, Orders: []domain.Order{}, Version: 1}
+	require.NoErrorf(t, tableRepo.Save(ctx, table), "failed to save table")
+
+	stale := table
+	stale.Version--
+	err := tableRepo.Delete(ctx, stale)
+	assert.Equal(t, domain.ESTALE, domain.ErrorCode(err), "table deleted from an outdated version")
+
+	require.NoErrorf(t, tableRepo.Delete(ctx, table), "failed to delete table")
+
+	_, err = tableRepo.FindByID(ctx, table.ID)
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err), "table not deleted")
+
+	err = tableRepo.Delete(ctx, table)
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+}
+
+func TestSaveAndRetrieveTableWithHistory(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	ctx := context.Background()
+	orderedAt := time.Date(2026, 10, 17, 19, 0, 0, 500, time.UTC)
+	table := GenerateDummyTable(domain.TableStatusOpened)
+	table.Orders[0].Preparations[0].History = []domain.PreparationTransition{
+		{Status: domain.PreparationStatusPending, At: orderedAt},
+		{Status: domain.PreparationStatusInProgress, At: orderedAt.Add(2 * time.Minute)},
+		{Status: domain.PreparationStatusReady, At: orderedAt.Add(12 * time.Minute)},
+	}
+	tableRepo := sqlite.NewTable(db)
+	MustPresaveItemsFromTable(t, db, table)
+
+	err := tableRepo.Save(ctx, table)
+	require.NoErrorf(t, err, "failed to save table: %v", err)
+
+	// Saving the table again appends the new transitions to the history.
+	prep := &table.Orders[0].Preparations[0]
+	prep.History = append(prep.History, domain.PreparationTransition{Status: domain.PreparationStatusServed, At: orderedAt.Add(13 * time.Minute)})
+	table.Version++
+	err = tableRepo.Save(ctx, table)
+	require.NoErrorf(t, err, "failed to save table: %v", err)
+
+	gotTable, err := tableRepo.FindByID(ctx, table.ID)
+	require.NoErrorf(t, err, "failed to retrieve table: %v", err)
+	assert.Equal(t, table, gotTable)
+
+	gotTables, err := tableRepo.FindOrderedBetween(ctx, orderedAt, orderedAt.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Table{table}, gotTables)
+
+	// The range ends before the order, the times being compared to the nanosecond.
+	gotTables, err = tableRepo.FindOrderedBetween(ctx, orderedAt.Add(-time.Hour), orderedAt)
+	require.NoError(t, err)
+	assert.Empty(t, gotTables)
+
+	gotTables, err = tableRepo.FindOrderedBetween(ctx, orderedAt.Add(time.Nanosecond), orderedAt.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, gotTables)
+}
+
+func TestSaveAndRetrieveTableWithCourses(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	table := GenerateDummyTable(domain.TableStatusOpened)
+	table.Orders[0].Status = domain.OrderStatusTaken
+	table.Orders[0].Preparations[0].Course = 1
+	held := domain.Preparation{ID: id.New(), MenuItem: GenerateDummyItem(), Course: 2, Status: domain.PreparationStatusHeld}
+	table.Orders[0].Preparations = append(table.Orders[0].Preparations, held)
+	tableRepo := sqlite.NewTable(db)
+	MustPresaveItemsFromTable(t, db, table)
+
+	err := tableRepo.Save(context.Background(), table)
+	require.NoErrorf(t, err, "failed to save table: %v", err)
+
+	gotTable, err := tableRepo.FindByID(context.Background(), table.ID)
+	require.NoErrorf(t, err, "failed to retrieve table: %v", err)
+	assert.Equal(t, table, gotTable)
+}
+
+func TestSaveAndRetrieveClosedTables(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	ctx := context.Background()
+	tableRepo := sqlite.NewTable(db)
+	noon := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+
+	opened := GenerateDummyTable(domain.TableStatusOpened)
+	opened.OpenedAt = noon
+	lunch := GenerateDummyTable(domain.TableStatusClosed)
+	lunch.OpenedAt = noon
+	lunch.ClosedAt = noon.Add(45 * time.Minute)
+	dinner := GenerateDummyTable(domain.TableStatusClosed)
+	dinner.OpenedAt = noon.Add(7 * time.Hour)
+	dinner.ClosedAt = noon.Add(8*time.Hour + 30*time.Minute)
+	// Tables closed before their opening was timed only know when they closed.
+	untimed := GenerateDummyTable(domain.TableStatusClosed)
+	untimed.ClosedAt = noon.Add(time.Hour)
+
+	for _, table := range []domain.Table{opened, lunch, dinner, untimed} {
+		MustPresaveItemsFromTable(t, db, table)
+		err := tableRepo.Save(ctx, table)
+		require.NoErrorf(t, err, "failed to save table: %v", err)
+	}
+
+	gotTable, err := tableRepo.FindByID(ctx, dinner.ID)
+	require.NoErrorf(t, err, "failed to retrieve table: %v", err)
+	assert.Equal(t, dinner, gotTable)
+
+	gotTable, err = tableRepo.FindByID(ctx, untimed.ID)
+	require.NoErrorf(t, err, "failed to retrieve table: %v", err)
+	assert.Equal(t, untimed, gotTable)
+
+	gotTables, err := tableRepo.FindClosedBetween(ctx, noon, noon.Add(2*time.Hour))
+	require.NoErrorf(t, err, "failed to retrieve tables: %v", err)
+	assert.ElementsMatch(t, []domain.Table{lunch, untimed}, gotTables)
+
+	// A table closing when the range ends is out of it.
+	gotTables, err = tableRepo.FindClosedBetween(ctx, noon.Add(time.Hour), dinner.ClosedAt)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Table{untimed}, gotTables)
+}
@@ -0,0 +1,148 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"time"
+)
+
+type dbWaitlistStatus string
+
+const (
+	dbWaitlistStatusWaiting dbWaitlistStatus = "waiting"
+	dbWaitlistStatusSeated  dbWaitlistStatus = "seated"
+	dbWaitlistStatusLeft    dbWaitlistStatus = "left"
+)
+
+func (s dbWaitlistStatus) IsValid() bool {
+	return s == dbWaitlistStatusWaiting || s == dbWaitlistStatusSeated || s == dbWaitlistStatusLeft
+}
+
+type dbWaitlistEntry struct {
+	id         id.ID            `db:"id"`
+	name       string           `db:"name"`
+	contact    string           `db:"contact"`
+	partySize  int              `db:"party_size"`
+	joinedAt   string           `db:"joined_at"`
+	quotedWait int64            `db:"quoted_wait"`
+	tableID    id.ID            `db:"table_id"`
+	status     dbWaitlistStatus `db:"status"`
+}
+
+func (e dbWaitlistEntry) IsValid() bool {
+	return e.id != id.NilID() && e.name != "" && e.partySize > 0 && e.joinedAt != "" && e.quotedWait >= 0 && e.status.IsValid()
+}
+
+type Waitlist struct {
+	*DB
+}
+
+func NewWaitlist(db *DB) *Waitlist {
+	return &Waitlist{DB: db}
+}
+
+func (w *Waitlist) Save(ctx context.Context, entry domain.WaitlistEntry) error {
+	if !entry.IsValid() {
+		return domain.Errorf(domain.EINVALID, "waitlist entry is invalid: %v", entry)
+	}
+
+	dbEntry := toDBWaitlistEntry(entry)
+	if !dbEntry.IsValid() {
+		return domain.Errorf(domain.EINVALID, "waitlist entry is invalid: %v", dbEntry)
+	}
+
+	_, err := w.ExecContext(ctx, `
+		INSERT INTO waitlist (id, name, contact, party_size, joined_at, quoted_wait, table_id, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET name = excluded.name, contact = excluded.contact, party_size = excluded.party_size,
+				joined_at = excluded.joined_at, quoted_wait = excluded.quoted_wait, table_id = excluded.table_id, status = excluded.status
+		`, dbEntry.id, dbEntry.name, dbEntry.contact, dbEntry.partySize, dbEntry.joinedAt, dbEntry.quotedWait, nullableID(dbEntry.tableID), dbEntry.status)
+	if err != nil {
+		return fmt.Errorf("failed to insert waitlist entry: %w", err)
+	}
+
+	return nil
+}
+
+func (w *Waitlist) FindByID(ctx context.Context, id id.ID) (domain.WaitlistEntry, error) {
+	var dbEntry dbWaitlistEntry
+	err := w.QueryRowContext(ctx, `
+		SELECT id, name, contact, party_size, joined_at, quoted_wait, table_id, status
+		FROM waitlist
+		WHERE id = ?
+		`, id).Scan(&dbEntry.id, &dbEntry.name, &dbEntry.contact, &dbEntry.partySize, &dbEntry.joinedAt, &dbEntry.quotedWait, &dbEntry.tableID, &dbEntry.status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.WaitlistEntry{}, domain.Errorf(domain.ENOTFOUND, "waitlist entry with id %s not found", id)
+		}
+		return domain.WaitlistEntry{}, fmt.Errorf("failed to find waitlist entry: %w", err)
+	}
+
+	return toDomainWaitlistEntry(dbEntry)
+}
+
+func (w *Waitlist) FindByStatus(ctx context.Context, status domain.WaitlistStatus) ([]domain.WaitlistEntry, error) {
+	rows, err := w.QueryContext(ctx, `
+		SELECT id, name, contact, party_size, joined_at, quoted_wait, table_id, status
+		FROM waitlist
+		WHERE status = ?
+		ORDER BY joined_at
+		`, dbWaitlistStatus(status))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query waitlist: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]domain.WaitlistEntry, 0)
+	for rows.Next() {
+		var dbEntry dbWaitlistEntry
+		if err := rows.Scan(&dbEntry.id, &dbEntry.name, &dbEntry.contact, &dbEntry.partySize, &dbEntry.joinedAt, &dbEntry.quotedWait, &dbEntry.tableID, &dbEntry.status); err != nil {
+			return nil, fmt.Errorf("failed to scan waitlist entry: %w", err)
+		}
+
+		entry, err := toDomainWaitlistEntry(dbEntry)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query waitlist: %w", err)
+	}
+
+	return entries, nil
+}
+
+func toDBWaitlistEntry(entry domain.WaitlistEntry) dbWaitlistEntry {
+	return dbWaitlistEntry{
+		id:         entry.ID,
+		name:       entry.Name,
+		contact:    entry.Contact,
+		partySize:  entry.PartySize,
+		joinedAt:   entry.JoinedAt.UTC().Format(dbTimeLayout),
+		quotedWait: int64(entry.QuotedWait),
+		tableID:    entry.TableID,
+		status:     dbWaitlistStatus(entry.Status),
+	}
+}
+
+func toDomainWaitlistEntry(entry dbWaitlistEntry) (domain.WaitlistEntry, error) {
+	joinedAt, err := time.Parse(dbTimeLayout, entry.joinedAt)
+	if err != nil {
+		return domain.WaitlistEntry{}, fmt.Errorf("failed to parse waitlist entry joining: %w", err)
+	}
+
+	return domain.WaitlistEntry{
+		ID:         entry.id,
+		Name:       entry.name,
+		Contact:    entry.contact,
+		PartySize:  entry.partySize,
+		JoinedAt:   joinedAt,
+		QuotedWait: time.Duration(entry.quotedWait),
+		TableID:    entry.tableID,
+		Status:     domain.WaitlistStatus(entry.status),
+	}, nil
+}
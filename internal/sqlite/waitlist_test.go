@@ -0,0 +1,57 @@
+package sqlite_test
+
+import (
+	"context"
+	"order_manager/internal/domain"
+	"order_manager/internal/id"
+	"order_manager/internal/sqlite"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveAndRetrieveWaitlistEntry(t *testing.T) {
+	db := MustOpenDB(t)
+	defer MustCloseDB(t, db)
+
+	ctx := context.Background()
+	waitlistRepo := sqlite.NewWaitlist(db)
+
+	table := GenerateDummyTable(domain.TableStatusOpened)
+	MustPresaveItemsFromTable(t, db, table)
+	require.NoError(t, sqlite.NewTable(db).Save(ctx, table), "Initial setup failed")
+
+	noon := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	seated := domain.WaitlistEntry{ID: id.New(), Name: "Smith", Contact: "555-0100", PartySize: 4, JoinedAt: noon, QuotedWait: 25 * time.Minute, TableID: table.ID, Status: domain.WaitlistStatusSeated}
+	late := domain.WaitlistEntry{ID: id.New(), Name: "Roe", PartySize: 3, JoinedAt: noon.Add(20 * time.Minute), QuotedWait: 40 * time.Minute, Status: domain.WaitlistStatusWaiting}
+	early := domain.WaitlistEntry{ID: id.New(), Name: "Doe", PartySize: 2, JoinedAt: noon.Add(5 * time.Minute), Status: domain.WaitlistStatusWaiting}
+	for _, entry := range []domain.WaitlistEntry{late, seated, early} {
+		err := waitlistRepo.Save(ctx, entry)
+		require.NoErrorf(t, err, "failed to save waitlist entry: %v", err)
+	}
+
+	gotEntry, err := waitlistRepo.FindByID(ctx, seated.ID)
+	require.NoErrorf(t, err, "failed to retrieve waitlist entry: %v", err)
+	assert.Equal(t, seated, gotEntry)
+
+	gotEntries, err := waitlistRepo.FindByStatus(ctx, domain.WaitlistStatusWaiting)
+	require.NoErrorf(t, err, "failed to retrieve waitlist entries: %v", err)
+	assert.Equal(t, []domain.WaitlistEntry{early, late}, gotEntries)
+
+	late.Status = domain.WaitlistStatusLeft
+	require.NoError(t, waitlistRepo.Save(ctx, late), "failed to update waitlist entry")
+	gotEntries, err = waitlistRepo.FindByStatus(ctx, domain.WaitlistStatusWaiting)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.WaitlistEntry{early}, gotEntries)
+
+	err = waitlistRepo.Save(ctx, domain.WaitlistEntry{ID: id.New(), Name: "Smith", PartySize: 2, JoinedAt: noon, Status: domain.WaitlistStatusSeated})
+	assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "seated entry without a table saved")
+
+	err = waitlistRepo.Save(ctx, domain.WaitlistEntry{ID: id.New(), Name: "Smith", PartySize: 2, JoinedAt: noon, Status: "called"})
+	assert.Equal(t, domain.EINVALID, domain.ErrorCode(err), "entry of an unknown status saved")
+
+	_, err = waitlistRepo.FindByID(ctx, id.New())
+	assert.Equal(t, domain.ENOTFOUND, domain.ErrorCode(err))
+}
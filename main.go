@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"order_manager/internal/config"
 	"order_manager/internal/domain"
+	"order_manager/internal/event"
 	"order_manager/internal/http"
+	"order_manager/internal/inmem"
 	"order_manager/internal/log"
 	"order_manager/internal/sqlite"
 	"os"
@@ -13,30 +16,150 @@ import (
 	"syscall"
 )
 
-func run(ctx context.Context, stdout, stderr io.Writer) error {
+type repositories struct {
+	table       domain.TableRepository
+	menu        domain.MenuRepository
+	bill        domain.BillRepository
+	diningTable domain.DiningTableRepository
+	promotion   domain.PromotionRepository
+	inventory   domain.InventoryRepository
+	station     domain.StationRepository
+	reservation domain.ReservationRepository
+	waitlist    domain.WaitlistRepository
+}
+
+func newRepositories(cfg config.Storage, logger *log.Logger) (repositories, func() error, error) {
+	if cfg.Backend == config.StorageInMem {
+		return repositories{
+			table:       inmem.NewTable(),
+			menu:        inmem.NewMenu(),
+			bill:        inmem.NewBill(),
+			diningTable: inmem.NewDiningTable(),
+			promotion:   inmem.NewPromotion(),
+			inventory:   inmem.NewInventory(),
+			station:     inmem.NewStation(),
+			reservation: inmem.NewReservation(),
+			waitlist:    inmem.NewWaitlist(),
+		}, func() error { return nil }, nil
+	}
+
+	db, err := sqlite.NewDB(cfg.DSN, logger)
+	if err != nil {
+		return repositories{}, nil, err
+	}
+
+	return repositories{
+		table:       sqlite.NewTable(db),
+		menu:        sqlite.NewMenu(db),
+		bill:        sqlite.NewBill(db),
+		diningTable: sqlite.NewDiningTable(db),
+		promotion:   sqlite.NewPromotion(db),
+		inventory:   sqlite.NewInventory(db),
+		station:     sqlite.NewStation(db),
+		reservation: sqlite.NewReservation(db),
+		waitlist:    sqlite.NewWaitlist(db),
+	}, db.Close, nil
+}
+
+func newLogger(cfg config.Log, stdout, stderr io.Writer) (*log.Logger, func() error, error) {
+	level, err := log.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch cfg.Output {
+	case config.OutputStdout:
+		return log.New(level, stdout, stderr), func() error { return nil }, nil
+	case config.OutputStderr:
+		return log.New(level, stderr, stderr), func() error { return nil }, nil
+	}
+
+	f, err := os.OpenFile(cfg.Output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot open log output: %w", err)
+	}
+
+	return log.New(level, f, f), f.Close, nil
+}
+
+func run(ctx context.Context, args []string, getenv func(string) string, stdout, stderr io.Writer) error {
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	logger := log.New(log.Info, stdout, stderr)
+	cfg, err := config.Load(args, getenv)
+	if err != nil {
+		return err
+	}
+
+	logger, closeLogger, err := newLogger(cfg.Log, stdout, stderr)
+	if err != nil {
+		return err
+	}
+	defer closeLogger()
 
-	db, err := sqlite.NewDB("./db", logger)
+	repos, closeRepos, err := newRepositories(cfg.Storage, logger)
 	if err != nil {
 		return err
 	}
+	defer closeRepos()
+
+	bus := event.NewBus()
+	defer bus.Close()
 
-	tableRepository := sqlite.NewTable(db)
-	menuRepository := sqlite.NewMenu(db)
-	billRepository := sqlite.NewBill(db)
+	events := http.NewEventStream()
+	bus.Subscribe(events.HandleEvent)
+	bus.SubscribeAsync(func(ctx context.Context, e domain.Event) {
+		logger.Debugf("[event] %s %+v\n", e.EventName(), e)
+	}, 64)
 
-	tableService := domain.NewTableService(tableRepository)
-	menuService := domain.NewMenuService(menuRepository)
-	billService := domain.NewBillService(billRepository)
+	inventoryService := domain.NewInventoryService(repos.inventory, repos.menu, bus).WithPolicy(domain.InventoryPolicy{
+		DeductOn: domain.StockDeduction(cfg.Inventory.DeductOn),
+		Restock:  domain.RestockPolicy(cfg.Inventory.Restock),
+	})
+	stationService := domain.NewStationService(repos.station, repos.menu, bus)
+	tableService := domain.NewTableService(repos.table, repos.diningTable, bus).WithRetry(domain.RetryPolicy{
+		Attempts: cfg.Retry.Attempts,
+		Backoff:  cfg.Retry.Backoff,
+	}).WithMenu(repos.menu).WithInventory(inventoryService).WithStations(stationService)
+	taxPolicy := domain.TaxPolicy{
+		Exclusive: cfg.Tax.Mode == config.TaxModeExclusive,
+		Rates:     cfg.Tax.Categories,
+	}
+	menuService := domain.NewMenuService(repos.menu, bus).WithTaxPolicy(taxPolicy)
+	billService := domain.NewBillService(repos.bill, bus).WithTaxPolicy(taxPolicy).WithServiceCharge(domain.ServiceChargePolicy{
+		Percent:   cfg.ServiceCharge.Percent,
+		MinGuests: cfg.ServiceCharge.MinGuests,
+	}).WithPromotions(repos.promotion)
+	diningTableService := domain.NewDiningTableService(repos.diningTable, bus)
+	promotionService := domain.NewPromotionService(repos.promotion, repos.menu, bus)
+	reservationService := domain.NewReservationService(repos.reservation, repos.diningTable, tableService, bus)
+	waitlistService := domain.NewWaitlistService(repos.waitlist, repos.table, repos.diningTable, tableService, bus).WithTurnTimePolicy(domain.TurnTimePolicy{
+		Lookback: cfg.Waitlist.TurnTimeLookback,
+		Default:  cfg.Waitlist.DefaultTurnTime,
+	})
+	tableService.WithWaitlist(waitlistService)
 
 	server := http.NewServer(
+		http.Config{
+			Addr:            cfg.HTTP.Addr,
+			ReadTimeout:     cfg.HTTP.ReadTimeout,
+			WriteTimeout:    cfg.HTTP.WriteTimeout,
+			IdleTimeout:     cfg.HTTP.IdleTimeout,
+			ShutdownTimeout: cfg.HTTP.ShutdownTimeout,
+		},
 		logger,
-		tableService,
-		menuService,
-		billService,
+		http.Services{
+			TableService:       tableService,
+			MenuService:        menuService,
+			BillService:        billService,
+			DiningTableService: diningTableService,
+			PromotionService:   promotionService,
+			InventoryService:   inventoryService,
+			StationService:     stationService,
+			ReservationService: reservationService,
+			WaitlistService:    waitlistService,
+		},
+		events,
 	)
 
 	return server.Run(ctx)
@@ -45,7 +168,7 @@ func run(ctx context.Context, stdout, stderr io.Writer) error {
 func main() {
 	ctx := context.Background()
 
-	if err := run(ctx, os.Stdout, os.Stderr); err != nil {
+	if err := run(ctx, os.Args[1:], os.Getenv, os.Stdout, os.Stderr); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}